@@ -0,0 +1,74 @@
+// Standalone gRPC server of the LibraryService (proto/library/v1/library.proto) for internal services.
+// It shares the dependencies of the REST API (same database, outbox dispatchers and event stream).
+package main
+
+import (
+	"context"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/santiago-buildit/code-challenge/backend/internal/config"
+	"google.golang.org/grpc"
+)
+
+const (
+	defaultAddress  = ":9090"
+	shutdownTimeout = 10 * time.Second
+)
+
+func main() {
+
+	log.Println("Initializing gRPC server...")
+
+	// Get all dependencies (with the live event stream of WatchBooks)
+	deps := config.InitDependencies(config.Options{EventStream: true})
+
+	// Register services (every call needs an API key in the x-api-key metadata, see cmd/libctl apikey rotate)
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(deps.APIKeyUnaryInterceptor),
+		grpc.StreamInterceptor(deps.APIKeyStreamInterceptor),
+	)
+	deps.LibraryGRPCHandler.Register(server)
+
+	// Serve (cleartext HTTP/2, TLS is expected to be terminated by the load balancer or mesh)
+	address := os.Getenv("GRPC_ADDRESS")
+	if address == "" {
+		address = defaultAddress
+	}
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Fatalf("Failed to listen on %s: %v", address, err)
+	}
+	go func() {
+		log.Printf("Serving gRPC on %s", address)
+		if err := server.Serve(listener); err != nil {
+			log.Fatalf("gRPC server failed: %v", err)
+		}
+	}()
+
+	// Stop on SIGINT / SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Run the outbox and webhook dispatchers and the event stream (long-running process)
+	deps.StartBackground(ctx)
+	<-ctx.Done()
+
+	// Finish the running calls (open streams are cut after the shutdown timeout)
+	log.Println("Shutting down gRPC server...")
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(shutdownTimeout):
+		log.Println("gRPC server shutdown timed out, closing open calls")
+		server.Stop()
+	}
+}
@@ -0,0 +1,368 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/santiago-buildit/code-challenge/backend/internal/config"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/repositories"
+	"github.com/santiago-buildit/code-challenge/backend/internal/seed"
+	"github.com/santiago-buildit/code-challenge/backend/internal/services"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+)
+
+// Default grace period of rotated API keys (clients switch to the new key meanwhile)
+const defaultKeyGracePeriod = 24 * time.Hour
+
+var commands = []*command{
+	migrateCommand(),
+	bookCreateCommand(),
+	bookRestoreCommand(),
+	bookRecomputeStatusCommand(),
+	apiKeyRotateCommand(),
+	apiKeyListCommand(),
+	seedCommand(),
+}
+
+/* Services (built on the command connection) */
+
+// bookEvents returns the outbox of book events. The dispatcher is not started:
+// events are committed with each change and delivered by the API instances.
+func (c *cli) bookEvents() services.BookEventOutbox {
+	return services.NewOutboxDispatcher(c.db, repositories.NewOutboxRepository(c.db), nil, c.logger)
+}
+
+func (c *cli) bookService() services.BookService {
+	return services.NewBookService(c.db, repositories.NewBookRepository(c.db), c.bookEvents())
+}
+
+func (c *cli) maintenanceService() services.MaintenanceService {
+	return services.NewMaintenanceService(c.db, repositories.NewBookRepository(c.db), c.bookEvents())
+}
+
+func (c *cli) apiKeyService() services.APIKeyService {
+	return services.NewAPIKeyService(c.db, repositories.NewAPIKeyRepository(c.db))
+}
+
+func (c *cli) seedService() services.SeedService {
+	return services.NewSeedService(c.db, repositories.NewSeedRepository())
+}
+
+/* Commands */
+
+func migrateCommand() *command {
+	return &command{
+		name:    "migrate",
+		summary: "Apply the database schema (extensions, tables, indexes and data migrations)",
+		dryRun:  true,
+		run: func(ctx context.Context, c *cli, args []string) error {
+			if len(args) > 0 {
+				return errUsage
+			}
+
+			// Single transaction (a dry run checks the migrations apply and rolls them back)
+			tx, err := c.db.BeginTxx(ctx, nil)
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback()
+			if err := config.Migrate(tx); err != nil {
+				return err
+			}
+			if !c.dryRun {
+				if err := tx.Commit(); err != nil {
+					return err
+				}
+			}
+
+			status := "applied"
+			if c.dryRun {
+				status = "rolled back"
+			}
+			return c.render(map[string]interface{}{"status": status, "dry_run": c.dryRun}, table{footer: "Migrations " + status})
+		},
+	}
+}
+
+func bookCreateCommand() *command {
+	var (
+		req  models.CreateBookRequest
+		tags stringList
+		file string
+	)
+	return &command{
+		name:    "book create",
+		summary: "Create a book (from flags, or from a JSON file with the API request body)",
+		flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&req.ISBN, "isbn", "", "ISBN")
+			fs.StringVar(&req.Title, "title", "", "Title")
+			fs.StringVar(&req.Author, "author", "", "Author")
+			fs.StringVar(&req.Description, "description", "", "Description")
+			fs.StringVar(&req.Publisher, "publisher", "", "Publisher")
+			fs.IntVar(&req.PublicationYear, "year", 0, "Publication year")
+			fs.StringVar(&req.Language, "language", "", "ISO 639-1 language code (e.g. en)")
+			fs.Var(&tags, "tag", "Tag name (repeatable)")
+			fs.StringVar(&file, "file", "", "JSON request body file ('-' for stdin), instead of the book flags")
+		},
+		run: func(ctx context.Context, c *cli, args []string) error {
+			if len(args) > 0 {
+				return errUsage
+			}
+
+			// Read request (file or flags)
+			if file != "" {
+				if err := readJSON(file, &req); err != nil {
+					return err
+				}
+			} else {
+				req.Tags = tags
+			}
+
+			// Sanitize and validate input (same rules as the API)
+			req.Sanitize()
+			if err := binding.Validator.ValidateStruct(&req); err != nil {
+				return fmt.Errorf("invalid book: %w", err)
+			}
+
+			// Invoke service
+			book, err := c.bookService().CreateBook(ctx, req)
+			if err != nil {
+				return err
+			}
+			return c.render(book, bookTable([]models.BookResponse{*book}, "Book created"))
+		},
+	}
+}
+
+func bookRestoreCommand() *command {
+	return &command{
+		name:    "book restore",
+		args:    "<id>",
+		summary: "Restore a deleted book",
+		dryRun:  true,
+		run: func(ctx context.Context, c *cli, args []string) error {
+			if len(args) != 1 {
+				return errUsage
+			}
+
+			// Invoke service
+			book, err := c.maintenanceService().RestoreBook(ctx, args[0], c.dryRun)
+			if errors.Is(err, utils.ErrNotFound) {
+				return fmt.Errorf("no deleted book with ID %s", args[0])
+			}
+			if err != nil {
+				return err
+			}
+
+			footer := "Book restored"
+			if c.dryRun {
+				footer = "Book would be restored"
+			}
+			return c.render(book, bookTable([]models.BookResponse{*book}, footer))
+		},
+	}
+}
+
+func bookRecomputeStatusCommand() *command {
+	return &command{
+		name:    "book recompute-status",
+		args:    "[<id>...]",
+		summary: "Reset book statuses to their latest status change (all books when no IDs are given)",
+		dryRun:  true,
+		run: func(ctx context.Context, c *cli, args []string) error {
+
+			// Invoke service
+			drift, err := c.maintenanceService().RecomputeBookStatuses(ctx, args, c.dryRun)
+			if errors.Is(err, utils.ErrNotFound) {
+				return errors.New("invalid book ID")
+			}
+			if err != nil {
+				return err
+			}
+
+			t := table{header: []string{"ID", "TITLE", "STATUS", "EXPECTED"}}
+			for _, d := range drift {
+				t.rows = append(t.rows, []string{d.BookID, d.Title, string(d.Status), string(d.Expected)})
+			}
+			t.footer = plural(len(drift), "book") + " fixed"
+			if c.dryRun {
+				t.footer = plural(len(drift), "book") + " to fix"
+			}
+			return c.render(drift, t)
+		},
+	}
+}
+
+func apiKeyRotateCommand() *command {
+	var req models.RotateAPIKeyRequest
+	return &command{
+		name:    "apikey rotate",
+		args:    "<name>",
+		summary: "Issue a new API key for a client and expire its previous keys after a grace period",
+		dryRun:  true,
+		flags: func(fs *flag.FlagSet) {
+			fs.DurationVar(&req.GracePeriod, "grace", defaultKeyGracePeriod, "Validity of the previous keys (0 revokes them now)")
+		},
+		run: func(ctx context.Context, c *cli, args []string) error {
+			if len(args) != 1 {
+				return errUsage
+			}
+			req.Name = args[0]
+			req.DryRun = c.dryRun
+
+			// Invoke service
+			res, err := c.apiKeyService().RotateAPIKey(ctx, req)
+			if err != nil {
+				return err
+			}
+
+			t := apiKeyTable(res.Expiring)
+			t.footer = plural(len(res.Expiring), "previous key") + " expiring"
+			if res.Key != nil {
+				t.footer += fmt.Sprintf("\nNew key %s (%s), store it now, it is not shown again:\n%s", res.Key.ID, res.Key.Prefix, res.Secret)
+			}
+			return c.render(res, t)
+		},
+	}
+}
+
+func apiKeyListCommand() *command {
+	var name string
+	return &command{
+		name:    "apikey list",
+		summary: "List API keys (hashes are never shown)",
+		flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&name, "name", "", "Client name (all clients when empty)")
+		},
+		run: func(ctx context.Context, c *cli, args []string) error {
+			if len(args) > 0 {
+				return errUsage
+			}
+
+			// Invoke service
+			keys, err := c.apiKeyService().ListAPIKeys(ctx, name)
+			if err != nil {
+				return err
+			}
+			t := apiKeyTable(keys)
+			t.footer = plural(len(keys), "key")
+			return c.render(keys, t)
+		},
+	}
+}
+
+func seedCommand() *command {
+	var (
+		opts     seed.Options
+		from, to string
+		days     int
+	)
+	return &command{
+		name:    "seed",
+		summary: "Load a synthetic catalog with checkout/checkin history (the same seed always generates the same data)",
+		flags: func(fs *flag.FlagSet) {
+			fs.IntVar(&opts.Books, "books", 1000, fmt.Sprintf("Number of books (up to %d)", seed.MaxBooks))
+			fs.Int64Var(&opts.Seed, "seed", 1, "Random seed")
+			fs.StringVar(&from, "from", "", "History window start (YYYY-MM-DD, default: --days before its end)")
+			fs.StringVar(&to, "to", "", "History window end (YYYY-MM-DD, default: today)")
+			fs.IntVar(&days, "days", 365, "History window length in days (when --from is not given)")
+		},
+		run: func(ctx context.Context, c *cli, args []string) error {
+			if len(args) > 0 {
+				return errUsage
+			}
+
+			// History window (whole UTC days, so the dataset does not depend on the time of day)
+			opts.To = time.Now().UTC().Truncate(24 * time.Hour)
+			if to != "" {
+				var err error
+				if opts.To, err = time.Parse(time.DateOnly, to); err != nil {
+					return fmt.Errorf("invalid --to date: %s", to)
+				}
+			}
+			opts.From = opts.To.AddDate(0, 0, -days)
+			if from != "" {
+				var err error
+				if opts.From, err = time.Parse(time.DateOnly, from); err != nil {
+					return fmt.Errorf("invalid --from date: %s", from)
+				}
+			}
+
+			// Invoke service
+			res, err := c.seedService().Seed(ctx, opts)
+			if errors.Is(err, utils.ErrConflict) {
+				return fmt.Errorf("dataset of seed %d already loaded, use another seed", opts.Seed)
+			}
+			if err != nil {
+				return err
+			}
+
+			t := table{
+				header: []string{"SEED", "FROM", "TO", "BOOKS", "AUTHORS", "TAGS", "STATUS CHANGES"},
+				rows: [][]string{{
+					strconv.FormatInt(res.Seed, 10), res.From.Format(time.DateOnly), res.To.Format(time.DateOnly),
+					strconv.Itoa(res.Books), strconv.Itoa(res.Authors), strconv.Itoa(res.Tags), strconv.Itoa(res.StatusChanges),
+				}},
+				footer: plural(res.Books, "book") + " loaded",
+			}
+			return c.render(res, t)
+		},
+	}
+}
+
+/* Helper functions */
+
+func bookTable(books []models.BookResponse, footer string) table {
+	t := table{header: []string{"ID", "ISBN", "TITLE", "AUTHOR", "STATUS"}, footer: footer}
+	for _, b := range books {
+		t.rows = append(t.rows, []string{b.ID, b.ISBN, b.Title, b.Author, string(b.Status)})
+	}
+	return t
+}
+
+func apiKeyTable(keys []models.APIKeyResponse) table {
+	t := table{header: []string{"ID", "NAME", "PREFIX", "CREATED", "EXPIRES"}}
+	for _, k := range keys {
+		t.rows = append(t.rows, []string{k.ID, k.Name, k.Prefix, formatTime(&k.CreatedAt), formatTime(k.ExpiresAt)})
+	}
+	return t
+}
+
+// readJSON decodes a JSON file ('-' for stdin), rejecting unknown fields
+func readJSON(path string, v interface{}) error {
+	f := os.Stdin
+	if path != "-" {
+		var err error
+		if f, err = os.Open(path); err != nil {
+			return err
+		}
+		defer f.Close()
+	}
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid JSON in %s: %w", path, err)
+	}
+	return nil
+}
+
+// stringList is a repeatable string flag
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
@@ -0,0 +1,166 @@
+// Administrative CLI (libctl) for operational tasks against the library database: schema migrations,
+// book creation and repairs, and API key rotation. It calls the service layer directly (no API round trip);
+// book events are recorded in the outbox and delivered by the API dispatchers.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// errUsage marks command line errors (exit code 2, usage printed)
+var errUsage = errors.New("usage")
+
+// command is a libctl subcommand (e.g. "book restore")
+type command struct {
+	name    string
+	args    string // Positional arguments (usage line)
+	summary string
+	dryRun  bool // Supports --dry-run (destructive operations)
+	flags   func(fs *flag.FlagSet)
+	run     func(ctx context.Context, c *cli, args []string) error
+}
+
+// cli holds the common flags and the state of a command run
+type cli struct {
+	dsn    string
+	output string // table | json
+	dryRun bool
+	out    io.Writer
+	errOut io.Writer
+	logger *zap.Logger
+	db     *sqlx.DB
+}
+
+func main() {
+
+	// Stop on SIGINT / SIGTERM (the running transaction is rolled back)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	c := &cli{out: os.Stdout, errOut: os.Stderr, logger: zap.NewNop()}
+	err := c.execute(ctx, os.Args[1:])
+	switch {
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "libctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// execute resolves the subcommand, parses its flags and runs it
+func (c *cli) execute(ctx context.Context, args []string) error {
+
+	// Resolve command (longest name match, e.g. "book restore" before "book")
+	cmd, rest := findCommand(args)
+	if cmd == nil {
+		c.usage()
+		return errUsage
+	}
+
+	// Parse flags (common and command flags, anywhere among the positional arguments)
+	fs := flag.NewFlagSet("libctl "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(c.errOut)
+	fs.StringVar(&c.dsn, "dsn", os.Getenv("LIBCTL_DSN"), "PostgreSQL connection string (default $LIBCTL_DSN)")
+	fs.StringVar(&c.output, "output", "table", "Output format: table or json")
+	if cmd.dryRun {
+		fs.BoolVar(&c.dryRun, "dry-run", false, "Report the changes without applying them")
+	}
+	if cmd.flags != nil {
+		cmd.flags(fs)
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(c.errOut, "Usage: libctl %s [flags] %s\n\n%s\n\nFlags:\n", cmd.name, cmd.args, cmd.summary)
+		fs.PrintDefaults()
+	}
+	positional, err := parseInterspersed(fs, rest)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return errUsage // Already reported by the flag set
+	}
+
+	// Validate common flags
+	if c.output != "table" && c.output != "json" {
+		return fmt.Errorf("invalid --output %q (table or json)", c.output)
+	}
+	if c.dsn == "" {
+		return errors.New("missing database connection: set --dsn or LIBCTL_DSN")
+	}
+
+	// Connect and run
+	if c.db, err = sqlx.ConnectContext(ctx, "postgres", c.dsn); err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	defer c.db.Close()
+
+	if err := cmd.run(ctx, c, positional); err != nil {
+		if errors.Is(err, errUsage) {
+			fs.Usage()
+		}
+		return err
+	}
+	if c.dryRun {
+		fmt.Fprintln(c.errOut, "Dry run: no changes were applied")
+	}
+	return nil
+}
+
+// findCommand returns the command named by the leading arguments and the remaining arguments
+func findCommand(args []string) (*command, []string) {
+	for n := min(2, len(args)); n > 0; n-- {
+		name := strings.Join(args[:n], " ")
+		for _, cmd := range commands {
+			if cmd.name == name {
+				return cmd, args[n:]
+			}
+		}
+	}
+	return nil, nil
+}
+
+// parseInterspersed parses flags placed before, between or after positional arguments
+// ("--" ends flag parsing)
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+func (c *cli) usage() {
+	fmt.Fprintln(c.errOut, "Usage: libctl <command> [flags] [arguments]")
+	fmt.Fprintln(c.errOut, "\nCommands:")
+	sorted := append([]*command(nil), commands...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].name < sorted[j].name })
+	for _, cmd := range sorted {
+		fmt.Fprintf(c.errOut, "  %-40s %s\n", strings.TrimSpace(cmd.name+" "+cmd.args), cmd.summary)
+	}
+	fmt.Fprintln(c.errOut, "\nCommon flags: --dsn, --output table|json, --dry-run (destructive commands).")
+	fmt.Fprintln(c.errOut, "Run 'libctl <command> -h' for the flags of a command.")
+}
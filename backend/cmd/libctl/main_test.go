@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/santiago-buildit/code-challenge/backend/internal/config"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// --- Helpers ---
+
+// newTestCLI returns a cli on a mocked database, writing to buffers
+func newTestCLI(t *testing.T, output string, dryRun bool) (*cli, sqlmock.Sqlmock, *bytes.Buffer) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	out := &bytes.Buffer{}
+	c := &cli{output: output, dryRun: dryRun, out: out, errOut: io.Discard, logger: zap.NewNop(), db: sqlx.NewDb(db, "postgres")}
+	return c, mock, out
+}
+
+// countingExecer counts the statements of a migration
+type countingExecer struct {
+	count int
+}
+
+func (e *countingExecer) Exec(query string, args ...interface{}) (sql.Result, error) {
+	e.count++
+	return sqlmock.NewResult(0, 0), nil
+}
+
+// --- Tests ---
+
+func TestFindCommand(t *testing.T) {
+	cases := []struct {
+		args     []string
+		expected string // Command name ("" when not found)
+		rest     []string
+	}{
+		{[]string{"migrate"}, "migrate", []string{}},
+		{[]string{"migrate", "--dry-run"}, "migrate", []string{"--dry-run"}},
+		{[]string{"book", "restore", "id-1"}, "book restore", []string{"id-1"}},
+		{[]string{"book", "recompute-status", "a", "b"}, "book recompute-status", []string{"a", "b"}},
+		{[]string{"apikey", "rotate", "--grace", "1h", "reports"}, "apikey rotate", []string{"--grace", "1h", "reports"}},
+		{[]string{"book"}, "", nil},
+		{[]string{"book", "delete", "id-1"}, "", nil},
+		{[]string{"--dsn", "x", "migrate"}, "", nil}, // Command first
+		{nil, "", nil},
+	}
+	for _, c := range cases {
+		cmd, rest := findCommand(c.args)
+		if c.expected == "" {
+			assert.Nil(t, cmd, "%v", c.args)
+			continue
+		}
+		if assert.NotNil(t, cmd, "%v", c.args) {
+			assert.Equal(t, c.expected, cmd.name, "%v", c.args)
+			assert.Equal(t, c.rest, rest, "%v", c.args)
+		}
+	}
+}
+
+func TestParseInterspersed(t *testing.T) {
+	cases := []struct {
+		name       string
+		args       []string
+		positional []string
+		grace      time.Duration
+		dryRun     bool
+		err        bool
+	}{
+		{"flags first", []string{"--dry-run", "--grace", "1h", "reports"}, []string{"reports"}, time.Hour, true, false},
+		{"flags last", []string{"reports", "--grace=2h", "--dry-run"}, []string{"reports"}, 2 * time.Hour, true, false},
+		{"flags between", []string{"a", "--dry-run", "b"}, []string{"a", "b"}, 0, true, false},
+		{"no flags", []string{"a", "b"}, []string{"a", "b"}, 0, false, false},
+		{"double dash", []string{"a", "--", "--dry-run"}, []string{"a", "--dry-run"}, 0, false, false},
+		{"empty", nil, nil, 0, false, false},
+		{"unknown flag", []string{"a", "--force"}, nil, 0, false, true},
+		{"invalid value", []string{"--grace", "soon"}, nil, 0, false, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			grace := fs.Duration("grace", 0, "")
+			dryRun := fs.Bool("dry-run", false, "")
+
+			positional, err := parseInterspersed(fs, c.args)
+
+			if c.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.positional, positional)
+			assert.Equal(t, c.grace, *grace)
+			assert.Equal(t, c.dryRun, *dryRun)
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	value := []map[string]string{{"id": "1", "title": "Dune"}}
+	result := table{header: []string{"ID", "TITLE"}, rows: [][]string{{"1", "Dune"}}, footer: "1 book"}
+
+	cases := []struct {
+		output   string
+		t        table
+		expected string
+	}{
+		{"table", result, "ID  TITLE\n1   Dune\n1 book\n"},
+		{"table", table{header: []string{"ID"}, footer: "0 books"}, "0 books\n"}, // No header without rows
+		{"json", result, "[\n  {\n    \"id\": \"1\",\n    \"title\": \"Dune\"\n  }\n]\n"},
+	}
+	for _, c := range cases {
+		out := &bytes.Buffer{}
+		cl := &cli{output: c.output, out: out}
+
+		assert.NoError(t, cl.render(value, c.t))
+		assert.Equal(t, c.expected, out.String(), c.output)
+	}
+}
+
+func TestExecute_UsageErrors(t *testing.T) {
+	t.Setenv("LIBCTL_DSN", "")
+	cases := []struct {
+		args []string
+		err  string // Empty for errUsage
+	}{
+		{[]string{"unknown"}, ""},
+		{[]string{"migrate", "--force"}, ""},
+		{[]string{"book", "create", "--dry-run"}, ""}, // Not a destructive command
+		{[]string{"migrate", "--output", "yaml", "--dsn", "postgres://x"}, `invalid --output "yaml" (table or json)`},
+		{[]string{"migrate"}, "missing database connection: set --dsn or LIBCTL_DSN"},
+	}
+	for _, c := range cases {
+		cl := &cli{out: io.Discard, errOut: io.Discard, logger: zap.NewNop()}
+
+		err := cl.execute(context.Background(), c.args)
+
+		if c.err == "" {
+			assert.ErrorIs(t, err, errUsage, "%v", c.args)
+		} else {
+			assert.EqualError(t, err, c.err, "%v", c.args)
+		}
+	}
+}
+
+func TestDryRun_RollsBack(t *testing.T) {
+	bookID := "fac2b19c-e857-4d40-8233-8132b9759b55"
+
+	// Statements of the schema migration
+	migration := &countingExecer{}
+	assert.NoError(t, config.Migrate(migration))
+
+	cases := []struct {
+		name   string
+		args   []string
+		expect func(mock sqlmock.Sqlmock) // Only reads, or a rolled back transaction
+		footer string
+	}{
+		{
+			name: "migrate",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				for i := 0; i < migration.count; i++ {
+					mock.ExpectExec(".+").WillReturnResult(sqlmock.NewResult(0, 0))
+				}
+				mock.ExpectRollback()
+			},
+			footer: "Migrations rolled back\n",
+		},
+		{
+			name: "book restore",
+			args: []string{bookID},
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)FROM books WHERE id = \$1 AND deleted = true`).
+					WithArgs(bookID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "author", "status", "deleted"}).
+						AddRow(bookID, "Dune", "Frank Herbert", models.BookStatusAvailable, true))
+			},
+			footer: "Book would be restored\n",
+		},
+		{
+			name: "book recompute-status",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)AS expected`).
+					WillReturnRows(sqlmock.NewRows([]string{"book_id", "title", "status", "expected"}).
+						AddRow(bookID, "Dune", models.BookStatusAvailable, models.BookStatusCheckedOut))
+			},
+			footer: "1 book to fix\n",
+		},
+		{
+			name: "apikey rotate",
+			args: []string{"reports"},
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)FROM api_keys`).
+					WithArgs("reports").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "prefix", "created_at", "expires_at"}).
+						AddRow("key-1", "reports", "lk_abc", time.Now(), nil))
+			},
+			footer: "1 previous key expiring\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cl, mock, out := newTestCLI(t, "table", true)
+			c.expect(mock)
+			cmd, _ := findCommand(strings.Fields(c.name))
+
+			err := cmd.run(context.Background(), cl, c.args)
+
+			assert.NoError(t, err)
+			assert.Contains(t, out.String(), c.footer)
+			assert.NoError(t, mock.ExpectationsWereMet()) // No writes, nothing committed
+		})
+	}
+}
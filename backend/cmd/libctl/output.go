@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// table is the tabular form of a command result
+type table struct {
+	header []string
+	rows   [][]string
+	footer string // Summary line (table output only)
+}
+
+// render writes the result as indented JSON (value) or as an aligned table
+func (c *cli) render(value interface{}, t table) error {
+	if c.output == "json" {
+		enc := json.NewEncoder(c.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(value)
+	}
+
+	if len(t.rows) > 0 {
+		w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(t.header, "\t"))
+		for _, row := range t.rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	if t.footer != "" {
+		fmt.Fprintln(c.out, t.footer)
+	}
+	return nil
+}
+
+// formatTime formats an optional timestamp for tables ("-" when nil)
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Local().Format(time.RFC3339)
+}
+
+// plural returns "n noun" or "n nouns"
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,32 @@
+// Scheduled worker of the outbox and webhook dispatchers (EventBridge rule, see infra/lambda.tf).
+// Lambda freezes the API function between invocations, so background goroutines can't deliver there.
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/santiago-buildit/code-challenge/backend/internal/config"
+)
+
+var deps *config.Dependencies
+
+// Cold start code here
+func init() {
+	log.Println("Initializing worker...")
+	deps = config.InitDependencies(config.Options{}) // No live event stream (only dispatches)
+}
+
+func handler(ctx context.Context, _ events.CloudWatchEvent) error {
+
+	// Deliver the pending outbox events and the due webhook deliveries
+	return deps.RunDispatchers(ctx)
+}
+
+func main() {
+
+	// Start handler
+	lambda.Start(handler)
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"go.uber.org/zap"
+)
+
+// awsRequestTimeout bounds each AWS call (S3 covers, SQS events)
+const awsRequestTimeout = 10 * time.Second
+
+// NewAWSConfig loads the AWS SDK config for a region (AWS_REGION, set by AWS Lambda, when empty).
+// Credentials come from the default chain: AWS_ACCESS_KEY_ID / AWS_SECRET_ACCESS_KEY (also for local
+// stand-ins such as MinIO or ElasticMQ), shared profiles, or the AWS Lambda execution role.
+func NewAWSConfig(logger *zap.Logger, region string) aws.Config {
+
+	opts := []func(*awsconfig.LoadOptions) error{
+		awsconfig.WithHTTPClient(awshttp.NewBuildableClient().WithTimeout(awsRequestTimeout)),
+	}
+	if region != "" {
+		opts = append(opts, awsconfig.WithRegion(region))
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		logger.Fatal("Failed to load AWS config", zap.Error(err))
+	}
+	return cfg
+}
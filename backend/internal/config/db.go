@@ -8,6 +8,7 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	"github.com/santiago-buildit/code-challenge/backend/internal/repositories"
 )
 
 func NewDatabase(logger *zap.Logger) *sqlx.DB {
@@ -24,13 +25,26 @@ func NewDatabase(logger *zap.Logger) *sqlx.DB {
 		logger.Fatal("Failed to connect to database", zap.Error(err))
 	}
 
-	// Create tables and indexes (if not exists)
-	createTables(db, logger)
-	createIndexes(db, logger)
+	// Create extensions, tables and indexes (if not exists)
+	if err := Migrate(db); err != nil {
+		logger.Fatal("Failed to migrate database", zap.Error(err))
+	}
 
 	return db
 }
 
+// Migrate applies the schema (extensions, tables, indexes and data migrations). Every statement is idempotent,
+// so it runs on each start; it can also run inside a transaction (e.g. rolled back to check pending changes).
+func Migrate(db sqlx.Execer) error {
+	steps := []func(sqlx.Execer) error{createExtensions, createTables, createIndexes, migrateData}
+	for _, step := range steps {
+		if err := step(db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func getConnectionString(logger *zap.Logger) (full string, safe string) {
 
 	// Get connection details from environment variables
@@ -56,7 +70,38 @@ func getConnectionString(logger *zap.Logger) (full string, safe string) {
 	return full, safe
 }
 
-func createTables(db *sqlx.DB, logger *zap.Logger) {
+func createExtensions(db sqlx.Execer) error {
+
+	// Define extension creation queries
+	queries := []string{
+		`CREATE EXTENSION IF NOT EXISTS pg_trgm;`,  // Trigram similarity (typo-tolerant search)
+		`CREATE EXTENSION IF NOT EXISTS unaccent;`, // Accent removal (accent-insensitive search)
+
+		// Immutable unaccent wrapper (unaccent() itself is only STABLE, so it cannot be used in generated columns or indexes)
+		`CREATE OR REPLACE FUNCTION f_unaccent(TEXT) RETURNS TEXT
+			LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+			AS $$ SELECT public.unaccent('public.unaccent'::regdictionary, $1) $$;`,
+
+		// English text search configuration that ignores accents (for search_vector and queries)
+		`DO $$ BEGIN
+			IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'english_unaccent') THEN
+				CREATE TEXT SEARCH CONFIGURATION english_unaccent (COPY = english);
+				ALTER TEXT SEARCH CONFIGURATION english_unaccent
+					ALTER MAPPING FOR hword, hword_part, word WITH unaccent, english_stem;
+			END IF;
+		END $$;`,
+	}
+
+	// Execute each query
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("failed to create extension: %w", err)
+		}
+	}
+	return nil
+}
+
+func createTables(db sqlx.Execer) error {
 
 	// Define table creation queries
 	queries := []string{
@@ -77,17 +122,143 @@ func createTables(db *sqlx.DB, logger *zap.Logger) {
 			status TEXT NOT NULL,
 			timestamp TIMESTAMPTZ NOT NULL
 		);`,
+		`CREATE TABLE IF NOT EXISTS tags (
+			id UUID PRIMARY KEY,
+			name TEXT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS book_tags (
+			book_id UUID NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+			tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+			PRIMARY KEY (book_id, tag_id)
+		);`,
+		`CREATE TABLE IF NOT EXISTS authors (
+			id UUID PRIMARY KEY,
+			name TEXT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS book_contributors (
+			book_id UUID NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+			author_id UUID NOT NULL REFERENCES authors(id) ON DELETE CASCADE,
+			role TEXT NOT NULL,
+			position INT NOT NULL,
+			PRIMARY KEY (book_id, author_id, role)
+		);`,
+
+		// Full-text search document (weighted: title > author > description, accent-insensitive).
+		// Recreated when it was generated with a previous configuration.
+		`DO $$ BEGIN
+			IF NOT EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_name = 'books' AND column_name = 'search_vector'
+					AND generation_expression LIKE '%english_unaccent%'
+			) THEN
+				ALTER TABLE books DROP COLUMN IF EXISTS search_vector;
+				ALTER TABLE books ADD COLUMN search_vector TSVECTOR
+					GENERATED ALWAYS AS (
+						setweight(to_tsvector('english_unaccent', coalesce(title, '')), 'A') ||
+						setweight(to_tsvector('english_unaccent', coalesce(author, '')), 'B') ||
+						setweight(to_tsvector('english_unaccent', coalesce(description, '')), 'C')
+					) STORED;
+			END IF;
+		END $$;`,
+
+		// Normalized (lowercase, unaccented) copies of the searchable text columns
+		`ALTER TABLE books ADD COLUMN IF NOT EXISTS title_search TEXT
+			GENERATED ALWAYS AS (lower(f_unaccent(title))) STORED;`,
+		`ALTER TABLE books ADD COLUMN IF NOT EXISTS author_search TEXT
+			GENERATED ALWAYS AS (lower(f_unaccent(author))) STORED;`,
+		`ALTER TABLE books ADD COLUMN IF NOT EXISTS description_search TEXT
+			GENERATED ALWAYS AS (lower(f_unaccent(coalesce(description, '')))) STORED;`,
+
+		// Bibliographic details (empty / 0 when unknown)
+		`ALTER TABLE books ADD COLUMN IF NOT EXISTS publisher TEXT NOT NULL DEFAULT '';`,
+		`ALTER TABLE books ADD COLUMN IF NOT EXISTS publication_year INT NOT NULL DEFAULT 0;`,
+		`ALTER TABLE books ADD COLUMN IF NOT EXISTS edition TEXT NOT NULL DEFAULT '';`,
+		`ALTER TABLE books ADD COLUMN IF NOT EXISTS language TEXT NOT NULL DEFAULT '';`,
+		`ALTER TABLE books ADD COLUMN IF NOT EXISTS page_count INT NOT NULL DEFAULT 0;`,
+		`ALTER TABLE books ADD COLUMN IF NOT EXISTS format TEXT NOT NULL DEFAULT '';`,
+
+		// Cover image upload time (NULL without cover, images are kept in the blob store)
+		`ALTER TABLE books ADD COLUMN IF NOT EXISTS cover_updated_at TIMESTAMPTZ;`,
+
+		// Status change details: actor, source (desk, kiosk, api) and note (empty when unknown)
+		`ALTER TABLE book_status_changes ADD COLUMN IF NOT EXISTS actor TEXT NOT NULL DEFAULT '';`,
+		`ALTER TABLE book_status_changes ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT '';`,
+		`ALTER TABLE book_status_changes ADD COLUMN IF NOT EXISTS note TEXT NOT NULL DEFAULT '';`,
+
+		// Outgoing webhooks (subscriptions and delivery log)
+		`CREATE TABLE IF NOT EXISTS webhooks (
+			id UUID PRIMARY KEY,
+			url TEXT NOT NULL,
+			secret TEXT NOT NULL,
+			events TEXT[] NOT NULL,
+			active BOOLEAN NOT NULL DEFAULT TRUE,
+			created_at TIMESTAMPTZ NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+			id UUID PRIMARY KEY,
+			webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+			event_id UUID NOT NULL,
+			event_type TEXT NOT NULL,
+			payload JSONB NOT NULL,
+			status TEXT NOT NULL,
+			attempts INT NOT NULL DEFAULT 0,
+			next_attempt_at TIMESTAMPTZ,
+			last_attempt_at TIMESTAMPTZ,
+			response_status INT NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMPTZ NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL
+		);`,
+
+		// Transactional outbox (domain events written with the state change, dispatched after commit)
+		`CREATE TABLE IF NOT EXISTS outbox (
+			id UUID PRIMARY KEY,
+			event_type TEXT NOT NULL,
+			aggregate_type TEXT NOT NULL,
+			aggregate_id TEXT NOT NULL,
+			payload JSONB NOT NULL,
+			attempts INT NOT NULL DEFAULT 0,
+			next_attempt_at TIMESTAMPTZ NOT NULL,
+			processed_at TIMESTAMPTZ,
+			last_error TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMPTZ NOT NULL
+		);`,
+
+		// Outbox stream position (SSE event ID, in insert order; resuming re-reads a commit grace window) and commit-time notification to every listening server instance
+		`ALTER TABLE outbox ADD COLUMN IF NOT EXISTS seq BIGSERIAL;`,
+		`CREATE OR REPLACE FUNCTION notify_outbox_event() RETURNS TRIGGER
+			LANGUAGE plpgsql
+			AS $$ BEGIN PERFORM pg_notify('` + repositories.OutboxNotifyChannel + `', NEW.seq::text); RETURN NEW; END $$;`,
+		`DROP TRIGGER IF EXISTS outbox_notify ON outbox;`,
+		`CREATE TRIGGER outbox_notify AFTER INSERT ON outbox
+			FOR EACH ROW EXECUTE FUNCTION notify_outbox_event();`,
+
+		// API keys (only the SHA-256 hash is stored, rotated keys stay valid until they expire)
+		`CREATE TABLE IF NOT EXISTS api_keys (
+			id UUID PRIMARY KEY,
+			name TEXT NOT NULL,
+			prefix TEXT NOT NULL,
+			key_hash TEXT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL,
+			expires_at TIMESTAMPTZ
+		);`,
 	}
 
 	// Execute each query
 	for _, query := range queries {
 		if _, err := db.Exec(query); err != nil {
-			logger.Fatal("failed to create table", zap.Error(err))
+			return fmt.Errorf("failed to create table: %w", err)
 		}
 	}
+	return nil
 }
 
-func createIndexes(db *sqlx.DB, logger *zap.Logger) {
+func createIndexes(db sqlx.Execer) error {
 
 	// Define index creation queries
 	queries := []string{
@@ -97,16 +268,81 @@ func createIndexes(db *sqlx.DB, logger *zap.Logger) {
 		`CREATE INDEX IF NOT EXISTS idx_books_isbn ON books(isbn);`,
 		`CREATE INDEX IF NOT EXISTS idx_books_title ON books(title);`,
 		`CREATE INDEX IF NOT EXISTS idx_books_author ON books(author);`,
+		`CREATE INDEX IF NOT EXISTS idx_books_created_at ON books(created_at);`,
+		`CREATE INDEX IF NOT EXISTS idx_books_updated_at ON books(updated_at);`,
+		`CREATE INDEX IF NOT EXISTS idx_books_search_vector ON books USING GIN(search_vector);`,
+		`DROP INDEX IF EXISTS idx_books_title_trgm;`,  // Replaced by normalized column index
+		`DROP INDEX IF EXISTS idx_books_author_trgm;`, // Replaced by normalized column index
+		`CREATE INDEX IF NOT EXISTS idx_books_title_search_trgm ON books USING GIN(title_search gin_trgm_ops);`,
+		`CREATE INDEX IF NOT EXISTS idx_books_author_search_trgm ON books USING GIN(author_search gin_trgm_ops);`,
+		`CREATE INDEX IF NOT EXISTS idx_books_description_search_trgm ON books USING GIN(description_search gin_trgm_ops);`,
+		`CREATE INDEX IF NOT EXISTS idx_books_publication_year ON books(publication_year);`,
+		`CREATE INDEX IF NOT EXISTS idx_books_language ON books(language);`,
+
+		// Indexes for tags (names are unique case-insensitively) and reverse lookup of book_tags
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name ON tags(lower(name));`,
+		`CREATE INDEX IF NOT EXISTS idx_book_tags_tag_id ON book_tags(tag_id);`,
+
+		// Indexes for authors (names are unique case-insensitively) and reverse lookup of book_contributors
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_authors_name ON authors(lower(name));`,
+		`CREATE INDEX IF NOT EXISTS idx_book_contributors_author_id ON book_contributors(author_id);`,
+
+		// Indexes for webhooks (subscriptions by event, delivery log per webhook and retry queue)
+		`CREATE INDEX IF NOT EXISTS idx_webhooks_events ON webhooks USING GIN(events);`,
+		`CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id
+			ON webhook_deliveries(webhook_id, created_at DESC);`,
+		`CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due
+			ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';`,
+
+		// Index for the outbox dispatcher (pending events by due time)
+		`CREATE INDEX IF NOT EXISTS idx_outbox_pending
+			ON outbox(next_attempt_at, created_at) WHERE processed_at IS NULL;`,
+
+		// Index for the event stream (replay after Last-Event-ID)
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_outbox_seq ON outbox(seq);`,
+
+		// Indexes for API keys (lookup by hash, keys of a client)
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_key_hash ON api_keys(key_hash);`,
+		`CREATE INDEX IF NOT EXISTS idx_api_keys_name ON api_keys(name, created_at DESC);`,
 
 		// Index for book_status_changes lookup
 		`CREATE INDEX IF NOT EXISTS idx_book_history_bookid_timestamp
 			ON book_status_changes(book_id, timestamp DESC);`,
+
+		// Index for circulation reports (changes of a status within a window)
+		`CREATE INDEX IF NOT EXISTS idx_book_history_status_timestamp
+			ON book_status_changes(status, timestamp);`,
+	}
+
+	// Execute each query
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("failed to create index: %w", err)
+		}
+	}
+	return nil
+}
+
+func migrateData(db sqlx.Execer) error {
+
+	// Define data migration queries (idempotent)
+	queries := []string{
+
+		// Books created before contributors existed: credit their author string as single author
+		`INSERT INTO authors (id, name, created_at, updated_at)
+			SELECT gen_random_uuid(), author, now(), now() FROM books b
+			WHERE NOT EXISTS (SELECT 1 FROM book_contributors bc WHERE bc.book_id = b.id)
+			ON CONFLICT ((lower(name))) DO NOTHING;`,
+		`INSERT INTO book_contributors (book_id, author_id, role, position)
+			SELECT b.id, a.id, 'author', 0 FROM books b JOIN authors a ON lower(a.name) = lower(b.author)
+			WHERE NOT EXISTS (SELECT 1 FROM book_contributors bc WHERE bc.book_id = b.id);`,
 	}
 
 	// Execute each query
 	for _, query := range queries {
 		if _, err := db.Exec(query); err != nil {
-			logger.Fatal("failed to create index", zap.Error(err))
+			return fmt.Errorf("failed to migrate data: %w", err)
 		}
 	}
+	return nil
 }
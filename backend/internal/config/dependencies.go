@@ -1,18 +1,52 @@
 package config
 
 import (
+	"context"
+
+	"github.com/gin-gonic/gin"
 	"github.com/santiago-buildit/code-challenge/backend/internal/handlers"
 	"github.com/santiago-buildit/code-challenge/backend/internal/repositories"
 	"github.com/santiago-buildit/code-challenge/backend/internal/services"
+	"google.golang.org/grpc"
 )
 
 // Dependencies holds all application dependencies
 type Dependencies struct {
-	BookHandler *handlers.BookHandler
+	BookHandler    *handlers.BookHandler
+	TagHandler     *handlers.TagHandler
+	AuthorHandler  *handlers.AuthorHandler
+	CoverHandler   *handlers.CoverHandler
+	WebhookHandler *handlers.WebhookHandler
+	EventHandler   *handlers.EventHandler
+	GraphQLHandler *handlers.GraphQLHandler
+	ReportHandler  *handlers.ReportHandler
+
+	// gRPC LibraryService (served by cmd/grpc)
+	LibraryGRPCHandler *handlers.LibraryGRPCHandler
+
+	// API key check (only applied when API_KEY_AUTH=required)
+	APIKeyMiddleware gin.HandlerFunc
+
+	// API key check of the gRPC server (always applied)
+	APIKeyUnaryInterceptor  grpc.UnaryServerInterceptor
+	APIKeyStreamInterceptor grpc.StreamServerInterceptor
+
+	// Background work (see StartBackground and RunDispatchers)
+	OutboxDispatcher  *services.OutboxDispatcher
+	WebhookDispatcher *services.WebhookDispatcher
+	EventStream       *services.BookEventStream
+}
+
+// Options select the optional dependencies of a binary
+type Options struct {
+
+	// Live event stream (dedicated Postgres LISTEN connection), only for long-running processes serving
+	// SSE or gRPC WatchBooks: behind the API Lambda the process is frozen between invocations
+	EventStream bool
 }
 
 // InitDependencies initializes and returns all dependencies
-func InitDependencies() *Dependencies {
+func InitDependencies(opts Options) *Dependencies {
 
 	// Initialize logger (ZAP)
 	logger := NewLogger()
@@ -20,17 +54,98 @@ func InitDependencies() *Dependencies {
 	// Initialize database client
 	db := NewDatabase(logger)
 
+	// Initialize blob store (cover images)
+	blobStore := NewBlobStore(logger)
+
 	// Initialize repositories
 	bookRepo := repositories.NewBookRepository(db)
+	tagRepo := repositories.NewTagRepository(db)
+	authorRepo := repositories.NewAuthorRepository(db)
+	webhookRepo := repositories.NewWebhookRepository(db)
+	outboxRepo := repositories.NewOutboxRepository(db)
+	apiKeyRepo := repositories.NewAPIKeyRepository(db)
+	reportRepo := repositories.NewReportRepository(db)
+
+	// Initialize webhook dispatcher (delivers and retries due deliveries)
+	webhookDispatcher := services.NewWebhookDispatcher(webhookRepo, nil, logger)
 
 	// Initialize services
-	bookService := services.NewBookService(db, bookRepo)
+	webhookService := services.NewWebhookService(webhookRepo, webhookDispatcher, logger)
+
+	// Initialize outbox dispatcher (domain events to the configured sinks and webhooks)
+	outboxDispatcher := services.NewOutboxDispatcher(db, outboxRepo, append(NewEventSinks(logger), webhookService), logger)
+
+	// Initialize live event stream (committed outbox events, notified to every instance), when served
+	var eventStream *services.BookEventStream
+	var eventStreamService services.EventStreamService // nil: stream endpoints answer "not available"
+	if opts.EventStream {
+		eventStream = services.NewBookEventStream(outboxRepo, NewEventListener(logger), logger)
+		eventStreamService = eventStream
+	}
+
+	bookService := services.NewBookService(db, bookRepo, outboxDispatcher)
+	tagService := services.NewTagService(db, tagRepo, bookRepo, outboxDispatcher)
+	authorService := services.NewAuthorService(authorRepo, bookService)
+	coverService := services.NewCoverService(db, bookRepo, blobStore, outboxDispatcher)
+	apiKeyService := services.NewAPIKeyService(db, apiKeyRepo)
+	reportService := services.NewReportService(reportRepo)
 
 	// Initialize handlers
 	bookHandler := handlers.NewBookHandler(bookService, logger)
+	tagHandler := handlers.NewTagHandler(tagService, logger)
+	authorHandler := handlers.NewAuthorHandler(authorService, logger)
+	coverHandler := handlers.NewCoverHandler(coverService, logger)
+	webhookHandler := handlers.NewWebhookHandler(webhookService, logger)
+	eventHandler := handlers.NewEventHandler(eventStreamService, EventStreamMaxDuration(logger), logger)
+	graphQLHandler := handlers.NewGraphQLHandler(bookService, logger)
+	reportHandler := handlers.NewReportHandler(reportService, logger)
+	libraryGRPCHandler := handlers.NewLibraryGRPCHandler(bookService, eventStreamService, logger)
+	apiKeyMiddleware := handlers.NewAPIKeyMiddleware(apiKeyService, logger)
+	apiKeyUnaryInterceptor := handlers.NewAPIKeyUnaryInterceptor(apiKeyService, logger)
+	apiKeyStreamInterceptor := handlers.NewAPIKeyStreamInterceptor(apiKeyService, logger)
 
 	// Build dependencies holder
 	return &Dependencies{
-		BookHandler: bookHandler,
+		BookHandler:    bookHandler,
+		TagHandler:     tagHandler,
+		AuthorHandler:  authorHandler,
+		CoverHandler:   coverHandler,
+		WebhookHandler: webhookHandler,
+		EventHandler:   eventHandler,
+		GraphQLHandler: graphQLHandler,
+		ReportHandler:  reportHandler,
+
+		LibraryGRPCHandler: libraryGRPCHandler,
+
+		APIKeyMiddleware: apiKeyMiddleware,
+
+		APIKeyUnaryInterceptor:  apiKeyUnaryInterceptor,
+		APIKeyStreamInterceptor: apiKeyStreamInterceptor,
+
+		OutboxDispatcher:  outboxDispatcher,
+		WebhookDispatcher: webhookDispatcher,
+		EventStream:       eventStream,
+	}
+}
+
+// StartBackground runs the outbox and webhook dispatchers and the live event stream (when enabled)
+// until the context is cancelled. Only for long-running processes (cmd/grpc): Lambda freezes the
+// process between invocations, so the dispatchers run from the scheduled worker function instead
+// (cmd/worker, see RunDispatchers) and the API function serves no live stream.
+func (d *Dependencies) StartBackground(ctx context.Context) {
+	go d.WebhookDispatcher.Start(ctx)
+	go d.OutboxDispatcher.Start(ctx)
+	if d.EventStream != nil {
+		go d.EventStream.Start(ctx)
+	}
+}
+
+// RunDispatchers delivers the pending outbox events, then the due webhook deliveries (including the
+// ones just created by those events), once
+func (d *Dependencies) RunDispatchers(ctx context.Context) error {
+	if _, err := d.OutboxDispatcher.RunOnce(ctx); err != nil {
+		return err
 	}
+	_, err := d.WebhookDispatcher.DeliverDue(ctx)
+	return err
 }
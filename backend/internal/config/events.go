@@ -0,0 +1,87 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/santiago-buildit/code-challenge/backend/internal/events"
+	"github.com/santiago-buildit/code-challenge/backend/internal/repositories"
+	"go.uber.org/zap"
+)
+
+// NewEventSinks creates the sinks that receive the domain events dispatched from the outbox.
+// EVENT_SINKS is a comma-separated list of "log" (default), "http" (POST to EVENT_HTTP_URL)
+// and "sqs" (SendMessage to SQS_QUEUE_URL, AWS SQS or a local stand-in such as ElasticMQ).
+// Webhooks are always added by the caller.
+func NewEventSinks(logger *zap.Logger) []events.Sink {
+
+	names := os.Getenv("EVENT_SINKS")
+	if names == "" {
+		names = "log"
+	}
+
+	sinks := []events.Sink{}
+	for _, name := range strings.Split(names, ",") {
+		switch strings.TrimSpace(name) {
+		case "log":
+			sinks = append(sinks, events.NewLogSink(logger))
+
+		case "http":
+			target := os.Getenv("EVENT_HTTP_URL")
+			if target == "" {
+				logger.Fatal("Missing EVENT_HTTP_URL for the http event sink")
+			}
+			sinks = append(sinks, events.NewHTTPSink(target, nil))
+
+		case "sqs":
+
+			// Region (AWS_REGION by default) and credentials from the default AWS chain
+			sink, err := events.NewSQSSink(NewAWSConfig(logger, os.Getenv("SQS_REGION")), events.SQSConfig{
+				QueueURL: os.Getenv("SQS_QUEUE_URL"),
+			})
+			if err != nil {
+				logger.Fatal("Invalid SQS event sink configuration", zap.Error(err))
+			}
+			sinks = append(sinks, sink)
+
+		default:
+			logger.Fatal("Unknown event sink", zap.String("sink", name))
+		}
+	}
+
+	logger.Info("Using event sinks", zap.String("sinks", names))
+	return sinks
+}
+
+// NewEventListener listens to the committed outbox events (Postgres NOTIFY) on a dedicated connection,
+// re-established automatically when lost
+func NewEventListener(logger *zap.Logger) *pq.Listener {
+
+	connStr, _ := getConnectionString(logger)
+	listener := pq.NewListener(connStr, 10*time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			logger.Warn("Event listener connection problem", zap.Int("event", int(event)), zap.Error(err))
+		}
+	})
+	if err := listener.Listen(repositories.OutboxNotifyChannel); err != nil {
+		logger.Fatal("Failed to listen to outbox events", zap.Error(err))
+	}
+	return listener
+}
+
+// EventStreamMaxDuration returns the length limit of event streams from EVENT_STREAM_MAX_DURATION
+// (e.g. "5m", unset = unlimited), for proxies that cut long responses: clients resume from the last event ID.
+func EventStreamMaxDuration(logger *zap.Logger) time.Duration {
+
+	value := os.Getenv("EVENT_STREAM_MAX_DURATION")
+	if value == "" {
+		return 0
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		logger.Fatal("Invalid EVENT_STREAM_MAX_DURATION", zap.String("value", value))
+	}
+	return duration
+}
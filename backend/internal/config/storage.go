@@ -0,0 +1,41 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/santiago-buildit/code-challenge/backend/internal/storage"
+	"go.uber.org/zap"
+)
+
+// NewBlobStore creates the object store for binary content (cover images).
+// BLOB_STORE selects the implementation: "local" (default, files under BLOB_DIR) or "s3" (any S3-compatible service).
+func NewBlobStore(logger *zap.Logger) storage.BlobStore {
+
+	switch os.Getenv("BLOB_STORE") {
+	case "s3":
+
+		// Region (AWS_REGION by default) and endpoint (AWS by default, e.g. http://localhost:9000 for MinIO)
+		endpoint := os.Getenv("S3_ENDPOINT")
+		store, err := storage.NewS3BlobStore(NewAWSConfig(logger, os.Getenv("S3_REGION")), storage.S3Config{
+			Endpoint:  endpoint,
+			Bucket:    os.Getenv("S3_BUCKET"),
+			PathStyle: os.Getenv("S3_PATH_STYLE") == "true",
+		})
+		if err != nil {
+			logger.Fatal("Invalid S3 blob store configuration", zap.Error(err))
+		}
+		logger.Info("Using S3 blob store", zap.String("endpoint", endpoint), zap.String("bucket", os.Getenv("S3_BUCKET")))
+		return store
+
+	default:
+
+		// Local directory (temporary directory by default)
+		dir := os.Getenv("BLOB_DIR")
+		if dir == "" {
+			dir = filepath.Join(os.TempDir(), "blobs")
+		}
+		logger.Info("Using local blob store", zap.String("dir", dir))
+		return storage.NewLocalBlobStore(dir)
+	}
+}
@@ -0,0 +1,60 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+)
+
+// HTTPSink posts each event payload as JSON to a fixed URL (e.g. an event collector).
+// The event ID is sent as Idempotency-Key so the receiver can drop duplicates.
+type HTTPSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTPSink(url string, client *http.Client) *HTTPSink {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &HTTPSink{
+		url:    url,
+		client: client,
+	}
+}
+
+func (s *HTTPSink) Name() string {
+	return "http"
+}
+
+func (s *HTTPSink) Send(ctx context.Context, event *models.OutboxEvent) error {
+
+	// Build request
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(event.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", event.ID)
+	req.Header.Set("X-Event-Id", event.ID)
+	req.Header.Set("X-Event-Type", event.EventType)
+
+	// Execute request (the response body is discarded)
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	// Check status
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("http sink: unexpected status %d", res.StatusCode)
+	}
+	return nil
+}
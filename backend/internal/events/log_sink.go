@@ -0,0 +1,33 @@
+package events
+
+import (
+	"context"
+
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"go.uber.org/zap"
+)
+
+// LogSink writes events to the application log
+type LogSink struct {
+	logger *zap.Logger
+}
+
+func NewLogSink(logger *zap.Logger) *LogSink {
+	return &LogSink{
+		logger: logger,
+	}
+}
+
+func (s *LogSink) Name() string {
+	return "log"
+}
+
+func (s *LogSink) Send(ctx context.Context, event *models.OutboxEvent) error {
+	s.logger.Info("Domain event",
+		zap.String("id", event.ID),
+		zap.String("type", event.EventType),
+		zap.String("aggregate_type", event.AggregateType),
+		zap.String("aggregate_id", event.AggregateID),
+		zap.ByteString("payload", event.Payload))
+	return nil
+}
@@ -0,0 +1,14 @@
+package events
+
+import (
+	"context"
+
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+)
+
+// Sink receives the domain events dispatched from the outbox. Delivery is at least once: a sink may get
+// the same event again (after a failure of any sink or a crash), so consumers deduplicate by event ID.
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, event *models.OutboxEvent) error
+}
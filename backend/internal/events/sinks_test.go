@@ -0,0 +1,137 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var testEvent = &models.OutboxEvent{
+	ID:            "3f1e2d4c-5b6a-4789-8c0d-1e2f3a4b5c6d",
+	EventType:     "book.checked_out",
+	AggregateType: models.AggregateBook,
+	AggregateID:   "book-1",
+	Payload:       []byte(`{"id":"3f1e2d4c-5b6a-4789-8c0d-1e2f3a4b5c6d","type":"book.checked_out"}`),
+}
+
+func TestHTTPSink_Send(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	err := NewHTTPSink(server.URL, server.Client()).Send(context.Background(), testEvent)
+
+	assert.NoError(t, err)
+	assert.Equal(t, testEvent.ID, received.Header.Get("Idempotency-Key"))
+	assert.Equal(t, "book.checked_out", received.Header.Get("X-Event-Type"))
+	assert.Equal(t, testEvent.Payload, body)
+}
+
+func TestHTTPSink_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	err := NewHTTPSink(server.URL, server.Client()).Send(context.Background(), testEvent)
+
+	assert.EqualError(t, err, "http sink: unexpected status 502")
+}
+
+// testAWSConfig has static credentials for the local SQS stand-ins
+var testAWSConfig = aws.Config{
+	Region:      "us-east-1",
+	Credentials: credentials.NewStaticCredentialsProvider("local", "local", ""),
+}
+
+// sqsRequest is the part of a SendMessage request (JSON protocol) checked by the tests
+type sqsRequest struct {
+	QueueURL               string `json:"QueueUrl"`
+	MessageBody            string
+	MessageDeduplicationID string `json:"MessageDeduplicationId"`
+	MessageGroupID         string `json:"MessageGroupId"`
+	MessageAttributes      map[string]struct {
+		DataType    string
+		StringValue string
+	}
+}
+
+func TestSQSSink_SendFIFO(t *testing.T) {
+	var sent sqsRequest
+	var target, authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target = r.Header.Get("X-Amz-Target")
+		authorization = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&sent)
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		_, _ = w.Write([]byte(`{"MessageId":"1"}`))
+	}))
+	defer server.Close()
+
+	queueURL := server.URL + "/000000000000/library-events.fifo"
+	sink, err := NewSQSSink(testAWSConfig, SQSConfig{QueueURL: queueURL})
+	assert.NoError(t, err)
+
+	err = sink.Send(context.Background(), testEvent)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "AmazonSQS.SendMessage", target)
+	assert.True(t, strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=local/"))
+	assert.Contains(t, authorization, "/us-east-1/sqs/aws4_request")
+	assert.Equal(t, queueURL, sent.QueueURL)
+	assert.Equal(t, string(testEvent.Payload), sent.MessageBody)
+	assert.Equal(t, testEvent.ID, sent.MessageDeduplicationID)
+	assert.Equal(t, "book:book-1", sent.MessageGroupID)
+	assert.Equal(t, testEvent.ID, sent.MessageAttributes["event_id"].StringValue)
+	assert.Equal(t, "String", sent.MessageAttributes["event_type"].DataType)
+}
+
+func TestSQSSink_StandardQueue(t *testing.T) {
+	var sent sqsRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&sent)
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		_, _ = w.Write([]byte(`{"MessageId":"1"}`))
+	}))
+	defer server.Close()
+
+	sink, err := NewSQSSink(testAWSConfig, SQSConfig{QueueURL: server.URL + "/000000000000/library-events"})
+	assert.NoError(t, err)
+
+	err = sink.Send(context.Background(), testEvent)
+
+	assert.NoError(t, err)
+	assert.Equal(t, string(testEvent.Payload), sent.MessageBody)
+	assert.Empty(t, sent.MessageDeduplicationID) // Only valid for FIFO queues
+	assert.Empty(t, sent.MessageGroupID)
+}
+
+func TestSQSSink_ErrorResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"__type":"com.amazonaws.sqs#QueueDoesNotExist","message":"The specified queue does not exist."}`))
+	}))
+	defer server.Close()
+
+	sink, err := NewSQSSink(testAWSConfig, SQSConfig{QueueURL: server.URL + "/000000000000/missing"})
+	assert.NoError(t, err)
+
+	err = sink.Send(context.Background(), testEvent)
+
+	assert.ErrorContains(t, err, "QueueDoesNotExist")
+}
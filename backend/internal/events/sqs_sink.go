@@ -0,0 +1,79 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+)
+
+// SQSConfig configures an SQS-compatible queue (AWS SQS, or a local stand-in such as ElasticMQ or LocalStack)
+type SQSConfig struct {
+	QueueURL string // e.g. https://sqs.us-east-1.amazonaws.com/123456789012/library-events.fifo or http://localhost:9324/000000000000/library-events
+}
+
+// SQSSink sends each event as a message with the AWS SDK (the endpoint is the queue URL host).
+// FIFO queues (".fifo") get the event ID as MessageDeduplicationId and the aggregate as MessageGroupId,
+// so duplicates within the deduplication interval are dropped and events of a book keep their order;
+// on standard queues consumers deduplicate with the event_id message attribute.
+type SQSSink struct {
+	queueURL string
+	fifo     bool
+	client   *sqs.Client
+}
+
+// NewSQSSink creates the sink with the region, credentials and HTTP client of the AWS config
+func NewSQSSink(awsConfig aws.Config, config SQSConfig) (*SQSSink, error) {
+	queueURL, err := url.Parse(config.QueueURL)
+	if err != nil || queueURL.Host == "" {
+		return nil, fmt.Errorf("invalid SQS queue URL %q", config.QueueURL)
+	}
+	if awsConfig.Region == "" {
+		return nil, fmt.Errorf("missing SQS region")
+	}
+	endpoint := queueURL.Scheme + "://" + queueURL.Host
+	return &SQSSink{
+		queueURL: config.QueueURL,
+		fifo:     strings.HasSuffix(queueURL.Path, ".fifo"),
+		client: sqs.NewFromConfig(awsConfig, func(o *sqs.Options) {
+			o.BaseEndpoint = aws.String(endpoint)
+		}),
+	}, nil
+}
+
+func (s *SQSSink) Name() string {
+	return "sqs"
+}
+
+func (s *SQSSink) Send(ctx context.Context, event *models.OutboxEvent) error {
+
+	// Build message
+	input := &sqs.SendMessageInput{
+		QueueUrl:    aws.String(s.queueURL),
+		MessageBody: aws.String(string(event.Payload)),
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			"event_id":   stringAttribute(event.ID),
+			"event_type": stringAttribute(event.EventType),
+		},
+	}
+	if s.fifo {
+		input.MessageDeduplicationId = aws.String(event.ID)
+		input.MessageGroupId = aws.String(event.AggregateType + ":" + event.AggregateID)
+	}
+
+	// Send message
+	if _, err := s.client.SendMessage(ctx, input); err != nil {
+		return fmt.Errorf("sqs sink: %w", err)
+	}
+	return nil
+}
+
+// stringAttribute builds a string message attribute
+func stringAttribute(value string) types.MessageAttributeValue {
+	return types.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(value)}
+}
@@ -0,0 +1,80 @@
+package graphql
+
+import "sync"
+
+// Loader batches the loads of a request (dataloader pattern). Prime registers the keys that are going
+// to be loaded (e.g. the books of a list, before their fields are resolved), and the first Load fetches
+// all the keys registered so far with a single call, so the fields of every item of a list are loaded
+// together even though they are resolved concurrently. Results are cached for the request (create one
+// loader per request). Safe for concurrent use.
+type Loader[K comparable, V any] struct {
+	fetch   func(keys []K) (map[K]V, error)
+	mu      sync.Mutex
+	queue   []K
+	queued  map[K]bool
+	batches map[K]*batch[K, V]
+}
+
+// batch is a fetch of keys, shared by the loads of those keys
+type batch[K comparable, V any] struct {
+	done   chan struct{}
+	values map[K]V
+	err    error
+}
+
+// NewLoader creates a loader; fetch returns the values by key (missing keys get the zero value)
+func NewLoader[K comparable, V any](fetch func(keys []K) (map[K]V, error)) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:   fetch,
+		queued:  map[K]bool{},
+		batches: map[K]*batch[K, V]{},
+	}
+}
+
+// Prime registers keys to be fetched with the next load
+func (l *Loader[K, V]) Prime(keys ...K) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, key := range keys {
+		l.enqueue(key)
+	}
+}
+
+// Load returns the value of the key, fetching it with the registered keys unless it is already fetched
+func (l *Loader[K, V]) Load(key K) (V, error) {
+	l.mu.Lock()
+	b, ok := l.batches[key]
+	if !ok {
+
+		// New batch of the registered keys (and this one)
+		l.enqueue(key)
+		b = &batch[K, V]{done: make(chan struct{})}
+		for _, queued := range l.queue {
+			l.batches[queued] = b
+		}
+		keys := l.queue
+		l.queue = nil
+		l.mu.Unlock()
+
+		defer close(b.done)
+		b.values, b.err = l.fetch(keys)
+	} else {
+		l.mu.Unlock()
+		<-b.done
+	}
+
+	if b.err != nil {
+		var zero V
+		return zero, b.err
+	}
+	return b.values[key], nil
+}
+
+// enqueue registers a key unless it is registered or fetched (mu held)
+func (l *Loader[K, V]) enqueue(key K) {
+	if l.queued[key] {
+		return
+	}
+	l.queued[key] = true
+	l.queue = append(l.queue, key)
+}
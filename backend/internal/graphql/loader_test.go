@@ -0,0 +1,61 @@
+package graphql
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoader_BatchesPrimedKeys(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]string
+	loader := NewLoader(func(keys []string) (map[string]int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		batches = append(batches, keys)
+		return map[string]int{"a": 1, "b": 2, "c": 3}, nil
+	})
+
+	// Concurrent loads of the primed keys share a single fetch
+	loader.Prime("a", "b", "c")
+	var wg sync.WaitGroup
+	values := make([]int, 3)
+	for i, key := range []string{"c", "a", "b"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			values[i], _ = loader.Load(key)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, []int{3, 1, 2}, values)
+	assert.Equal(t, [][]string{{"a", "b", "c"}}, batches)
+}
+
+func TestLoader_CachesAndReportsErrors(t *testing.T) {
+	calls := 0
+	loader := NewLoader(func(keys []int) (map[int]string, error) {
+		calls++
+		if keys[0] == 3 {
+			return nil, errors.New("boom")
+		}
+		return map[int]string{1: "one"}, nil
+	})
+
+	loader.Prime(1, 2)
+	v1, err1 := loader.Load(1)
+	v2, err2 := loader.Load(2)
+	v1Again, _ := loader.Load(1)
+	_, err3 := loader.Load(3)
+
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.Equal(t, "one", v1)
+	assert.Equal(t, "", v2) // Missing keys get the zero value
+	assert.Equal(t, "one", v1Again)
+	assert.EqualError(t, err3, "boom")
+	assert.Equal(t, 2, calls)
+}
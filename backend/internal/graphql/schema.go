@@ -0,0 +1,40 @@
+// Package graphql holds the GraphQL schema of the book API and the helpers of its resolvers
+// (executed with github.com/graph-gophers/graphql-go by the GraphQL Handler).
+package graphql
+
+import _ "embed"
+
+// Schema is the schema of the endpoint (SDL), the source of truth of the resolvers
+//
+//go:embed schema.graphql
+var Schema string
+
+// MaxDepth is the maximum selection depth of a request
+const MaxDepth = 10
+
+// Request is a GraphQL request (GraphQL over HTTP)
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Error is a resolver error with an extensions code (e.g. NOT_FOUND)
+type Error struct {
+	Message string
+	Code    string
+}
+
+// NewError creates a resolver error with an extensions code
+func NewError(code string, message string) *Error {
+	return &Error{Message: message, Code: code}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Extensions are added to the error of the response
+func (e *Error) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.Code}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/santiago-buildit/code-challenge/backend/internal/services"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// apiKeyClientContextKey is the context key of the authenticated client name of a gRPC call
+type apiKeyClientContextKey struct{}
+
+// APIKeyClient returns the client name authenticated by the API key interceptors ("" when none)
+func APIKeyClient(ctx context.Context) string {
+	name, _ := ctx.Value(apiKeyClientContextKey{}).(string)
+	return name
+}
+
+// NewAPIKeyUnaryInterceptor rejects unary calls without a valid API key (x-api-key metadata), as
+// NewAPIKeyMiddleware does for REST requests
+func NewAPIKeyUnaryInterceptor(service services.APIKeyService, logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticateAPIKey(ctx, service, logger, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// NewAPIKeyStreamInterceptor rejects streaming calls without a valid API key (x-api-key metadata)
+func NewAPIKeyStreamInterceptor(service services.APIKeyService, logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticateAPIKey(stream.Context(), service, logger, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+/* Helper functions */
+
+// authenticateAPIKey verifies the API key of a call and returns its context with the client name
+func authenticateAPIKey(ctx context.Context, service services.APIKeyService, logger *zap.Logger, method string) (context.Context, error) {
+	var key string
+	if values := metadata.ValueFromIncomingContext(ctx, strings.ToLower(APIKeyHeader)); len(values) > 0 {
+		key = values[0]
+	}
+
+	// Verify key
+	res, err := service.VerifyAPIKey(ctx, key)
+	if errors.Is(err, utils.ErrNotFound) {
+		logger.Warn("Invalid or missing API key", zap.String("method", method))
+		return nil, status.Errorf(codes.Unauthenticated, "Invalid or missing API key")
+	}
+	if err != nil {
+		logger.Error("Failed to verify API key", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "Failed to verify API key")
+	}
+
+	return context.WithValue(ctx, apiKeyClientContextKey{}, res.Name), nil
+}
+
+// authenticatedStream is a server stream carrying the authenticated context
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
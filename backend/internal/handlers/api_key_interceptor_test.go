@@ -0,0 +1,62 @@
+package handlers_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/santiago-buildit/code-challenge/backend/internal/grpc/librarypb"
+	"github.com/santiago-buildit/code-challenge/backend/internal/handlers"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/services"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAPIKeyInterceptors(t *testing.T) {
+	keySvc := new(MockAPIKeyService)
+	bookSvc := new(MockBookService)
+	eventSvc := new(MockEventStreamService)
+	logger := zaptest.NewLogger(t)
+
+	keySvc.On("VerifyAPIKey", mock.Anything, "lib_valid").Return(&models.APIKeyResponse{Name: "kiosk"}, nil)
+	keySvc.On("VerifyAPIKey", mock.Anything, "").Return(nil, utils.ErrNotFound)
+	keySvc.On("VerifyAPIKey", mock.Anything, "lib_broken").Return(nil, assert.AnError)
+
+	// The client name is the default actor of status changes
+	bookSvc.On("CheckoutBook", mock.Anything, "b1", mock.MatchedBy(func(req models.StatusChangeRequest) bool {
+		return req.Actor == "kiosk"
+	})).Return(nil)
+	live := make(chan *models.OutboxEvent)
+	close(live)
+	eventSvc.On("Subscribe", mock.Anything, mock.Anything).Return(&services.BookEventSubscription{Events: live}, nil)
+
+	client := newLibraryClient(t, bookSvc, eventSvc,
+		grpc.UnaryInterceptor(handlers.NewAPIKeyUnaryInterceptor(keySvc, logger)),
+		grpc.StreamInterceptor(handlers.NewAPIKeyStreamInterceptor(keySvc, logger)),
+	)
+
+	for key, expected := range map[string]codes.Code{"lib_valid": codes.OK, "": codes.Unauthenticated, "lib_broken": codes.Internal} {
+		ctx := context.Background()
+		if key != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", key)
+		}
+
+		_, err := client.CheckoutBook(ctx, &librarypb.StatusChangeRequest{Id: "b1"})
+		assert.Equal(t, expected, status.Code(err), "unary %q", key)
+
+		// Authenticated streams end when the subscription is closed
+		if expected == codes.OK {
+			expected = codes.Unavailable
+		}
+		_, err = watchBooks(ctx, client, &librarypb.WatchBooksRequest{})
+		assert.Equal(t, expected, status.Code(err), "stream %q", key)
+	}
+	bookSvc.AssertNumberOfCalls(t, "CheckoutBook", 1)
+	eventSvc.AssertNumberOfCalls(t, "Subscribe", 1)
+}
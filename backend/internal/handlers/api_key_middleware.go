@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/services"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// APIKeyHeader carries the client API key (issued and rotated with cmd/libctl)
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyClientKey is the context key of the authenticated client name
+const APIKeyClientKey = "api_key_client"
+
+// NewAPIKeyMiddleware rejects requests without a valid API key (current, or rotated and within its grace period)
+func NewAPIKeyMiddleware(service services.APIKeyService, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		// Verify key
+		key, err := service.VerifyAPIKey(c.Request.Context(), c.GetHeader(APIKeyHeader))
+		if errors.Is(err, utils.ErrNotFound) {
+			logger.Warn("Invalid or missing API key", zap.String("path", c.Request.URL.Path))
+			problem(c, http.StatusUnauthorized, models.ProblemInvalidAPIKey)
+			return
+		}
+		if err != nil {
+			logger.Error("Failed to verify API key", zap.Error(err))
+			problemDetail(c, http.StatusInternalServerError, models.ProblemInternal, "Failed to verify API key")
+			return
+		}
+
+		c.Set(APIKeyClientKey, key.Name)
+		c.Next()
+	}
+}
@@ -0,0 +1,74 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/santiago-buildit/code-challenge/backend/internal/handlers"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+// MockAPIKeyService implements APIKeyService for testing
+type MockAPIKeyService struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyService) RotateAPIKey(ctx context.Context, req models.RotateAPIKeyRequest) (*models.RotateAPIKeyResponse, error) {
+	args := m.Called(ctx, req)
+	if res := args.Get(0); res != nil {
+		return res.(*models.RotateAPIKeyResponse), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAPIKeyService) ListAPIKeys(ctx context.Context, name string) ([]models.APIKeyResponse, error) {
+	args := m.Called(ctx, name)
+	return args.Get(0).([]models.APIKeyResponse), args.Error(1)
+}
+
+func (m *MockAPIKeyService) VerifyAPIKey(ctx context.Context, key string) (*models.APIKeyResponse, error) {
+	args := m.Called(ctx, key)
+	if res := args.Get(0); res != nil {
+		return res.(*models.APIKeyResponse), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockAPIKeyService)
+	logger := zaptest.NewLogger(t)
+
+	r := gin.New()
+	r.Use(handlers.NewAPIKeyMiddleware(mockSvc, logger))
+	r.GET("/books", func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString(handlers.APIKeyClientKey))
+	})
+
+	mockSvc.On("VerifyAPIKey", mock.Anything, "lib_valid").Return(&models.APIKeyResponse{Name: "kiosk"}, nil)
+	mockSvc.On("VerifyAPIKey", mock.Anything, "").Return(nil, utils.ErrNotFound)
+	mockSvc.On("VerifyAPIKey", mock.Anything, "lib_broken").Return(nil, assert.AnError)
+
+	for key, expected := range map[string]int{"lib_valid": http.StatusOK, "": http.StatusUnauthorized, "lib_broken": http.StatusInternalServerError} {
+		req := httptest.NewRequest(http.MethodGet, "/books", nil)
+		if key != "" {
+			req.Header.Set(handlers.APIKeyHeader, key)
+		}
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, expected, resp.Code, key)
+		if expected == http.StatusOK {
+			assert.Equal(t, "kiosk", resp.Body.String()) // Client name available to handlers
+		}
+	}
+	mockSvc.AssertExpectations(t)
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/services"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+type AuthorHandler struct {
+	service services.AuthorService
+	logger  *zap.Logger
+}
+
+func NewAuthorHandler(service services.AuthorService, logger *zap.Logger) *AuthorHandler {
+	return &AuthorHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ListAuthors godoc
+// @Summary List authors
+// @Description Returns a paginated list of authors (and other contributors) sorted by name, with the number of books credited to each. Supports filtering by name (contains, accent- and case-insensitive).
+// @Tags authors
+// @Produce json
+// @Param request query models.ListAuthorsRequest false "Filter and pagination parameters"
+// @Success 200 {object} models.ListAuthorsResponse
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /authors [get]
+func (h *AuthorHandler) ListAuthors(c *gin.Context) {
+
+	h.logger.Info("Listing authors")
+	ctx := c.Request.Context()
+
+	// Parse query string
+	var req models.ListAuthorsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Invalid query parameters", zap.Error(err))
+		bindingProblem(c, err, models.ProblemInvalidQuery)
+		return
+	}
+
+	// Validate pagination parameters
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = defaultPageSize
+	}
+	if req.PageSize > maxPageSize {
+		req.PageSize = maxPageSize
+	}
+
+	// Invoke service
+	res, err := h.service.ListAuthors(ctx, req)
+	if err != nil {
+		h.logger.Error("Failed to list authors", zap.Error(err))
+		problemDetail(c, http.StatusInternalServerError, models.ProblemInternal, "Failed to list authors")
+		return
+	}
+	h.logger.Info("Authors listed successfully", zap.Int("count", len(res.Authors)))
+	c.JSON(http.StatusOK, res)
+}
+
+// ListAuthorBooks godoc
+// @Summary List the books of an author
+// @Description Same as GET /books, restricted to the books crediting the author (optionally only with contributor_role, e.g. translator)
+// @Tags authors
+// @Produce json
+// @Param id path string true "Author ID"
+// @Param request query models.ListBooksRequest true "Filter and pagination parameters"
+// @Success 200 {object} models.ListBooksResponse
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 404 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /authors/{id}/books [get]
+func (h *AuthorHandler) ListAuthorBooks(c *gin.Context) {
+
+	h.logger.Info("Listing author books")
+	ctx := c.Request.Context()
+
+	// Extract params
+	id := c.Param("id")
+
+	// Parse query string
+	var req models.ListBooksRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Invalid query parameters", zap.Error(err))
+		bindingProblem(c, err, models.ProblemInvalidQuery)
+		return
+	}
+	if len(req.SortQuery) > 0 {
+		req.Sort = parseSortQuery(req.SortQuery)
+	}
+
+	// Validate pagination and sort parameters
+	if err := prepareListRequest(&req); err != nil {
+		h.logger.Warn("Invalid sort", zap.Error(err))
+		problemError(c, http.StatusBadRequest, models.ProblemValidationFailed, err)
+		return
+	}
+
+	// Invoke service
+	res, err := h.service.ListAuthorBooks(ctx, id, req)
+	switch {
+	case errors.Is(err, utils.ErrNotFound):
+		h.logger.Warn("Author not found", zap.String("id", id))
+		problem(c, http.StatusNotFound, models.ProblemAuthorNotFound)
+		return
+	case errors.Is(err, utils.ErrBadRequest): // Invalid request (e.g. cursor)
+		h.logger.Warn("Invalid list request", zap.Error(err))
+		problemError(c, http.StatusBadRequest, models.ProblemValidationFailed, err)
+		return
+	case err != nil:
+		h.logger.Error("Failed to list author books", zap.String("id", id), zap.Error(err))
+		problemDetail(c, http.StatusInternalServerError, models.ProblemInternal, "Failed to list author books")
+		return
+	}
+	h.logger.Info("Author books listed successfully", zap.String("id", id), zap.Int("count", len(res.Books)))
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,100 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/santiago-buildit/code-challenge/backend/internal/handlers"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+// MockAuthorService implements AuthorService for testing
+type MockAuthorService struct {
+	mock.Mock
+}
+
+func (m *MockAuthorService) ListAuthors(ctx context.Context, req models.ListAuthorsRequest) (*models.ListAuthorsResponse, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*models.ListAuthorsResponse), args.Error(1)
+}
+
+func (m *MockAuthorService) ListAuthorBooks(ctx context.Context, id string, req models.ListBooksRequest) (*models.ListBooksResponse, error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(*models.ListBooksResponse), args.Error(1)
+}
+
+func TestListAuthors_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockAuthorService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewAuthorHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.GET("/authors", handler.ListAuthors)
+
+	expectedReq := models.ListAuthorsRequest{Name: "homer", Page: 1, PageSize: 10} // Defaults applied
+	mockSvc.On("ListAuthors", mock.Anything, expectedReq).Return(&models.ListAuthorsResponse{
+		Authors:    []models.AuthorResponse{{ID: "a-1", Name: "Homer", BookCount: 2}},
+		TotalItems: 1, TotalPages: 1, CurrentPage: 1, PageSize: 10,
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/authors?name=homer", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var decoded models.ListAuthorsResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &decoded))
+	assert.Equal(t, "Homer", decoded.Authors[0].Name)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestListAuthorBooks_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockAuthorService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewAuthorHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.GET("/authors/:id/books", handler.ListAuthorBooks)
+
+	mockSvc.On("ListAuthorBooks", mock.Anything, "missing", mock.Anything).
+		Return((*models.ListBooksResponse)(nil), utils.ErrNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/authors/missing/books?page=1&page_size=10", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.Contains(t, resp.Body.String(), "Author not found")
+	mockSvc.AssertExpectations(t)
+}
+
+func TestListAuthorBooks_InvalidRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockAuthorService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewAuthorHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.GET("/authors/:id/books", handler.ListAuthorBooks)
+
+	req := httptest.NewRequest(http.MethodGet, "/authors/a-1/books?page=1&page_size=10&contributor_role=narrator", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	mockSvc.AssertNotCalled(t, "ListAuthorBooks", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -1,10 +1,18 @@
 package handlers
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/santiago-buildit/code-challenge/backend/internal/i18n"
 	"github.com/santiago-buildit/code-challenge/backend/internal/models"
 	"github.com/santiago-buildit/code-challenge/backend/internal/services"
 	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
@@ -17,13 +25,8 @@ const (
 	maxPageSize     = 100
 )
 
-// Valid sort fields for pagination
-var validSortFields = map[string]bool{
-	"isbn":   true,
-	"title":  true,
-	"author": true,
-	"status": true,
-}
+// Cache policy for GET list responses (stored, but always revalidated with ETag / Last-Modified)
+const listCacheControl = "public, no-cache"
 
 type BookHandler struct {
 	service services.BookService
@@ -45,8 +48,8 @@ func NewBookHandler(service services.BookService, logger *zap.Logger) *BookHandl
 // @Produce json
 // @Param request body models.CreateBookRequest true "Book data"
 // @Success 201 {object} models.BookResponse
-// @Failure 400 {object} models.ErrorResponse
-// @Failure 500 {object} models.ErrorResponse
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
 // @Router /books [post]
 func (h *BookHandler) CreateBook(c *gin.Context) {
 
@@ -57,7 +60,7 @@ func (h *BookHandler) CreateBook(c *gin.Context) {
 	var req models.CreateBookRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Warn("Invalid request body", zap.Error(err))
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+		bindingProblem(c, err, models.ProblemInvalidRequestBody)
 		return
 	}
 
@@ -80,50 +83,83 @@ func (h *BookHandler) CreateBook(c *gin.Context) {
 
 // ListBooks godoc
 // @Summary List books with filters, ordering, and pagination
-// @Description Returns a paginated list of books. Supports filtering by ISBN, Title, Author, Status, and full-text search over Title/Description. Also supports ordering by field and direction.
+// @Description Returns a paginated list of books (page numbers, or opaque keyset cursors with pagination=cursor). Supports filtering by ISBN, Title, Author (contains or exact), Status (one or many), Tags (any or all), contributor (author_id, optionally with contributor_role), Publisher, Language, Format (one or many), publication year range, creation/update date ranges, and ranked full-text search (web search syntax) over Title/Author/Description with highlighted snippets. Also supports ordering by one or more fields and directions (id as final tiebreaker), including relevance when searching. Unknown sort fields are rejected.
 // @Tags books
 // @Accept json
 // @Produce json
 // @Param request body models.ListBooksRequest true "Filter and pagination parameters"
 // @Success 200 {object} models.ListBooksResponse
-// @Failure 400 {object} models.ErrorResponse
-// @Failure 500 {object} models.ErrorResponse
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
 // @Router /books/list [post]
 func (h *BookHandler) ListBooks(c *gin.Context) {
 
 	h.logger.Info("Listing books")
-	ctx := c.Request.Context()
 
 	// Parse request body
 	var req models.ListBooksRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Warn("Invalid request body", zap.Error(err))
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+		bindingProblem(c, err, models.ProblemInvalidRequestBody)
 		return
 	}
 
-	// Validate pagination parameters
-	if req.PageSize <= 0 {
-		req.PageSize = defaultPageSize
+	// List books
+	res, ok := h.listBooks(c, req)
+	if !ok {
+		return
 	}
-	if req.PageSize > maxPageSize {
-		req.PageSize = maxPageSize
+	c.JSON(http.StatusOK, res)
+}
+
+// ListBooksQuery godoc
+// @Summary List books with query-string filters (cacheable)
+// @Description Same as POST /books/list, with parameters in the query string (repeat a parameter for multi-value filters, e.g. facets=status&facets=author, and sorts as sort=author:asc&sort=title:desc). Responses carry ETag and Last-Modified validators and answer 304 Not Modified to conditional requests.
+// @Tags books
+// @Produce json
+// @Param request query models.ListBooksRequest true "Filter and pagination parameters"
+// @Param If-None-Match header string false "ETag of a previous response"
+// @Param If-Modified-Since header string false "Last-Modified of a previous response"
+// @Success 200 {object} models.ListBooksResponse
+// @Success 304 "Not modified"
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /books [get]
+func (h *BookHandler) ListBooksQuery(c *gin.Context) {
+
+	h.logger.Info("Listing books (query)")
+
+	// Parse query string
+	var req models.ListBooksRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Invalid query parameters", zap.Error(err))
+		bindingProblem(c, err, models.ProblemInvalidQuery)
+		return
 	}
-	if !validSortFields[req.SortBy] {
-		req.SortBy = "title"
+	if len(req.SortQuery) > 0 {
+		req.Sort = parseSortQuery(req.SortQuery)
 	}
-	if req.SortOrder != "desc" {
-		req.SortOrder = "asc"
+
+	// List books
+	res, ok := h.listBooks(c, req)
+	if !ok {
+		return
 	}
 
-	// Invoke service
-	res, err := h.service.ListBooks(ctx, req)
-	if err != nil {
-		h.logger.Error("Failed to list books", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list books"})
+	// Set cache validators (replace the global no-store policy)
+	etag, lastModified := listValidators(c.Request.URL.RawQuery, res)
+	c.Header("Cache-Control", listCacheControl)
+	c.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	// Answer conditional requests
+	if notModified(c.Request, etag, lastModified) {
+		h.logger.Info("Books not modified", zap.String("etag", etag))
+		c.Status(http.StatusNotModified)
 		return
 	}
-	h.logger.Info("Books listed successfully", zap.Int("count", len(res.Books)))
 	c.JSON(http.StatusOK, res)
 }
 
@@ -135,9 +171,9 @@ func (h *BookHandler) ListBooks(c *gin.Context) {
 // @Produce json
 // @Param id path string true "Book ID"
 // @Success 200 {object} models.BookResponse
-// @Failure 400 {object} models.ErrorResponse
-// @Failure 404 {object} models.ErrorResponse
-// @Failure 500 {object} models.ErrorResponse
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 404 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
 // @Router /books/{id} [get]
 func (h *BookHandler) GetBook(c *gin.Context) {
 
@@ -172,9 +208,9 @@ func (h *BookHandler) GetBook(c *gin.Context) {
 // @Param id path string true "Book ID"
 // @Param request body models.UpdateBookRequest true "Updated book data"
 // @Success 200 {object} models.BookResponse
-// @Failure 400 {object} models.ErrorResponse
-// @Failure 404 {object} models.ErrorResponse
-// @Failure 500 {object} models.ErrorResponse
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 404 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
 // @Router /books/{id} [put]
 func (h *BookHandler) UpdateBook(c *gin.Context) {
 
@@ -191,7 +227,7 @@ func (h *BookHandler) UpdateBook(c *gin.Context) {
 	var req models.UpdateBookRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Warn("Invalid request body", zap.Error(err))
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request body"})
+		bindingProblem(c, err, models.ProblemInvalidRequestBody)
 		return
 	}
 
@@ -220,9 +256,9 @@ func (h *BookHandler) UpdateBook(c *gin.Context) {
 // @Produce json
 // @Param id path string true "Book ID"
 // @Success 200 {object} models.MessageResponse
-// @Failure 400 {object} models.ErrorResponse
-// @Failure 404 {object} models.ErrorResponse
-// @Failure 500 {object} models.ErrorResponse
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 404 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
 // @Router /books/{id} [delete]
 func (h *BookHandler) DeleteBook(c *gin.Context) {
 
@@ -242,20 +278,22 @@ func (h *BookHandler) DeleteBook(c *gin.Context) {
 		return
 	}
 	h.logger.Info("Book deleted successfully", zap.String("id", id))
-	c.JSON(http.StatusOK, models.MessageResponse{Message: "Book deleted"})
+	message(c, i18n.MessageBookDeleted)
 }
 
 // CheckoutBook godoc
 // @Summary Checkout a book by ID
-// @Description Marks the book as checked out and updates history
+// @Description Marks the book as checked out and updates history. Only available books can be checked out (409 with the allowed statuses otherwise). The optional body records who made the change (actor, default: the API key client), where (source: desk, kiosk or api, default api) and a note.
 // @Tags books
 // @Accept json
 // @Produce json
 // @Param id path string true "Book ID"
+// @Param request body models.StatusChangeRequest false "Status change details"
 // @Success 200 {object} models.MessageResponse
-// @Failure 400 {object} models.ErrorResponse
-// @Failure 404 {object} models.ErrorResponse
-// @Failure 500 {object} models.ErrorResponse
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 404 {object} models.ProblemResponse
+// @Failure 409 {object} models.InvalidTransitionResponse
+// @Failure 500 {object} models.ProblemResponse
 // @Router /books/{id}/checkout [put]
 func (h *BookHandler) CheckoutBook(c *gin.Context) {
 
@@ -268,27 +306,35 @@ func (h *BookHandler) CheckoutBook(c *gin.Context) {
 		return
 	}
 
+	// Parse request body (optional)
+	req, ok := h.bindStatusChange(c)
+	if !ok {
+		return
+	}
+
 	// Invoke service
-	err := h.service.CheckoutBook(ctx, id)
+	err := h.service.CheckoutBook(ctx, id, req)
 	if err != nil {
 		h.handleBookError(c, id, err, "checkout")
 		return
 	}
 	h.logger.Info("Book checked out successfully", zap.String("id", id))
-	c.JSON(http.StatusOK, models.MessageResponse{Message: "Book checked out"})
+	message(c, i18n.MessageBookCheckedOut)
 }
 
 // CheckinBook godoc
 // @Summary Checkin a book by ID
-// @Description Marks the book as available and updates history
+// @Description Marks the book as available and updates history. The optional body records who made the change (actor, default: the API key client), where (source: desk, kiosk or api, default api) and a note.
 // @Tags books
 // @Accept json
 // @Produce json
 // @Param id path string true "Book ID"
+// @Param request body models.StatusChangeRequest false "Status change details"
 // @Success 200 {object} models.MessageResponse
-// @Failure 400 {object} models.ErrorResponse
-// @Failure 404 {object} models.ErrorResponse
-// @Failure 500 {object} models.ErrorResponse
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 404 {object} models.ProblemResponse
+// @Failure 409 {object} models.InvalidTransitionResponse
+// @Failure 500 {object} models.ProblemResponse
 // @Router /books/{id}/checkin [put]
 func (h *BookHandler) CheckinBook(c *gin.Context) {
 
@@ -301,27 +347,140 @@ func (h *BookHandler) CheckinBook(c *gin.Context) {
 		return
 	}
 
+	// Parse request body (optional)
+	req, ok := h.bindStatusChange(c)
+	if !ok {
+		return
+	}
+
 	// Invoke service
-	err := h.service.CheckinBook(ctx, id)
+	err := h.service.CheckinBook(ctx, id, req)
 	if err != nil {
 		h.handleBookError(c, id, err, "checkin")
 		return
 	}
 	h.logger.Info("Book checked in successfully", zap.String("id", id))
-	c.JSON(http.StatusOK, models.MessageResponse{Message: "Book checked in"})
+	message(c, i18n.MessageBookCheckedIn)
+}
+
+// MarkBookLost godoc
+// @Summary Mark a book as lost
+// @Description Marks an available, checked-out or missing book as lost and updates history (409 with the allowed statuses otherwise). Same optional body as checkout.
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param request body models.StatusChangeRequest false "Status change details"
+// @Success 200 {object} models.MessageResponse
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 404 {object} models.ProblemResponse
+// @Failure 409 {object} models.InvalidTransitionResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /books/{id}/lost [put]
+func (h *BookHandler) MarkBookLost(c *gin.Context) {
+	h.logger.Info("Marking book as lost")
+	h.changeStatus(c, "mark as lost", func(ctx context.Context, id string, req models.StatusChangeRequest) error {
+		return h.service.ChangeBookStatus(ctx, id, models.BookStatusLost, req)
+	}, i18n.MessageBookMarkedLost)
+}
+
+// MarkBookDamaged godoc
+// @Summary Mark a book as damaged
+// @Description Marks an available or checked-out book as damaged and updates history (409 with the allowed statuses otherwise). Damaged books go to repair or are withdrawn (PUT /books/{id}/status). Same optional body as checkout.
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param request body models.StatusChangeRequest false "Status change details"
+// @Success 200 {object} models.MessageResponse
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 404 {object} models.ProblemResponse
+// @Failure 409 {object} models.InvalidTransitionResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /books/{id}/damaged [put]
+func (h *BookHandler) MarkBookDamaged(c *gin.Context) {
+	h.logger.Info("Marking book as damaged")
+	h.changeStatus(c, "mark as damaged", func(ctx context.Context, id string, req models.StatusChangeRequest) error {
+		return h.service.ChangeBookStatus(ctx, id, models.BookStatusDamaged, req)
+	}, i18n.MessageBookMarkedDamaged)
+}
+
+// MarkBookFound godoc
+// @Summary Mark a book as found
+// @Description Marks a lost or missing book as available and updates history (409 with the allowed statuses otherwise). Same optional body as checkout.
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param request body models.StatusChangeRequest false "Status change details"
+// @Success 200 {object} models.MessageResponse
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 404 {object} models.ProblemResponse
+// @Failure 409 {object} models.InvalidTransitionResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /books/{id}/found [put]
+func (h *BookHandler) MarkBookFound(c *gin.Context) {
+	h.logger.Info("Marking book as found")
+	h.changeStatus(c, "mark as found", h.service.MarkBookFound, i18n.MessageBookMarkedFound)
+}
+
+// SetBookStatus godoc
+// @Summary Change the status of a book
+// @Description Moves the book to any status allowed from the current one and updates history (409 with the allowed statuses otherwise): available -> checked_out, lost, damaged, missing, withdrawn; checked_out -> available, lost, damaged; lost -> available, withdrawn; damaged -> in_repair, withdrawn; in_repair -> available, withdrawn; missing -> available, lost, withdrawn. Withdrawn is final.
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param request body models.BookStatusRequest true "New status and status change details"
+// @Success 200 {object} models.MessageResponse
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 404 {object} models.ProblemResponse
+// @Failure 409 {object} models.InvalidTransitionResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /books/{id}/status [put]
+func (h *BookHandler) SetBookStatus(c *gin.Context) {
+
+	h.logger.Info("Changing book status")
+	ctx := c.Request.Context()
+
+	// Extract params
+	id, ok := h.extractID(c)
+	if !ok {
+		return
+	}
+
+	// Parse request body
+	var req models.BookStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid request body", zap.Error(err))
+		bindingProblem(c, err, models.ProblemInvalidRequestBody)
+		return
+	}
+	if req.Actor == "" {
+		req.Actor = c.GetString(APIKeyClientKey)
+	}
+
+	// Invoke service
+	err := h.service.ChangeBookStatus(ctx, id, req.Status, req.StatusChangeRequest)
+	if err != nil {
+		h.handleBookError(c, id, err, "change status of")
+		return
+	}
+	h.logger.Info("Book status changed successfully", zap.String("id", id), zap.String("status", string(req.Status)))
+	message(c, i18n.MessageBookStatusChanged)
 }
 
 // GetBookWithHistory godoc
 // @Summary Get a book by ID with status change history
-// @Description Retrieves book metadata and its full status change history
+// @Description Retrieves book metadata and its latest 50 status changes, newest first. history_truncated is set when there are older ones (see GET /books/{id}/history for the whole history, paginated and filterable)
 // @Tags books
 // @Accept json
 // @Produce json
 // @Param id path string true "Book ID"
 // @Success 200 {object} models.BookDetailResponse
-// @Failure 400 {object} models.ErrorResponse
-// @Failure 404 {object} models.ErrorResponse
-// @Failure 500 {object} models.ErrorResponse
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 404 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
 // @Router /books/{id}/details [get]
 func (h *BookHandler) GetBookWithHistory(c *gin.Context) {
 
@@ -344,14 +503,202 @@ func (h *BookHandler) GetBookWithHistory(c *gin.Context) {
 	c.JSON(http.StatusOK, res)
 }
 
+// ListBookHistory godoc
+// @Summary List the status change history of a book
+// @Description Returns a paginated list of the status changes of a book, newest first, with actor, source and note. Supports filtering by date window [from, to) (UTC dates).
+// @Tags books
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param request query models.ListHistoryRequest false "Date window and pagination parameters"
+// @Success 200 {object} models.ListHistoryResponse
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 404 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /books/{id}/history [get]
+func (h *BookHandler) ListBookHistory(c *gin.Context) {
+
+	h.logger.Info("Listing book history")
+	ctx := c.Request.Context()
+
+	// Extract params
+	id, ok := h.extractID(c)
+	if !ok {
+		return
+	}
+
+	// Parse query string
+	var req models.ListHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Invalid query parameters", zap.Error(err))
+		bindingProblem(c, err, models.ProblemInvalidQuery)
+		return
+	}
+
+	// Validate pagination parameters
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = defaultPageSize
+	}
+	if req.PageSize > maxPageSize {
+		req.PageSize = maxPageSize
+	}
+
+	// Invoke service
+	res, err := h.service.ListBookHistory(ctx, id, req)
+	if err != nil {
+		h.handleBookError(c, id, err, "list history of")
+		return
+	}
+	h.logger.Info("Book history listed successfully", zap.String("id", id), zap.Int("count", len(res.History)))
+	c.JSON(http.StatusOK, res)
+}
+
 /* Helper functions */
 
+// bindStatusChange parses the optional body of checkout and checkin.
+// The actor defaults to the API key client (when authenticated with one).
+func (h *BookHandler) bindStatusChange(c *gin.Context) (models.StatusChangeRequest, bool) {
+	var req models.StatusChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) { // Empty body
+		h.logger.Warn("Invalid request body", zap.Error(err))
+		bindingProblem(c, err, models.ProblemInvalidRequestBody)
+		return req, false
+	}
+	if req.Actor == "" {
+		req.Actor = c.GetString(APIKeyClientKey)
+	}
+	return req, true
+}
+
+// changeStatus runs a status change with the optional status change body
+func (h *BookHandler) changeStatus(c *gin.Context, action string, change func(context.Context, string, models.StatusChangeRequest) error, key string) {
+
+	// Extract params
+	id, ok := h.extractID(c)
+	if !ok {
+		return
+	}
+
+	// Parse request body (optional)
+	req, ok := h.bindStatusChange(c)
+	if !ok {
+		return
+	}
+
+	// Invoke service
+	if err := change(c.Request.Context(), id, req); err != nil {
+		h.handleBookError(c, id, err, action)
+		return
+	}
+	h.logger.Info("Book status changed successfully", zap.String("id", id), zap.String("action", action))
+	message(c, key)
+}
+
+func (h *BookHandler) listBooks(c *gin.Context, req models.ListBooksRequest) (*models.ListBooksResponse, bool) {
+
+	ctx := c.Request.Context()
+
+	// Validate pagination and sort parameters
+	if err := prepareListRequest(&req); err != nil {
+		h.logger.Warn("Invalid sort", zap.Error(err))
+		problemError(c, http.StatusBadRequest, models.ProblemValidationFailed, err)
+		return nil, false
+	}
+
+	// Invoke service
+	res, err := h.service.ListBooks(ctx, req)
+	if errors.Is(err, utils.ErrBadRequest) { // Invalid request (e.g. cursor)
+		h.logger.Warn("Invalid list request", zap.Error(err))
+		problemError(c, http.StatusBadRequest, models.ProblemValidationFailed, err)
+		return nil, false
+	}
+	if err != nil {
+		h.logger.Error("Failed to list books", zap.Error(err))
+		problemDetail(c, http.StatusInternalServerError, models.ProblemInternal, "Failed to list books")
+		return nil, false
+	}
+	h.logger.Info("Books listed successfully", zap.Int("count", len(res.Books)))
+	return res, true
+}
+
+// prepareListRequest applies the page size limits and validates the sorts of a list request
+func prepareListRequest(req *models.ListBooksRequest) error {
+	if req.PageSize <= 0 {
+		req.PageSize = defaultPageSize
+	}
+	if req.PageSize > maxPageSize {
+		req.PageSize = maxPageSize
+	}
+	return validateSorts(*req)
+}
+
+// validateSorts checks the effective sort specs against the shared whitelist
+func validateSorts(req models.ListBooksRequest) error {
+	for _, spec := range req.Sorts() {
+		if !models.SortFields[spec.Field] {
+			return i18n.NewError(utils.ErrBadRequest, i18n.ErrorInvalidSortField, i18n.Params{"field": spec.Field})
+		}
+		if spec.Order != models.SortAsc && spec.Order != models.SortDesc {
+			return i18n.NewError(utils.ErrBadRequest, i18n.ErrorInvalidSortOrder, i18n.Params{"field": spec.Field, "order": string(spec.Order)})
+		}
+		if spec.Field == "relevance" && req.Text == "" {
+			return i18n.NewError(utils.ErrBadRequest, i18n.ErrorRelevanceSort, nil)
+		}
+	}
+	return nil
+}
+
+// parseSortQuery converts query-string sorts ("field" or "field:order") into sort specs
+func parseSortQuery(values []string) []models.SortSpec {
+	specs := make([]models.SortSpec, 0, len(values))
+	for _, value := range values {
+		field, order, _ := strings.Cut(value, ":")
+		specs = append(specs, models.SortSpec{Field: field, Order: order})
+	}
+	return specs
+}
+
+// listValidators computes the ETag (query and whole response, so facets, suggestions and totals count too)
+// and Last-Modified (latest change of the library, since a change outside the page can alter the response)
+func listValidators(rawQuery string, res *models.ListBooksResponse) (string, time.Time) {
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s|", rawQuery)
+	_ = json.NewEncoder(hash).Encode(res) // Encoding a response can't fail
+	etag := fmt.Sprintf(`W/"%x"`, hash.Sum(nil)[:16])
+	return etag, res.LastModified
+}
+
+// notModified evaluates If-None-Match (preferred) or If-Modified-Since against the response validators
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+
+	// Entity tag comparison (weak, any of the listed tags)
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Date comparison (HTTP dates have second precision)
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(ims)
+		return err == nil && !lastModified.Truncate(time.Second).After(since)
+	}
+	return false
+}
+
 func (h *BookHandler) extractID(c *gin.Context) (string, bool) {
 
 	id := c.Param("id")
 	if id == "" {
 		h.logger.Warn("Missing parameter ID")
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Missing parameter ID"})
+		problem(c, http.StatusBadRequest, models.ProblemMissingID)
 		return "", false
 	}
 	return id, true
@@ -360,14 +707,29 @@ func (h *BookHandler) extractID(c *gin.Context) (string, bool) {
 func (h *BookHandler) handleBookError(c *gin.Context, id string, err error, action string) {
 
 	// Handle specific errors
+	var transition *utils.TransitionError
 	if errors.Is(err, utils.ErrNotFound) { // Not found error
 		h.logger.Warn("Book not found", zap.String("id", id))
-		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Book not found"})
+		problem(c, http.StatusNotFound, models.ProblemBookNotFound)
+	} else if errors.Is(err, utils.ErrBadRequest) { // Invalid request (e.g. missing author)
+		h.logger.Warn("Invalid book request", zap.String("id", id), zap.Error(err))
+		problemError(c, http.StatusBadRequest, models.ProblemValidationFailed, err)
+	} else if errors.As(err, &transition) { // Status change not allowed from the current status
+		h.logger.Warn("Invalid book status transition", zap.String("id", id), zap.Error(err))
+		res := models.InvalidTransitionResponse{
+			ProblemResponse: newProblem(c, http.StatusConflict, models.ProblemInvalidTransition, i18n.Params{"from": transition.From, "to": transition.To}),
+			CurrentStatus:   models.BookStatus(transition.From),
+			AllowedStatuses: []models.BookStatus{},
+		}
+		for _, status := range transition.Allowed {
+			res.AllowedStatuses = append(res.AllowedStatuses, models.BookStatus(status))
+		}
+		writeProblem(c, http.StatusConflict, res)
 	} else { // Generic error
 		h.logger.Error("Failed to "+action+" book",
 			zap.String("id", id),
 			zap.Error(err),
 		)
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to " + action + " book"})
+		problemDetail(c, http.StatusInternalServerError, models.ProblemInternal, "Failed to "+action+" book")
 	}
 }
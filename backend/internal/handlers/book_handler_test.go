@@ -4,15 +4,19 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/santiago-buildit/code-challenge/backend/internal/handlers"
+	"github.com/santiago-buildit/code-challenge/backend/internal/i18n"
 	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/query"
 	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap/zaptest"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -51,14 +55,30 @@ func (m *MockBookService) DeleteBook(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
-func (m *MockBookService) CheckoutBook(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *MockBookService) CheckoutBook(ctx context.Context, id string, req models.StatusChangeRequest) error {
+	args := m.Called(ctx, id, req)
 	return args.Error(0)
 }
-func (m *MockBookService) CheckinBook(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *MockBookService) CheckinBook(ctx context.Context, id string, req models.StatusChangeRequest) error {
+	args := m.Called(ctx, id, req)
+	return args.Error(0)
+}
+func (m *MockBookService) MarkBookFound(ctx context.Context, id string, req models.StatusChangeRequest) error {
+	args := m.Called(ctx, id, req)
 	return args.Error(0)
 }
+func (m *MockBookService) ChangeBookStatus(ctx context.Context, id string, status models.BookStatus, req models.StatusChangeRequest) error {
+	args := m.Called(ctx, id, status, req)
+	return args.Error(0)
+}
+func (m *MockBookService) ListBookHistory(ctx context.Context, id string, req models.ListHistoryRequest) (*models.ListHistoryResponse, error) {
+	args := m.Called(ctx, id, req)
+	result := args.Get(0)
+	if result == nil {
+		return nil, args.Error(1)
+	}
+	return result.(*models.ListHistoryResponse), args.Error(1)
+}
 func (m *MockBookService) GetBookWithHistory(ctx context.Context, id string) (*models.BookDetailResponse, error) {
 	args := m.Called(ctx, id)
 	result := args.Get(0)
@@ -68,6 +88,11 @@ func (m *MockBookService) GetBookWithHistory(ctx context.Context, id string) (*m
 	return result.(*models.BookDetailResponse), args.Error(1)
 }
 
+func (m *MockBookService) GetBooksHistory(ctx context.Context, ids []string) (map[string][]models.StatusChangeResponse, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).(map[string][]models.StatusChangeResponse), args.Error(1)
+}
+
 func TestCreateBook_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -127,6 +152,32 @@ func TestCreateBook_Success(t *testing.T) {
 	mockSvc.AssertExpectations(t)
 }
 
+func TestCreateBook_WithTags(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockBookService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewBookHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.POST("/books", handler.CreateBook)
+
+	// Tags are trimmed and deduplicated case-insensitively before reaching the service
+	mockSvc.On("CreateBook", mock.Anything, mock.MatchedBy(func(req models.CreateBookRequest) bool {
+		return assert.ObjectsAreEqual([]string{"Fantasy", "Classics"}, req.Tags)
+	})).Return(&models.BookResponse{ID: "book-1", Tags: []string{"Classics", "Fantasy"}}, nil)
+
+	body := `{"isbn":"123456","title":"The Hobbit","author":"J.R.R. Tolkien","tags":[" Fantasy ","Classics","fantasy",""]}`
+	req := httptest.NewRequest(http.MethodPost, "/books", bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	mockSvc.AssertExpectations(t)
+}
+
 func TestCreateBook_InvalidPayload(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -148,6 +199,45 @@ func TestCreateBook_InvalidPayload(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, resp.Code)
 }
 
+func TestCreateBook_BibliographicFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockBookService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewBookHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.POST("/books", handler.CreateBook)
+
+	mockSvc.On("CreateBook", mock.Anything, mock.MatchedBy(func(req models.CreateBookRequest) bool {
+		return req.Publisher == "Emecé" && req.PublicationYear == 1944 && req.Language == "es" && // Language lowercased
+			req.PageCount == 203 && req.Format == models.BookFormatPaperback
+	})).Return(&models.BookResponse{ID: "book-1", Language: "es"}, nil)
+
+	body := []byte(`{"isbn": "9789875666481", "title": "Ficciones", "author": "Jorge Luis Borges",
+		"publisher": " Emecé ", "publication_year": 1944, "language": "ES", "page_count": 203, "format": "paperback"}`)
+
+	req := httptest.NewRequest(http.MethodPost, "/books", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	mockSvc.AssertExpectations(t)
+
+	// Unknown format is rejected before reaching the service
+	body = []byte(`{"isbn": "9789875666481", "title": "Ficciones", "author": "Jorge Luis Borges", "format": "scroll"}`)
+	req = httptest.NewRequest(http.MethodPost, "/books", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	mockSvc.AssertNumberOfCalls(t, "CreateBook", 1)
+}
+
 func TestListBooks_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -224,6 +314,330 @@ func TestListBooks_InvalidRequest(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, resp.Code)
 }
 
+func TestListBooks_InvalidFacet(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockBookService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewBookHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.POST("/books/list", handler.ListBooks)
+
+	// Unknown facet field
+	body := []byte(`{"page":1, "page_size":5, "facets":["isbn"]}`)
+
+	req := httptest.NewRequest(http.MethodPost, "/books/list", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	mockSvc.AssertNotCalled(t, "ListBooks", mock.Anything, mock.Anything)
+}
+
+func TestListBooksQuery_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockBookService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewBookHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.GET("/books", handler.ListBooksQuery)
+
+	// Same request as the POST body, from query parameters (repeated facets)
+	expectedReq := models.ListBooksRequest{
+		Page:      2,
+		PageSize:  5,
+		SortBy:    "author",
+		SortOrder: "desc",
+		Title:     "Rings",
+		Facets:    []string{"status", "author"},
+	}
+
+	updatedAt := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	expectedResp := &models.ListBooksResponse{
+		Books: []models.BookResponse{
+			{ID: "book-1", Title: "The Lord of the Rings", UpdatedAt: updatedAt.Add(-time.Hour)},
+			{ID: "book-2", Title: "The Rings of Power", UpdatedAt: updatedAt},
+		},
+		TotalItems:   7,
+		TotalPages:   2,
+		CurrentPage:  2,
+		PageSize:     5,
+		LastModified: updatedAt,
+	}
+
+	mockSvc.On("ListBooks", mock.Anything, expectedReq).Return(expectedResp, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/books?page=2&page_size=5&sort_by=author&sort_order=desc&title=Rings&facets=status&facets=author", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.NotEmpty(t, resp.Header().Get("ETag"))
+	assert.Equal(t, "Fri, 01 Mar 2024 10:30:00 GMT", resp.Header().Get("Last-Modified"))
+
+	var decoded models.ListBooksResponse
+	err := json.Unmarshal(resp.Body.Bytes(), &decoded)
+	assert.NoError(t, err)
+	assert.Len(t, decoded.Books, 2)
+
+	// Conditional requests with the returned validators
+	etag := resp.Header().Get("ETag")
+	req = httptest.NewRequest(http.MethodGet, "/books?page=2&page_size=5&sort_by=author&sort_order=desc&title=Rings&facets=status&facets=author", nil)
+	req.Header.Set("If-None-Match", etag)
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotModified, resp.Code)
+	assert.Empty(t, resp.Body.Bytes())
+
+	req = httptest.NewRequest(http.MethodGet, "/books?page=2&page_size=5&sort_by=author&sort_order=desc&title=Rings&facets=status&facets=author", nil)
+	req.Header.Set("If-Modified-Since", "Fri, 01 Mar 2024 10:00:00 GMT") // Older than the latest change
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	mockSvc.AssertExpectations(t)
+}
+
+func TestListBooksQuery_ETagChangesAfterUpdate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockBookService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewBookHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.GET("/books", handler.ListBooksQuery)
+	r.PUT("/books/:id", handler.UpdateBook)
+
+	listReq := models.ListBooksRequest{Page: 1, PageSize: 10}
+	before := models.BookResponse{ID: "book-1", Title: "Dune", UpdatedAt: time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)}
+	mockSvc.On("ListBooks", mock.Anything, listReq).Return(&models.ListBooksResponse{Books: []models.BookResponse{before}, TotalItems: 1, LastModified: before.UpdatedAt}, nil).Once()
+
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/books?page=1&page_size=10", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	etag := resp.Header().Get("ETag")
+
+	// Update the book (the service sets a new updated_at)
+	update := models.UpdateBookRequest{ISBN: "9780441172696", Title: "Dune Messiah", Author: "Frank Herbert"}
+	after := before
+	after.Title = update.Title
+	after.UpdatedAt = before.UpdatedAt.Add(time.Minute)
+	mockSvc.On("UpdateBook", mock.Anything, "book-1", update).Return(&after, nil)
+	mockSvc.On("ListBooks", mock.Anything, listReq).Return(&models.ListBooksResponse{Books: []models.BookResponse{after}, TotalItems: 1, LastModified: after.UpdatedAt}, nil).Once()
+
+	body, _ := json.Marshal(update)
+	req := httptest.NewRequest(http.MethodPut, "/books/book-1", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	// The list validators no longer match
+	req = httptest.NewRequest(http.MethodGet, "/books?page=1&page_size=10", nil)
+	req.Header.Set("If-None-Match", etag)
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.NotEqual(t, etag, resp.Header().Get("ETag"))
+	assert.Equal(t, "Fri, 01 Mar 2024 10:31:00 GMT", resp.Header().Get("Last-Modified"))
+	mockSvc.AssertExpectations(t)
+}
+
+func TestListBooksQuery_ValidatorsChangeOutsideThePage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockBookService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewBookHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.GET("/books", handler.ListBooksQuery)
+
+	// Same page before and after a book outside it is deleted: only totals and facets change
+	listReq := models.ListBooksRequest{Page: 1, PageSize: 1, Facets: []string{"status"}}
+	page := []models.BookResponse{{ID: "book-1", Title: "Dune", UpdatedAt: time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)}}
+	mockSvc.On("ListBooks", mock.Anything, listReq).Return(&models.ListBooksResponse{
+		Books:        page,
+		TotalItems:   2,
+		Facets:       map[string][]models.FacetBucket{"status": {{Value: "available", Count: 2}}},
+		LastModified: page[0].UpdatedAt,
+	}, nil).Once()
+	mockSvc.On("ListBooks", mock.Anything, listReq).Return(&models.ListBooksResponse{
+		Books:        page,
+		TotalItems:   1,
+		Facets:       map[string][]models.FacetBucket{"status": {{Value: "available", Count: 1}}},
+		LastModified: page[0].UpdatedAt.Add(time.Hour), // Deletes bump updated_at
+	}, nil).Twice()
+
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/books?page=1&page_size=1&facets=status", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	etag, lastModified := resp.Header().Get("ETag"), resp.Header().Get("Last-Modified")
+
+	// Neither validator matches anymore
+	req := httptest.NewRequest(http.MethodGet, "/books?page=1&page_size=1&facets=status", nil)
+	req.Header.Set("If-None-Match", etag)
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.NotEqual(t, etag, resp.Header().Get("ETag"))
+
+	req = httptest.NewRequest(http.MethodGet, "/books?page=1&page_size=1&facets=status", nil)
+	req.Header.Set("If-Modified-Since", lastModified)
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	mockSvc.AssertExpectations(t)
+}
+
+func TestListBooksQuery_MultiValueAndDateFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockBookService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewBookHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.GET("/books", handler.ListBooksQuery)
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	expectedReq := models.ListBooksRequest{
+		Page:        1,
+		PageSize:    10,
+		SortBy:      "created_at",
+		SortOrder:   "desc",
+		ISBN:        "9780544003415",
+		ISBNMatch:   models.MatchExact,
+		Statuses:    []string{"available", "checked_out"},
+		CreatedFrom: &from,
+	}
+
+	mockSvc.On("ListBooks", mock.Anything, mock.MatchedBy(func(req models.ListBooksRequest) bool {
+		return assert.ObjectsAreEqual(expectedReq.Statuses, req.Statuses) &&
+			req.CreatedFrom != nil && req.CreatedFrom.Equal(from) &&
+			req.SortBy == "created_at" && req.ISBNMatch == models.MatchExact
+	})).Return(&models.ListBooksResponse{Books: []models.BookResponse{}, TotalPages: 1, CurrentPage: 1, PageSize: 10}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/books?page=1&page_size=10&sort_by=created_at&sort_order=desc&isbn=9780544003415&isbn_match=exact&statuses=available&statuses=checked_out&created_from=2024-03-01T00:00:00Z", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestListBooks_InvalidSortField(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockBookService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewBookHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.POST("/books/list", handler.ListBooks)
+
+	for _, body := range []string{
+		`{"page":1, "page_size":5, "sort_by":"description"}`,
+		`{"page":1, "page_size":5, "sort":[{"field":"title"},{"field":"deleted","order":"asc"}]}`,
+		`{"page":1, "page_size":5, "sort":[{"field":"title","order":"up"}]}`,
+		`{"page":1, "page_size":5, "sort_by":"relevance"}`, // Without text
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/books/list", bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code, body)
+	}
+	mockSvc.AssertNotCalled(t, "ListBooks", mock.Anything, mock.Anything)
+}
+
+func TestListBooksQuery_InvalidQueryExpression(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockBookService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewBookHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.GET("/books", handler.ListBooksQuery)
+
+	syntaxErr := &query.SyntaxError{Pos: 12, Key: i18n.QueryExpectedParen, Params: i18n.Params{"found": ""}}
+	mockSvc.On("ListBooks", mock.Anything, mock.MatchedBy(func(req models.ListBooksRequest) bool {
+		return req.Q == "(title:ring"
+	})).Return((*models.ListBooksResponse)(nil), syntaxErr)
+
+	req := httptest.NewRequest(http.MethodGet, "/books?page=1&page_size=10&q=%28title%3Aring", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Equal(t, "application/problem+json", resp.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "about:blank",
+		"title": "Bad Request",
+		"status": 400,
+		"detail": "Invalid request: invalid query at position 12: expected ')' but found end of query",
+		"instance": "/books",
+		"code": "validation_failed",
+		"request_id": ""
+	}`, resp.Body.String())
+	mockSvc.AssertExpectations(t)
+}
+
+func TestListBooksQuery_MultiColumnSort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockBookService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewBookHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.GET("/books", handler.ListBooksQuery)
+
+	expectedSort := []models.SortSpec{{Field: "author", Order: "asc"}, {Field: "title", Order: "desc"}, {Field: "isbn"}}
+	mockSvc.On("ListBooks", mock.Anything, mock.MatchedBy(func(req models.ListBooksRequest) bool {
+		return assert.ObjectsAreEqual(expectedSort, req.Sort)
+	})).Return(&models.ListBooksResponse{Books: []models.BookResponse{}, TotalPages: 1, CurrentPage: 1, PageSize: 10}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/books?page=1&page_size=10&sort=author:asc&sort=title:desc&sort=isbn", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestListBooksQuery_InvalidRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockBookService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewBookHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.GET("/books", handler.ListBooksQuery)
+
+	// Missing page_size
+	req := httptest.NewRequest(http.MethodGet, "/books?page=1", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	mockSvc.AssertNotCalled(t, "ListBooks", mock.Anything, mock.Anything)
+}
+
 func TestGetBook_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -436,7 +850,7 @@ func TestCheckoutBook_Success(t *testing.T) {
 	r.PUT("/books/:id/checkout", handler.CheckoutBook)
 
 	bookID := "book-1"
-	mockSvc.On("CheckoutBook", mock.Anything, bookID).Return(nil)
+	mockSvc.On("CheckoutBook", mock.Anything, bookID, models.StatusChangeRequest{}).Return(nil)
 
 	req := httptest.NewRequest(http.MethodPut, "/books/"+bookID+"/checkout", nil)
 	resp := httptest.NewRecorder()
@@ -456,7 +870,7 @@ func TestCheckoutBook_NotFound(t *testing.T) {
 	r.PUT("/books/:id/checkout", handler.CheckoutBook)
 
 	bookID := "not-found"
-	mockSvc.On("CheckoutBook", mock.Anything, bookID).Return(utils.ErrNotFound)
+	mockSvc.On("CheckoutBook", mock.Anything, bookID, models.StatusChangeRequest{}).Return(utils.ErrNotFound)
 
 	req := httptest.NewRequest(http.MethodPut, "/books/"+bookID+"/checkout", nil)
 	resp := httptest.NewRecorder()
@@ -478,7 +892,7 @@ func TestCheckinBook_Success(t *testing.T) {
 	r.PUT("/books/:id/checkin", handler.CheckinBook)
 
 	bookID := "book-1"
-	mockSvc.On("CheckinBook", mock.Anything, bookID).Return(nil)
+	mockSvc.On("CheckinBook", mock.Anything, bookID, models.StatusChangeRequest{}).Return(nil)
 
 	req := httptest.NewRequest(http.MethodPut, "/books/"+bookID+"/checkin", nil)
 	resp := httptest.NewRecorder()
@@ -498,7 +912,7 @@ func TestCheckinBook_NotFound(t *testing.T) {
 	r.PUT("/books/:id/checkin", handler.CheckinBook)
 
 	bookID := "not-found"
-	mockSvc.On("CheckinBook", mock.Anything, bookID).Return(utils.ErrNotFound)
+	mockSvc.On("CheckinBook", mock.Anything, bookID, models.StatusChangeRequest{}).Return(utils.ErrNotFound)
 
 	req := httptest.NewRequest(http.MethodPut, "/books/"+bookID+"/checkin", nil)
 	resp := httptest.NewRecorder()
@@ -509,6 +923,178 @@ func TestCheckinBook_NotFound(t *testing.T) {
 	mockSvc.AssertExpectations(t)
 }
 
+func TestCheckoutBook_WithBody(t *testing.T) {
+	mockSvc := new(MockBookService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewBookHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.PUT("/books/:id/checkout", handler.CheckoutBook)
+
+	bookID := "book-1"
+	expected := models.StatusChangeRequest{Actor: "jane", Source: models.StatusChangeSourceDesk, Note: "Cover slightly torn"}
+	mockSvc.On("CheckoutBook", mock.Anything, bookID, expected).Return(nil)
+
+	body := `{"actor":"jane","source":"desk","note":"Cover slightly torn"}`
+	req := httptest.NewRequest(http.MethodPut, "/books/"+bookID+"/checkout", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestCheckinBook_InvalidSource(t *testing.T) {
+	mockSvc := new(MockBookService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewBookHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.PUT("/books/:id/checkin", handler.CheckinBook)
+
+	req := httptest.NewRequest(http.MethodPut, "/books/book-1/checkin", strings.NewReader(`{"source":"mail"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	mockSvc.AssertNotCalled(t, "CheckinBook", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCheckoutBook_InvalidTransition(t *testing.T) {
+	mockSvc := new(MockBookService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewBookHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.PUT("/books/:id/checkout", handler.CheckoutBook)
+
+	transition := &utils.TransitionError{From: "lost", To: "checked_out", Allowed: []string{"available", "withdrawn"}}
+	mockSvc.On("CheckoutBook", mock.Anything, "book-1", models.StatusChangeRequest{}).Return(transition)
+
+	req := httptest.NewRequest(http.MethodPut, "/books/book-1/checkout", nil)
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	var decoded models.InvalidTransitionResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &decoded))
+	assert.Equal(t, models.ProblemInvalidTransition, decoded.Code)
+	assert.Equal(t, models.BookStatusLost, decoded.CurrentStatus)
+	assert.Equal(t, []models.BookStatus{models.BookStatusAvailable, models.BookStatusWithdrawn}, decoded.AllowedStatuses)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestCheckinBook_InvalidTransition(t *testing.T) {
+	for _, status := range []models.BookStatus{models.BookStatusLost, models.BookStatusInRepair} {
+		t.Run(string(status), func(t *testing.T) {
+			mockSvc := new(MockBookService)
+			logger := zaptest.NewLogger(t)
+			handler := handlers.NewBookHandler(mockSvc, logger)
+
+			r := gin.New()
+			r.PUT("/books/:id/checkin", handler.CheckinBook)
+
+			transition := &utils.TransitionError{From: string(status), To: "available", Allowed: []string{"available", "withdrawn"}}
+			mockSvc.On("CheckinBook", mock.Anything, "book-1", models.StatusChangeRequest{}).Return(transition)
+
+			req := httptest.NewRequest(http.MethodPut, "/books/book-1/checkin", nil)
+			resp := httptest.NewRecorder()
+
+			r.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusConflict, resp.Code)
+			var decoded models.InvalidTransitionResponse
+			assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &decoded))
+			assert.Equal(t, status, decoded.CurrentStatus)
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMarkBookLost_Success(t *testing.T) {
+	mockSvc := new(MockBookService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewBookHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.PUT("/books/:id/lost", handler.MarkBookLost)
+
+	mockSvc.On("ChangeBookStatus", mock.Anything, "book-1", models.BookStatusLost, models.StatusChangeRequest{Note: "Patron moved away"}).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPut, "/books/book-1/lost", strings.NewReader(`{"note":"Patron moved away"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestMarkBookFound_NotFound(t *testing.T) {
+	mockSvc := new(MockBookService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewBookHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.PUT("/books/:id/found", handler.MarkBookFound)
+
+	mockSvc.On("MarkBookFound", mock.Anything, "not-found", models.StatusChangeRequest{}).Return(utils.ErrNotFound)
+
+	req := httptest.NewRequest(http.MethodPut, "/books/not-found/found", nil)
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestSetBookStatus_Success(t *testing.T) {
+	mockSvc := new(MockBookService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewBookHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.PUT("/books/:id/status", handler.SetBookStatus)
+
+	expected := models.StatusChangeRequest{Actor: "jane", Source: models.StatusChangeSourceDesk}
+	mockSvc.On("ChangeBookStatus", mock.Anything, "book-1", models.BookStatusInRepair, expected).Return(nil)
+
+	body := `{"status":"in_repair","actor":"jane","source":"desk"}`
+	req := httptest.NewRequest(http.MethodPut, "/books/book-1/status", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestSetBookStatus_InvalidStatus(t *testing.T) {
+	mockSvc := new(MockBookService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewBookHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.PUT("/books/:id/status", handler.SetBookStatus)
+
+	req := httptest.NewRequest(http.MethodPut, "/books/book-1/status", strings.NewReader(`{"status":"stolen"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	mockSvc.AssertNotCalled(t, "ChangeBookStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestGetBookWithHistory_Success(t *testing.T) {
 	mockSvc := new(MockBookService)
 	logger := zaptest.NewLogger(t)
@@ -561,3 +1147,60 @@ func TestGetBookWithHistory_NotFound(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, resp.Code)
 	mockSvc.AssertExpectations(t)
 }
+
+func TestListBookHistory_Success(t *testing.T) {
+	mockSvc := new(MockBookService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewBookHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.GET("/books/:id/history", handler.ListBookHistory)
+
+	bookID := "book-1"
+	expectedReq := models.ListHistoryRequest{
+		Page:     2,
+		PageSize: 10,
+		From:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	expected := &models.ListHistoryResponse{
+		History: []models.StatusChangeResponse{
+			{Status: models.BookStatusCheckedOut, Timestamp: time.Now(), Actor: "jane", Source: models.StatusChangeSourceKiosk},
+		},
+		TotalItems:  11,
+		TotalPages:  2,
+		CurrentPage: 2,
+		PageSize:    10,
+	}
+	mockSvc.On("ListBookHistory", mock.Anything, bookID, expectedReq).Return(expected, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/books/"+bookID+"/history?page=2&from=2025-01-01", nil)
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var decoded models.ListHistoryResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &decoded))
+	assert.Equal(t, 11, decoded.TotalItems)
+	assert.Equal(t, "kiosk", string(decoded.History[0].Source))
+	mockSvc.AssertExpectations(t)
+}
+
+func TestListBookHistory_InvalidWindow(t *testing.T) {
+	mockSvc := new(MockBookService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewBookHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.GET("/books/:id/history", handler.ListBookHistory)
+
+	mockSvc.On("ListBookHistory", mock.Anything, "book-1", mock.Anything).Return(nil, fmt.Errorf("%w: from must be before to", utils.ErrBadRequest))
+
+	req := httptest.NewRequest(http.MethodGet, "/books/book-1/history?from=2025-02-01&to=2025-01-01", nil)
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	mockSvc.AssertExpectations(t)
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/services"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// Allowance for multipart boundaries and part headers on top of the cover size limit
+const multipartOverhead = 64 << 10
+
+// Cache policy for cover images: versioned URLs (?v=) never change, unversioned ones are revalidated
+const (
+	coverCacheControl            = "public, max-age=31536000, immutable"
+	unversionedCoverCacheControl = "public, no-cache"
+)
+
+type CoverHandler struct {
+	service services.CoverService
+	logger  *zap.Logger
+}
+
+func NewCoverHandler(service services.CoverService, logger *zap.Logger) *CoverHandler {
+	return &CoverHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// UploadCover godoc
+// @Summary Upload the cover image of a book
+// @Description Replaces the cover of a book with a JPEG, PNG or GIF image (max 4 MB, type detected from the content). Generates small, medium and large JPEG thumbnails and returns the book with its new cover_url.
+// @Tags books
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param file formData file true "Cover image"
+// @Success 200 {object} models.BookResponse
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 404 {object} models.ProblemResponse
+// @Failure 413 {object} models.ProblemResponse
+// @Failure 415 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /books/{id}/cover [put]
+func (h *CoverHandler) UploadCover(c *gin.Context) {
+
+	h.logger.Info("Uploading cover")
+	ctx := c.Request.Context()
+
+	// Extract params
+	id := c.Param("id")
+
+	// Limit request body size
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, models.MaxCoverSize+multipartOverhead)
+
+	// Parse multipart file
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.logger.Warn("Cover too large", zap.String("id", id))
+			problem(c, http.StatusRequestEntityTooLarge, models.ProblemCoverTooLarge)
+			return
+		}
+		h.logger.Warn("Invalid cover upload", zap.Error(err))
+		problem(c, http.StatusBadRequest, models.ProblemMissingCover)
+		return
+	}
+	defer file.Close()
+
+	// Read file (checking size limit)
+	data, err := io.ReadAll(io.LimitReader(file, models.MaxCoverSize+1))
+	if err != nil {
+		h.logger.Warn("Failed to read cover upload", zap.Error(err))
+		problem(c, http.StatusBadRequest, models.ProblemInvalidCover)
+		return
+	}
+	if header.Size > models.MaxCoverSize || len(data) > models.MaxCoverSize {
+		h.logger.Warn("Cover too large", zap.String("id", id), zap.Int64("size", header.Size))
+		problem(c, http.StatusRequestEntityTooLarge, models.ProblemCoverTooLarge)
+		return
+	}
+
+	// Invoke service
+	res, err := h.service.UploadCover(ctx, id, data)
+	if err != nil {
+		h.handleCoverError(c, id, err, "upload")
+		return
+	}
+	h.logger.Info("Cover uploaded successfully", zap.String("id", id), zap.Int("size", len(data)))
+	c.JSON(http.StatusOK, res)
+}
+
+// GetCover godoc
+// @Summary Get the cover image of a book
+// @Description Returns the original cover image, or a generated JPEG thumbnail (size small, medium or large). With S3 storage it redirects to a temporary signed URL of the image instead.
+// @Tags books
+// @Produce image/jpeg,image/png,image/gif
+// @Param id path string true "Book ID"
+// @Param size query string false "Size (original, small, medium, large)" default(original)
+// @Success 200 {file} binary
+// @Success 307 "Redirect to a signed URL of the image (S3 storage)"
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 404 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /books/{id}/cover [get]
+func (h *CoverHandler) GetCover(c *gin.Context) {
+
+	h.logger.Info("Getting cover")
+	ctx := c.Request.Context()
+
+	// Extract params
+	id := c.Param("id")
+	size := c.DefaultQuery("size", models.CoverSizeOriginal)
+
+	// Invoke service
+	cover, err := h.service.GetCover(ctx, id, size)
+	if err != nil {
+		h.handleCoverError(c, id, err, "get")
+		return
+	}
+
+	// Redirect to the signed URL (cached for half its lifetime, so it is still valid when followed)
+	if cover.URL != "" {
+		c.Header("Cache-Control", "private, max-age="+strconv.Itoa(int(cover.URLExpiresIn.Seconds())/2))
+		h.logger.Info("Cover redirected to signed URL", zap.String("id", id), zap.String("size", size))
+		c.Redirect(http.StatusTemporaryRedirect, cover.URL)
+		return
+	}
+
+	// Cache headers (versioned URLs are immutable)
+	cacheControl := unversionedCoverCacheControl
+	if c.Query("v") != "" {
+		cacheControl = coverCacheControl
+	}
+	c.Header("Cache-Control", cacheControl)
+	if !cover.LastModified.IsZero() {
+		c.Header("Last-Modified", cover.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	h.logger.Info("Cover retrieved successfully", zap.String("id", id), zap.String("size", size))
+	c.Data(http.StatusOK, cover.ContentType, cover.Data)
+}
+
+/* Helper functions */
+
+func (h *CoverHandler) handleCoverError(c *gin.Context, id string, err error, action string) {
+
+	// Handle specific errors
+	switch {
+	case errors.Is(err, utils.ErrNotFound): // Book (or cover) not found
+		h.logger.Warn("Cover not found", zap.String("id", id))
+		problem(c, http.StatusNotFound, models.ProblemCoverNotFound)
+	case errors.Is(err, utils.ErrUnsupportedMediaType): // Not a supported image
+		h.logger.Warn("Unsupported cover type", zap.String("id", id), zap.Error(err))
+		problem(c, http.StatusUnsupportedMediaType, models.ProblemUnsupportedCover)
+	case errors.Is(err, utils.ErrBadRequest): // Invalid request (e.g. unknown size)
+		h.logger.Warn("Invalid cover request", zap.String("id", id), zap.Error(err))
+		problemError(c, http.StatusBadRequest, models.ProblemValidationFailed, err)
+	default: // Generic error
+		h.logger.Error("Failed to "+action+" cover",
+			zap.String("id", id),
+			zap.Error(err),
+		)
+		problemDetail(c, http.StatusInternalServerError, models.ProblemInternal, "Failed to "+action+" cover")
+	}
+}
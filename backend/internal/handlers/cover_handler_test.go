@@ -0,0 +1,169 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/santiago-buildit/code-challenge/backend/internal/handlers"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+// MockCoverService implements CoverService for testing
+type MockCoverService struct {
+	mock.Mock
+}
+
+func (m *MockCoverService) UploadCover(ctx context.Context, id string, data []byte) (*models.BookResponse, error) {
+	args := m.Called(ctx, id, data)
+	return args.Get(0).(*models.BookResponse), args.Error(1)
+}
+
+func (m *MockCoverService) GetCover(ctx context.Context, id string, size string) (*models.Cover, error) {
+	args := m.Called(ctx, id, size)
+	return args.Get(0).(*models.Cover), args.Error(1)
+}
+
+// multipartCover builds a multipart body with the given content in the "file" field
+func multipartCover(t *testing.T, content []byte) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", "cover.png")
+	assert.NoError(t, err)
+	_, err = part.Write(content)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	return body, writer.FormDataContentType()
+}
+
+func TestUploadCover_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockCoverService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewCoverHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.PUT("/books/:id/cover", handler.UploadCover)
+
+	content := []byte("\x89PNG\r\n\x1a\n image")
+	mockSvc.On("UploadCover", mock.Anything, "book-1", content).
+		Return(&models.BookResponse{ID: "book-1", CoverURL: "/books/book-1/cover?v=1"}, nil)
+
+	body, contentType := multipartCover(t, content)
+	req := httptest.NewRequest(http.MethodPut, "/books/book-1/cover", body)
+	req.Header.Set("Content-Type", contentType)
+
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"cover_url":"/books/book-1/cover?v=1"`)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestUploadCover_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockCoverService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewCoverHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.PUT("/books/:id/cover", handler.UploadCover)
+
+	mockSvc.On("UploadCover", mock.Anything, "book-1", []byte("%PDF")).
+		Return((*models.BookResponse)(nil), utils.ErrUnsupportedMediaType)
+
+	// Too large (rejected before reaching the service)
+	body, contentType := multipartCover(t, make([]byte, models.MaxCoverSize+1))
+	req := httptest.NewRequest(http.MethodPut, "/books/book-1/cover", body)
+	req.Header.Set("Content-Type", contentType)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+
+	// Missing file
+	req = httptest.NewRequest(http.MethodPut, "/books/book-1/cover", bytes.NewReader([]byte(`{}`)))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	// Not an image
+	body, contentType = multipartCover(t, []byte("%PDF"))
+	req = httptest.NewRequest(http.MethodPut, "/books/book-1/cover", body)
+	req.Header.Set("Content-Type", contentType)
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.Code)
+
+	mockSvc.AssertNumberOfCalls(t, "UploadCover", 1)
+}
+
+func TestGetCover_CacheHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockCoverService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewCoverHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.GET("/books/:id/cover", handler.GetCover)
+
+	modified := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	mockSvc.On("GetCover", mock.Anything, "book-1", "small").
+		Return(&models.Cover{Data: []byte("jpeg"), ContentType: "image/jpeg", LastModified: modified}, nil)
+	mockSvc.On("GetCover", mock.Anything, "book-2", "original").
+		Return((*models.Cover)(nil), utils.ErrNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/books/book-1/cover?size=small&v=1760788800", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "image/jpeg", resp.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=31536000, immutable", resp.Header().Get("Cache-Control"))
+	assert.Equal(t, "Sun, 18 Oct 2026 12:00:00 GMT", resp.Header().Get("Last-Modified"))
+	assert.Equal(t, "jpeg", resp.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/books/book-2/cover", nil) // Default size
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestGetCover_SignedURLRedirect(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockCoverService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewCoverHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.GET("/books/:id/cover", handler.GetCover)
+
+	signedURL := "https://covers.s3.us-east-1.amazonaws.com/covers/book-1/original?X-Amz-Signature=abc"
+	mockSvc.On("GetCover", mock.Anything, "book-1", "original").
+		Return(&models.Cover{URL: signedURL, URLExpiresIn: 15 * time.Minute}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/books/book-1/cover?v=1760788800", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusTemporaryRedirect, resp.Code)
+	assert.Equal(t, signedURL, resp.Header().Get("Location"))
+	assert.Equal(t, "private, max-age=450", resp.Header().Get("Cache-Control")) // Not the immutable policy
+	mockSvc.AssertExpectations(t)
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/services"
+	"go.uber.org/zap"
+)
+
+const (
+	streamHeartbeatInterval = 15 * time.Second // Comment line that keeps idle connections (and proxies) open
+	streamRetryMillis       = 3000             // Client reconnection delay
+)
+
+type EventHandler struct {
+	service     services.EventStreamService // nil when the process serves no live stream (API Lambda)
+	maxDuration time.Duration               // Stream length limit (0 = until the client disconnects)
+	logger      *zap.Logger
+}
+
+func NewEventHandler(service services.EventStreamService, maxDuration time.Duration, logger *zap.Logger) *EventHandler {
+	return &EventHandler{
+		service:     service,
+		maxDuration: maxDuration,
+		logger:      logger,
+	}
+}
+
+// StreamEvents godoc
+// @Summary Stream live book events
+// @Description Server-Sent Events stream of committed book changes (book.created, book.updated, book.deleted, book.checked_out, book.checked_in, book.inventory_changed; book.status_changed matches every status change). Each event has the stream position as id, the type as event name and the BookEvent JSON as data. Reconnecting with the Last-Event-ID header (or last_event_id) first replays the retained events missed since then (including the ones just below it that committed later, so clients skip ids already received). The stream may end after a server-configured duration; clients just reconnect. Not available from the API Lambda (frozen between invocations, and API Gateway buffers the response), which answers 501: use the gRPC WatchBooks call or webhooks there.
+// @Tags events
+// @Produce text/event-stream
+// @Param book_id query string false "Only events of this book"
+// @Param type query []string false "Only events of these types (repeatable)" collectionFormat(multi)
+// @Param last_event_id query int false "Resume after this event (when the Last-Event-ID header cannot be set)"
+// @Param Last-Event-ID header int false "Resume after this event"
+// @Success 200 {string} string "text/event-stream"
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Failure 501 {object} models.ProblemResponse
+// @Router /events/stream [get]
+func (h *EventHandler) StreamEvents(c *gin.Context) {
+
+	h.logger.Info("Streaming events")
+	ctx := c.Request.Context()
+
+	// Live streams need a long-running process
+	if h.service == nil {
+		h.logger.Warn("Event stream not available")
+		problem(c, http.StatusNotImplemented, models.ProblemStreamUnavailable)
+		return
+	}
+
+	// Parse query string
+	var req models.BookEventStreamRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Invalid query parameters", zap.Error(err))
+		bindingProblem(c, err, models.ProblemInvalidQuery)
+		return
+	}
+
+	// Extract resume position (header sent by EventSource on reconnection)
+	if header := c.GetHeader("Last-Event-ID"); header != "" {
+		lastEventID, err := strconv.ParseInt(header, 10, 64)
+		if err != nil || lastEventID < 0 {
+			h.logger.Warn("Invalid Last-Event-ID", zap.String("last_event_id", header))
+			problem(c, http.StatusBadRequest, models.ProblemInvalidLastEventID)
+			return
+		}
+		req.LastEventID = &lastEventID
+	}
+
+	// Sanitize input
+	req.Sanitize()
+
+	// Invoke service
+	sub, err := h.service.Subscribe(ctx, &req)
+	if err != nil {
+		h.logger.Error("Failed to subscribe to events", zap.Error(err))
+		problemDetail(c, http.StatusInternalServerError, models.ProblemInternal, "Failed to subscribe to events")
+		return
+	}
+	defer sub.Close()
+
+	// Start stream
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Disable proxy buffering
+	c.Status(http.StatusOK)
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", streamRetryMillis)
+
+	// Write missed events
+	replayed := make(map[int64]bool, len(sub.Replay))
+	for i := range sub.Replay {
+		writeStreamEvent(c, &sub.Replay[i])
+		replayed[sub.Replay[i].Seq] = true
+	}
+	fmt.Fprintf(c.Writer, "id: %d\n\n", sub.Position) // Sets the client resume position without an event
+	c.Writer.Flush()
+
+	// Write live events until the client leaves, the subscription ends or the duration limit is reached
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+	var deadline <-chan time.Time
+	if h.maxDuration > 0 {
+		timer := time.NewTimer(h.maxDuration)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline:
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": keep-alive\n\n")
+			c.Writer.Flush()
+		case event, ok := <-sub.Events:
+			if !ok {
+				return
+			}
+			if replayed[event.Seq] {
+				continue
+			}
+			writeStreamEvent(c, event)
+			c.Writer.Flush()
+		}
+	}
+}
+
+/* Helper functions */
+
+// writeStreamEvent writes an outbox event in SSE format (the JSON payload is a single line)
+func writeStreamEvent(c *gin.Context, event *models.OutboxEvent) {
+	fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.Seq, event.EventType, event.Payload)
+}
@@ -0,0 +1,114 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/santiago-buildit/code-challenge/backend/internal/handlers"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+// MockEventStreamService implements EventStreamService for testing
+type MockEventStreamService struct {
+	mock.Mock
+}
+
+func (m *MockEventStreamService) Subscribe(ctx context.Context, req *models.BookEventStreamRequest) (*services.BookEventSubscription, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*services.BookEventSubscription), args.Error(1)
+}
+
+func TestStreamEvents_ReplayThenLive(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockEventStreamService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewEventHandler(mockSvc, 0, logger)
+
+	r := gin.New()
+	r.GET("/events/stream", handler.StreamEvents)
+
+	// Live events overlap the replay (duplicates skipped), the stream ends when the channel is closed
+	live := make(chan *models.OutboxEvent, 2)
+	live <- &models.OutboxEvent{Seq: 7, EventType: "book.checked_out", Payload: []byte(`{"id":"e7"}`)}
+	live <- &models.OutboxEvent{Seq: 9, EventType: "book.checked_in", Payload: []byte(`{"id":"e9"}`)}
+	close(live)
+	sub := &services.BookEventSubscription{
+		Replay:   []models.OutboxEvent{{Seq: 7, EventType: "book.checked_out", Payload: []byte(`{"id":"e7"}`)}},
+		Position: 7,
+		Events:   live,
+	}
+
+	// Status change alias is expanded, the header sets the resume position
+	mockSvc.On("Subscribe", mock.Anything, mock.MatchedBy(func(req *models.BookEventStreamRequest) bool {
+		return req.LastEventID != nil && *req.LastEventID == 5 &&
+			assert.ObjectsAreEqual([]string{"book.checked_out", "book.checked_in", "book.inventory_changed"}, req.Types)
+	})).Return(sub, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/events/stream?type=book.status_changed", nil)
+	req.Header.Set("Last-Event-ID", "5")
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "text/event-stream", resp.Header().Get("Content-Type"))
+	body := resp.Body.String()
+	assert.Equal(t, 1, strings.Count(body, "id: 7\nevent: book.checked_out\ndata: {\"id\":\"e7\"}\n\n"))
+	assert.Contains(t, body, "id: 9\nevent: book.checked_in\ndata: {\"id\":\"e9\"}\n\n")
+	mockSvc.AssertExpectations(t)
+}
+
+func TestStreamEvents_InvalidParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockEventStreamService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewEventHandler(mockSvc, 0, logger)
+
+	r := gin.New()
+	r.GET("/events/stream", handler.StreamEvents)
+
+	for _, tc := range []struct {
+		query       string
+		lastEventID string
+	}{
+		{query: "?book_id=not-a-uuid"},
+		{query: "?type=book.renamed"},
+		{query: "", lastEventID: "abc"},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/events/stream"+tc.query, nil)
+		if tc.lastEventID != "" {
+			req.Header.Set("Last-Event-ID", tc.lastEventID)
+		}
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code, tc)
+	}
+	mockSvc.AssertNotCalled(t, "Subscribe", mock.Anything, mock.Anything)
+}
+
+func TestStreamEvents_NotAvailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewEventHandler(nil, 0, logger) // Process without a live stream (API Lambda)
+
+	r := gin.New()
+	r.GET("/events/stream", handler.StreamEvents)
+
+	req := httptest.NewRequest(http.MethodGet, "/events/stream", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotImplemented, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"code":"event_stream_unavailable"`)
+}
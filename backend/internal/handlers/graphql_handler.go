@@ -0,0 +1,583 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	graphqlgo "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/santiago-buildit/code-challenge/backend/internal/graphql"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/services"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// GraphQL error codes (extensions.code)
+const (
+	gqlNotFound       = "NOT_FOUND"
+	gqlBadUserInput   = "BAD_USER_INPUT"
+	gqlConflict       = "CONFLICT"
+	gqlInternalError  = "INTERNAL_SERVER_ERROR"
+	gqlInvalidRequest = "BAD_REQUEST"
+)
+
+// historyLoaderKey is the context key of the per-request history loader
+type historyLoaderKey struct{}
+
+type historyLoader = graphql.Loader[string, []models.StatusChangeResponse]
+
+type GraphQLHandler struct {
+	service services.BookService
+	schema  *graphqlgo.Schema
+	logger  *zap.Logger
+}
+
+func NewGraphQLHandler(service services.BookService, logger *zap.Logger) *GraphQLHandler {
+	h := &GraphQLHandler{
+		service: service,
+		logger:  logger,
+	}
+
+	// Resolvers are checked against the schema (panics when they don't match)
+	h.schema = graphqlgo.MustParseSchema(graphql.Schema, &graphQLResolver{h: h}, graphqlgo.MaxDepth(graphql.MaxDepth))
+	return h
+}
+
+// Execute godoc
+// @Summary Execute a GraphQL request
+// @Description Executes a GraphQL query or mutation over books (see GET /graphql/schema). The history of the listed books is loaded in a single batch. Field errors are returned with data (200), invalid requests without it (400).
+// @Tags graphql
+// @Accept json
+// @Produce json
+// @Param request body graphql.Request true "GraphQL request"
+// @Success 200 {object} map[string]interface{} "GraphQL response (data and errors)"
+// @Failure 400 {object} map[string]interface{} "GraphQL response (errors)"
+// @Router /graphql [post]
+func (h *GraphQLHandler) Execute(c *gin.Context) {
+
+	h.logger.Info("Executing GraphQL request")
+
+	// Parse request body
+	var req graphql.Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid request body", zap.Error(err))
+		c.JSON(http.StatusBadRequest, &graphqlgo.Response{Errors: []*gqlerrors.QueryError{
+			{Message: "Invalid request body", Extensions: map[string]interface{}{"code": gqlInvalidRequest}},
+		}})
+		return
+	}
+
+	// Per-request history loader (batches the history of all the resolved books)
+	ctx := c.Request.Context()
+	loader := graphql.NewLoader(func(ids []string) (map[string][]models.StatusChangeResponse, error) {
+		return h.service.GetBooksHistory(ctx, ids)
+	})
+	ctx = context.WithValue(ctx, historyLoaderKey{}, loader)
+
+	// Execute (no data when the request is not valid against the schema)
+	res := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	if res.Data == nil {
+		h.logger.Warn("Invalid GraphQL request", zap.String("error", res.Errors[0].Message))
+		c.JSON(http.StatusBadRequest, res)
+		return
+	}
+	h.logger.Info("GraphQL request executed", zap.String("operation", req.OperationName), zap.Int("errors", len(res.Errors)))
+	c.JSON(http.StatusOK, res)
+}
+
+// Schema godoc
+// @Summary Get the GraphQL schema
+// @Description Returns the schema of the GraphQL endpoint in SDL (also available through introspection)
+// @Tags graphql
+// @Produce plain
+// @Success 200 {string} string "Schema (SDL)"
+// @Router /graphql/schema [get]
+func (h *GraphQLHandler) Schema(c *gin.Context) {
+	c.String(http.StatusOK, graphql.Schema)
+}
+
+/* Root resolvers */
+
+// graphQLResolver resolves the Query and Mutation fields of the schema
+type graphQLResolver struct {
+	h *GraphQLHandler
+}
+
+func (r *graphQLResolver) Book(ctx context.Context, args struct{ ID graphqlgo.ID }) (*bookResolver, error) {
+	id := string(args.ID)
+	res, err := r.h.service.GetBook(ctx, id)
+	if errors.Is(err, utils.ErrNotFound) { // Nullable field
+		return nil, nil
+	}
+	if err != nil {
+		return nil, r.h.resolverError(id, err, "get")
+	}
+	return &bookResolver{h: r.h, book: res}, nil
+}
+
+func (r *graphQLResolver) Books(ctx context.Context, args struct{ Input *listBooksInput }) (*bookListResolver, error) {
+
+	// Convert and validate input
+	var req models.ListBooksRequest
+	if args.Input != nil {
+		var err error
+		if req, err = args.Input.request(); err != nil {
+			return nil, err
+		}
+	}
+	if req.Page == 0 && !req.IsCursorMode() {
+		req.Page = 1
+	}
+	if err := prepareListRequest(&req); err != nil {
+		return nil, graphql.NewError(gqlBadUserInput, err.Error())
+	}
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return nil, graphql.NewError(gqlBadUserInput, "Invalid input: "+err.Error())
+	}
+
+	// Invoke service
+	res, err := r.h.service.ListBooks(ctx, req)
+	if err != nil {
+		return nil, r.h.resolverError("", err, "list")
+	}
+	return &bookListResolver{h: r.h, list: res}, nil
+}
+
+func (r *graphQLResolver) CreateBook(ctx context.Context, args struct{ Input bookInput }) (*bookResolver, error) {
+	req, err := args.Input.payload()
+	if err != nil {
+		return nil, err
+	}
+	res, err := r.h.service.CreateBook(ctx, req)
+	if err != nil {
+		return nil, r.h.resolverError("", err, "create")
+	}
+	r.h.logger.Info("Book created successfully", zap.String("id", res.ID))
+	return &bookResolver{h: r.h, book: res}, nil
+}
+
+func (r *graphQLResolver) UpdateBook(ctx context.Context, args struct {
+	ID    graphqlgo.ID
+	Input bookInput
+}) (*bookResolver, error) {
+	id := string(args.ID)
+	req, err := args.Input.payload()
+	if err != nil {
+		return nil, err
+	}
+	res, err := r.h.service.UpdateBook(ctx, id, req)
+	if err != nil {
+		return nil, r.h.resolverError(id, err, "update")
+	}
+	r.h.logger.Info("Book updated successfully", zap.String("id", res.ID))
+	return &bookResolver{h: r.h, book: res}, nil
+}
+
+func (r *graphQLResolver) DeleteBook(ctx context.Context, args struct{ ID graphqlgo.ID }) (bool, error) {
+	id := string(args.ID)
+	if err := r.h.service.DeleteBook(ctx, id); err != nil {
+		return false, r.h.resolverError(id, err, "delete")
+	}
+	r.h.logger.Info("Book deleted successfully", zap.String("id", id))
+	return true, nil
+}
+
+func (r *graphQLResolver) CheckoutBook(ctx context.Context, args statusChangeArgs) (*bookResolver, error) {
+	return r.changeStatus(ctx, args, r.h.service.CheckoutBook, "checkout")
+}
+
+func (r *graphQLResolver) CheckinBook(ctx context.Context, args statusChangeArgs) (*bookResolver, error) {
+	return r.changeStatus(ctx, args, r.h.service.CheckinBook, "checkin")
+}
+
+// changeStatus runs a checkout / checkin and returns the updated book
+func (r *graphQLResolver) changeStatus(ctx context.Context, args statusChangeArgs, change func(context.Context, string, models.StatusChangeRequest) error, action string) (*bookResolver, error) {
+	id := string(args.ID)
+	req := models.StatusChangeRequest{Actor: derefString(args.Actor), Source: models.StatusChangeSource(derefString(args.Source)), Note: derefString(args.Note)}
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return nil, graphql.NewError(gqlBadUserInput, "Invalid input: "+err.Error())
+	}
+	if err := change(ctx, id, req); err != nil {
+		return nil, r.h.resolverError(id, err, action)
+	}
+	res, err := r.h.service.GetBook(ctx, id)
+	if err != nil {
+		return nil, r.h.resolverError(id, err, "get")
+	}
+	r.h.logger.Info("Book status changed successfully", zap.String("id", id), zap.String("status", string(res.Status)))
+	return &bookResolver{h: r.h, book: res}, nil
+}
+
+/* Inputs */
+
+// statusChangeArgs are the arguments of the checkout and checkin mutations
+type statusChangeArgs struct {
+	ID     graphqlgo.ID
+	Actor  *string
+	Source *string
+	Note   *string
+}
+
+type bookInput struct {
+	ISBN            string
+	Title           string
+	Author          *string
+	Description     *string
+	Publisher       *string
+	PublicationYear *int32
+	Edition         *string
+	Language        *string
+	PageCount       *int32
+	Format          *string
+	Contributors    *[]contributorInput
+	Tags            *[]string
+}
+
+type contributorInput struct {
+	Name string
+	Role *string
+}
+
+type sortInput struct {
+	Field string
+	Order *string
+}
+
+type listBooksInput struct {
+	Page            *int32
+	PageSize        *int32
+	Sort            *[]sortInput
+	Pagination      *string
+	Cursor          *string
+	SkipTotal       *bool
+	ISBN            *string
+	Title           *string
+	Author          *string
+	Status          *string
+	Text            *string
+	Q               *string
+	ISBNMatch       *string
+	TitleMatch      *string
+	AuthorMatch     *string
+	Tags            *[]string
+	TagsMatch       *string
+	AuthorID        *graphqlgo.ID
+	ContributorRole *string
+	Statuses        *[]string
+	Publisher       *string
+	Language        *string
+	Formats         *[]string
+	YearFrom        *int32
+	YearTo          *int32
+	CreatedFrom     *string
+	CreatedTo       *string
+	UpdatedFrom     *string
+	UpdatedTo       *string
+	Facets          *[]string
+}
+
+// payload converts, sanitizes and validates a book input (omitted tags are kept on update, [] removes them all)
+func (in *bookInput) payload() (models.BookPayload, error) {
+	req := models.BookPayload{
+		ISBN:            in.ISBN,
+		Title:           in.Title,
+		Author:          derefString(in.Author),
+		Description:     derefString(in.Description),
+		Publisher:       derefString(in.Publisher),
+		PublicationYear: derefInt(in.PublicationYear),
+		Edition:         derefString(in.Edition),
+		Language:        derefString(in.Language),
+		PageCount:       derefInt(in.PageCount),
+		Format:          models.BookFormat(derefString(in.Format)),
+	}
+	if in.Contributors != nil {
+		for _, contributor := range *in.Contributors {
+			req.Contributors = append(req.Contributors, models.ContributorPayload{Name: contributor.Name, Role: models.ContributorRole(derefString(contributor.Role))})
+		}
+	}
+	if in.Tags != nil {
+		req.Tags = append([]string{}, *in.Tags...)
+	}
+
+	req.Sanitize()
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return req, graphql.NewError(gqlBadUserInput, "Invalid input: "+err.Error())
+	}
+	return req, nil
+}
+
+// request converts a list input
+func (in *listBooksInput) request() (models.ListBooksRequest, error) {
+	req := models.ListBooksRequest{
+		Page:            derefInt(in.Page),
+		PageSize:        derefInt(in.PageSize),
+		Pagination:      derefString(in.Pagination),
+		Cursor:          derefString(in.Cursor),
+		SkipTotal:       in.SkipTotal != nil && *in.SkipTotal,
+		ISBN:            derefString(in.ISBN),
+		Title:           derefString(in.Title),
+		Author:          derefString(in.Author),
+		Status:          derefString(in.Status),
+		Text:            derefString(in.Text),
+		Q:               derefString(in.Q),
+		ISBNMatch:       derefString(in.ISBNMatch),
+		TitleMatch:      derefString(in.TitleMatch),
+		AuthorMatch:     derefString(in.AuthorMatch),
+		Tags:            derefStrings(in.Tags),
+		TagsMatch:       derefString(in.TagsMatch),
+		ContributorRole: derefString(in.ContributorRole),
+		Statuses:        derefStrings(in.Statuses),
+		Publisher:       derefString(in.Publisher),
+		Language:        derefString(in.Language),
+		Formats:         derefStrings(in.Formats),
+		YearFrom:        derefInt(in.YearFrom),
+		YearTo:          derefInt(in.YearTo),
+		Facets:          derefStrings(in.Facets),
+	}
+	if in.AuthorID != nil {
+		req.AuthorID = string(*in.AuthorID)
+	}
+	if in.Sort != nil {
+		for _, spec := range *in.Sort {
+			req.Sort = append(req.Sort, models.SortSpec{Field: spec.Field, Order: derefString(spec.Order)})
+		}
+	}
+
+	// Date ranges (RFC 3339)
+	for _, date := range []struct {
+		name   string
+		value  *string
+		target **time.Time
+	}{
+		{"createdFrom", in.CreatedFrom, &req.CreatedFrom},
+		{"createdTo", in.CreatedTo, &req.CreatedTo},
+		{"updatedFrom", in.UpdatedFrom, &req.UpdatedFrom},
+		{"updatedTo", in.UpdatedTo, &req.UpdatedTo},
+	} {
+		if date.value == nil {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, *date.value)
+		if err != nil {
+			return req, graphql.NewError(gqlBadUserInput, "Invalid input: "+date.name+" must be an RFC 3339 date")
+		}
+		*date.target = &parsed
+	}
+	return req, nil
+}
+
+/* Object resolvers */
+
+type bookResolver struct {
+	h    *GraphQLHandler
+	book *models.BookResponse
+}
+
+func (r *bookResolver) ID() graphqlgo.ID       { return graphqlgo.ID(r.book.ID) }
+func (r *bookResolver) ISBN() string           { return r.book.ISBN }
+func (r *bookResolver) Title() string          { return r.book.Title }
+func (r *bookResolver) Author() string         { return r.book.Author }
+func (r *bookResolver) Description() string    { return r.book.Description }
+func (r *bookResolver) Status() string         { return string(r.book.Status) }
+func (r *bookResolver) CreatedAt() string      { return formatTime(r.book.CreatedAt) }
+func (r *bookResolver) UpdatedAt() string      { return formatTime(r.book.UpdatedAt) }
+func (r *bookResolver) Tags() []string         { return nonNilStrings(r.book.Tags) }
+func (r *bookResolver) Publisher() string      { return r.book.Publisher }
+func (r *bookResolver) PublicationYear() int32 { return int32(r.book.PublicationYear) }
+func (r *bookResolver) Edition() string        { return r.book.Edition }
+func (r *bookResolver) Language() string       { return r.book.Language }
+func (r *bookResolver) PageCount() int32       { return int32(r.book.PageCount) }
+func (r *bookResolver) Format() *string        { return emptyToNil(string(r.book.Format)) }
+func (r *bookResolver) CoverURL() *string      { return emptyToNil(r.book.CoverURL) }
+
+func (r *bookResolver) Contributors() []*contributorResolver {
+	contributors := make([]*contributorResolver, len(r.book.Contributors))
+	for i := range r.book.Contributors {
+		contributors[i] = &contributorResolver{contributor: &r.book.Contributors[i]}
+	}
+	return contributors
+}
+
+func (r *bookResolver) Highlights() *highlightsResolver {
+	if r.book.Highlights == nil {
+		return nil
+	}
+	return &highlightsResolver{highlights: r.book.Highlights}
+}
+
+// History is loaded with the history of the other resolved books (see bookListResolver.Books)
+func (r *bookResolver) History(ctx context.Context) ([]*statusChangeResolver, error) {
+	history, err := ctx.Value(historyLoaderKey{}).(*historyLoader).Load(r.book.ID)
+	if err != nil {
+		return nil, r.h.resolverError("", err, "get history of")
+	}
+	changes := make([]*statusChangeResolver, len(history))
+	for i := range history {
+		changes[i] = &statusChangeResolver{change: &history[i]}
+	}
+	return changes, nil
+}
+
+type contributorResolver struct {
+	contributor *models.ContributorResponse
+}
+
+func (r *contributorResolver) AuthorID() graphqlgo.ID { return graphqlgo.ID(r.contributor.AuthorID) }
+func (r *contributorResolver) Name() string           { return r.contributor.Name }
+func (r *contributorResolver) Role() string           { return string(r.contributor.Role) }
+
+type highlightsResolver struct {
+	highlights *models.BookHighlights
+}
+
+func (r *highlightsResolver) Title() *string       { return emptyToNil(r.highlights.Title) }
+func (r *highlightsResolver) Description() *string { return emptyToNil(r.highlights.Description) }
+
+type statusChangeResolver struct {
+	change *models.StatusChangeResponse
+}
+
+func (r *statusChangeResolver) Status() string    { return string(r.change.Status) }
+func (r *statusChangeResolver) Timestamp() string { return formatTime(r.change.Timestamp) }
+func (r *statusChangeResolver) Actor() *string    { return emptyToNil(r.change.Actor) }
+func (r *statusChangeResolver) Source() *string   { return emptyToNil(string(r.change.Source)) }
+func (r *statusChangeResolver) Note() *string     { return emptyToNil(r.change.Note) }
+
+type bookListResolver struct {
+	h    *GraphQLHandler
+	list *models.ListBooksResponse
+}
+
+// Books registers the books in the history loader, so their history is fetched in a single batch
+func (r *bookListResolver) Books(ctx context.Context) []*bookResolver {
+	books := make([]*bookResolver, len(r.list.Books))
+	ids := make([]string, len(r.list.Books))
+	for i := range r.list.Books {
+		books[i] = &bookResolver{h: r.h, book: &r.list.Books[i]}
+		ids[i] = r.list.Books[i].ID
+	}
+	ctx.Value(historyLoaderKey{}).(*historyLoader).Prime(ids...)
+	return books
+}
+
+func (r *bookListResolver) TotalItems() int32   { return int32(r.list.TotalItems) }
+func (r *bookListResolver) TotalPages() int32   { return int32(r.list.TotalPages) }
+func (r *bookListResolver) CurrentPage() int32  { return int32(r.list.CurrentPage) }
+func (r *bookListResolver) PageSize() int32     { return int32(r.list.PageSize) }
+func (r *bookListResolver) NextCursor() *string { return emptyToNil(r.list.NextCursor) }
+func (r *bookListResolver) PrevCursor() *string { return emptyToNil(r.list.PrevCursor) }
+func (r *bookListResolver) FuzzyMatch() bool    { return r.list.FuzzyMatch }
+
+func (r *bookListResolver) Suggestions() *[]string {
+	if r.list.Suggestions == nil {
+		return nil
+	}
+	return &r.list.Suggestions
+}
+
+// Facets are a map in REST, a list of named facets (sorted by name) in GraphQL
+func (r *bookListResolver) Facets() *[]*facetResolver {
+	if r.list.Facets == nil {
+		return nil
+	}
+	facets := make([]*facetResolver, 0, len(r.list.Facets))
+	for name, buckets := range r.list.Facets {
+		facets = append(facets, &facetResolver{name: name, buckets: buckets})
+	}
+	slices.SortFunc(facets, func(a, b *facetResolver) int { return strings.Compare(a.name, b.name) })
+	return &facets
+}
+
+type facetResolver struct {
+	name    string
+	buckets []models.FacetBucket
+}
+
+func (r *facetResolver) Name() string { return r.name }
+
+func (r *facetResolver) Buckets() []*facetBucketResolver {
+	buckets := make([]*facetBucketResolver, len(r.buckets))
+	for i := range r.buckets {
+		buckets[i] = &facetBucketResolver{bucket: &r.buckets[i]}
+	}
+	return buckets
+}
+
+type facetBucketResolver struct {
+	bucket *models.FacetBucket
+}
+
+func (r *facetBucketResolver) Value() string { return r.bucket.Value }
+func (r *facetBucketResolver) Count() int32  { return int32(r.bucket.Count) }
+
+/* Helper functions */
+
+// resolverError maps a service error to a GraphQL error (as handleBookError does to a status code)
+func (h *GraphQLHandler) resolverError(id string, err error, action string) error {
+
+	// Handle specific errors
+	if errors.Is(err, utils.ErrNotFound) { // Not found error
+		h.logger.Warn("Book not found", zap.String("id", id))
+		return graphql.NewError(gqlNotFound, "Book not found")
+	} else if errors.Is(err, utils.ErrBadRequest) { // Invalid request (e.g. missing author)
+		h.logger.Warn("Invalid book request", zap.String("id", id), zap.Error(err))
+		return graphql.NewError(gqlBadUserInput, err.Error())
+	} else if errors.Is(err, utils.ErrInvalidTransition) { // Status change not allowed from the current status
+		h.logger.Warn("Invalid book status transition", zap.String("id", id), zap.Error(err))
+		return graphql.NewError(gqlConflict, err.Error())
+	} else { // Generic error
+		h.logger.Error("Failed to "+action+" book",
+			zap.String("id", id),
+			zap.Error(err),
+		)
+		return graphql.NewError(gqlInternalError, "Failed to "+action+" book")
+	}
+}
+
+// formatTime formats a timestamp as encoding/json does (RFC 3339)
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+// emptyToNil returns nil for empty optional strings (null in the response)
+func emptyToNil(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func derefInt(value *int32) int {
+	if value == nil {
+		return 0
+	}
+	return int(*value)
+}
+
+func derefStrings(values *[]string) []string {
+	if values == nil {
+		return nil
+	}
+	return *values
+}
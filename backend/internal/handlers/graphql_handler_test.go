@@ -0,0 +1,190 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/santiago-buildit/code-challenge/backend/internal/graphql"
+	"github.com/santiago-buildit/code-challenge/backend/internal/handlers"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+// postGraphQL executes a GraphQL request against a handler backed by the mock service
+func postGraphQL(t *testing.T, mockSvc *MockBookService, body any) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewGraphQLHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.POST("/graphql", handler.Execute)
+
+	payload, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestGraphQL_ListBooksBatchesHistory(t *testing.T) {
+	mockSvc := new(MockBookService)
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// camelCase input mapped to the list request, defaults applied
+	mockSvc.On("ListBooks", mock.Anything, mock.MatchedBy(func(req models.ListBooksRequest) bool {
+		return req.Page == 1 && req.PageSize == 2 && req.Author == "tolkien" && req.AuthorMatch == "exact" &&
+			len(req.Sort) == 1 && req.Sort[0].Field == "title"
+	})).Return(&models.ListBooksResponse{
+		Books: []models.BookResponse{
+			{ID: "b1", Title: "The Hobbit", Status: models.BookStatusAvailable},
+			{ID: "b2", Title: "The Silmarillion", Status: models.BookStatusCheckedOut},
+		},
+		TotalItems: 2, TotalPages: 1, CurrentPage: 1, PageSize: 2,
+		Facets: map[string][]models.FacetBucket{"status": {{Value: "available", Count: 1}}},
+	}, nil)
+
+	// One history call for every book of the list
+	mockSvc.On("GetBooksHistory", mock.Anything, []string{"b1", "b2"}).Return(map[string][]models.StatusChangeResponse{
+		"b1": {},
+		"b2": {{Status: models.BookStatusCheckedOut, Timestamp: at}},
+	}, nil).Once()
+
+	resp := postGraphQL(t, mockSvc, map[string]any{
+		"query": `query List($author: String) {
+			books(input: {pageSize: 2, author: $author, authorMatch: "exact", sort: [{field: "title"}], facets: ["status"]}) {
+				totalItems
+				books { id title status history { status timestamp } }
+				facets { name buckets { value count } }
+			}
+		}`,
+		"variables": map[string]any{"author": "tolkien"},
+	})
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"data":{"books":{
+		"totalItems":2,
+		"books":[
+			{"id":"b1","title":"The Hobbit","status":"available","history":[]},
+			{"id":"b2","title":"The Silmarillion","status":"checked_out","history":[{"status":"checked_out","timestamp":"2025-01-02T03:04:05Z"}]}
+		],
+		"facets":[{"name":"status","buckets":[{"value":"available","count":1}]}]
+	}}}`, resp.Body.String())
+	mockSvc.AssertExpectations(t)
+}
+
+func TestGraphQL_CheckoutMutation(t *testing.T) {
+	mockSvc := new(MockBookService)
+
+	mockSvc.On("CheckoutBook", mock.Anything, "b1", models.StatusChangeRequest{}).Return(nil)
+	mockSvc.On("GetBook", mock.Anything, "b1").Return(&models.BookResponse{ID: "b1", Status: models.BookStatusCheckedOut}, nil)
+
+	resp := postGraphQL(t, mockSvc, map[string]any{
+		"query": `mutation { checkoutBook(id: "b1") { id status } }`,
+	})
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"data":{"checkoutBook":{"id":"b1","status":"checked_out"}}}`, resp.Body.String())
+	mockSvc.AssertExpectations(t)
+}
+
+func TestGraphQL_CheckinMutationWithArguments(t *testing.T) {
+	mockSvc := new(MockBookService)
+
+	mockSvc.On("CheckinBook", mock.Anything, "b1", models.StatusChangeRequest{Actor: "jane", Source: models.StatusChangeSourceKiosk}).Return(nil)
+	mockSvc.On("GetBook", mock.Anything, "b1").Return(&models.BookResponse{ID: "b1", Status: models.BookStatusAvailable}, nil)
+
+	resp := postGraphQL(t, mockSvc, map[string]any{
+		"query": `mutation { checkinBook(id: "b1", actor: "jane", source: kiosk) { id status } }`,
+	})
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"data":{"checkinBook":{"id":"b1","status":"available"}}}`, resp.Body.String())
+	mockSvc.AssertExpectations(t)
+}
+
+func TestGraphQL_CreateBookInvalidInput(t *testing.T) {
+	mockSvc := new(MockBookService)
+
+	resp := postGraphQL(t, mockSvc, map[string]any{
+		"query": `mutation { createBook(input: {isbn: "123", title: "  "}) { id } }`,
+	})
+
+	// Field error, validated before calling the service (the null of the non-null field propagates to data)
+	var body map[string]any
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Contains(t, body, "data")
+	assert.Nil(t, body["data"])
+	assert.Contains(t, resp.Body.String(), `"code":"BAD_USER_INPUT"`)
+	mockSvc.AssertNotCalled(t, "CreateBook", mock.Anything, mock.Anything)
+}
+
+func TestGraphQL_ErrorCodes(t *testing.T) {
+	mockSvc := new(MockBookService)
+
+	mockSvc.On("GetBook", mock.Anything, "missing").Return(nil, utils.ErrNotFound)
+	mockSvc.On("DeleteBook", mock.Anything, "missing").Return(utils.ErrNotFound)
+
+	resp := postGraphQL(t, mockSvc, map[string]any{
+		"query": `{ book(id: "missing") { id } }`,
+	})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"data":{"book":null}}`, resp.Body.String()) // Nullable lookup
+
+	resp = postGraphQL(t, mockSvc, map[string]any{
+		"query": `mutation { deleteBook(id: "missing") }`,
+	})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{
+		"data":null,
+		"errors":[{"message":"Book not found","path":["deleteBook"],"extensions":{"code":"NOT_FOUND"}}]
+	}`, resp.Body.String())
+}
+
+func TestGraphQL_InvalidRequest(t *testing.T) {
+	mockSvc := new(MockBookService)
+
+	for _, body := range []any{
+		"not an object",
+		map[string]any{"query": `{ book(id: "b1") { id unknown } }`},
+		map[string]any{"query": `{ books(input: {pageSize: 2}) { books { id } `},
+		map[string]any{"query": `mutation { checkinBook(id: "b1", source: "kiosk") { id } }`}, // Enum, not a string
+		map[string]any{"query": `{ books(input: {pageSize: "2"}) { totalItems } }`},
+	} {
+		resp := postGraphQL(t, mockSvc, body)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code, body)
+		assert.NotContains(t, resp.Body.String(), `"data"`, body)
+	}
+	mockSvc.AssertNotCalled(t, "GetBook", mock.Anything, mock.Anything)
+}
+
+func TestGraphQL_Schema(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.GET("/graphql/schema", handlers.NewGraphQLHandler(new(MockBookService), zaptest.NewLogger(t)).Schema)
+	req := httptest.NewRequest(http.MethodGet, "/graphql/schema", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, graphql.Schema, resp.Body.String())
+
+	// Introspection of the same schema
+	resp = postGraphQL(t, new(MockBookService), map[string]any{
+		"query": `{ __type(name: "StatusChangeSource") { enumValues { name } } }`,
+	})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"data":{"__type":{"enumValues":[{"name":"desk"},{"name":"kiosk"},{"name":"api"}]}}}`, resp.Body.String())
+}
@@ -0,0 +1,454 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/santiago-buildit/code-challenge/backend/internal/grpc/librarypb"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/services"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// LibraryGRPCHandler implements the gRPC LibraryService (proto/library/v1/library.proto) over the Book Service
+type LibraryGRPCHandler struct {
+	librarypb.UnimplementedLibraryServiceServer
+
+	service     services.BookService
+	eventStream services.EventStreamService // nil when the process serves no live stream
+	logger      *zap.Logger
+}
+
+func NewLibraryGRPCHandler(service services.BookService, eventStream services.EventStreamService, logger *zap.Logger) *LibraryGRPCHandler {
+	return &LibraryGRPCHandler{
+		service:     service,
+		eventStream: eventStream,
+		logger:      logger,
+	}
+}
+
+// Register adds the LibraryService to a gRPC server
+func (h *LibraryGRPCHandler) Register(server grpc.ServiceRegistrar) {
+	librarypb.RegisterLibraryServiceServer(server, h)
+}
+
+func (h *LibraryGRPCHandler) CreateBook(ctx context.Context, in *librarypb.BookInput) (*librarypb.Book, error) {
+
+	h.logger.Info("Creating book (gRPC)")
+
+	// Convert and validate input
+	req, err := bookPayloadFromPB(in, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invoke service
+	res, err := h.service.CreateBook(ctx, req)
+	if err != nil {
+		return nil, h.grpcBookError("", err, "create")
+	}
+	h.logger.Info("Book created successfully", zap.String("id", res.ID))
+	return bookToPB(res), nil
+}
+
+func (h *LibraryGRPCHandler) ListBooks(ctx context.Context, in *librarypb.ListBooksRequest) (*librarypb.ListBooksResponse, error) {
+
+	h.logger.Info("Listing books (gRPC)")
+
+	// Convert and validate input
+	req := listRequestFromPB(in)
+	if req.Page == 0 && !req.IsCursorMode() {
+		req.Page = 1
+	}
+	if err := validateListRequest(&req); err != nil {
+		return nil, err
+	}
+
+	// Invoke service
+	res, err := h.service.ListBooks(ctx, req)
+	if err != nil {
+		return nil, h.grpcBookError("", err, "list")
+	}
+	out := &librarypb.ListBooksResponse{
+		Books:       make([]*librarypb.Book, len(res.Books)),
+		TotalItems:  int32(res.TotalItems),
+		TotalPages:  int32(res.TotalPages),
+		CurrentPage: int32(res.CurrentPage),
+		PageSize:    int32(res.PageSize),
+		NextCursor:  res.NextCursor,
+		PrevCursor:  res.PrevCursor,
+	}
+	for i := range res.Books {
+		out.Books[i] = bookToPB(&res.Books[i])
+	}
+	h.logger.Info("Books listed successfully", zap.Int("count", len(res.Books)))
+	return out, nil
+}
+
+func (h *LibraryGRPCHandler) GetBook(ctx context.Context, in *librarypb.BookRef) (*librarypb.Book, error) {
+
+	h.logger.Info("Getting book (gRPC)")
+
+	// Validate input
+	if in.GetId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Missing book ID")
+	}
+
+	// Invoke service
+	res, err := h.service.GetBook(ctx, in.GetId())
+	if err != nil {
+		return nil, h.grpcBookError(in.GetId(), err, "get")
+	}
+	return bookToPB(res), nil
+}
+
+func (h *LibraryGRPCHandler) UpdateBook(ctx context.Context, in *librarypb.UpdateBookRequest) (*librarypb.Book, error) {
+
+	h.logger.Info("Updating book (gRPC)")
+
+	// Convert and validate input
+	if in.GetId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Missing book ID")
+	}
+	if in.GetBook() == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Missing book")
+	}
+	req, err := bookPayloadFromPB(in.GetBook(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invoke service
+	res, err := h.service.UpdateBook(ctx, in.GetId(), req)
+	if err != nil {
+		return nil, h.grpcBookError(in.GetId(), err, "update")
+	}
+	h.logger.Info("Book updated successfully", zap.String("id", res.ID))
+	return bookToPB(res), nil
+}
+
+func (h *LibraryGRPCHandler) DeleteBook(ctx context.Context, in *librarypb.BookRef) (*librarypb.Empty, error) {
+
+	h.logger.Info("Deleting book (gRPC)")
+
+	// Validate input
+	if in.GetId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Missing book ID")
+	}
+
+	// Invoke service
+	if err := h.service.DeleteBook(ctx, in.GetId()); err != nil {
+		return nil, h.grpcBookError(in.GetId(), err, "delete")
+	}
+	h.logger.Info("Book deleted successfully", zap.String("id", in.GetId()))
+	return &librarypb.Empty{}, nil
+}
+
+func (h *LibraryGRPCHandler) CheckoutBook(ctx context.Context, in *librarypb.StatusChangeRequest) (*librarypb.Empty, error) {
+
+	h.logger.Info("Checking out book (gRPC)")
+
+	// Validate input
+	req, err := statusChangeRequestFromPB(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invoke service
+	if err := h.service.CheckoutBook(ctx, in.GetId(), req); err != nil {
+		return nil, h.grpcBookError(in.GetId(), err, "checkout")
+	}
+	h.logger.Info("Book checked out successfully", zap.String("id", in.GetId()))
+	return &librarypb.Empty{}, nil
+}
+
+func (h *LibraryGRPCHandler) CheckinBook(ctx context.Context, in *librarypb.StatusChangeRequest) (*librarypb.Empty, error) {
+
+	h.logger.Info("Checking in book (gRPC)")
+
+	// Validate input
+	req, err := statusChangeRequestFromPB(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invoke service
+	if err := h.service.CheckinBook(ctx, in.GetId(), req); err != nil {
+		return nil, h.grpcBookError(in.GetId(), err, "checkin")
+	}
+	h.logger.Info("Book checked in successfully", zap.String("id", in.GetId()))
+	return &librarypb.Empty{}, nil
+}
+
+func (h *LibraryGRPCHandler) GetBookWithHistory(ctx context.Context, in *librarypb.BookRef) (*librarypb.BookDetail, error) {
+
+	h.logger.Info("Getting book with history (gRPC)")
+
+	// Validate input
+	if in.GetId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Missing book ID")
+	}
+
+	// Invoke service
+	res, err := h.service.GetBookWithHistory(ctx, in.GetId())
+	if err != nil {
+		return nil, h.grpcBookError(in.GetId(), err, "get")
+	}
+	out := &librarypb.BookDetail{
+		Book:    bookToPB(&res.Book),
+		History: make([]*librarypb.StatusChange, len(res.History)),
+	}
+	for i, change := range res.History {
+		out.History[i] = &librarypb.StatusChange{
+			Status:    string(change.Status),
+			Timestamp: timestamppb.New(change.Timestamp),
+			Actor:     change.Actor,
+			Source:    string(change.Source),
+			Note:      change.Note,
+		}
+	}
+	return out, nil
+}
+
+// ExportBooks streams every matching book, walking the result set with keyset cursors
+func (h *LibraryGRPCHandler) ExportBooks(in *librarypb.ListBooksRequest, stream librarypb.LibraryService_ExportBooksServer) error {
+
+	h.logger.Info("Exporting books (gRPC)")
+	ctx := stream.Context()
+
+	// Convert and validate input (largest pages, no totals)
+	req := listRequestFromPB(in)
+	req.Page, req.PageSize, req.Pagination, req.Cursor, req.SkipTotal = 0, maxPageSize, models.PaginationCursor, "", true
+	if err := validateListRequest(&req); err != nil {
+		return err
+	}
+
+	// Stream pages
+	count := 0
+	for {
+		res, err := h.service.ListBooks(ctx, req)
+		if err != nil {
+			return h.grpcBookError("", err, "export")
+		}
+		for i := range res.Books {
+			if err := stream.Send(bookToPB(&res.Books[i])); err != nil {
+				return err
+			}
+		}
+		count += len(res.Books)
+		if res.NextCursor == "" {
+			break
+		}
+		req.Cursor = res.NextCursor
+	}
+	h.logger.Info("Books exported successfully", zap.Int("count", count))
+	return nil
+}
+
+// WatchBooks streams committed book events (missed ones after last_event_id first)
+func (h *LibraryGRPCHandler) WatchBooks(in *librarypb.WatchBooksRequest, stream librarypb.LibraryService_WatchBooksServer) error {
+
+	h.logger.Info("Watching books (gRPC)")
+	ctx := stream.Context()
+
+	// Live streams need a long-running process
+	if h.eventStream == nil {
+		return status.Errorf(codes.Unimplemented, "Live event stream not available")
+	}
+
+	// Convert and validate input
+	req := models.BookEventStreamRequest{BookID: in.GetBookId(), Types: in.GetTypes(), LastEventID: in.LastEventId}
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid request: %v", err)
+	}
+	req.Sanitize()
+
+	// Invoke service
+	sub, err := h.eventStream.Subscribe(ctx, &req)
+	if err != nil {
+		h.logger.Error("Failed to subscribe to events", zap.Error(err))
+		return status.Errorf(codes.Internal, "Failed to subscribe to events")
+	}
+	defer sub.Close()
+
+	// Send missed events
+	replayed := make(map[int64]bool, len(sub.Replay))
+	for i := range sub.Replay {
+		if err := stream.Send(eventToPB(&sub.Replay[i])); err != nil {
+			return err
+		}
+		replayed[sub.Replay[i].Seq] = true
+	}
+
+	// Send live events until the client leaves or the subscription ends
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case event, ok := <-sub.Events:
+			if !ok {
+				return status.Errorf(codes.Unavailable, "Event stream closed, resume after the last event received")
+			}
+			if replayed[event.Seq] {
+				continue
+			}
+			if err := stream.Send(eventToPB(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+/* Helper functions */
+
+// grpcBookError maps a service error to a gRPC status (as handleBookError does to an HTTP status)
+func (h *LibraryGRPCHandler) grpcBookError(id string, err error, action string) error {
+
+	// Handle specific errors
+	if errors.Is(err, utils.ErrNotFound) { // Not found error
+		h.logger.Warn("Book not found", zap.String("id", id))
+		return status.Errorf(codes.NotFound, "Book not found")
+	} else if errors.Is(err, utils.ErrBadRequest) { // Invalid request (e.g. missing author)
+		h.logger.Warn("Invalid book request", zap.String("id", id), zap.Error(err))
+		return status.Errorf(codes.InvalidArgument, "%s", err.Error())
+	} else if errors.Is(err, utils.ErrInvalidTransition) { // Status change not allowed from the current status
+		h.logger.Warn("Invalid book status transition", zap.String("id", id), zap.Error(err))
+		return status.Errorf(codes.FailedPrecondition, "%s", err.Error())
+	} else if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) { // Client left or deadline expired
+		return status.FromContextError(err).Err()
+	} else { // Generic error
+		h.logger.Error("Failed to "+action+" book",
+			zap.String("id", id),
+			zap.Error(err),
+		)
+		return status.Errorf(codes.Internal, "Failed to %s book", action)
+	}
+}
+
+// validateListRequest applies the list defaults and validates the request as the REST binding does
+func validateListRequest(req *models.ListBooksRequest) error {
+	if err := prepareListRequest(req); err != nil {
+		return status.Errorf(codes.InvalidArgument, "%s", err.Error())
+	}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid request: %v", err)
+	}
+	return nil
+}
+
+// bookPayloadFromPB converts, sanitizes and validates a book input (tags are kept on update unless replaced)
+func bookPayloadFromPB(in *librarypb.BookInput, update bool) (models.BookPayload, error) {
+	req := models.BookPayload{
+		ISBN:            in.GetIsbn(),
+		Title:           in.GetTitle(),
+		Author:          in.GetAuthor(),
+		Description:     in.GetDescription(),
+		Publisher:       in.GetPublisher(),
+		PublicationYear: int(in.GetPublicationYear()),
+		Edition:         in.GetEdition(),
+		Language:        in.GetLanguage(),
+		PageCount:       int(in.GetPageCount()),
+		Format:          models.BookFormat(in.GetFormat()),
+		Tags:            in.GetTags(),
+	}
+	for _, contributor := range in.GetContributors() {
+		req.Contributors = append(req.Contributors, models.ContributorPayload{Name: contributor.GetName(), Role: models.ContributorRole(contributor.GetRole())})
+	}
+	if update && !in.GetReplaceTags() {
+		req.Tags = nil
+	} else if in.GetReplaceTags() && req.Tags == nil {
+		req.Tags = []string{}
+	}
+
+	req.Sanitize()
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return req, status.Errorf(codes.InvalidArgument, "Invalid book: %v", err)
+	}
+	return req, nil
+}
+
+// statusChangeRequestFromPB converts and validates a checkout / checkin request
+func statusChangeRequestFromPB(ctx context.Context, in *librarypb.StatusChangeRequest) (models.StatusChangeRequest, error) {
+	req := models.StatusChangeRequest{Actor: in.GetActor(), Source: models.StatusChangeSource(in.GetSource()), Note: in.GetNote()}
+	if req.Actor == "" {
+		req.Actor = APIKeyClient(ctx)
+	}
+	if in.GetId() == "" {
+		return req, status.Errorf(codes.InvalidArgument, "Missing book ID")
+	}
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return req, status.Errorf(codes.InvalidArgument, "Invalid request: %v", err)
+	}
+	return req, nil
+}
+
+// listRequestFromPB converts a list request
+func listRequestFromPB(in *librarypb.ListBooksRequest) models.ListBooksRequest {
+	req := models.ListBooksRequest{
+		Page:       int(in.GetPage()),
+		PageSize:   int(in.GetPageSize()),
+		Pagination: in.GetPagination(),
+		Cursor:     in.GetCursor(),
+		SkipTotal:  in.GetSkipTotal(),
+		ISBN:       in.GetIsbn(),
+		Title:      in.GetTitle(),
+		Author:     in.GetAuthor(),
+		Text:       in.GetText(),
+		Q:          in.GetQ(),
+		Statuses:   in.GetStatuses(),
+		Tags:       in.GetTags(),
+		TagsMatch:  in.GetTagsMatch(),
+		AuthorID:   in.GetAuthorId(),
+		Publisher:  in.GetPublisher(),
+		Language:   in.GetLanguage(),
+		Formats:    in.GetFormats(),
+		YearFrom:   int(in.GetYearFrom()),
+		YearTo:     int(in.GetYearTo()),
+	}
+	for _, spec := range in.GetSort() {
+		req.Sort = append(req.Sort, models.SortSpec{Field: spec.GetField(), Order: spec.GetOrder()})
+	}
+	return req
+}
+
+func bookToPB(book *models.BookResponse) *librarypb.Book {
+	out := &librarypb.Book{
+		Id:              book.ID,
+		Isbn:            book.ISBN,
+		Title:           book.Title,
+		Author:          book.Author,
+		Description:     book.Description,
+		Status:          string(book.Status),
+		CreatedAt:       timestamppb.New(book.CreatedAt),
+		UpdatedAt:       timestamppb.New(book.UpdatedAt),
+		Tags:            book.Tags,
+		Publisher:       book.Publisher,
+		PublicationYear: int32(book.PublicationYear),
+		Edition:         book.Edition,
+		Language:        book.Language,
+		PageCount:       int32(book.PageCount),
+		Format:          string(book.Format),
+		CoverUrl:        book.CoverURL,
+	}
+	for _, contributor := range book.Contributors {
+		out.Contributors = append(out.Contributors, &librarypb.Contributor{AuthorId: contributor.AuthorID, Name: contributor.Name, Role: string(contributor.Role)})
+	}
+	return out
+}
+
+func eventToPB(event *models.OutboxEvent) *librarypb.BookEvent {
+	return &librarypb.BookEvent{
+		Seq:        event.Seq,
+		Id:         event.ID,
+		Type:       event.EventType,
+		BookId:     event.AggregateID,
+		OccurredAt: timestamppb.New(event.CreatedAt),
+		Payload:    event.Payload,
+	}
+}
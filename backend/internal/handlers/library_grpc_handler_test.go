@@ -0,0 +1,194 @@
+package handlers_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/santiago-buildit/code-challenge/backend/internal/grpc/librarypb"
+	"github.com/santiago-buildit/code-challenge/backend/internal/handlers"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/services"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newLibraryClient serves the LibraryService in memory and returns a client of it
+func newLibraryClient(t *testing.T, bookSvc *MockBookService, eventSvc *MockEventStreamService, opts ...grpc.ServerOption) librarypb.LibraryServiceClient {
+	logger := zaptest.NewLogger(t)
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(opts...)
+	handlers.NewLibraryGRPCHandler(bookSvc, eventSvc, logger).Register(server)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return librarypb.NewLibraryServiceClient(conn)
+}
+
+// watchBooks receives the events of a WatchBooks call until the stream ends
+func watchBooks(ctx context.Context, client librarypb.LibraryServiceClient, req *librarypb.WatchBooksRequest) ([]*librarypb.BookEvent, error) {
+	stream, err := client.WatchBooks(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var events []*librarypb.BookEvent
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return events, err
+		}
+		events = append(events, event)
+	}
+}
+
+func TestLibraryGRPC_GetBook(t *testing.T) {
+	bookSvc := new(MockBookService)
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	bookSvc.On("GetBook", mock.Anything, "b1").Return(&models.BookResponse{
+		ID: "b1", Title: "The Hobbit", Status: models.BookStatusAvailable, CreatedAt: createdAt,
+		Contributors: []models.ContributorResponse{{AuthorID: "a1", Name: "J.R.R. Tolkien", Role: models.ContributorRoleAuthor}},
+	}, nil)
+	bookSvc.On("GetBook", mock.Anything, "missing").Return(nil, utils.ErrNotFound)
+
+	client := newLibraryClient(t, bookSvc, nil)
+	ctx := context.Background()
+
+	book, err := client.GetBook(ctx, &librarypb.BookRef{Id: "b1"})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, "The Hobbit", book.GetTitle())
+		assert.Equal(t, "available", book.GetStatus())
+		assert.Equal(t, createdAt, book.GetCreatedAt().AsTime())
+		if assert.Len(t, book.GetContributors(), 1) {
+			contributor := book.GetContributors()[0]
+			assert.Equal(t, []string{"a1", "J.R.R. Tolkien", "author"}, []string{contributor.GetAuthorId(), contributor.GetName(), contributor.GetRole()})
+		}
+	}
+
+	// Error mapping
+	_, err = client.GetBook(ctx, &librarypb.BookRef{Id: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.GetBook(ctx, &librarypb.BookRef{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestLibraryGRPC_UpdateBookKeepsTags(t *testing.T) {
+	bookSvc := new(MockBookService)
+
+	// Tags are kept (nil) unless replaced
+	bookSvc.On("UpdateBook", mock.Anything, "b1", mock.MatchedBy(func(req models.UpdateBookRequest) bool {
+		return req.Title == "The Hobbit" && req.Tags == nil
+	})).Return(&models.BookResponse{ID: "b1", Title: "The Hobbit"}, nil)
+	bookSvc.On("UpdateBook", mock.Anything, "b2", mock.MatchedBy(func(req models.UpdateBookRequest) bool {
+		return req.Tags != nil && len(req.Tags) == 0
+	})).Return(&models.BookResponse{ID: "b2"}, nil)
+
+	client := newLibraryClient(t, bookSvc, nil)
+	ctx := context.Background()
+
+	input := &librarypb.BookInput{Isbn: "9780261103344", Title: " The Hobbit ", Author: "Tolkien", Tags: []string{"ignored"}}
+	_, err := client.UpdateBook(ctx, &librarypb.UpdateBookRequest{Id: "b1", Book: input})
+	assert.NoError(t, err)
+
+	replace := &librarypb.BookInput{Isbn: "9780261103344", Title: "The Hobbit", Author: "Tolkien", ReplaceTags: true}
+	_, err = client.UpdateBook(ctx, &librarypb.UpdateBookRequest{Id: "b2", Book: replace})
+	assert.NoError(t, err)
+	bookSvc.AssertExpectations(t)
+}
+
+func TestLibraryGRPC_CreateBookInvalid(t *testing.T) {
+	bookSvc := new(MockBookService)
+
+	client := newLibraryClient(t, bookSvc, nil)
+
+	_, err := client.CreateBook(context.Background(), &librarypb.BookInput{Isbn: "9780261103344", Author: "Tolkien"})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	bookSvc.AssertNotCalled(t, "CreateBook", mock.Anything, mock.Anything)
+}
+
+func TestLibraryGRPC_ExportBooksWalksCursors(t *testing.T) {
+	bookSvc := new(MockBookService)
+
+	// Largest cursor pages without totals, filters kept
+	bookSvc.On("ListBooks", mock.Anything, mock.MatchedBy(func(req models.ListBooksRequest) bool {
+		return req.IsCursorMode() && req.SkipTotal && req.PageSize == 100 && req.Author == "tolkien" && req.Cursor == ""
+	})).Return(&models.ListBooksResponse{Books: []models.BookResponse{{ID: "b1"}, {ID: "b2"}}, NextCursor: "next"}, nil)
+	bookSvc.On("ListBooks", mock.Anything, mock.MatchedBy(func(req models.ListBooksRequest) bool {
+		return req.Cursor == "next"
+	})).Return(&models.ListBooksResponse{Books: []models.BookResponse{{ID: "b3"}}}, nil)
+
+	client := newLibraryClient(t, bookSvc, nil)
+
+	stream, err := client.ExportBooks(context.Background(), &librarypb.ListBooksRequest{Author: "tolkien", Page: 7})
+
+	assert.NoError(t, err)
+	var ids []string
+	for {
+		book, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(t, err) {
+			break
+		}
+		ids = append(ids, book.GetId())
+	}
+	assert.Equal(t, []string{"b1", "b2", "b3"}, ids)
+	bookSvc.AssertExpectations(t)
+}
+
+func TestLibraryGRPC_WatchBooks(t *testing.T) {
+	eventSvc := new(MockEventStreamService)
+	lastEventID := int64(5)
+
+	// Replay then live events (duplicates skipped); the stream ends when the subscription is closed
+	live := make(chan *models.OutboxEvent, 2)
+	live <- &models.OutboxEvent{Seq: 7, ID: "e7", EventType: "book.checked_out", AggregateID: "b1"}
+	live <- &models.OutboxEvent{Seq: 9, ID: "e9", EventType: "book.checked_in", AggregateID: "b1", Payload: []byte(`{"id":"e9"}`)}
+	close(live)
+	sub := &services.BookEventSubscription{
+		Replay:   []models.OutboxEvent{{Seq: 7, ID: "e7", EventType: "book.checked_out", AggregateID: "b1"}},
+		Position: 7,
+		Events:   live,
+	}
+	eventSvc.On("Subscribe", mock.Anything, mock.MatchedBy(func(req *models.BookEventStreamRequest) bool {
+		return *req.LastEventID == 5 && assert.ObjectsAreEqual([]string{"book.checked_out", "book.checked_in", "book.inventory_changed"}, req.Types)
+	})).Return(sub, nil)
+
+	client := newLibraryClient(t, nil, eventSvc)
+	ctx := context.Background()
+
+	events, err := watchBooks(ctx, client, &librarypb.WatchBooksRequest{
+		Types:       []string{"book.status_changed"},
+		LastEventId: &lastEventID,
+	})
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, int64(7), events[0].GetSeq())
+		assert.Equal(t, "e9", events[1].GetId())
+		assert.Equal(t, []byte(`{"id":"e9"}`), events[1].GetPayload())
+	}
+
+	// Invalid filters
+	_, err = watchBooks(ctx, client, &librarypb.WatchBooksRequest{BookId: "not-a-uuid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
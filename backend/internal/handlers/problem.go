@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/santiago-buildit/code-challenge/backend/internal/i18n"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+)
+
+const mimeProblemJSON = "application/problem+json"
+
+func init() {
+
+	// Name fields as clients send them (JSON, or query string) in validation errors
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			for _, tag := range []string{"json", "form"} {
+				name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+				if name == "-" {
+					return ""
+				}
+				if name != "" {
+					return name
+				}
+			}
+			return field.Name // Embedded structs (removed from the path)
+		})
+	}
+}
+
+// problem writes an RFC 7807 problem (with the catalog detail of its code) and aborts the request
+func problem(c *gin.Context, status int, code models.ProblemCode) {
+	writeProblem(c, status, newProblem(c, status, code, nil))
+}
+
+// problemDetail writes a problem whose detail includes a runtime English detail (e.g. the failed action)
+func problemDetail(c *gin.Context, status int, code models.ProblemCode, detail string) {
+	writeProblem(c, status, newProblem(c, status, code, i18n.Params{"detail": detail}))
+}
+
+// problemError writes a problem whose detail is an error, localized when it has a catalog key (e.g. service validation errors)
+func problemError(c *gin.Context, status int, code models.ProblemCode, err error) {
+	writeProblem(c, status, newProblem(c, status, code, i18n.Params{"detail": i18n.Describe(language(c), err)}))
+}
+
+// bindingProblem writes a 400 problem with the field errors of a failed binding
+func bindingProblem(c *gin.Context, err error, code models.ProblemCode) {
+	res := newProblem(c, http.StatusBadRequest, code, nil)
+	res.Errors = fieldErrors(language(c), err)
+	writeProblem(c, http.StatusBadRequest, res)
+}
+
+// RouteNotFound is the problem of unknown routes (NoRoute handler)
+func RouteNotFound(c *gin.Context) {
+	problem(c, http.StatusNotFound, models.ProblemRouteNotFound)
+}
+
+// newProblem builds the problem of the current request, with the detail in the negotiated language
+func newProblem(c *gin.Context, status int, code models.ProblemCode, params i18n.Params) models.ProblemResponse {
+	return models.ProblemResponse{
+		Type:      "about:blank", // Problems are identified by code
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    i18n.Text(language(c), string(code), params),
+		Instance:  c.Request.URL.Path,
+		Code:      code,
+		RequestID: c.GetString(RequestIDKey),
+	}
+}
+
+// writeProblem writes a problem (or a problem with extension members) with its media type
+func writeProblem(c *gin.Context, status int, res any) {
+	c.Header("Content-Type", mimeProblemJSON) // Kept by the JSON render
+	c.AbortWithStatusJSON(status, res)
+}
+
+// message writes a success message in the negotiated language
+func message(c *gin.Context, key string) {
+	c.JSON(http.StatusOK, models.MessageResponse{Message: i18n.Text(language(c), key, nil)})
+}
+
+// language negotiates the language of the response (Accept-Language) and declares it in the response headers
+func language(c *gin.Context) string {
+	lang := i18n.Negotiate(c.GetHeader("Accept-Language"))
+	c.Header("Content-Language", lang)
+	c.Header("Vary", "Accept-Language")
+	return lang
+}
+
+// fieldErrors translates validator and JSON type errors to field errors (empty for other errors, e.g. malformed JSON)
+func fieldErrors(lang string, err error) []models.FieldError {
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &validationErrs):
+		res := make([]models.FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			field := fieldPath(fe.Namespace())
+			res = append(res, models.FieldError{Field: field, Rule: fe.Tag(), Message: ruleMessage(lang, field, fe)})
+		}
+		return res
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		text := i18n.Text(lang, i18n.RuleWrongType, i18n.Params{"field": typeErr.Field, "type": jsonType(typeErr.Type), "value": typeErr.Value})
+		return []models.FieldError{{Field: typeErr.Field, Rule: "type", Message: text}}
+	}
+	return nil
+}
+
+// fieldPath removes the root struct and embedded structs (Go names, JSON names are snake_case) from a validator namespace
+func fieldPath(namespace string) string {
+	segments := strings.Split(namespace, ".")[1:]
+	path := segments[:0]
+	for _, segment := range segments {
+		if segment != "" && !unicode.IsUpper([]rune(segment)[0]) {
+			path = append(path, segment)
+		}
+	}
+	return strings.Join(path, ".")
+}
+
+// requiredField is the field error of a missing field
+func requiredField(lang, field string) models.FieldError {
+	return models.FieldError{Field: field, Rule: "required", Message: i18n.Text(lang, i18n.RuleRequired, i18n.Params{"field": field})}
+}
+
+// ruleMessage describes a failed validation of a field
+func ruleMessage(lang, field string, fe validator.FieldError) string {
+
+	// Lengths for strings and lists, values for numbers
+	unit := ""
+	switch fe.Kind() {
+	case reflect.String:
+		unit = ".string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		unit = ".list"
+	}
+
+	key := i18n.RuleInvalid
+	param := fe.Param()
+	switch fe.Tag() {
+	case "required", "required_without", "required_with", "required_if":
+		key = i18n.RuleRequired
+	case "min", "gte":
+		key = i18n.RuleMin + unit
+	case "max", "lte":
+		key = i18n.RuleMax + unit
+	case "len":
+		key = i18n.RuleLen + unit
+	case "oneof":
+		key = i18n.RuleOneOf
+		param = strings.Join(strings.Fields(param), ", ")
+	case "uuid", "uuid4":
+		key = i18n.RuleUUID
+	case "url", "http_url":
+		key = i18n.RuleURL
+	case "alpha":
+		key = i18n.RuleAlpha
+	}
+	return i18n.Text(lang, key, i18n.Params{"field": field, "param": param, "rule": fe.Tag()})
+}
+
+// jsonType names the JSON type expected for a Go type
+func jsonType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
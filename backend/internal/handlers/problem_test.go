@@ -0,0 +1,197 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/santiago-buildit/code-challenge/backend/internal/handlers"
+	"github.com/santiago-buildit/code-challenge/backend/internal/i18n"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func newProblemRouter(t *testing.T, mockSvc *MockBookService) *gin.Engine {
+	handler := handlers.NewBookHandler(mockSvc, zaptest.NewLogger(t))
+	r := gin.New()
+	r.Use(handlers.RequestIDMiddleware())
+	r.POST("/books", handler.CreateBook)
+	r.NoRoute(handlers.RouteNotFound)
+	return r
+}
+
+func decodeProblem(t *testing.T, resp *httptest.ResponseRecorder) models.ProblemResponse {
+	var res models.ProblemResponse
+	assert.Equal(t, "application/problem+json", resp.Header().Get("Content-Type"))
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &res))
+	return res
+}
+
+func TestProblem_FieldErrors(t *testing.T) {
+	mockSvc := new(MockBookService)
+	r := newProblemRouter(t, mockSvc)
+
+	body := `{"isbn":"123","language":"english","contributors":[{"name":"","role":"narrator"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/books", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(handlers.RequestIDHeader, "req-42")
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	res := decodeProblem(t, resp)
+	assert.Equal(t, models.ProblemInvalidRequestBody, res.Code)
+	assert.Equal(t, "req-42", res.RequestID)
+	assert.Equal(t, "/books", res.Instance)
+	assert.ElementsMatch(t, []models.FieldError{
+		{Field: "title", Rule: "required", Message: "title is required"},
+		{Field: "language", Rule: "len", Message: "language must be exactly 2 characters"},
+		{Field: "contributors[0].name", Rule: "required", Message: "contributors[0].name is required"},
+		{Field: "contributors[0].role", Rule: "oneof", Message: "contributors[0].role must be one of: author, editor, translator, illustrator"},
+	}, res.Errors)
+	mockSvc.AssertNotCalled(t, "CreateBook", mock.Anything, mock.Anything)
+}
+
+func TestProblem_TypeError(t *testing.T) {
+	r := newProblemRouter(t, new(MockBookService))
+
+	req := httptest.NewRequest(http.MethodPost, "/books", strings.NewReader(`{"isbn":"123","title":"Dune","page_count":"many"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	res := decodeProblem(t, resp)
+	assert.Equal(t, []models.FieldError{
+		{Field: "page_count", Rule: "type", Message: "page_count must be a JSON number (got string)"},
+	}, res.Errors)
+}
+
+func TestProblem_RouteNotFoundWithGeneratedRequestID(t *testing.T) {
+	r := newProblemRouter(t, new(MockBookService))
+
+	req := httptest.NewRequest(http.MethodGet, "/nowhere", nil)
+	req.Header.Set(handlers.RequestIDHeader, "bad id\n") // Not echoed
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	res := decodeProblem(t, resp)
+	assert.Equal(t, models.ProblemRouteNotFound, res.Code)
+	assert.Equal(t, "Not Found", res.Title)
+	assert.Len(t, res.RequestID, 36)
+	assert.Equal(t, res.RequestID, resp.Header().Get(handlers.RequestIDHeader))
+	assert.Empty(t, res.Errors)
+}
+
+func TestProblem_LocalizedFieldErrors(t *testing.T) {
+	r := newProblemRouter(t, new(MockBookService))
+
+	req := httptest.NewRequest(http.MethodPost, "/books", strings.NewReader(`{"isbn":"123","language":"english"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "es-AR,es;q=0.9,en;q=0.8")
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Equal(t, "es", resp.Header().Get("Content-Language"))
+	res := decodeProblem(t, resp)
+	assert.Equal(t, models.ProblemInvalidRequestBody, res.Code) // Codes and rules are not translated
+	assert.Equal(t, "Cuerpo de la solicitud no válido", res.Detail)
+	assert.ElementsMatch(t, []models.FieldError{
+		{Field: "title", Rule: "required", Message: "title es obligatorio"},
+		{Field: "author", Rule: "required_without", Message: "author es obligatorio"},
+		{Field: "language", Rule: "len", Message: "language debe tener exactamente 2 caracteres"},
+	}, res.Errors)
+}
+
+func TestProblem_LocalizedTransition(t *testing.T) {
+	mockSvc := new(MockBookService)
+	handler := handlers.NewBookHandler(mockSvc, zaptest.NewLogger(t))
+	r := gin.New()
+	r.PUT("/books/:id/checkout", handler.CheckoutBook)
+
+	transition := &utils.TransitionError{From: "lost", To: "checked_out", Allowed: []string{"available", "withdrawn"}}
+	mockSvc.On("CheckoutBook", mock.Anything, "book-1", models.StatusChangeRequest{}).Return(transition)
+
+	req := httptest.NewRequest(http.MethodPut, "/books/book-1/checkout", nil)
+	req.Header.Set("Accept-Language", "es")
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	res := decodeProblem(t, resp)
+	assert.Equal(t, "No se puede cambiar el estado de lost a checked_out", res.Detail)
+
+	// Same params in every language
+	req = httptest.NewRequest(http.MethodPut, "/books/book-1/checkout", nil)
+	resp = httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, "Cannot change status from lost to checked_out", decodeProblem(t, resp).Detail)
+}
+
+func TestProblem_LocalizedServiceError(t *testing.T) {
+	mockSvc := new(MockBookService)
+	handler := handlers.NewBookHandler(mockSvc, zaptest.NewLogger(t))
+	r := gin.New()
+	r.PUT("/books/:id", handler.UpdateBook)
+
+	serviceErr := i18n.NewError(utils.ErrBadRequest, i18n.ErrorUnknownLanguage, i18n.Params{"code": "xx"})
+	mockSvc.On("UpdateBook", mock.Anything, "book-1", mock.Anything).Return((*models.BookResponse)(nil), serviceErr)
+
+	for header, expected := range map[string]string{
+		"es": "Solicitud no válida: código de idioma desconocido 'xx'",
+		"en": "Invalid request: unknown language code 'xx'",
+	} {
+		req := httptest.NewRequest(http.MethodPut, "/books/book-1", strings.NewReader(`{"isbn":"123","title":"Dune","author":"Frank Herbert","language":"xx"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Language", header)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code, header)
+		res := decodeProblem(t, resp)
+		assert.Equal(t, models.ProblemValidationFailed, res.Code)
+		assert.Equal(t, expected, res.Detail, header)
+	}
+}
+
+func TestMessage_Localized(t *testing.T) {
+	mockSvc := new(MockBookService)
+	handler := handlers.NewBookHandler(mockSvc, zaptest.NewLogger(t))
+	r := gin.New()
+	r.DELETE("/books/:id", handler.DeleteBook)
+	mockSvc.On("DeleteBook", mock.Anything, "book-1").Return(nil)
+
+	for header, expected := range map[string]string{
+		"es":    "Libro eliminado",
+		"en-GB": "Book deleted",
+		"fr":    "Book deleted", // Unsupported, English fallback
+		"":      "Book deleted",
+	} {
+		req := httptest.NewRequest(http.MethodDelete, "/books/book-1", nil)
+		req.Header.Set("Accept-Language", header)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		var res models.MessageResponse
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &res))
+		assert.Equal(t, expected, res.Message, header)
+	}
+}
@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/services"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// Rows of the top and never-borrowed reports
+const (
+	defaultReportLimit = 10
+	maxReportLimit     = 1000
+)
+
+const mimeCSV = "text/csv"
+
+// csvReport is a report exportable as CSV
+type csvReport interface {
+	CSV() ([]string, [][]string)
+}
+
+type ReportHandler struct {
+	service services.ReportService
+	logger  *zap.Logger
+}
+
+func NewReportHandler(service services.ReportService, logger *zap.Logger) *ReportHandler {
+	return &ReportHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// TopBooks godoc
+// @Summary Most borrowed books
+// @Description Returns the books with the most checkouts started within the window [from, to) (default: the last 30 days, today included). Exportable as CSV with format=csv or Accept: text/csv.
+// @Tags reports
+// @Produce json,text/csv
+// @Param request query models.TopReportRequest false "Window, limit (default 10, max 1000) and format"
+// @Success 200 {object} models.TopBooksReport
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /reports/top-books [get]
+func (h *ReportHandler) TopBooks(c *gin.Context) {
+
+	h.logger.Info("Reporting most borrowed books")
+
+	// Parse query string
+	var req models.TopReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Invalid query parameters", zap.Error(err))
+		bindingProblem(c, err, models.ProblemInvalidQuery)
+		return
+	}
+	req.Limit = reportLimit(req.Limit)
+
+	// Invoke service
+	report, err := h.service.TopBooks(c.Request.Context(), req)
+	h.respond(c, req.Format, "top-books", report, err)
+}
+
+// TopAuthors godoc
+// @Summary Most borrowed authors
+// @Description Returns the authors whose books (credited as author) have the most checkouts started within the window [from, to) (default: the last 30 days, today included). Exportable as CSV with format=csv or Accept: text/csv.
+// @Tags reports
+// @Produce json,text/csv
+// @Param request query models.TopReportRequest false "Window, limit (default 10, max 1000) and format"
+// @Success 200 {object} models.TopAuthorsReport
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /reports/top-authors [get]
+func (h *ReportHandler) TopAuthors(c *gin.Context) {
+
+	h.logger.Info("Reporting most borrowed authors")
+
+	// Parse query string
+	var req models.TopReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Invalid query parameters", zap.Error(err))
+		bindingProblem(c, err, models.ProblemInvalidQuery)
+		return
+	}
+	req.Limit = reportLimit(req.Limit)
+
+	// Invoke service
+	report, err := h.service.TopAuthors(c.Request.Context(), req)
+	h.respond(c, req.Format, "top-authors", report, err)
+}
+
+// Checkouts godoc
+// @Summary Checkouts time series
+// @Description Returns the checkouts per day, week (ISO, from Monday) or month (UTC) within the window [from, to) (default: the last 30 days, today included), including intervals without checkouts. Exportable as CSV with format=csv or Accept: text/csv.
+// @Tags reports
+// @Produce json,text/csv
+// @Param request query models.CheckoutsReportRequest false "Window, interval (default day) and format"
+// @Success 200 {object} models.CheckoutsReport
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /reports/checkouts [get]
+func (h *ReportHandler) Checkouts(c *gin.Context) {
+
+	h.logger.Info("Reporting checkouts")
+
+	// Parse query string
+	var req models.CheckoutsReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Invalid query parameters", zap.Error(err))
+		bindingProblem(c, err, models.ProblemInvalidQuery)
+		return
+	}
+
+	// Invoke service
+	report, err := h.service.Checkouts(c.Request.Context(), req)
+	h.respond(c, req.Format, "checkouts", report, err)
+}
+
+// LoanDuration godoc
+// @Summary Average loan duration
+// @Description Returns the number of loans returned within the window [from, to) (default: the last 30 days, today included) and their average and median duration in days. Loans still open are not counted. Exportable as CSV with format=csv or Accept: text/csv.
+// @Tags reports
+// @Produce json,text/csv
+// @Param request query models.ReportWindowRequest false "Window and format"
+// @Success 200 {object} models.LoanDurationReport
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /reports/loan-duration [get]
+func (h *ReportHandler) LoanDuration(c *gin.Context) {
+
+	h.logger.Info("Reporting loan duration")
+
+	// Parse query string
+	var req models.ReportWindowRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Invalid query parameters", zap.Error(err))
+		bindingProblem(c, err, models.ProblemInvalidQuery)
+		return
+	}
+
+	// Invoke service
+	report, err := h.service.LoanDuration(c.Request.Context(), req)
+	h.respond(c, req.Format, "loan-duration", report, err)
+}
+
+// CheckedOut godoc
+// @Summary Currently checked-out ratio
+// @Description Returns the number of books (withdrawn excluded), how many are checked out now and their ratio. Exportable as CSV with format=csv or Accept: text/csv.
+// @Tags reports
+// @Produce json,text/csv
+// @Param request query models.CheckedOutReportRequest false "Format"
+// @Success 200 {object} models.CheckedOutReport
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /reports/checked-out [get]
+func (h *ReportHandler) CheckedOut(c *gin.Context) {
+
+	h.logger.Info("Reporting checked-out ratio")
+
+	// Parse query string
+	var req models.CheckedOutReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Invalid query parameters", zap.Error(err))
+		bindingProblem(c, err, models.ProblemInvalidQuery)
+		return
+	}
+
+	// Invoke service
+	report, err := h.service.CheckedOut(c.Request.Context())
+	h.respond(c, req.Format, "checked-out", report, err)
+}
+
+// NeverBorrowed godoc
+// @Summary Never borrowed books
+// @Description Returns the books (withdrawn excluded) that were never checked out, oldest first, with their total. Exportable as CSV with format=csv or Accept: text/csv.
+// @Tags reports
+// @Produce json,text/csv
+// @Param request query models.NeverBorrowedReportRequest false "Limit (default 10, max 1000) and format"
+// @Success 200 {object} models.NeverBorrowedReport
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /reports/never-borrowed [get]
+func (h *ReportHandler) NeverBorrowed(c *gin.Context) {
+
+	h.logger.Info("Reporting never borrowed books")
+
+	// Parse query string
+	var req models.NeverBorrowedReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Invalid query parameters", zap.Error(err))
+		bindingProblem(c, err, models.ProblemInvalidQuery)
+		return
+	}
+	req.Limit = reportLimit(req.Limit)
+
+	// Invoke service
+	report, err := h.service.NeverBorrowed(c.Request.Context(), req)
+	h.respond(c, req.Format, "never-borrowed", report, err)
+}
+
+/* Helper functions */
+
+// respond writes the report as JSON or CSV (format parameter, or Accept: text/csv), or maps the service error
+func (h *ReportHandler) respond(c *gin.Context, format models.ReportFormat, name string, report csvReport, err error) {
+	switch {
+	case errors.Is(err, utils.ErrBadRequest): // Invalid window or interval
+		h.logger.Warn("Invalid report request", zap.String("report", name), zap.Error(err))
+		problemError(c, http.StatusBadRequest, models.ProblemValidationFailed, err)
+		return
+	case err != nil:
+		h.logger.Error("Failed to compute report", zap.String("report", name), zap.Error(err))
+		problemDetail(c, http.StatusInternalServerError, models.ProblemInternal, "Failed to compute report")
+		return
+	}
+	h.logger.Info("Report computed successfully", zap.String("report", name))
+
+	// JSON unless CSV is asked for
+	if format == "" && c.NegotiateFormat(gin.MIMEJSON, mimeCSV) == mimeCSV {
+		format = models.ReportFormatCSV
+	}
+	if format != models.ReportFormatCSV {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	header, rows := report.CSV()
+	c.Header("Content-Type", mimeCSV+"; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="`+name+`.csv"`)
+	c.Status(http.StatusOK)
+	w := csv.NewWriter(c.Writer)
+	w.Write(header)
+	w.WriteAll(rows) // Flushes
+	if err := w.Error(); err != nil {
+		h.logger.Error("Failed to write CSV report", zap.String("report", name), zap.Error(err))
+	}
+}
+
+// reportLimit applies the default and maximum rows of a report
+func reportLimit(limit int) int {
+	if limit <= 0 {
+		return defaultReportLimit
+	}
+	return min(limit, maxReportLimit)
+}
@@ -0,0 +1,144 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/santiago-buildit/code-challenge/backend/internal/handlers"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+// MockReportService implements ReportService for testing
+type MockReportService struct {
+	mock.Mock
+}
+
+func (m *MockReportService) TopBooks(ctx context.Context, req models.TopReportRequest) (*models.TopBooksReport, error) {
+	args := m.Called(ctx, req)
+	report, _ := args.Get(0).(*models.TopBooksReport)
+	return report, args.Error(1)
+}
+
+func (m *MockReportService) TopAuthors(ctx context.Context, req models.TopReportRequest) (*models.TopAuthorsReport, error) {
+	args := m.Called(ctx, req)
+	report, _ := args.Get(0).(*models.TopAuthorsReport)
+	return report, args.Error(1)
+}
+
+func (m *MockReportService) Checkouts(ctx context.Context, req models.CheckoutsReportRequest) (*models.CheckoutsReport, error) {
+	args := m.Called(ctx, req)
+	report, _ := args.Get(0).(*models.CheckoutsReport)
+	return report, args.Error(1)
+}
+
+func (m *MockReportService) LoanDuration(ctx context.Context, req models.ReportWindowRequest) (*models.LoanDurationReport, error) {
+	args := m.Called(ctx, req)
+	report, _ := args.Get(0).(*models.LoanDurationReport)
+	return report, args.Error(1)
+}
+
+func (m *MockReportService) CheckedOut(ctx context.Context) (*models.CheckedOutReport, error) {
+	args := m.Called(ctx)
+	report, _ := args.Get(0).(*models.CheckedOutReport)
+	return report, args.Error(1)
+}
+
+func (m *MockReportService) NeverBorrowed(ctx context.Context, req models.NeverBorrowedReportRequest) (*models.NeverBorrowedReport, error) {
+	args := m.Called(ctx, req)
+	report, _ := args.Get(0).(*models.NeverBorrowedReport)
+	return report, args.Error(1)
+}
+
+func setupReportRouter(t *testing.T, mockSvc *MockReportService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewReportHandler(mockSvc, zaptest.NewLogger(t))
+
+	r := gin.New()
+	r.GET("/reports/top-books", handler.TopBooks)
+	r.GET("/reports/checkouts", handler.Checkouts)
+	r.GET("/reports/checked-out", handler.CheckedOut)
+	return r
+}
+
+func TestTopBooksReport_JSON(t *testing.T) {
+	mockSvc := new(MockReportService)
+	r := setupReportRouter(t, mockSvc)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	expectedReq := models.TopReportRequest{ReportWindowRequest: models.ReportWindowRequest{From: from}, Limit: 1000} // Limit capped
+	mockSvc.On("TopBooks", mock.Anything, expectedReq).Return(&models.TopBooksReport{
+		Books: []models.BookCheckouts{{BookID: "b1", Title: "The Hobbit", Checkouts: 12}},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/reports/top-books?from=2025-01-01&limit=5000", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var decoded models.TopBooksReport
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &decoded))
+	assert.Equal(t, 12, decoded.Books[0].Checkouts)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestTopBooksReport_CSV(t *testing.T) {
+	mockSvc := new(MockReportService)
+	r := setupReportRouter(t, mockSvc)
+
+	mockSvc.On("TopBooks", mock.Anything, mock.Anything).Return(&models.TopBooksReport{
+		Books: []models.BookCheckouts{{BookID: "b1", ISBN: "9780261103344", Title: "There and Back, Again", Author: "J.R.R. Tolkien", Checkouts: 12}},
+	}, nil)
+
+	// Format parameter and Accept header
+	for _, target := range []string{"/reports/top-books?format=csv", "/reports/top-books"} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("Accept", "text/csv")
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", resp.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="top-books.csv"`, resp.Header().Get("Content-Disposition"))
+		assert.Equal(t, "book_id,isbn,title,author,checkouts\nb1,9780261103344,\"There and Back, Again\",J.R.R. Tolkien,12\n", resp.Body.String())
+	}
+}
+
+func TestCheckoutsReport_InvalidRequest(t *testing.T) {
+	mockSvc := new(MockReportService)
+	r := setupReportRouter(t, mockSvc)
+
+	// Unknown interval or malformed date
+	for _, target := range []string{"/reports/checkouts?interval=year", "/reports/checkouts?from=01/02/2025"} {
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	}
+
+	// Rejected by the service
+	mockSvc.On("Checkouts", mock.Anything, mock.Anything).Return(nil, utils.ErrBadRequest)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/reports/checkouts?from=2025-02-01&to=2025-01-01", nil))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestCheckedOutReport_Error(t *testing.T) {
+	mockSvc := new(MockReportService)
+	r := setupReportRouter(t, mockSvc)
+
+	mockSvc.On("CheckedOut", mock.Anything).Return(nil, assert.AnError)
+
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/reports/checked-out", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.Contains(t, resp.Body.String(), "Failed to compute report")
+}
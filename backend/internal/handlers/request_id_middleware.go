@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the request ID (the client's one, or generated) in requests and responses
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDKey is the context key of the request ID (also returned in problem responses)
+const RequestIDKey = "request_id"
+
+// Longest client request ID kept (longer ones are replaced)
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware sets the request ID of every request, reusing a valid X-Request-ID from the client
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+		c.Set(RequestIDKey, id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// validRequestID accepts non-empty printable ASCII IDs up to maxRequestIDLength (safe to log and echo)
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/santiago-buildit/code-challenge/backend/internal/i18n"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/services"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+type TagHandler struct {
+	service services.TagService
+	logger  *zap.Logger
+}
+
+func NewTagHandler(service services.TagService, logger *zap.Logger) *TagHandler {
+	return &TagHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// CreateTag godoc
+// @Summary Create a new tag
+// @Description Registers a new tag (subject) for classifying books. Names are unique (case-insensitive).
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param request body models.CreateTagRequest true "Tag data"
+// @Success 201 {object} models.TagResponse
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 409 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /tags [post]
+func (h *TagHandler) CreateTag(c *gin.Context) {
+
+	h.logger.Info("Creating tag")
+	ctx := c.Request.Context()
+
+	// Parse request body
+	var req models.CreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid request body", zap.Error(err))
+		bindingProblem(c, err, models.ProblemInvalidRequestBody)
+		return
+	}
+
+	// Sanitize input
+	req.Sanitize()
+	if req.Name == "" {
+		h.logger.Warn("Empty tag name")
+		res := newProblem(c, http.StatusBadRequest, models.ProblemInvalidRequestBody, nil)
+		res.Errors = []models.FieldError{requiredField(language(c), "name")}
+		writeProblem(c, http.StatusBadRequest, res)
+		return
+	}
+
+	// Invoke service
+	res, err := h.service.CreateTag(ctx, req)
+	if err != nil {
+		h.handleTagError(c, "", err, "create")
+		return
+	}
+	h.logger.Info("Tag created successfully", zap.String("id", res.ID), zap.String("name", res.Name))
+	c.JSON(http.StatusCreated, res)
+}
+
+// ListTags godoc
+// @Summary List tags
+// @Description Returns all tags sorted by name, with the number of books assigned to each
+// @Tags tags
+// @Produce json
+// @Success 200 {array} models.TagResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /tags [get]
+func (h *TagHandler) ListTags(c *gin.Context) {
+
+	h.logger.Info("Listing tags")
+	ctx := c.Request.Context()
+
+	// Invoke service
+	res, err := h.service.ListTags(ctx)
+	if err != nil {
+		h.handleTagError(c, "", err, "list")
+		return
+	}
+	h.logger.Info("Tags listed successfully", zap.Int("count", len(res)))
+	c.JSON(http.StatusOK, res)
+}
+
+// GetTag godoc
+// @Summary Get a tag by ID
+// @Description Retrieves a tag with the number of books assigned
+// @Tags tags
+// @Produce json
+// @Param id path string true "Tag ID"
+// @Success 200 {object} models.TagResponse
+// @Failure 404 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /tags/{id} [get]
+func (h *TagHandler) GetTag(c *gin.Context) {
+
+	h.logger.Info("Getting tag")
+	ctx := c.Request.Context()
+
+	// Extract params
+	id := c.Param("id")
+
+	// Invoke service
+	res, err := h.service.GetTag(ctx, id)
+	if err != nil {
+		h.handleTagError(c, id, err, "get")
+		return
+	}
+	h.logger.Info("Tag retrieved successfully", zap.String("id", res.ID))
+	c.JSON(http.StatusOK, res)
+}
+
+// UpdateTag godoc
+// @Summary Rename a tag by ID
+// @Description Updates the name of a tag (assigned books keep it)
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path string true "Tag ID"
+// @Param request body models.UpdateTagRequest true "Updated tag data"
+// @Success 200 {object} models.TagResponse
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 404 {object} models.ProblemResponse
+// @Failure 409 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /tags/{id} [put]
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+
+	h.logger.Info("Updating tag")
+	ctx := c.Request.Context()
+
+	// Extract params
+	id := c.Param("id")
+
+	// Parse request body
+	var req models.UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid request body", zap.Error(err))
+		bindingProblem(c, err, models.ProblemInvalidRequestBody)
+		return
+	}
+
+	// Sanitize input
+	req.Sanitize()
+	if req.Name == "" {
+		h.logger.Warn("Empty tag name")
+		res := newProblem(c, http.StatusBadRequest, models.ProblemInvalidRequestBody, nil)
+		res.Errors = []models.FieldError{requiredField(language(c), "name")}
+		writeProblem(c, http.StatusBadRequest, res)
+		return
+	}
+
+	// Invoke service
+	res, err := h.service.UpdateTag(ctx, id, req)
+	if err != nil {
+		h.handleTagError(c, id, err, "update")
+		return
+	}
+	h.logger.Info("Tag updated successfully", zap.String("id", res.ID), zap.String("name", res.Name))
+	c.JSON(http.StatusOK, res)
+}
+
+// DeleteTag godoc
+// @Summary Delete a tag by ID
+// @Description Deletes a tag and removes it from all books
+// @Tags tags
+// @Produce json
+// @Param id path string true "Tag ID"
+// @Success 200 {object} models.MessageResponse
+// @Failure 404 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /tags/{id} [delete]
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+
+	h.logger.Info("Deleting tag")
+	ctx := c.Request.Context()
+
+	// Extract params
+	id := c.Param("id")
+
+	// Invoke service
+	if err := h.service.DeleteTag(ctx, id); err != nil {
+		h.handleTagError(c, id, err, "delete")
+		return
+	}
+	h.logger.Info("Tag deleted successfully", zap.String("id", id))
+	message(c, i18n.MessageTagDeleted)
+}
+
+/* Helper functions */
+
+func (h *TagHandler) handleTagError(c *gin.Context, id string, err error, action string) {
+
+	// Handle specific errors
+	switch {
+	case errors.Is(err, utils.ErrNotFound): // Not found error
+		h.logger.Warn("Tag not found", zap.String("id", id))
+		problem(c, http.StatusNotFound, models.ProblemTagNotFound)
+	case errors.Is(err, utils.ErrConflict): // Duplicate name
+		h.logger.Warn("Tag name already exists", zap.String("id", id))
+		problem(c, http.StatusConflict, models.ProblemTagNameConflict)
+	default: // Generic error
+		h.logger.Error("Failed to "+action+" tag",
+			zap.String("id", id),
+			zap.Error(err),
+		)
+		problemDetail(c, http.StatusInternalServerError, models.ProblemInternal, "Failed to "+action+" tag")
+	}
+}
@@ -0,0 +1,167 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/santiago-buildit/code-challenge/backend/internal/handlers"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+// MockTagService implements TagService for testing
+type MockTagService struct {
+	mock.Mock
+}
+
+func (m *MockTagService) CreateTag(ctx context.Context, req models.CreateTagRequest) (*models.TagResponse, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*models.TagResponse), args.Error(1)
+}
+
+func (m *MockTagService) ListTags(ctx context.Context) ([]models.TagResponse, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.TagResponse), args.Error(1)
+}
+
+func (m *MockTagService) GetTag(ctx context.Context, id string) (*models.TagResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.TagResponse), args.Error(1)
+}
+
+func (m *MockTagService) UpdateTag(ctx context.Context, id string, req models.UpdateTagRequest) (*models.TagResponse, error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(*models.TagResponse), args.Error(1)
+}
+
+func (m *MockTagService) DeleteTag(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestCreateTag_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockTagService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewTagHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.POST("/tags", handler.CreateTag)
+
+	mockSvc.On("CreateTag", mock.Anything, models.CreateTagRequest{Name: "Fantasy"}).
+		Return(&models.TagResponse{ID: "tag-1", Name: "Fantasy"}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/tags", bytes.NewReader([]byte(`{"name":"  Fantasy "}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	var decoded models.TagResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &decoded))
+	assert.Equal(t, "tag-1", decoded.ID)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestCreateTag_InvalidPayload(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockTagService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewTagHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.POST("/tags", handler.CreateTag)
+
+	for _, body := range []string{`{}`, `{"name":"   "}`} {
+		req := httptest.NewRequest(http.MethodPost, "/tags", bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code, body)
+	}
+	mockSvc.AssertNotCalled(t, "CreateTag", mock.Anything, mock.Anything)
+}
+
+func TestUpdateTag_Conflict(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockTagService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewTagHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.PUT("/tags/:id", handler.UpdateTag)
+
+	mockSvc.On("UpdateTag", mock.Anything, "tag-1", models.UpdateTagRequest{Name: "Classics"}).
+		Return((*models.TagResponse)(nil), utils.ErrConflict)
+
+	req := httptest.NewRequest(http.MethodPut, "/tags/tag-1", bytes.NewReader([]byte(`{"name":"Classics"}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestListTags_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockTagService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewTagHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.GET("/tags", handler.ListTags)
+
+	mockSvc.On("ListTags", mock.Anything).Return([]models.TagResponse{
+		{ID: "tag-1", Name: "Classics", BookCount: 3},
+		{ID: "tag-2", Name: "Fantasy", BookCount: 5},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/tags", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var decoded []models.TagResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &decoded))
+	assert.Len(t, decoded, 2)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestDeleteTag_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockTagService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewTagHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.DELETE("/tags/:id", handler.DeleteTag)
+
+	mockSvc.On("DeleteTag", mock.Anything, "missing").Return(utils.ErrNotFound)
+
+	req := httptest.NewRequest(http.MethodDelete, "/tags/missing", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	mockSvc.AssertExpectations(t)
+}
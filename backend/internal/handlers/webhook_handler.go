@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/santiago-buildit/code-challenge/backend/internal/i18n"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/services"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+type WebhookHandler struct {
+	service services.WebhookService
+	logger  *zap.Logger
+}
+
+func NewWebhookHandler(service services.WebhookService, logger *zap.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// CreateWebhook godoc
+// @Summary Create a webhook subscription
+// @Description Subscribes a URL to book lifecycle events (book.created, book.updated, book.deleted, book.checked_out, book.checked_in, book.inventory_changed). Deliveries are JSON POSTs signed with HMAC-SHA256 in the X-Webhook-Signature header ("sha256=" + hex of the HMAC of "<X-Webhook-Timestamp>.<body>"), retried with exponential backoff until a 2xx response. The secret is generated when omitted and only returned here.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param request body models.CreateWebhookRequest true "Webhook data"
+// @Success 201 {object} models.WebhookResponse
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+
+	h.logger.Info("Creating webhook")
+	ctx := c.Request.Context()
+
+	// Parse request body
+	var req models.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid request body", zap.Error(err))
+		bindingProblem(c, err, models.ProblemInvalidRequestBody)
+		return
+	}
+
+	// Sanitize input
+	req.Sanitize()
+
+	// Invoke service
+	res, err := h.service.CreateWebhook(ctx, req)
+	if err != nil {
+		h.handleWebhookError(c, "", err, "create")
+		return
+	}
+	h.logger.Info("Webhook created successfully", zap.String("id", res.ID), zap.String("url", res.URL))
+	c.JSON(http.StatusCreated, res)
+}
+
+// ListWebhooks godoc
+// @Summary List webhooks
+// @Description Returns all webhook subscriptions (without secrets)
+// @Tags webhooks
+// @Produce json
+// @Success 200 {array} models.WebhookResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+
+	h.logger.Info("Listing webhooks")
+	ctx := c.Request.Context()
+
+	// Invoke service
+	res, err := h.service.ListWebhooks(ctx)
+	if err != nil {
+		h.handleWebhookError(c, "", err, "list")
+		return
+	}
+	h.logger.Info("Webhooks listed successfully", zap.Int("count", len(res)))
+	c.JSON(http.StatusOK, res)
+}
+
+// GetWebhook godoc
+// @Summary Get a webhook by ID
+// @Description Retrieves a webhook subscription (without secret)
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} models.WebhookResponse
+// @Failure 404 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+
+	h.logger.Info("Getting webhook")
+	ctx := c.Request.Context()
+
+	// Extract params
+	id := c.Param("id")
+
+	// Invoke service
+	res, err := h.service.GetWebhook(ctx, id)
+	if err != nil {
+		h.handleWebhookError(c, id, err, "get")
+		return
+	}
+	h.logger.Info("Webhook retrieved successfully", zap.String("id", res.ID))
+	c.JSON(http.StatusOK, res)
+}
+
+// UpdateWebhook godoc
+// @Summary Update a webhook by ID
+// @Description Replaces the URL and events of a webhook. The secret and active flag are kept when omitted.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param request body models.UpdateWebhookRequest true "Updated webhook data"
+// @Success 200 {object} models.WebhookResponse
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 404 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+
+	h.logger.Info("Updating webhook")
+	ctx := c.Request.Context()
+
+	// Extract params
+	id := c.Param("id")
+
+	// Parse request body
+	var req models.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid request body", zap.Error(err))
+		bindingProblem(c, err, models.ProblemInvalidRequestBody)
+		return
+	}
+
+	// Sanitize input
+	req.Sanitize()
+
+	// Invoke service
+	res, err := h.service.UpdateWebhook(ctx, id, req)
+	if err != nil {
+		h.handleWebhookError(c, id, err, "update")
+		return
+	}
+	h.logger.Info("Webhook updated successfully", zap.String("id", res.ID))
+	c.JSON(http.StatusOK, res)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook by ID
+// @Description Deletes a webhook subscription with its delivery log (pending deliveries are dropped)
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} models.MessageResponse
+// @Failure 404 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+
+	h.logger.Info("Deleting webhook")
+	ctx := c.Request.Context()
+
+	// Extract params
+	id := c.Param("id")
+
+	// Invoke service
+	if err := h.service.DeleteWebhook(ctx, id); err != nil {
+		h.handleWebhookError(c, id, err, "delete")
+		return
+	}
+	h.logger.Info("Webhook deleted successfully", zap.String("id", id))
+	message(c, i18n.MessageWebhookDeleted)
+}
+
+// ListWebhookDeliveries godoc
+// @Summary List the deliveries of a webhook
+// @Description Returns a paginated delivery log (newest first) with status, attempts, last response and payload. Supports filtering by status (pending, succeeded, failed).
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param request query models.ListWebhookDeliveriesRequest false "Filter and pagination parameters"
+// @Success 200 {object} models.ListWebhookDeliveriesResponse
+// @Failure 400 {object} models.ProblemResponse
+// @Failure 404 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListWebhookDeliveries(c *gin.Context) {
+
+	h.logger.Info("Listing webhook deliveries")
+	ctx := c.Request.Context()
+
+	// Extract params
+	id := c.Param("id")
+
+	// Parse query string
+	var req models.ListWebhookDeliveriesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Invalid query parameters", zap.Error(err))
+		bindingProblem(c, err, models.ProblemInvalidQuery)
+		return
+	}
+
+	// Validate pagination parameters
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = defaultPageSize
+	}
+	if req.PageSize > maxPageSize {
+		req.PageSize = maxPageSize
+	}
+
+	// Invoke service
+	res, err := h.service.ListDeliveries(ctx, id, req)
+	if err != nil {
+		h.handleWebhookError(c, id, err, "list deliveries of")
+		return
+	}
+	h.logger.Info("Webhook deliveries listed successfully", zap.String("id", id), zap.Int("count", len(res.Deliveries)))
+	c.JSON(http.StatusOK, res)
+}
+
+// RedeliverWebhook godoc
+// @Summary Redeliver a webhook delivery
+// @Description Sends the payload of a previous delivery again (same event ID) as a new delivery, attempted immediately. A failed attempt is retried like any delivery.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param delivery_id path string true "Delivery ID"
+// @Success 201 {object} models.WebhookDeliveryResponse
+// @Failure 404 {object} models.ProblemResponse
+// @Failure 500 {object} models.ProblemResponse
+// @Router /webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func (h *WebhookHandler) RedeliverWebhook(c *gin.Context) {
+
+	h.logger.Info("Redelivering webhook")
+	ctx := c.Request.Context()
+
+	// Extract params
+	id := c.Param("id")
+	deliveryID := c.Param("delivery_id")
+
+	// Invoke service
+	res, err := h.service.Redeliver(ctx, id, deliveryID)
+	if err != nil {
+		h.handleWebhookError(c, id, err, "redeliver")
+		return
+	}
+	h.logger.Info("Webhook redelivered",
+		zap.String("id", id),
+		zap.String("delivery_id", res.ID),
+		zap.String("status", string(res.Status)))
+	c.JSON(http.StatusCreated, res)
+}
+
+/* Helper functions */
+
+func (h *WebhookHandler) handleWebhookError(c *gin.Context, id string, err error, action string) {
+
+	// Handle specific errors
+	switch {
+	case errors.Is(err, utils.ErrNotFound): // Not found error (webhook or delivery)
+		h.logger.Warn("Webhook not found", zap.String("id", id))
+		problem(c, http.StatusNotFound, models.ProblemWebhookNotFound)
+	case errors.Is(err, utils.ErrBadRequest): // Validation error
+		h.logger.Warn("Invalid webhook request", zap.String("id", id), zap.Error(err))
+		problemError(c, http.StatusBadRequest, models.ProblemValidationFailed, err)
+	default: // Generic error
+		h.logger.Error("Failed to "+action+" webhook",
+			zap.String("id", id),
+			zap.Error(err),
+		)
+		problemDetail(c, http.StatusInternalServerError, models.ProblemInternal, "Failed to "+action+" webhook")
+	}
+}
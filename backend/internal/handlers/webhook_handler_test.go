@@ -0,0 +1,146 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/santiago-buildit/code-challenge/backend/internal/handlers"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+// MockWebhookService implements WebhookService for testing
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (m *MockWebhookService) CreateWebhook(ctx context.Context, req models.CreateWebhookRequest) (*models.WebhookResponse, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*models.WebhookResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) ListWebhooks(ctx context.Context) ([]models.WebhookResponse, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.WebhookResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) GetWebhook(ctx context.Context, id string) (*models.WebhookResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.WebhookResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) UpdateWebhook(ctx context.Context, id string, req models.UpdateWebhookRequest) (*models.WebhookResponse, error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(*models.WebhookResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) DeleteWebhook(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookService) ListDeliveries(ctx context.Context, id string, req models.ListWebhookDeliveriesRequest) (*models.ListWebhookDeliveriesResponse, error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(*models.ListWebhookDeliveriesResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) Redeliver(ctx context.Context, id string, deliveryID string) (*models.WebhookDeliveryResponse, error) {
+	args := m.Called(ctx, id, deliveryID)
+	return args.Get(0).(*models.WebhookDeliveryResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) Name() string {
+	return "webhooks"
+}
+
+func (m *MockWebhookService) Send(ctx context.Context, event *models.OutboxEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func TestCreateWebhook_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockWebhookService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewWebhookHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.POST("/webhooks", handler.CreateWebhook)
+
+	// Duplicated events are removed
+	mockSvc.On("CreateWebhook", mock.Anything, models.CreateWebhookRequest{
+		URL:    "https://example.com/hooks",
+		Events: []string{"book.created", "book.checked_out"},
+	}).Return(&models.WebhookResponse{ID: "webhook-1", Secret: "whsec_generated"}, nil)
+
+	body := `{"url":"https://example.com/hooks","events":["book.created","book.checked_out","book.created"]}`
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	var decoded models.WebhookResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &decoded))
+	assert.Equal(t, "whsec_generated", decoded.Secret)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestCreateWebhook_InvalidPayload(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockWebhookService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewWebhookHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.POST("/webhooks", handler.CreateWebhook)
+
+	for _, body := range []string{
+		`{"url":"https://example.com/hooks","events":[]}`,                            // No events
+		`{"url":"https://example.com/hooks","events":["book.renamed"]}`,              // Unknown event
+		`{"url":"ftp://example.com/hooks","events":["book.created"]}`,                // Not HTTP
+		`{"url":"https://example.com/hooks","events":["book.created"],"secret":"x"}`, // Short secret
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code, body)
+	}
+	mockSvc.AssertNotCalled(t, "CreateWebhook", mock.Anything, mock.Anything)
+}
+
+func TestRedeliverWebhook_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(MockWebhookService)
+	logger := zaptest.NewLogger(t)
+	handler := handlers.NewWebhookHandler(mockSvc, logger)
+
+	r := gin.New()
+	r.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", handler.RedeliverWebhook)
+
+	mockSvc.On("Redeliver", mock.Anything, "webhook-1", "delivery-9").
+		Return((*models.WebhookDeliveryResponse)(nil), utils.ErrNotFound)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/webhook-1/deliveries/delivery-9/redeliver", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	mockSvc.AssertExpectations(t)
+}
@@ -0,0 +1,227 @@
+package i18n
+
+import "github.com/santiago-buildit/code-challenge/backend/internal/models"
+
+// Keys of the success messages (MessageResponse)
+const (
+	MessageBookDeleted       = "book_deleted"
+	MessageBookCheckedOut    = "book_checked_out"
+	MessageBookCheckedIn     = "book_checked_in"
+	MessageBookStatusChanged = "book_status_changed"
+	MessageBookMarkedLost    = "book_marked_lost"
+	MessageBookMarkedDamaged = "book_marked_damaged"
+	MessageBookMarkedFound   = "book_marked_found"
+	MessageTagDeleted        = "tag_deleted"
+	MessageWebhookDeleted    = "webhook_deleted"
+)
+
+// Keys of the field error messages, by failed validation rule (params: field, param, rule, type, value)
+const (
+	RuleRequired  = "rule.required"
+	RuleMin       = "rule.min"
+	RuleMinString = "rule.min.string"
+	RuleMinList   = "rule.min.list"
+	RuleMax       = "rule.max"
+	RuleMaxString = "rule.max.string"
+	RuleMaxList   = "rule.max.list"
+	RuleLen       = "rule.len"
+	RuleLenString = "rule.len.string"
+	RuleLenList   = "rule.len.list"
+	RuleOneOf     = "rule.oneof"
+	RuleUUID      = "rule.uuid"
+	RuleURL       = "rule.url"
+	RuleAlpha     = "rule.alpha"
+	RuleInvalid   = "rule.invalid" // Rules without a specific message
+	RuleWrongType = "rule.type"    // JSON value of the wrong type
+)
+
+// Keys of the service error messages (see Error)
+const (
+	ErrorAuthorRequired     = "error.author_required"
+	ErrorUnknownStatus      = "error.unknown_status"     // status
+	ErrorUnknownLanguage    = "error.unknown_language"   // code
+	ErrorRangeOrder         = "error.range_order"        // From after to
+	ErrorInvalidCursor      = "error.invalid_cursor"     // Malformed, or issued for another sort
+	ErrorInvalidSortField   = "error.invalid_sort_field" // field
+	ErrorInvalidSortOrder   = "error.invalid_sort_order" // field, order
+	ErrorRelevanceSort      = "error.relevance_sort"     // Relevance without text
+	ErrorTooManyIntervals   = "error.too_many_intervals" // interval, max
+	ErrorCoverTooLarge      = "error.cover_too_large"    // max (bytes)
+	ErrorImageTooLarge      = "error.image_too_large"    // Dimensions
+	ErrorUnknownCoverSize   = "error.unknown_cover_size" // size
+	ErrorInvalidWebhookURL  = "error.invalid_webhook_url"
+	ErrorPrivateWebhookURL  = "error.private_webhook_url"
+	ErrorInvalidQuery       = "error.invalid_query" // pos, reason (one of the Query keys)
+	QueryUnexpected         = "query.unexpected"    // found
+	QueryUnterminatedPhrase = "query.unterminated_phrase"
+	QueryExpectedParen      = "query.expected_paren" // found
+	QueryUnknownField       = "query.unknown_field"  // field
+	QueryExpectedValue      = "query.expected_value" // field, found
+	QueryEmptyValue         = "query.empty_value"
+	QueryInvalidValue       = "query.invalid_value" // value, field, values
+	QueryExpectedTerm       = "query.expected_term" // found
+	QueryEnd                = "query.end"           // Found at the end of the query
+)
+
+// Message catalogs by language. Problem details are keyed by problem code; {detail} is a runtime
+// detail given by the handler (service errors localized by their key, failed action)
+var catalogs = map[string]map[string]string{
+	"en": {
+
+		// Problems
+		string(models.ProblemInvalidRequestBody): "Invalid request body",
+		string(models.ProblemInvalidQuery):       "Invalid query parameters",
+		string(models.ProblemMissingID):          "Missing parameter ID",
+		string(models.ProblemValidationFailed):   "Invalid request: {detail}",
+		string(models.ProblemInvalidLastEventID): "Invalid Last-Event-ID",
+		string(models.ProblemMissingCover):       "Missing cover image (multipart field 'file')",
+		string(models.ProblemInvalidCover):       "Invalid cover image",
+		string(models.ProblemCoverTooLarge):      "Cover image too large",
+		string(models.ProblemUnsupportedCover):   "Cover must be a JPEG, PNG or GIF image",
+		string(models.ProblemInvalidAPIKey):      "Invalid or missing API key",
+		string(models.ProblemInvalidTransition):  "Cannot change status from {from} to {to}",
+		string(models.ProblemTagNameConflict):    "Tag name already exists",
+		string(models.ProblemBookNotFound):       "Book not found",
+		string(models.ProblemAuthorNotFound):     "Author not found",
+		string(models.ProblemTagNotFound):        "Tag not found",
+		string(models.ProblemCoverNotFound):      "Cover not found",
+		string(models.ProblemWebhookNotFound):    "Webhook not found",
+		string(models.ProblemRouteNotFound):      "Route not found",
+		string(models.ProblemInternal):           "{detail}",
+		string(models.ProblemStreamUnavailable):  "Live event stream not available here (use the gRPC WatchBooks call or webhooks)",
+
+		// Messages
+		MessageBookDeleted:       "Book deleted",
+		MessageBookCheckedOut:    "Book checked out",
+		MessageBookCheckedIn:     "Book checked in",
+		MessageBookStatusChanged: "Book status changed",
+		MessageBookMarkedLost:    "Book marked as lost",
+		MessageBookMarkedDamaged: "Book marked as damaged",
+		MessageBookMarkedFound:   "Book marked as found",
+		MessageTagDeleted:        "Tag deleted",
+		MessageWebhookDeleted:    "Webhook deleted",
+
+		// Field errors
+		RuleRequired:  "{field} is required",
+		RuleMin:       "{field} must be at least {param}",
+		RuleMinString: "{field} must be at least {param} characters",
+		RuleMinList:   "{field} must be at least {param} items",
+		RuleMax:       "{field} must be at most {param}",
+		RuleMaxString: "{field} must be at most {param} characters",
+		RuleMaxList:   "{field} must be at most {param} items",
+		RuleLen:       "{field} must be exactly {param}",
+		RuleLenString: "{field} must be exactly {param} characters",
+		RuleLenList:   "{field} must be exactly {param} items",
+		RuleOneOf:     "{field} must be one of: {param}",
+		RuleUUID:      "{field} must be a valid UUID",
+		RuleURL:       "{field} must be a valid URL",
+		RuleAlpha:     "{field} must contain only letters",
+		RuleInvalid:   "{field} is invalid ({rule})",
+		RuleWrongType: "{field} must be a JSON {type} (got {value})",
+
+		// Service errors
+		ErrorAuthorRequired:     "author or contributors required",
+		ErrorUnknownStatus:      "unknown status '{status}'",
+		ErrorUnknownLanguage:    "unknown language code '{code}'",
+		ErrorRangeOrder:         "from must be before to",
+		ErrorInvalidCursor:      "invalid cursor",
+		ErrorInvalidSortField:   "invalid sort field '{field}'",
+		ErrorInvalidSortOrder:   "invalid sort order for {field}: '{order}'",
+		ErrorRelevanceSort:      "sorting by relevance requires text",
+		ErrorTooManyIntervals:   "too many {interval} intervals in the window (max {max})",
+		ErrorCoverTooLarge:      "cover exceeds {max} bytes",
+		ErrorImageTooLarge:      "image dimensions too large",
+		ErrorUnknownCoverSize:   "unknown cover size '{size}'",
+		ErrorInvalidWebhookURL:  "invalid webhook URL",
+		ErrorPrivateWebhookURL:  "webhook URL must not point to a local or private address",
+		ErrorInvalidQuery:       "invalid query at position {pos}: {reason}",
+		QueryUnexpected:         "unexpected {found}",
+		QueryUnterminatedPhrase: "unterminated quoted phrase",
+		QueryExpectedParen:      "expected ')' but found {found}",
+		QueryUnknownField:       "unknown field '{field}'",
+		QueryExpectedValue:      "expected value for field '{field}' but found {found}",
+		QueryEmptyValue:         "empty value",
+		QueryInvalidValue:       "invalid value '{value}' for field '{field}' (expected one of: {values})",
+		QueryExpectedTerm:       "expected a term but found {found}",
+		QueryEnd:                "end of query",
+	},
+	"es": {
+
+		// Problems
+		string(models.ProblemInvalidRequestBody): "Cuerpo de la solicitud no válido",
+		string(models.ProblemInvalidQuery):       "Parámetros de consulta no válidos",
+		string(models.ProblemMissingID):          "Falta el parámetro ID",
+		string(models.ProblemValidationFailed):   "Solicitud no válida: {detail}",
+		string(models.ProblemInvalidLastEventID): "Last-Event-ID no válido",
+		string(models.ProblemMissingCover):       "Falta la imagen de portada (campo multipart 'file')",
+		string(models.ProblemInvalidCover):       "Imagen de portada no válida",
+		string(models.ProblemCoverTooLarge):      "La imagen de portada es demasiado grande",
+		string(models.ProblemUnsupportedCover):   "La portada debe ser una imagen JPEG, PNG o GIF",
+		string(models.ProblemInvalidAPIKey):      "API key no válida o ausente",
+		string(models.ProblemInvalidTransition):  "No se puede cambiar el estado de {from} a {to}",
+		string(models.ProblemTagNameConflict):    "Ya existe una etiqueta con ese nombre",
+		string(models.ProblemBookNotFound):       "Libro no encontrado",
+		string(models.ProblemAuthorNotFound):     "Autor no encontrado",
+		string(models.ProblemTagNotFound):        "Etiqueta no encontrada",
+		string(models.ProblemCoverNotFound):      "Portada no encontrada",
+		string(models.ProblemWebhookNotFound):    "Webhook no encontrado",
+		string(models.ProblemRouteNotFound):      "Ruta no encontrada",
+		string(models.ProblemInternal):           "Error interno del servidor",
+		string(models.ProblemStreamUnavailable):  "Flujo de eventos en vivo no disponible aquí (use la llamada gRPC WatchBooks o webhooks)",
+
+		// Messages
+		MessageBookDeleted:       "Libro eliminado",
+		MessageBookCheckedOut:    "Libro prestado",
+		MessageBookCheckedIn:     "Libro devuelto",
+		MessageBookStatusChanged: "Estado del libro actualizado",
+		MessageBookMarkedLost:    "Libro marcado como perdido",
+		MessageBookMarkedDamaged: "Libro marcado como dañado",
+		MessageBookMarkedFound:   "Libro marcado como encontrado",
+		MessageTagDeleted:        "Etiqueta eliminada",
+		MessageWebhookDeleted:    "Webhook eliminado",
+
+		// Field errors
+		RuleRequired:  "{field} es obligatorio",
+		RuleMin:       "{field} debe ser al menos {param}",
+		RuleMinString: "{field} debe tener al menos {param} caracteres",
+		RuleMinList:   "{field} debe tener al menos {param} elementos",
+		RuleMax:       "{field} debe ser como máximo {param}",
+		RuleMaxString: "{field} debe tener como máximo {param} caracteres",
+		RuleMaxList:   "{field} debe tener como máximo {param} elementos",
+		RuleLen:       "{field} debe ser exactamente {param}",
+		RuleLenString: "{field} debe tener exactamente {param} caracteres",
+		RuleLenList:   "{field} debe tener exactamente {param} elementos",
+		RuleOneOf:     "{field} debe ser uno de: {param}",
+		RuleUUID:      "{field} debe ser un UUID válido",
+		RuleURL:       "{field} debe ser una URL válida",
+		RuleAlpha:     "{field} solo puede contener letras",
+		RuleInvalid:   "{field} no es válido ({rule})",
+		RuleWrongType: "{field} debe ser un {type} JSON (se recibió {value})",
+
+		// Service errors
+		ErrorAuthorRequired:     "se requiere author o contributors",
+		ErrorUnknownStatus:      "estado desconocido '{status}'",
+		ErrorUnknownLanguage:    "código de idioma desconocido '{code}'",
+		ErrorRangeOrder:         "from debe ser anterior a to",
+		ErrorInvalidCursor:      "cursor no válido",
+		ErrorInvalidSortField:   "campo de orden no válido '{field}'",
+		ErrorInvalidSortOrder:   "sentido de orden no válido para {field}: '{order}'",
+		ErrorRelevanceSort:      "ordenar por relevancia requiere text",
+		ErrorTooManyIntervals:   "demasiados intervalos {interval} en el período (máximo {max})",
+		ErrorCoverTooLarge:      "la portada supera los {max} bytes",
+		ErrorImageTooLarge:      "las dimensiones de la imagen son demasiado grandes",
+		ErrorUnknownCoverSize:   "tamaño de portada desconocido '{size}'",
+		ErrorInvalidWebhookURL:  "URL de webhook no válida",
+		ErrorPrivateWebhookURL:  "la URL del webhook no puede apuntar a una dirección local o privada",
+		ErrorInvalidQuery:       "consulta no válida en la posición {pos}: {reason}",
+		QueryUnexpected:         "no se esperaba {found}",
+		QueryUnterminatedPhrase: "frase entre comillas sin cerrar",
+		QueryExpectedParen:      "se esperaba ')' pero se encontró {found}",
+		QueryUnknownField:       "campo desconocido '{field}'",
+		QueryExpectedValue:      "se esperaba un valor para el campo '{field}' pero se encontró {found}",
+		QueryEmptyValue:         "valor vacío",
+		QueryInvalidValue:       "valor no válido '{value}' para el campo '{field}' (se esperaba uno de: {values})",
+		QueryExpectedTerm:       "se esperaba un término pero se encontró {found}",
+		QueryEnd:                "el fin de la consulta",
+	},
+}
@@ -0,0 +1,43 @@
+package i18n
+
+import "errors"
+
+// Localized is an error that can describe itself in a language (problem details of service errors)
+type Localized interface {
+	error
+	Localize(lang string) string
+}
+
+// Error is an error kind (e.g. utils.ErrBadRequest) with a stable message key and its params
+type Error struct {
+	Err    error
+	Key    string
+	Params Params
+}
+
+// NewError builds an error of a kind described by a catalog message
+func NewError(err error, key string, params Params) *Error {
+	return &Error{Err: err, Key: key, Params: params}
+}
+
+// Error is the English message, prefixed by the kind (logs)
+func (e *Error) Error() string {
+	return e.Err.Error() + ": " + e.Localize(DefaultLanguage)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Localize(lang string) string {
+	return Text(lang, e.Key, e.Params)
+}
+
+// Describe returns the message of an error in a language (the error text when it can't be localized)
+func Describe(lang string, err error) string {
+	var localized Localized
+	if errors.As(err, &localized) {
+		return localized.Localize(lang)
+	}
+	return err.Error()
+}
@@ -0,0 +1,90 @@
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLanguage is used when the client accepts no supported language
+const DefaultLanguage = "en"
+
+// Params are the values of the {name} placeholders of a message
+type Params map[string]string
+
+// Supported reports whether there is a catalog for a language (base tag, e.g. es)
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Negotiate picks the supported language preferred by an Accept-Language header
+// (regional tags match their base language, e.g. es-AR matches es)
+func Negotiate(acceptLanguage string) string {
+
+	type candidate struct {
+		lang    string
+		quality float64
+	}
+
+	// Parse ranges and quality values (q=0 means not acceptable)
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			value, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = value
+		}
+		if tag == "" || quality <= 0 {
+			continue
+		}
+		base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		candidates = append(candidates, candidate{lang: base, quality: quality})
+	}
+
+	// Highest quality first, header order for ties
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+	for _, c := range candidates {
+		if c.lang == "*" {
+			return DefaultLanguage
+		}
+		if Supported(c.lang) {
+			return c.lang
+		}
+	}
+	return DefaultLanguage
+}
+
+// Text returns the message of a key in a language, falling back to the default language,
+// and then to the detail param (or the key itself) for keys missing from every catalog
+func Text(lang, key string, params Params) string {
+	text, ok := catalogs[lang][key]
+	if !ok {
+		text, ok = catalogs[DefaultLanguage][key]
+	}
+	if !ok {
+		if detail := params["detail"]; detail != "" {
+			return detail
+		}
+		return key
+	}
+	return expand(text, params)
+}
+
+// expand replaces the {name} placeholders of a message (unknown placeholders are kept)
+func expand(text string, params Params) string {
+	if len(params) == 0 || !strings.Contains(text, "{") {
+		return text
+	}
+	pairs := make([]string, 0, 2*len(params))
+	for name, value := range params {
+		pairs = append(pairs, "{"+name+"}", value)
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
@@ -0,0 +1,60 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	cases := map[string]string{
+		"":                          "en",
+		"es":                        "es",
+		"es-AR,es;q=0.9,en;q=0.8":   "es",
+		"EN-us":                     "en",
+		"fr-FR, es;q=0.5, en;q=0.4": "es", // First supported by quality
+		"en;q=0.3, es;q=0.7":        "es",
+		"es;q=0, en":                "en", // Not acceptable
+		"fr, de":                    "en", // Default fallback
+		"*":                         "en",
+		"es;q=abc, en":              "en", // Malformed quality ignored
+	}
+	for header, expected := range cases {
+		assert.Equal(t, expected, Negotiate(header), header)
+	}
+}
+
+func TestText(t *testing.T) {
+	assert.Equal(t, "Libro no encontrado", Text("es", "book_not_found", nil))
+	assert.Equal(t, "Book not found", Text("en", "book_not_found", nil))
+
+	// Unsupported language falls back to English
+	assert.Equal(t, "Book deleted", Text("fr", MessageBookDeleted, nil))
+
+	// Placeholders
+	assert.Equal(t, "title es obligatorio", Text("es", RuleRequired, Params{"field": "title"}))
+	assert.Equal(t, "Solicitud no válida: invalid cursor", Text("es", "validation_failed", Params{"detail": "invalid cursor"}))
+	assert.Equal(t, "Invalid request: invalid cursor", Text("en", "validation_failed", Params{"detail": "invalid cursor"}))
+
+	// Keys missing from every catalog fall back to the detail, or the key
+	assert.Equal(t, "Something failed", Text("es", "unknown_code", Params{"detail": "Something failed"}))
+	assert.Equal(t, "unknown_code", Text("es", "unknown_code", nil))
+}
+
+func TestError(t *testing.T) {
+	err := NewError(errors.New("bad request"), ErrorUnknownStatus, Params{"status": "stolen"})
+
+	assert.Equal(t, "bad request: unknown status 'stolen'", err.Error())
+	assert.Equal(t, "estado desconocido 'stolen'", Describe("es", fmt.Errorf("wrapped: %w", err)))
+	assert.Equal(t, "plain", Describe("es", errors.New("plain"))) // Not localizable
+}
+
+func TestCatalogsComplete(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for key := range catalogs[DefaultLanguage] {
+			assert.NotEmpty(t, catalog[key], "%s: %s", lang, key)
+		}
+	}
+}
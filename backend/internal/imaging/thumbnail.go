@@ -0,0 +1,120 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"net/http"
+
+	_ "image/gif" // Register GIF decoder
+	_ "image/png" // Register PNG decoder
+)
+
+// Supported image content types (sniffed from the data, not trusted from the client)
+var supportedTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// Maximum decoded dimensions (protects against decompression bombs)
+const maxPixels = 40_000_000
+
+// Thumbnail encoding quality (1-100)
+const jpegQuality = 85
+
+// ErrUnsupportedType is returned for data that is not a supported image
+var ErrUnsupportedType = errors.New("unsupported image type")
+
+// ErrTooLarge is returned for images whose dimensions exceed the decoding limit
+var ErrTooLarge = errors.New("image dimensions too large")
+
+// DetectContentType sniffs the content type of an image, failing when it is not supported
+func DetectContentType(data []byte) (string, error) {
+	contentType := http.DetectContentType(data)
+	if !supportedTypes[contentType] {
+		return "", ErrUnsupportedType
+	}
+	return contentType, nil
+}
+
+// Decode checks the dimensions of an image before decoding it
+func Decode(data []byte) (image.Image, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxPixels {
+		return nil, ErrTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+	return img, nil
+}
+
+// Thumbnail scales an image down to the given width (keeping the aspect ratio) and encodes it as JPEG.
+// Images narrower than width are not upscaled.
+func Thumbnail(img image.Image, width int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, Resize(img, width), &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Resize scales an image down to the given width with area averaging (box filter), keeping the aspect ratio.
+// Transparent pixels are composed over white (JPEG has no alpha channel).
+func Resize(img image.Image, width int) *image.RGBA {
+
+	// Target size (never upscaled, at least 1 pixel)
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	if width <= 0 || width > srcW {
+		width = srcW
+	}
+	height := srcH * width / srcW
+	if height < 1 {
+		height = 1
+	}
+
+	// Average the source pixels covered by each target pixel
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := span(y, height, srcH)
+		for x := 0; x < width; x++ {
+			x0, x1 := span(x, width, srcW)
+			var r, g, b, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := color.NRGBA64Model.Convert(img.At(bounds.Min.X+sx, bounds.Min.Y+sy)).(color.NRGBA64)
+					a := uint64(c.A)
+					r += (uint64(c.R)*a + 0xffff*(0xffff-a)) / 0xffff // Over white
+					g += (uint64(c.G)*a + 0xffff*(0xffff-a)) / 0xffff
+					b += (uint64(c.B)*a + 0xffff*(0xffff-a)) / 0xffff
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(b / n >> 8),
+				A: 0xff,
+			})
+		}
+	}
+	return dst
+}
+
+// span returns the source range [start, end) covered by target index i (at least one pixel)
+func span(i, target, source int) (int, int) {
+	start := i * source / target
+	end := (i + 1) * source / target
+	if end <= start {
+		end = start + 1
+	}
+	return start, end
+}
@@ -0,0 +1,78 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestDetectContentType(t *testing.T) {
+	data := encodePNG(t, image.NewRGBA(image.Rect(0, 0, 2, 2)))
+
+	contentType, err := DetectContentType(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+
+	_, err = DetectContentType([]byte("%PDF-1.7 not an image"))
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+}
+
+func TestDecode_TooLarge(t *testing.T) {
+	data := encodePNG(t, image.NewGray(image.Rect(0, 0, 8000, 6000))) // 48 Mpx
+
+	_, err := Decode(data)
+	assert.ErrorIs(t, err, ErrTooLarge)
+}
+
+func TestResize_KeepsAspectRatioAndAverages(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 400; x++ {
+			if x%2 == 0 {
+				src.Set(x, y, color.White)
+			} else {
+				src.Set(x, y, color.Black)
+			}
+		}
+	}
+
+	dst := Resize(src, 100)
+
+	assert.Equal(t, image.Rect(0, 0, 100, 50), dst.Bounds())
+	c := dst.RGBAAt(10, 10) // 4x4 block of alternating columns: mid gray
+	assert.InDelta(t, 127, int(c.R), 1)
+	assert.Equal(t, uint8(0xff), c.A)
+}
+
+func TestResize_NoUpscaleAndTransparencyOverWhite(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 50, 80)) // Fully transparent
+
+	dst := Resize(src, 300)
+
+	assert.Equal(t, image.Rect(0, 0, 50, 80), dst.Bounds())
+	assert.Equal(t, color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}, dst.RGBAAt(0, 0))
+}
+
+func TestThumbnail_EncodesJPEG(t *testing.T) {
+	img, err := Decode(encodePNG(t, image.NewRGBA(image.Rect(0, 0, 600, 900))))
+	assert.NoError(t, err)
+
+	data, err := Thumbnail(img, 200)
+	assert.NoError(t, err)
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, "jpeg", format)
+	assert.Equal(t, 200, config.Width)
+	assert.Equal(t, 300, config.Height)
+}
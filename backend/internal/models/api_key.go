@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+/* Persistence */
+
+// APIKey is a credential issued to an API client. Only the SHA-256 hash of the key is stored.
+type APIKey struct {
+	ID        string     `db:"id"`     // Generated UUID
+	Name      string     `db:"name"`   // Client the key is issued to (rotation replaces its keys)
+	Prefix    string     `db:"prefix"` // First characters of the key (identifies it in listings)
+	KeyHash   string     `db:"key_hash"`
+	CreatedAt time.Time  `db:"created_at"`
+	ExpiresAt *time.Time `db:"expires_at"` // nil while the key is current
+}
+
+/* API */
+
+type RotateAPIKeyRequest struct {
+	Name        string        `json:"name" binding:"required,max=100"`
+	GracePeriod time.Duration `json:"grace_period"` // Previous keys stay valid for this long (0 revokes them now)
+	DryRun      bool          `json:"dry_run"`      // Report the keys that would expire without changing anything
+}
+
+type APIKeyResponse struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type RotateAPIKeyResponse struct {
+	Key      *APIKeyResponse  `json:"key,omitempty"`    // New key (omitted on dry run)
+	Secret   string           `json:"secret,omitempty"` // Plain key, only returned once
+	Expiring []APIKeyResponse `json:"expiring"`         // Previous keys of the client, with their new expiry
+	DryRun   bool             `json:"dry_run"`
+}
+
+// Active reports whether the key is current or still within its grace period
+func (k *APIKey) Active(now time.Time) bool {
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
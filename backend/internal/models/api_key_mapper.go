@@ -0,0 +1,21 @@
+package models
+
+// Map APIKey to APIKeyResponse (without hash)
+func ToAPIKeyResponse(key *APIKey) *APIKeyResponse {
+	return &APIKeyResponse{
+		ID:        key.ID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		CreatedAt: key.CreatedAt,
+		ExpiresAt: key.ExpiresAt,
+	}
+}
+
+// Map APIKey[] to APIKeyResponse[]
+func ToAPIKeyResponseList(keys []APIKey) []APIKeyResponse {
+	responses := make([]APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		responses = append(responses, *ToAPIKeyResponse(&key))
+	}
+	return responses
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+/* Persistence */
+
+type ContributorRole string
+
+const (
+	ContributorRoleAuthor      ContributorRole = "author"
+	ContributorRoleEditor      ContributorRole = "editor"
+	ContributorRoleTranslator  ContributorRole = "translator"
+	ContributorRoleIllustrator ContributorRole = "illustrator"
+)
+
+type Author struct {
+	ID        string    `db:"id"` // Generated UUID
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+
+	// Number of (non-deleted) books contributed to (not persisted, filled by queries)
+	BookCount int `db:"book_count"`
+}
+
+// Contributor is an author credited on a book with a role (ordered by position)
+type Contributor struct {
+	AuthorID string          `json:"author_id"`
+	Name     string          `json:"name"`
+	Role     ContributorRole `json:"role"`
+}
+
+// Contributors is scanned from a JSON array aggregated by the book queries
+type Contributors []Contributor
+
+// Scan implements sql.Scanner (JSON array)
+func (c *Contributors) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	default:
+		return fmt.Errorf("cannot scan %T into Contributors", src)
+	}
+}
+
+// AuthorDisplay computes the backward-compatible author string: the names credited as authors, in order.
+// Falls back to all contributor names when nobody is credited as author.
+func (c Contributors) AuthorDisplay() string {
+	var authors, all []string
+	for _, contributor := range c {
+		all = append(all, contributor.Name)
+		if contributor.Role == ContributorRoleAuthor {
+			authors = append(authors, contributor.Name)
+		}
+	}
+	if len(authors) == 0 {
+		authors = all
+	}
+	return strings.Join(authors, ", ")
+}
+
+/* API */
+
+// ContributorPayload credits an author (by name, created when unknown) on a book
+type ContributorPayload struct {
+	Name string          `json:"name" binding:"required,max=255"`
+	Role ContributorRole `json:"role" binding:"omitempty,oneof=author editor translator illustrator"` // Default author
+}
+
+type ContributorResponse struct {
+	AuthorID string          `json:"author_id"`
+	Name     string          `json:"name"`
+	Role     ContributorRole `json:"role"`
+}
+
+type AuthorResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	BookCount int       `json:"book_count"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ListAuthorsRequest struct {
+	Page     int    `form:"page" binding:"omitempty,min=1"`      // 1-based index (default 1)
+	PageSize int    `form:"page_size" binding:"omitempty,min=1"` // items per page (default 10)
+	Name     string `form:"name" binding:"max=255"`              // Contains (accent- and case-insensitive)
+}
+
+type ListAuthorsResponse struct {
+
+	// Data
+	Authors []AuthorResponse `json:"authors"`
+
+	// Pagination
+	TotalItems  int `json:"total_items"`
+	TotalPages  int `json:"total_pages"`
+	CurrentPage int `json:"current_page"`
+	PageSize    int `json:"page_size"`
+}
+
+// NormalizeContributors trims names, applies the default role and removes blanks and
+// case-insensitive duplicates of the same name and role (first occurrence wins). A nil slice stays nil.
+func NormalizeContributors(contributors []ContributorPayload) []ContributorPayload {
+	if contributors == nil {
+		return nil
+	}
+	seen := map[string]bool{}
+	result := []ContributorPayload{}
+	for _, contributor := range contributors {
+		contributor.Name = strings.TrimSpace(contributor.Name)
+		if contributor.Role == "" {
+			contributor.Role = ContributorRoleAuthor
+		}
+		key := strings.ToLower(contributor.Name) + "|" + string(contributor.Role)
+		if contributor.Name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, contributor)
+	}
+	return result
+}
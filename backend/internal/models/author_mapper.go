@@ -0,0 +1,34 @@
+package models
+
+// Map Author to AuthorResponse
+func ToAuthorResponse(author *Author) *AuthorResponse {
+	return &AuthorResponse{
+		ID:        author.ID,
+		Name:      author.Name,
+		BookCount: author.BookCount,
+		CreatedAt: author.CreatedAt,
+		UpdatedAt: author.UpdatedAt,
+	}
+}
+
+// Map Author[] to AuthorResponse[]
+func ToAuthorResponseList(authors []Author) []AuthorResponse {
+	responses := make([]AuthorResponse, 0, len(authors))
+	for _, author := range authors {
+		responses = append(responses, *ToAuthorResponse(&author))
+	}
+	return responses
+}
+
+// Map Contributor[] to ContributorResponse[]
+func ToContributorResponseList(contributors Contributors) []ContributorResponse {
+	responses := make([]ContributorResponse, 0, len(contributors))
+	for _, c := range contributors {
+		responses = append(responses, ContributorResponse{
+			AuthorID: c.AuthorID,
+			Name:     c.Name,
+			Role:     c.Role,
+		})
+	}
+	return responses
+}
@@ -3,6 +3,8 @@ package models
 import (
 	"strings"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 /* Persistence */
@@ -12,8 +14,40 @@ type BookStatus string
 const (
 	BookStatusAvailable  BookStatus = "available"
 	BookStatusCheckedOut BookStatus = "checked_out"
+	BookStatusLost       BookStatus = "lost"
+	BookStatusDamaged    BookStatus = "damaged"
+	BookStatusInRepair   BookStatus = "in_repair"
+	BookStatusMissing    BookStatus = "missing"   // Not found on the shelf (e.g. during inventory)
+	BookStatusWithdrawn  BookStatus = "withdrawn" // Removed from circulation (final)
+)
+
+// BookStatuses lists every book status
+var BookStatuses = []BookStatus{
+	BookStatusAvailable, BookStatusCheckedOut, BookStatusLost, BookStatusDamaged,
+	BookStatusInRepair, BookStatusMissing, BookStatusWithdrawn,
+}
+
+// StatusChangeSource is where a status change was made
+type StatusChangeSource string
+
+const (
+	StatusChangeSourceDesk  StatusChangeSource = "desk"
+	StatusChangeSourceKiosk StatusChangeSource = "kiosk"
+	StatusChangeSourceAPI   StatusChangeSource = "api"
+)
+
+type BookFormat string
+
+const (
+	BookFormatHardcover BookFormat = "hardcover"
+	BookFormatPaperback BookFormat = "paperback"
+	BookFormatEbook     BookFormat = "ebook"
+	BookFormatAudiobook BookFormat = "audiobook"
 )
 
+// BookFormats lists every book format
+var BookFormats = []BookFormat{BookFormatHardcover, BookFormatPaperback, BookFormatEbook, BookFormatAudiobook}
+
 type Book struct {
 	ID          string     `db:"id"` // Generated UUID
 	ISBN        string     `db:"isbn"`
@@ -24,6 +58,28 @@ type Book struct {
 	CreatedAt   time.Time  `db:"created_at"`
 	UpdatedAt   time.Time  `db:"updated_at"`
 	Deleted     bool       `db:"deleted"` // Logical delete
+
+	// Bibliographic details (empty / 0 when unknown)
+	Publisher       string     `db:"publisher"`
+	PublicationYear int        `db:"publication_year"`
+	Edition         string     `db:"edition"`
+	Language        string     `db:"language"` // ISO 639-1 code (lowercase)
+	PageCount       int        `db:"page_count"`
+	Format          BookFormat `db:"format"`
+
+	// Cover image upload time (nil without cover)
+	CoverUpdatedAt *time.Time `db:"cover_updated_at"`
+
+	// Tag names, sorted (read-only, loaded from book_tags)
+	Tags pq.StringArray `db:"tags"`
+
+	// Credited authors with roles, in order (read-only, loaded from book_contributors). Author holds their display string.
+	Contributors Contributors `db:"contributors"`
+
+	// Search metadata (not persisted, only filled by full-text list queries)
+	Rank                 float64 `db:"rank"`
+	TitleHighlight       string  `db:"title_highlight"`
+	DescriptionHighlight string  `db:"description_highlight"`
 }
 
 type BookStatusChange struct {
@@ -31,16 +87,73 @@ type BookStatusChange struct {
 	BookID    string     `db:"book_id"` // FK to Book.ID
 	Status    BookStatus `db:"status"`
 	Timestamp time.Time  `db:"timestamp"`
+
+	// Who made the change, where, and why (empty when unknown, e.g. changes recorded before they were tracked)
+	Actor  string             `db:"actor"`
+	Source StatusChangeSource `db:"source"`
+	Note   string             `db:"note"`
 }
 
 /* API */
 
+// SortFields is the whitelist of sortable fields (relevance requires a text query)
+var SortFields = map[string]bool{
+	"isbn":             true,
+	"title":            true,
+	"author":           true,
+	"status":           true,
+	"created_at":       true,
+	"updated_at":       true,
+	"publisher":        true,
+	"publication_year": true,
+	"page_count":       true,
+	"relevance":        true,
+}
+
+// Sort directions
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// SortSpec is one ordering criterion of a list request
+type SortSpec struct {
+	Field string `json:"field" binding:"required"`
+	Order string `json:"order"` // asc / desc (default asc, desc for relevance)
+}
+
+// Match modes for text filters
+const (
+	MatchContains = "contains"
+	MatchExact    = "exact"
+)
+
+// Match modes for multi-valued filters
+const (
+	MatchAny = "any"
+	MatchAll = "all"
+)
+
 // BookPayload is a common request for creating and updating books
 type BookPayload struct {
 	ISBN        string `json:"isbn" binding:"required,max=20"`
 	Title       string `json:"title" binding:"required,max=255"`
-	Author      string `json:"author" binding:"required,max=255"`
+	Author      string `json:"author" binding:"required_without=Contributors,max=255"` // Single author (ignored when Contributors is given)
 	Description string `json:"description" binding:"max=1000"`
+
+	// Bibliographic details (optional)
+	Publisher       string     `json:"publisher" binding:"max=255"`
+	PublicationYear int        `json:"publication_year" binding:"omitempty,min=1,max=9999"`
+	Edition         string     `json:"edition" binding:"max=50"`
+	Language        string     `json:"language" binding:"omitempty,len=2,alpha"` // ISO 639-1 code (e.g. en, es)
+	PageCount       int        `json:"page_count" binding:"omitempty,min=1,max=100000"`
+	Format          BookFormat `json:"format" binding:"omitempty,oneof=hardcover paperback ebook audiobook"`
+
+	// Credited authors with roles, in order. Author is then computed from the names credited as authors.
+	Contributors []ContributorPayload `json:"contributors" binding:"omitempty,max=20,dive"`
+
+	// Tag names (created when unknown). Omitted keeps the current tags on update, [] removes them all.
+	Tags []string `json:"tags" binding:"omitempty,max=20,dive,max=50"`
 }
 
 type CreateBookRequest = BookPayload
@@ -49,17 +162,111 @@ type UpdateBookRequest = BookPayload
 type ListBooksRequest struct {
 
 	// Pagination
-	Page      int    `json:"page" binding:"required,min=1"`      // 1-based index
-	PageSize  int    `json:"page_size" binding:"required,min=1"` // items per page
-	SortBy    string `json:"sort_by"`                            // isbn, title, author, status
-	SortOrder string `json:"sort_order"`                         // asc / desc
+	Page      int    `json:"page" form:"page" binding:"required_unless=Pagination cursor,omitempty,min=1"` // 1-based index (offset mode)
+	PageSize  int    `json:"page_size" form:"page_size" binding:"required,min=1"`                          // items per page
+	SortBy    string `json:"sort_by" form:"sort_by"`                                                       // Single sort (see SortFields), ignored when Sort is given
+	SortOrder string `json:"sort_order" form:"sort_order"`                                                 // asc / desc
+
+	// Multi-column sort, in priority order (query string: sort=field:order, repeated)
+	Sort      []SortSpec `json:"sort" form:"-" binding:"max=5,dive"`
+	SortQuery []string   `json:"-" form:"sort" binding:"max=5"`
+
+	// Keyset pagination (pagination = "cursor"): empty cursor for the first page, then next_cursor / prev_cursor
+	Pagination string `json:"pagination" form:"pagination" binding:"omitempty,oneof=offset cursor"`
+	Cursor     string `json:"cursor" form:"cursor" binding:"max=1000"`
+	SkipTotal  bool   `json:"skip_total" form:"skip_total"` // Skip the COUNT query (cursor mode only, total_items/total_pages are then 0)
 
 	// Filters
-	ISBN   string `json:"isbn" binding:"max=20"`
-	Title  string `json:"title" binding:"max=255"`
-	Author string `json:"author" binding:"max=255"`
-	Status string `json:"status" binding:"max=20"`
-	Text   string `json:"text" binding:"max=500"`
+	ISBN   string `json:"isbn" form:"isbn" binding:"max=20"`
+	Title  string `json:"title" form:"title" binding:"max=255"`
+	Author string `json:"author" form:"author" binding:"max=255"`
+	Status string `json:"status" form:"status" binding:"max=20"`
+	Text   string `json:"text" form:"text" binding:"max=500"` // Full-text search (web search syntax) over title, author, description
+	Q      string `json:"q" form:"q" binding:"max=500"`       // Query-language expression, e.g. author:tolkien AND (title:"ring" OR status:available) -description:abridged
+
+	// Match mode per text filter: contains (default) or exact (case-insensitive)
+	ISBNMatch   string `json:"isbn_match" form:"isbn_match" binding:"omitempty,oneof=contains exact"`
+	TitleMatch  string `json:"title_match" form:"title_match" binding:"omitempty,oneof=contains exact"`
+	AuthorMatch string `json:"author_match" form:"author_match" binding:"omitempty,oneof=contains exact"`
+
+	// Tag filter (case-insensitive names), matching books with any (default) or all of them
+	Tags      []string `json:"tags" form:"tags" binding:"max=20,dive,max=50"`
+	TagsMatch string   `json:"tags_match" form:"tags_match" binding:"omitempty,oneof=any all"`
+
+	// Contributor filter: books credited to the author (optionally only with the given role)
+	AuthorID        string `json:"author_id" form:"author_id" binding:"omitempty,uuid"`
+	ContributorRole string `json:"contributor_role" form:"contributor_role" binding:"omitempty,oneof=author editor translator illustrator"`
+
+	// Multi-valued status filter (any of)
+	Statuses []string `json:"statuses" form:"statuses" binding:"max=10,dive,max=20"`
+
+	// Bibliographic filters: publisher (contains), language (ISO 639-1), formats (any of), publication year range (inclusive)
+	Publisher string   `json:"publisher" form:"publisher" binding:"max=255"`
+	Language  string   `json:"language" form:"language" binding:"omitempty,len=2,alpha"`
+	Formats   []string `json:"formats" form:"formats" binding:"max=4,dive,oneof=hardcover paperback ebook audiobook"`
+	YearFrom  int      `json:"year_from" form:"year_from" binding:"omitempty,min=1,max=9999"`
+	YearTo    int      `json:"year_to" form:"year_to" binding:"omitempty,min=1,max=9999"`
+
+	// Date ranges (RFC 3339, from inclusive, to exclusive)
+	CreatedFrom *time.Time `json:"created_from" form:"created_from"`
+	CreatedTo   *time.Time `json:"created_to" form:"created_to"`
+	UpdatedFrom *time.Time `json:"updated_from" form:"updated_from"`
+	UpdatedTo   *time.Time `json:"updated_to" form:"updated_to"`
+
+	// Facets to count over the filtered result (status, author, language, format)
+	Facets []string `json:"facets" form:"facets" binding:"max=5,dive,oneof=status author language format"`
+
+	// Internal: match title/author/text by trigram similarity (set by the service when exact matching finds nothing)
+	Fuzzy bool `json:"-" form:"-"`
+}
+
+// Sorts returns the effective ordering: the Sort specs, or the single SortBy/SortOrder (default title asc).
+// Fields are not validated here, only orders are normalized.
+func (r *ListBooksRequest) Sorts() []SortSpec {
+
+	specs := r.Sort
+	if len(specs) == 0 {
+		field, order := r.SortBy, r.SortOrder
+		if field == "" {
+			field = "title"
+		}
+		if field == "relevance" {
+			order = SortDesc // Single relevance sort is always best matches first
+		}
+		specs = []SortSpec{{Field: field, Order: order}}
+	}
+
+	sorts := make([]SortSpec, 0, len(specs))
+	for _, spec := range specs {
+		order := strings.ToLower(spec.Order)
+		if order == "" {
+			order = SortAsc
+			if spec.Field == "relevance" {
+				order = SortDesc // Best matches first
+			}
+		}
+		sorts = append(sorts, SortSpec{Field: spec.Field, Order: order})
+	}
+	return sorts
+}
+
+// SortSignature identifies the effective ordering (e.g. "author:asc,title:desc")
+func (r *ListBooksRequest) SortSignature() string {
+	parts := []string{}
+	for _, spec := range r.Sorts() {
+		parts = append(parts, spec.Field+":"+spec.Order)
+	}
+	return strings.Join(parts, ",")
+}
+
+// IsCursorMode reports whether the request uses keyset pagination
+func (r *ListBooksRequest) IsCursorMode() bool {
+	return r.Pagination == PaginationCursor
+}
+
+// HasSearchTerms reports whether the request has free-text terms that can be matched approximately
+func (r *ListBooksRequest) HasSearchTerms() bool {
+	return r.Text != "" || r.Title != "" || r.Author != ""
 }
 
 // BookResponse is a common request for creating, getting, updating, and listing books (within ListBooksResponse)
@@ -72,6 +279,30 @@ type BookResponse struct {
 	Status      BookStatus `json:"status"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
+	Tags        []string   `json:"tags"`
+
+	// Bibliographic details (empty / 0 when unknown)
+	Publisher       string     `json:"publisher"`
+	PublicationYear int        `json:"publication_year"`
+	Edition         string     `json:"edition"`
+	Language        string     `json:"language"`
+	PageCount       int        `json:"page_count"`
+	Format          BookFormat `json:"format"`
+
+	// Cover image path relative to the API base URL, versioned by upload time (omitted without cover)
+	CoverURL string `json:"cover_url,omitempty"`
+
+	// Credited authors with roles, in order
+	Contributors []ContributorResponse `json:"contributors"`
+
+	// Full-text search snippets (only when listing with text)
+	Highlights *BookHighlights `json:"highlights,omitempty"`
+}
+
+// BookHighlights holds HTML-escaped matched fragments, with the matches wrapped in <mark> tags
+type BookHighlights struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
 }
 
 type ListBooksResponse struct {
@@ -82,18 +313,85 @@ type ListBooksResponse struct {
 	// Pagination
 	TotalItems  int `json:"total_items"` // total items matching the filter
 	TotalPages  int `json:"total_pages"`
-	CurrentPage int `json:"current_page"`
+	CurrentPage int `json:"current_page"` // 0 in cursor mode
 	PageSize    int `json:"page_size"`
+
+	// Keyset pagination (cursor mode only)
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+
+	// Typo tolerance
+	FuzzyMatch  bool     `json:"fuzzy_match,omitempty"` // true when results come from approximate matching
+	Suggestions []string `json:"suggestions,omitempty"` // "did you mean" titles and authors (only when nothing matched exactly)
+
+	// Facet buckets per requested facet (e.g. "status": [{"value": "available", "count": 12}])
+	Facets map[string][]FacetBucket `json:"facets,omitempty"`
+
+	// Latest change of any book, deletes included (Last-Modified validator, not serialized)
+	LastModified time.Time `json:"-"`
+}
+
+// FacetBucket is the number of matching books for one value of a facet field
+type FacetBucket struct {
+	Value string `db:"value" json:"value"`
+	Count int    `db:"count" json:"count"`
+}
+
+// StatusChangeRequest is the optional body of checkout and checkin
+type StatusChangeRequest struct {
+	Actor  string             `json:"actor" binding:"max=255"`                         // e.g. staff member or patron card
+	Source StatusChangeSource `json:"source" binding:"omitempty,oneof=desk kiosk api"` // Default api
+	Note   string             `json:"note" binding:"max=1000"`
+}
+
+// BookStatusRequest sets the status of a book (subject to the allowed transitions)
+type BookStatusRequest struct {
+	Status BookStatus `json:"status" binding:"required,oneof=available checked_out lost damaged in_repair missing withdrawn"`
+	StatusChangeRequest
+}
+
+// InvalidTransitionResponse is the problem returned (409) when the book cannot move to the requested status
+type InvalidTransitionResponse struct {
+	ProblemResponse
+	CurrentStatus   BookStatus   `json:"current_status"`
+	AllowedStatuses []BookStatus `json:"allowed_statuses"` // Statuses the book can move to
 }
 
 type StatusChangeResponse struct {
-	Status    BookStatus `json:"status"`
-	Timestamp time.Time  `json:"timestamp"`
+	Status    BookStatus         `json:"status"`
+	Timestamp time.Time          `json:"timestamp"`
+	Actor     string             `json:"actor,omitempty"`
+	Source    StatusChangeSource `json:"source,omitempty"`
+	Note      string             `json:"note,omitempty"`
 }
 
-type BookDetailResponse struct {
-	Book    BookResponse           `json:"book"`
+type ListHistoryRequest struct {
+	Page     int       `form:"page" binding:"omitempty,min=1"`             // 1-based index (default 1)
+	PageSize int       `form:"page_size" binding:"omitempty,min=1"`        // items per page (default 10)
+	From     time.Time `form:"from" time_format:"2006-01-02" time_utc:"1"` // Inclusive (UTC date)
+	To       time.Time `form:"to" time_format:"2006-01-02" time_utc:"1"`   // Exclusive (UTC date)
+}
+
+type ListHistoryResponse struct {
+
+	// Data (newest first)
 	History []StatusChangeResponse `json:"history"`
+
+	// Pagination
+	TotalItems  int `json:"total_items"`
+	TotalPages  int `json:"total_pages"`
+	CurrentPage int `json:"current_page"`
+	PageSize    int `json:"page_size"`
+}
+
+// EmbeddedHistoryLimit caps the status changes embedded in a book (details and GraphQL), newest first.
+// The whole history is paginated at GET /books/:id/history.
+const EmbeddedHistoryLimit = 50
+
+type BookDetailResponse struct {
+	Book             BookResponse           `json:"book"`
+	History          []StatusChangeResponse `json:"history"`           // Latest EmbeddedHistoryLimit changes
+	HistoryTruncated bool                   `json:"history_truncated"` // Older changes at GET /books/:id/history
 }
 
 // Sanitize request fields
@@ -102,4 +400,33 @@ func (r *BookPayload) Sanitize() {
 	r.Title = strings.TrimSpace(r.Title)
 	r.Author = strings.TrimSpace(r.Author)
 	r.Description = strings.TrimSpace(r.Description)
+	r.Publisher = strings.TrimSpace(r.Publisher)
+	r.Edition = strings.TrimSpace(r.Edition)
+	r.Language = strings.ToLower(r.Language)
+	r.Tags = NormalizeTags(r.Tags)
+	r.Contributors = NormalizeContributors(r.Contributors)
+}
+
+// Sanitize request fields (and apply the default source)
+func (r *StatusChangeRequest) Sanitize() {
+	r.Actor = strings.TrimSpace(r.Actor)
+	r.Note = strings.TrimSpace(r.Note)
+	if r.Source == "" {
+		r.Source = StatusChangeSourceAPI
+	}
+}
+
+// ResolveContributors returns the contributors to store: the given list, or the single Author (legacy payloads)
+func (r *BookPayload) ResolveContributors() Contributors {
+	if len(r.Contributors) == 0 {
+		if r.Author == "" {
+			return Contributors{}
+		}
+		return Contributors{{Name: r.Author, Role: ContributorRoleAuthor}}
+	}
+	contributors := make(Contributors, 0, len(r.Contributors))
+	for _, c := range r.Contributors {
+		contributors = append(contributors, Contributor{Name: c.Name, Role: c.Role})
+	}
+	return contributors
 }
@@ -2,7 +2,7 @@ package models
 
 // Map Book to BookResponse
 func ToBookResponse(book *Book) *BookResponse {
-	return &BookResponse{
+	res := &BookResponse{
 		ID:          book.ID,
 		ISBN:        book.ISBN,
 		Title:       book.Title,
@@ -11,7 +11,29 @@ func ToBookResponse(book *Book) *BookResponse {
 		Status:      book.Status,
 		CreatedAt:   book.CreatedAt,
 		UpdatedAt:   book.UpdatedAt,
+		Tags:        book.Tags,
+
+		Publisher:       book.Publisher,
+		PublicationYear: book.PublicationYear,
+		Edition:         book.Edition,
+		Language:        book.Language,
+		PageCount:       book.PageCount,
+		Format:          book.Format,
+	}
+	if res.Tags == nil {
+		res.Tags = []string{}
+	}
+	res.Contributors = ToContributorResponseList(book.Contributors)
+	if book.CoverUpdatedAt != nil {
+		res.CoverURL = CoverURL(book.ID, *book.CoverUpdatedAt)
+	}
+	if book.TitleHighlight != "" || book.DescriptionHighlight != "" {
+		res.Highlights = &BookHighlights{
+			Title:       book.TitleHighlight,
+			Description: book.DescriptionHighlight,
+		}
 	}
+	return res
 }
 
 // Map Book[] to BookResponse[]
@@ -28,6 +50,9 @@ func ToStatusChangeResponse(sc BookStatusChange) StatusChangeResponse {
 	return StatusChangeResponse{
 		Status:    sc.Status,
 		Timestamp: sc.Timestamp,
+		Actor:     sc.Actor,
+		Source:    sc.Source,
+		Note:      sc.Note,
 	}
 }
 
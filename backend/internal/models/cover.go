@@ -0,0 +1,60 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Maximum size of an uploaded cover image (bytes). Kept below the AWS Lambda request payload limit.
+const MaxCoverSize = 4 << 20
+
+// CoverSizeOriginal identifies the uploaded image (as is)
+const CoverSizeOriginal = "original"
+
+// CoverThumbnail is a generated cover size (JPEG scaled down to Width, keeping the aspect ratio)
+type CoverThumbnail struct {
+	Size  string
+	Width int
+}
+
+// CoverThumbnails are generated on upload, smallest first
+var CoverThumbnails = []CoverThumbnail{
+	{Size: "small", Width: 96},
+	{Size: "medium", Width: 240},
+	{Size: "large", Width: 480},
+}
+
+// IsCoverSize reports whether size is the original or a generated thumbnail size
+func IsCoverSize(size string) bool {
+	if size == CoverSizeOriginal {
+		return true
+	}
+	for _, thumbnail := range CoverThumbnails {
+		if thumbnail.Size == size {
+			return true
+		}
+	}
+	return false
+}
+
+// CoverKey is the blob store key of a cover size of a book
+func CoverKey(bookID, size string) string {
+	if size == CoverSizeOriginal {
+		return fmt.Sprintf("covers/%s/original", bookID)
+	}
+	return fmt.Sprintf("covers/%s/%s.jpg", bookID, size)
+}
+
+// CoverURL is the path of the cover of a book relative to the API base URL (versioned so caches pick up new uploads)
+func CoverURL(bookID string, updatedAt time.Time) string {
+	return fmt.Sprintf("/books/%s/cover?v=%d", bookID, updatedAt.Unix())
+}
+
+// Cover is a cover image to serve: its content, or a temporary download URL from the blob store
+type Cover struct {
+	Data         []byte
+	ContentType  string
+	LastModified time.Time // Zero when unknown
+	URL          string    // Signed download URL (no content), valid for URLExpiresIn
+	URLExpiresIn time.Duration
+}
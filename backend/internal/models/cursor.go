@@ -0,0 +1,40 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// Pagination modes for ListBooksRequest
+const (
+	PaginationOffset = "offset" // Page number + page size (default)
+	PaginationCursor = "cursor" // Keyset pagination with opaque cursors
+)
+
+// Cursor is the decoded keyset position of a list page boundary
+type Cursor struct {
+	Sort     string        `json:"s"`           // Sort signature the cursor was issued for
+	Fuzzy    bool          `json:"f,omitempty"` // Issued for a typo-tolerant result set
+	Keys     []interface{} `json:"k"`           // Sort key values of the boundary book, one per sort spec
+	ID       string        `json:"i"`           // Boundary book ID (tiebreaker)
+	Backward bool          `json:"b,omitempty"` // true for prev cursors (fetch books before the boundary)
+}
+
+// EncodeCursor serializes a cursor into an opaque URL-safe token
+func EncodeCursor(c Cursor) string {
+	data, _ := json.Marshal(c) // Cannot fail for this struct
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses an opaque token generated by EncodeCursor
+func DecodeCursor(token string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,53 @@
+package models
+
+/* API */
+
+// BookEventStatusChanged is a stream filter alias matching every status change (checked out, checked in and inventory changes)
+const BookEventStatusChanged BookEventType = "book.status_changed"
+
+// BookEventStreamRequest holds the filters of the live book event stream (SSE)
+type BookEventStreamRequest struct {
+	BookID      string   `form:"book_id" binding:"omitempty,uuid"`
+	Types       []string `form:"type" binding:"max=7,dive,oneof=book.created book.updated book.deleted book.checked_out book.checked_in book.inventory_changed book.status_changed"` // Repeatable, any type when empty
+	LastEventID *int64   `form:"last_event_id" binding:"omitempty,min=0"`                                                                                                            // Resume position (Last-Event-ID header takes precedence)
+}
+
+// Sanitize request fields
+func (r *BookEventStreamRequest) Sanitize() {
+
+	// Expand the status change alias and deduplicate types (first occurrence wins)
+	seen := map[string]bool{}
+	types := []string{}
+	for _, eventType := range r.Types {
+		expanded := []string{eventType}
+		if eventType == string(BookEventStatusChanged) {
+			expanded = []string{string(BookEventCheckedOut), string(BookEventCheckedIn), string(BookEventInventoryChanged)}
+		}
+		for _, t := range expanded {
+			if !seen[t] {
+				seen[t] = true
+				types = append(types, t)
+			}
+		}
+	}
+	r.Types = types
+}
+
+// Matches reports whether an outbox event passes the stream filters
+func (r *BookEventStreamRequest) Matches(event *OutboxEvent) bool {
+	if event.AggregateType != AggregateBook {
+		return false
+	}
+	if r.BookID != "" && event.AggregateID != r.BookID {
+		return false
+	}
+	if len(r.Types) == 0 {
+		return true
+	}
+	for _, eventType := range r.Types {
+		if eventType == event.EventType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// BookStatusDrift is a book whose status does not match its latest status change
+// (books without history are expected to be available, the status they are created with)
+type BookStatusDrift struct {
+	BookID   string     `db:"book_id" json:"book_id"`
+	Title    string     `db:"title" json:"title"`
+	Status   BookStatus `db:"status" json:"status"`
+	Expected BookStatus `db:"expected" json:"expected"`
+}
+
+// SeedResponse summarizes a loaded synthetic dataset
+type SeedResponse struct {
+	Seed          int64     `json:"seed"`
+	From          time.Time `json:"from"`
+	To            time.Time `json:"to"`
+	Books         int       `json:"books"`
+	Authors       int       `json:"authors"`
+	Tags          int       `json:"tags"`
+	StatusChanges int       `json:"status_changes"`
+}
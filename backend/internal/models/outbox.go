@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Aggregate types of outbox events
+const (
+	AggregateBook = "book"
+)
+
+// OutboxEvent is a domain event recorded in the transaction of the state change (transactional outbox)
+// and dispatched to the event sinks after commit, at least once
+type OutboxEvent struct {
+	ID            string     `db:"id"`  // Event ID, also the deduplication ID for consumers
+	Seq           int64      `db:"seq"` // Commit stream position (assigned by the database, SSE event ID)
+	EventType     string     `db:"event_type"`
+	AggregateType string     `db:"aggregate_type"`
+	AggregateID   string     `db:"aggregate_id"`
+	Payload       []byte     `db:"payload"` // JSON body (e.g. BookEvent)
+	Attempts      int        `db:"attempts"`
+	NextAttemptAt time.Time  `db:"next_attempt_at"`
+	ProcessedAt   *time.Time `db:"processed_at"` // nil until delivered to all sinks
+	LastError     string     `db:"last_error"`
+	CreatedAt     time.Time  `db:"created_at"`
+}
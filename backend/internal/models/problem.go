@@ -0,0 +1,50 @@
+package models
+
+// ProblemCode is the stable, machine-readable code of an API error (the detail text may change)
+type ProblemCode string
+
+const (
+
+	// Invalid requests
+	ProblemInvalidRequestBody ProblemCode = "invalid_request_body"     // Binding of the JSON body failed (see errors)
+	ProblemInvalidQuery       ProblemCode = "invalid_query_parameters" // Binding of the query string failed (see errors)
+	ProblemMissingID          ProblemCode = "missing_id"
+	ProblemValidationFailed   ProblemCode = "validation_failed" // Rejected by the service (e.g. unknown language, invalid cursor)
+	ProblemInvalidLastEventID ProblemCode = "invalid_last_event_id"
+	ProblemMissingCover       ProblemCode = "missing_cover"
+	ProblemInvalidCover       ProblemCode = "invalid_cover"
+	ProblemCoverTooLarge      ProblemCode = "cover_too_large"
+	ProblemUnsupportedCover   ProblemCode = "unsupported_cover_type"
+	ProblemInvalidAPIKey      ProblemCode = "invalid_api_key"
+	ProblemInvalidTransition  ProblemCode = "invalid_status_transition"
+	ProblemTagNameConflict    ProblemCode = "tag_name_conflict"
+	ProblemBookNotFound       ProblemCode = "book_not_found"
+	ProblemAuthorNotFound     ProblemCode = "author_not_found"
+	ProblemTagNotFound        ProblemCode = "tag_not_found"
+	ProblemCoverNotFound      ProblemCode = "cover_not_found"
+	ProblemWebhookNotFound    ProblemCode = "webhook_not_found"
+	ProblemRouteNotFound      ProblemCode = "route_not_found"
+
+	// Server errors
+	ProblemInternal          ProblemCode = "internal_error"
+	ProblemStreamUnavailable ProblemCode = "event_stream_unavailable" // Live stream not served by this deployment (API Lambda)
+)
+
+// FieldError is a validation failure of a request field
+type FieldError struct {
+	Field   string `json:"field" example:"contributors[0].name"` // JSON (or query) name, with the path of nested fields
+	Rule    string `json:"rule" example:"required"`              // Failed validation (required, max, oneof...), or type for a wrong JSON type
+	Message string `json:"message" example:"contributors[0].name is required"`
+}
+
+// ProblemResponse is the body of every API error: an RFC 7807 problem (application/problem+json)
+type ProblemResponse struct {
+	Type      string       `json:"type" example:"about:blank"`
+	Title     string       `json:"title" example:"Bad Request"` // HTTP status text
+	Status    int          `json:"status" example:"400"`
+	Detail    string       `json:"detail" example:"Invalid request body"`
+	Instance  string       `json:"instance" example:"/books"` // Request path
+	Code      ProblemCode  `json:"code" example:"invalid_request_body"`
+	RequestID string       `json:"request_id" example:"5f0c6c2e-7a43-4b8e-9d0b-2a4f3c1e8b71"` // X-Request-ID of the response
+	Errors    []FieldError `json:"errors,omitempty"`                                          // Binding errors, one per invalid field
+}
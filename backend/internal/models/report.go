@@ -0,0 +1,174 @@
+package models
+
+import (
+	"strconv"
+	"time"
+)
+
+// Circulation reports are computed from the status changes of non-deleted books.
+// Windows are [from, to) in UTC; checkouts are counted when they start within the window.
+
+type ReportInterval string
+
+const (
+	ReportIntervalDay   ReportInterval = "day"
+	ReportIntervalWeek  ReportInterval = "week" // ISO weeks (starting on Monday)
+	ReportIntervalMonth ReportInterval = "month"
+)
+
+type ReportFormat string
+
+const (
+	ReportFormatJSON ReportFormat = "json"
+	ReportFormatCSV  ReportFormat = "csv"
+)
+
+/* Persistence (rows computed by the report queries, also returned by the API) */
+
+type BookCheckouts struct {
+	BookID    string `db:"book_id" json:"book_id"`
+	ISBN      string `db:"isbn" json:"isbn"`
+	Title     string `db:"title" json:"title"`
+	Author    string `db:"author" json:"author"`
+	Checkouts int    `db:"checkouts" json:"checkouts"`
+}
+
+type AuthorCheckouts struct {
+	AuthorID  string `db:"author_id" json:"author_id"`
+	Name      string `db:"name" json:"name"`
+	Checkouts int    `db:"checkouts" json:"checkouts"`
+}
+
+type CheckoutsPoint struct {
+	Period    time.Time `db:"period" json:"period"` // Start of the interval
+	Checkouts int       `db:"checkouts" json:"checkouts"`
+}
+
+type LoanDuration struct {
+	Loans       int     `db:"loans" json:"loans"`
+	AverageDays float64 `db:"average_days" json:"average_days"`
+	MedianDays  float64 `db:"median_days" json:"median_days"`
+}
+
+type CheckedOutCount struct {
+	Books      int `db:"books" json:"books"`
+	CheckedOut int `db:"checked_out" json:"checked_out"`
+}
+
+type NeverBorrowedBook struct {
+	BookID    string    `db:"book_id" json:"book_id"`
+	ISBN      string    `db:"isbn" json:"isbn"`
+	Title     string    `db:"title" json:"title"`
+	Author    string    `db:"author" json:"author"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+/* API */
+
+// ReportWindowRequest selects the window and the output format of a report
+type ReportWindowRequest struct {
+	From   time.Time    `form:"from" time_format:"2006-01-02" time_utc:"1"` // Inclusive (default: 30 days before to)
+	To     time.Time    `form:"to" time_format:"2006-01-02" time_utc:"1"`   // Exclusive (default: tomorrow, so today is included)
+	Format ReportFormat `form:"format" binding:"omitempty,oneof=json csv"`  // Default json (also csv with Accept: text/csv)
+}
+
+type TopReportRequest struct {
+	ReportWindowRequest
+	Limit int `form:"limit" binding:"omitempty,min=1"` // Default 10
+}
+
+type CheckoutsReportRequest struct {
+	ReportWindowRequest
+	Interval ReportInterval `form:"interval" binding:"omitempty,oneof=day week month"` // Default day
+}
+
+type NeverBorrowedReportRequest struct {
+	Limit  int          `form:"limit" binding:"omitempty,min=1"` // Default 10
+	Format ReportFormat `form:"format" binding:"omitempty,oneof=json csv"`
+}
+
+type CheckedOutReportRequest struct {
+	Format ReportFormat `form:"format" binding:"omitempty,oneof=json csv"`
+}
+
+type TopBooksReport struct {
+	From  time.Time       `json:"from"`
+	To    time.Time       `json:"to"`
+	Books []BookCheckouts `json:"books"`
+}
+
+type TopAuthorsReport struct {
+	From    time.Time         `json:"from"`
+	To      time.Time         `json:"to"`
+	Authors []AuthorCheckouts `json:"authors"`
+}
+
+type CheckoutsReport struct {
+	From     time.Time        `json:"from"`
+	To       time.Time        `json:"to"`
+	Interval ReportInterval   `json:"interval"`
+	Total    int              `json:"total"`
+	Points   []CheckoutsPoint `json:"points"` // Every interval of the window, oldest first (zero when no checkouts)
+}
+
+type LoanDurationReport struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	LoanDuration
+}
+
+type CheckedOutReport struct {
+	CheckedOutCount
+	Ratio float64 `json:"ratio"` // checked_out / books (0 without books)
+}
+
+type NeverBorrowedReport struct {
+	Total int                 `json:"total"`
+	Books []NeverBorrowedBook `json:"books"`
+}
+
+// CSV returns the header and rows of the report (same data as the JSON)
+func (r *TopBooksReport) CSV() ([]string, [][]string) {
+	rows := [][]string{}
+	for _, b := range r.Books {
+		rows = append(rows, []string{b.BookID, b.ISBN, b.Title, b.Author, strconv.Itoa(b.Checkouts)})
+	}
+	return []string{"book_id", "isbn", "title", "author", "checkouts"}, rows
+}
+
+func (r *TopAuthorsReport) CSV() ([]string, [][]string) {
+	rows := [][]string{}
+	for _, a := range r.Authors {
+		rows = append(rows, []string{a.AuthorID, a.Name, strconv.Itoa(a.Checkouts)})
+	}
+	return []string{"author_id", "name", "checkouts"}, rows
+}
+
+func (r *CheckoutsReport) CSV() ([]string, [][]string) {
+	rows := [][]string{}
+	for _, p := range r.Points {
+		rows = append(rows, []string{p.Period.Format("2006-01-02"), strconv.Itoa(p.Checkouts)})
+	}
+	return []string{"period", "checkouts"}, rows
+}
+
+func (r *LoanDurationReport) CSV() ([]string, [][]string) {
+	return []string{"from", "to", "loans", "average_days", "median_days"}, [][]string{{
+		r.From.Format("2006-01-02"), r.To.Format("2006-01-02"), strconv.Itoa(r.Loans),
+		strconv.FormatFloat(r.AverageDays, 'f', 2, 64), strconv.FormatFloat(r.MedianDays, 'f', 2, 64),
+	}}
+}
+
+func (r *CheckedOutReport) CSV() ([]string, [][]string) {
+	return []string{"books", "checked_out", "ratio"}, [][]string{{
+		strconv.Itoa(r.Books), strconv.Itoa(r.CheckedOut), strconv.FormatFloat(r.Ratio, 'f', 4, 64),
+	}}
+}
+
+func (r *NeverBorrowedReport) CSV() ([]string, [][]string) {
+	rows := [][]string{}
+	for _, b := range r.Books {
+		rows = append(rows, []string{b.BookID, b.ISBN, b.Title, b.Author, b.CreatedAt.Format(time.RFC3339)})
+	}
+	return []string{"book_id", "isbn", "title", "author", "created_at"}, rows
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+/* Persistence */
+
+type Tag struct {
+	ID        string    `db:"id"` // Generated UUID
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+
+	// Number of (non-deleted) books with the tag (not persisted, filled by queries)
+	BookCount int `db:"book_count"`
+}
+
+/* API */
+
+// TagPayload is a common request for creating and updating tags
+type TagPayload struct {
+	Name string `json:"name" binding:"required,max=50"`
+}
+
+type CreateTagRequest = TagPayload
+type UpdateTagRequest = TagPayload
+
+type TagResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	BookCount int       `json:"book_count"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Sanitize request fields
+func (r *TagPayload) Sanitize() {
+	r.Name = strings.TrimSpace(r.Name)
+}
+
+// NormalizeTags trims tag names and removes blanks and case-insensitive duplicates (first spelling wins).
+// A nil slice stays nil.
+func NormalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	seen := map[string]bool{}
+	result := []string{}
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, tag)
+	}
+	return result
+}
@@ -0,0 +1,21 @@
+package models
+
+// Map Tag to TagResponse
+func ToTagResponse(tag *Tag) *TagResponse {
+	return &TagResponse{
+		ID:        tag.ID,
+		Name:      tag.Name,
+		BookCount: tag.BookCount,
+		CreatedAt: tag.CreatedAt,
+		UpdatedAt: tag.UpdatedAt,
+	}
+}
+
+// Map Tag[] to TagResponse[]
+func ToTagResponseList(tags []Tag) []TagResponse {
+	responses := make([]TagResponse, 0, len(tags))
+	for _, tag := range tags {
+		responses = append(responses, *ToTagResponse(&tag))
+	}
+	return responses
+}
@@ -0,0 +1,158 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+/* Persistence */
+
+type BookEventType string
+
+const (
+	BookEventCreated    BookEventType = "book.created"
+	BookEventUpdated    BookEventType = "book.updated"
+	BookEventDeleted    BookEventType = "book.deleted"
+	BookEventCheckedOut BookEventType = "book.checked_out"
+	BookEventCheckedIn  BookEventType = "book.checked_in"
+
+	// Any other status change (lost, damaged, in repair, missing, withdrawn, or back to available)
+	BookEventInventoryChanged BookEventType = "book.inventory_changed"
+)
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"   // Waiting for the first attempt or a retry
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded" // Subscriber answered 2xx
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"    // Retries exhausted
+)
+
+// Webhook is a subscription of an external URL to book lifecycle events
+type Webhook struct {
+	ID        string         `db:"id"` // Generated UUID
+	URL       string         `db:"url"`
+	Secret    string         `db:"secret"` // HMAC-SHA256 key for delivery signatures
+	Events    pq.StringArray `db:"events"` // Subscribed BookEventType values
+	Active    bool           `db:"active"`
+	CreatedAt time.Time      `db:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at"`
+}
+
+// WebhookDelivery is one event sent (or to be sent) to a webhook, with the result of its last attempt
+type WebhookDelivery struct {
+	ID             string                `db:"id"`         // Generated UUID (X-Webhook-Delivery header)
+	WebhookID      string                `db:"webhook_id"` // FK to Webhook.ID
+	EventID        string                `db:"event_id"`   // Same for all deliveries (and redeliveries) of an event
+	EventType      BookEventType         `db:"event_type"`
+	Payload        []byte                `db:"payload"` // JSON body (BookEvent)
+	Status         WebhookDeliveryStatus `db:"status"`
+	Attempts       int                   `db:"attempts"`
+	NextAttemptAt  *time.Time            `db:"next_attempt_at"` // nil once succeeded or failed
+	LastAttemptAt  *time.Time            `db:"last_attempt_at"`
+	ResponseStatus int                   `db:"response_status"` // HTTP status of the last attempt (0 when no response)
+	LastError      string                `db:"last_error"`
+	CreatedAt      time.Time             `db:"created_at"`
+	UpdatedAt      time.Time             `db:"updated_at"`
+
+	// Webhook target (not persisted, filled by the due deliveries query)
+	URL    string `db:"url"`
+	Secret string `db:"secret"`
+}
+
+/* API */
+
+// BookEvent is the JSON body delivered to webhooks
+type BookEvent struct {
+	ID         string        `json:"id"` // Event ID (stable across retries and redeliveries)
+	Type       BookEventType `json:"type"`
+	OccurredAt time.Time     `json:"occurred_at"`
+	BookID     string        `json:"book_id"`
+	Book       *BookResponse `json:"book,omitempty"` // Book state after the operation (omitted for book.deleted)
+}
+
+// WebhookPayload is a common request for creating and updating webhooks
+type WebhookPayload struct {
+	URL    string   `json:"url" binding:"required,http_url,max=2048"`
+	Secret string   `json:"secret" binding:"omitempty,min=16,max=255"` // Generated on create when empty, kept on update when empty
+	Events []string `json:"events" binding:"required,min=1,max=6,dive,oneof=book.created book.updated book.deleted book.checked_out book.checked_in book.inventory_changed"`
+	Active *bool    `json:"active"` // Default true on create, kept on update when omitted
+}
+
+type CreateWebhookRequest = WebhookPayload
+type UpdateWebhookRequest = WebhookPayload
+
+type WebhookResponse struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	Secret    string    `json:"secret,omitempty"` // Only returned on create
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             string                `json:"id"`
+	WebhookID      string                `json:"webhook_id"`
+	EventID        string                `json:"event_id"`
+	EventType      BookEventType         `json:"event_type"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at,omitempty"`
+	LastAttemptAt  *time.Time            `json:"last_attempt_at,omitempty"`
+	ResponseStatus int                   `json:"response_status,omitempty"`
+	LastError      string                `json:"last_error,omitempty"`
+	Payload        json.RawMessage       `json:"payload" swaggertype:"object"`
+	CreatedAt      time.Time             `json:"created_at"`
+}
+
+type ListWebhookDeliveriesRequest struct {
+	Page     int    `form:"page" binding:"omitempty,min=1"`      // 1-based index (default 1)
+	PageSize int    `form:"page_size" binding:"omitempty,min=1"` // items per page (default 10)
+	Status   string `form:"status" binding:"omitempty,oneof=pending succeeded failed"`
+}
+
+type ListWebhookDeliveriesResponse struct {
+
+	// Data
+	Deliveries []WebhookDeliveryResponse `json:"deliveries"`
+
+	// Pagination
+	TotalItems  int `json:"total_items"`
+	TotalPages  int `json:"total_pages"`
+	CurrentPage int `json:"current_page"`
+	PageSize    int `json:"page_size"`
+}
+
+// Sanitize request fields
+func (r *WebhookPayload) Sanitize() {
+	r.URL = strings.TrimSpace(r.URL)
+
+	// Deduplicate events (first occurrence wins)
+	seen := map[string]bool{}
+	events := []string{}
+	for _, event := range r.Events {
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+	r.Events = events
+}
+
+// Subscribes reports whether the webhook is active and subscribed to the event type
+func (w *Webhook) Subscribes(eventType BookEventType) bool {
+	if !w.Active {
+		return false
+	}
+	for _, event := range w.Events {
+		if event == string(eventType) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package models
+
+// Map Webhook to WebhookResponse (without secret)
+func ToWebhookResponse(webhook *Webhook) *WebhookResponse {
+	events := []string(webhook.Events)
+	if events == nil {
+		events = []string{}
+	}
+	return &WebhookResponse{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    events,
+		Active:    webhook.Active,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+}
+
+// Map Webhook[] to WebhookResponse[]
+func ToWebhookResponseList(webhooks []Webhook) []WebhookResponse {
+	responses := make([]WebhookResponse, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		responses = append(responses, *ToWebhookResponse(&webhook))
+	}
+	return responses
+}
+
+// Map WebhookDelivery to WebhookDeliveryResponse
+func ToWebhookDeliveryResponse(delivery *WebhookDelivery) *WebhookDeliveryResponse {
+	return &WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastAttemptAt:  delivery.LastAttemptAt,
+		ResponseStatus: delivery.ResponseStatus,
+		LastError:      delivery.LastError,
+		Payload:        delivery.Payload,
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
+
+// Map WebhookDelivery[] to WebhookDeliveryResponse[]
+func ToWebhookDeliveryResponseList(deliveries []WebhookDelivery) []WebhookDeliveryResponse {
+	responses := make([]WebhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		responses = append(responses, *ToWebhookDeliveryResponse(&delivery))
+	}
+	return responses
+}
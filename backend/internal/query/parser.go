@@ -0,0 +1,370 @@
+package query
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/santiago-buildit/code-challenge/backend/internal/i18n"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+)
+
+/*
+Search expression syntax:
+
+	expr    := or
+	or      := and ( "OR" and )*
+	and     := unary ( [ "AND" ] unary )*        (adjacent terms are ANDed)
+	unary   := ( "NOT" | "-" ) unary | primary
+	primary := "(" expr ")" | term
+	term    := [ field ":" ] ( word | "quoted phrase" )
+
+Keywords are case-sensitive (lowercase "and" is a plain word).
+Example: author:tolkien AND (title:"ring" OR status:available) -description:abridged
+*/
+
+// Field describes how a qualified term (field:value) is matched
+type Field struct {
+	Column string   // SQL column
+	Exact  bool     // Equality instead of substring match (against a lowercase column: values are lowercased)
+	Values []string // Allowed values of exact fields (any value when empty)
+}
+
+// Schema defines the fields available to search expressions
+type Schema struct {
+	Fields    map[string]Field // Qualified terms (field:value)
+	Default   []string         // Columns matched by unqualified terms (any of)
+	Normalize string           // Optional SQL format applied to substring-match placeholders, e.g. "lower(f_unaccent(%s))"
+}
+
+// SyntaxError reports an invalid search expression at a 1-based character position, with the catalog key
+// of the reason (an empty found param is the end of the query)
+type SyntaxError struct {
+	Pos    int
+	Key    string
+	Params i18n.Params
+}
+
+func (e *SyntaxError) Error() string {
+	return e.Localize(i18n.DefaultLanguage)
+}
+
+// Localize describes the error in a language (problem details)
+func (e *SyntaxError) Localize(lang string) string {
+	return i18n.Text(lang, i18n.ErrorInvalidQuery, i18n.Params{"pos": strconv.Itoa(e.Pos), "reason": e.Reason(lang)})
+}
+
+// Reason describes what is wrong at the position in a language
+func (e *SyntaxError) Reason(lang string) string {
+	params := e.Params
+	if found, ok := params["found"]; ok && found == "" {
+		params = maps.Clone(params)
+		params["found"] = i18n.Text(lang, i18n.QueryEnd, nil)
+	}
+	return i18n.Text(lang, e.Key, params)
+}
+
+// Unwrap makes syntax errors match utils.ErrBadRequest
+func (e *SyntaxError) Unwrap() error {
+	return utils.ErrBadRequest
+}
+
+// Compile parses a search expression and translates it into a SQL condition with '?' placeholders
+func (s Schema) Compile(input string) (string, []interface{}, error) {
+
+	// Tokenize
+	tokens, err := tokenize(input)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Parse
+	p := &parser{tokens: tokens, schema: s}
+	node, err := p.parseOr()
+	if err != nil {
+		return "", nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return "", nil, &SyntaxError{Pos: tok.pos, Key: i18n.QueryUnexpected, Params: i18n.Params{"found": tok.found()}}
+	}
+
+	// Generate SQL
+	var args []interface{}
+	sql := node.sql(&args)
+	return sql, args, nil
+}
+
+/* Lexer */
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenColon
+	tokenMinus
+	tokenLParen
+	tokenRParen
+	tokenAnd
+	tokenOr
+	tokenNot
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int // 1-based character position
+}
+
+// found describes the token in syntax errors (empty at the end of the query, localized by SyntaxError)
+func (t token) found() string {
+	switch t.kind {
+	case tokenEOF:
+		return ""
+	case tokenString:
+		return fmt.Sprintf("%q", t.value)
+	default:
+		return fmt.Sprintf("'%s'", t.value)
+	}
+}
+
+func tokenize(input string) ([]token, error) {
+
+	var tokens []token
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, value: "(", pos: pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, value: ")", pos: pos})
+			i++
+		case r == ':':
+			tokens = append(tokens, token{kind: tokenColon, value: ":", pos: pos})
+			i++
+		case r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]): // Negation prefix
+			tokens = append(tokens, token{kind: tokenMinus, value: "-", pos: pos})
+			i++
+		case r == '"': // Quoted phrase (\" and \\ escapes)
+			var sb strings.Builder
+			i++
+			closed := false
+			for i < len(runes) {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					sb.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				if runes[i] == '"' {
+					closed = true
+					i++
+					break
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			if !closed {
+				return nil, &SyntaxError{Pos: pos, Key: i18n.QueryUnterminatedPhrase}
+			}
+			tokens = append(tokens, token{kind: tokenString, value: sb.String(), pos: pos})
+		default: // Word (or keyword)
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune(`():"`, runes[i]) {
+				i++
+			}
+			word := string(runes[start:i])
+			kind := map[string]tokenKind{"AND": tokenAnd, "OR": tokenOr, "NOT": tokenNot}[word]
+			if kind == 0 {
+				kind = tokenWord
+			}
+			tokens = append(tokens, token{kind: kind, value: word, pos: pos})
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(runes) + 1}), nil
+}
+
+/* Parser */
+
+type parser struct {
+	tokens []token
+	index  int
+	schema Schema
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.index]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.index]
+	if tok.kind != tokenEOF {
+		p.index++
+	}
+	return tok
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch p.peek().kind {
+		case tokenAnd:
+			p.next()
+		case tokenWord, tokenString, tokenMinus, tokenNot, tokenLParen: // Implicit AND
+		default:
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: "AND", left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if kind := p.peek().kind; kind == tokenNot || kind == tokenMinus {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, &SyntaxError{Pos: closing.pos, Key: i18n.QueryExpectedParen, Params: i18n.Params{"found": closing.found()}}
+		}
+		return inner, nil
+	case tokenString:
+		return p.defaultTerm(tok)
+	case tokenWord:
+		if p.peek().kind != tokenColon {
+			return p.defaultTerm(tok)
+		}
+
+		// Qualified term (field:value)
+		field, ok := p.schema.Fields[strings.ToLower(tok.value)]
+		if !ok {
+			return nil, &SyntaxError{Pos: tok.pos, Key: i18n.QueryUnknownField, Params: i18n.Params{"field": tok.value}}
+		}
+		p.next() // Colon
+		value := p.next()
+		if value.kind != tokenWord && value.kind != tokenString {
+			return nil, &SyntaxError{Pos: value.pos, Key: i18n.QueryExpectedValue, Params: i18n.Params{"field": tok.value, "found": value.found()}}
+		}
+		if value.value == "" {
+			return nil, &SyntaxError{Pos: value.pos, Key: i18n.QueryEmptyValue}
+		}
+		term := termNode{columns: []string{field.Column}, exact: field.Exact, normalize: p.schema.Normalize, value: value.value}
+		if field.Exact {
+			term.value = strings.ToLower(term.value)
+			if len(field.Values) > 0 && !slices.Contains(field.Values, term.value) {
+				return nil, &SyntaxError{Pos: value.pos, Key: i18n.QueryInvalidValue,
+					Params: i18n.Params{"value": value.value, "field": tok.value, "values": strings.Join(field.Values, ", ")}}
+			}
+		}
+		return term, nil
+	default:
+		return nil, &SyntaxError{Pos: tok.pos, Key: i18n.QueryExpectedTerm, Params: i18n.Params{"found": tok.found()}}
+	}
+}
+
+func (p *parser) defaultTerm(tok token) (node, error) {
+	if tok.value == "" {
+		return nil, &SyntaxError{Pos: tok.pos, Key: i18n.QueryEmptyValue}
+	}
+	return termNode{columns: p.schema.Default, normalize: p.schema.Normalize, value: tok.value}, nil
+}
+
+/* AST */
+
+type node interface {
+	sql(args *[]interface{}) string
+}
+
+type binaryNode struct {
+	op          string // AND / OR
+	left, right node
+}
+
+func (n binaryNode) sql(args *[]interface{}) string {
+	return "(" + n.left.sql(args) + " " + n.op + " " + n.right.sql(args) + ")"
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n notNode) sql(args *[]interface{}) string {
+	return "NOT " + n.operand.sql(args)
+}
+
+type termNode struct {
+	columns   []string // Any of
+	exact     bool
+	normalize string
+	value     string
+}
+
+func (n termNode) sql(args *[]interface{}) string {
+	placeholder := "?"
+	if n.normalize != "" && !n.exact {
+		placeholder = fmt.Sprintf(n.normalize, "?")
+	}
+
+	parts := make([]string, 0, len(n.columns))
+	for _, column := range n.columns {
+		if n.exact {
+			parts = append(parts, column+" = ?")
+			*args = append(*args, n.value)
+		} else {
+			parts = append(parts, "coalesce("+column+", '') ILIKE "+placeholder)
+			*args = append(*args, "%"+escapeLike(n.value)+"%")
+		}
+	}
+	return "(" + strings.Join(parts, " OR ") + ")"
+}
+
+// escapeLike escapes LIKE wildcards so values are matched literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
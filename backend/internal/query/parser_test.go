@@ -0,0 +1,105 @@
+package query_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/santiago-buildit/code-challenge/backend/internal/i18n"
+	"github.com/santiago-buildit/code-challenge/backend/internal/query"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var schema = query.Schema{
+	Fields: map[string]query.Field{
+		"title":  {Column: "title"},
+		"author": {Column: "author"},
+		"status": {Column: "status", Exact: true, Values: []string{"available", "checked_out"}},
+		"lang":   {Column: "language", Exact: true},
+	},
+	Default: []string{"title", "author"},
+}
+
+func TestCompile_Expression(t *testing.T) {
+	sql, args, err := schema.Compile(`author:tolkien AND (title:"ring" OR status:available) -title:abridged`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `(((coalesce(author, '') ILIKE ?) AND ((coalesce(title, '') ILIKE ?) OR (status = ?))) AND NOT (coalesce(title, '') ILIKE ?))`, sql)
+	assert.Equal(t, []interface{}{"%tolkien%", "%ring%", "available", "%abridged%"}, args)
+}
+
+func TestCompile_Precedence(t *testing.T) {
+
+	// AND binds tighter than OR, adjacent terms are ANDed
+	sql, args, err := schema.Compile(`title:a OR title:b title:c`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `((coalesce(title, '') ILIKE ?) OR ((coalesce(title, '') ILIKE ?) AND (coalesce(title, '') ILIKE ?)))`, sql)
+	assert.Equal(t, []interface{}{"%a%", "%b%", "%c%"}, args)
+}
+
+func TestCompile_DefaultTermsAndEscaping(t *testing.T) {
+	sql, args, err := schema.Compile(`NOT "100% \"pure\"" and_more`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `(NOT (coalesce(title, '') ILIKE ? OR coalesce(author, '') ILIKE ?) AND (coalesce(title, '') ILIKE ? OR coalesce(author, '') ILIKE ?))`, sql)
+	assert.Equal(t, []interface{}{`%100\% "pure"%`, `%100\% "pure"%`, `%and\_more%`, `%and\_more%`}, args)
+}
+
+func TestCompile_SyntaxErrors(t *testing.T) {
+	cases := []struct {
+		input string
+		pos   int
+		msg   string
+	}{
+		{`title:"ring`, 7, "unterminated quoted phrase"},
+		{`(title:ring`, 12, "expected ')' but found end of query"},
+		{`title:ring)`, 11, "unexpected ')'"},
+		{`publisher:penguin`, 1, "unknown field 'publisher'"},
+		{`author: tolkien OR`, 19, "expected a term but found end of query"},
+		{`title:(ring)`, 7, "expected value for field 'title' but found '('"},
+		{`title:ring AND OR author:x`, 16, "expected a term but found 'OR'"},
+		{`""`, 1, "empty value"},
+		{`title:ring status:borrowed`, 19, "invalid value 'borrowed' for field 'status' (expected one of: available, checked_out)"},
+	}
+	for _, c := range cases {
+		_, _, err := schema.Compile(c.input)
+
+		var syntaxErr *query.SyntaxError
+		if assert.True(t, errors.As(err, &syntaxErr), c.input) {
+			assert.Equal(t, c.pos, syntaxErr.Pos, c.input)
+			assert.Equal(t, c.msg, syntaxErr.Reason(i18n.DefaultLanguage), c.input)
+		}
+		assert.ErrorIs(t, err, utils.ErrBadRequest, c.input)
+	}
+}
+
+func TestSyntaxError_Localize(t *testing.T) {
+	_, _, err := schema.Compile(`(title:ring`)
+
+	var syntaxErr *query.SyntaxError
+	if assert.True(t, errors.As(err, &syntaxErr)) {
+		assert.Equal(t, "invalid query at position 12: expected ')' but found end of query", err.Error())
+		assert.Equal(t, "consulta no válida en la posición 12: se esperaba ')' pero se encontró el fin de la consulta", syntaxErr.Localize("es"))
+	}
+}
+
+func TestCompile_Normalize(t *testing.T) {
+	normalized := schema
+	normalized.Normalize = "lower(f_unaccent(%s))"
+
+	// Substring matches normalize the value, exact matches are left untouched
+	sql, args, err := normalized.Compile(`author:"García Márquez" status:available`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `((coalesce(author, '') ILIKE lower(f_unaccent(?))) AND (status = ?))`, sql)
+	assert.Equal(t, []interface{}{"%García Márquez%", "available"}, args)
+}
+
+func TestCompile_ExactValuesAreCaseInsensitive(t *testing.T) {
+	sql, args, err := schema.Compile(`status:AVAILABLE OR Status:Checked_Out lang:EN`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `((status = ?) OR ((status = ?) AND (language = ?)))`, sql)
+	assert.Equal(t, []interface{}{"available", "checked_out", "en"}, args)
+}
@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+)
+
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, tx *sqlx.Tx, key *models.APIKey) error // External TX
+	ListAPIKeys(ctx context.Context, name string) ([]models.APIKey, error)   // All clients when name is empty
+	GetAPIKeyByHash(ctx context.Context, hash string) (*models.APIKey, error)
+
+	// Rotation
+	ExpireAPIKeys(ctx context.Context, tx *sqlx.Tx, name string, expiresAt time.Time) ([]models.APIKey, error) // External TX
+}
+
+const apiKeyColumns = `id, name, prefix, key_hash, created_at, expires_at`
+
+type apiKeyRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+func NewAPIKeyRepository(db *sqlx.DB) APIKeyRepository {
+	return &apiKeyRepositoryImpl{
+		db: db,
+	}
+}
+
+// External TX
+func (r *apiKeyRepositoryImpl) CreateAPIKey(ctx context.Context, tx *sqlx.Tx, key *models.APIKey) error {
+
+	// Execute insert
+	_, err := tx.NamedExecContext(ctx, `
+		INSERT INTO api_keys (id, name, prefix, key_hash, created_at, expires_at)
+		VALUES (:id, :name, :prefix, :key_hash, :created_at, :expires_at)
+	`, key)
+	return err
+}
+
+func (r *apiKeyRepositoryImpl) ListAPIKeys(ctx context.Context, name string) ([]models.APIKey, error) {
+
+	// Execute query (newest first per client)
+	keys := []models.APIKey{}
+	err := r.db.SelectContext(ctx, &keys, `
+		SELECT `+apiKeyColumns+` FROM api_keys
+		WHERE $1 = '' OR name = $1
+		ORDER BY name ASC, created_at DESC
+	`, name)
+	return keys, err
+}
+
+func (r *apiKeyRepositoryImpl) GetAPIKeyByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+
+	// Execute query
+	var key models.APIKey
+	err := r.db.GetContext(ctx, &key, `
+		SELECT `+apiKeyColumns+` FROM api_keys
+		WHERE key_hash = $1
+	`, hash)
+
+	// Check for not found error
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, utils.ErrNotFound
+	}
+	return &key, err
+}
+
+// External TX
+func (r *apiKeyRepositoryImpl) ExpireAPIKeys(ctx context.Context, tx *sqlx.Tx, name string, expiresAt time.Time) ([]models.APIKey, error) {
+
+	// Execute update (keys of the client valid beyond the new expiry; earlier expiries are kept)
+	keys := []models.APIKey{}
+	err := sqlx.SelectContext(ctx, tx, &keys, `
+		UPDATE api_keys SET expires_at = $1
+		WHERE name = $2 AND (expires_at IS NULL OR expires_at > $1)
+		RETURNING `+apiKeyColumns+`
+	`, expiresAt, name)
+	return keys, err
+}
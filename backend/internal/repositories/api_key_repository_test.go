@@ -0,0 +1,60 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/santiago-buildit/code-challenge/backend/internal/repositories"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var apiKeyColumns = []string{"id", "name", "prefix", "key_hash", "created_at", "expires_at"}
+
+func TestExpireAPIKeys(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewAPIKeyRepository(sqlxDB)
+
+	ctx := context.Background()
+	expiresAt := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	// Only keys valid beyond the new expiry are updated
+	mock.ExpectBegin()
+	mock.ExpectQuery(`(?i)^UPDATE api_keys SET expires_at = \$1 WHERE name = \$2 AND \(expires_at IS NULL OR expires_at > \$1\) RETURNING id, name, prefix, key_hash, created_at, expires_at$`).
+		WithArgs(expiresAt, "kiosk").
+		WillReturnRows(sqlmock.NewRows(apiKeyColumns).AddRow("k1", "kiosk", "lib_00000000", "hash", expiresAt.Add(-time.Hour), expiresAt))
+
+	tx := sqlxDB.MustBegin()
+	keys, err := repo.ExpireAPIKeys(ctx, tx, "kiosk", expiresAt)
+
+	assert.NoError(t, err)
+	if assert.Len(t, keys, 1) {
+		assert.Equal(t, expiresAt, *keys[0].ExpiresAt)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAPIKeyByHash_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewAPIKeyRepository(sqlxDB)
+
+	mock.ExpectQuery(`(?i)^SELECT .+ FROM api_keys WHERE key_hash = \$1$`).
+		WithArgs("hash").
+		WillReturnRows(sqlmock.NewRows(apiKeyColumns))
+
+	_, err = repo.GetAPIKeyByHash(context.Background(), "hash")
+
+	assert.ErrorIs(t, err, utils.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
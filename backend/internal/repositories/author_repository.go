@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+)
+
+type AuthorRepository interface {
+	ListAuthors(ctx context.Context, req models.ListAuthorsRequest) ([]models.Author, int /* total */, error)
+	GetAuthorByID(ctx context.Context, id string) (*models.Author, error)
+}
+
+// Author columns with the number of (non-deleted) books credited
+const authorColumns = `id, name, created_at, updated_at,
+	(SELECT COUNT(DISTINCT bc.book_id) FROM book_contributors bc JOIN books b ON b.id = bc.book_id
+		WHERE bc.author_id = authors.id AND b.deleted = false) AS book_count`
+
+type authorRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+func NewAuthorRepository(db *sqlx.DB) AuthorRepository {
+	return &authorRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *authorRepositoryImpl) ListAuthors(ctx context.Context, req models.ListAuthorsRequest) ([]models.Author, int, error) {
+
+	// Build WHERE clause (name contains, accent- and case-insensitive)
+	where := ""
+	var args []interface{}
+	if req.Name != "" {
+		where = "WHERE lower(f_unaccent(name)) LIKE " + fmt.Sprintf(normalizeValue, "?")
+		args = append(args, "%"+req.Name+"%")
+	}
+
+	// Execute count query (for pagination)
+	var total int
+	query := r.db.Rebind(fmt.Sprintf(`SELECT COUNT(*) FROM authors %s`, where))
+	if err := r.db.GetContext(ctx, &total, query, args...); err != nil {
+		return nil, 0, err
+	}
+
+	// Execute query
+	authors := []models.Author{}
+	query = r.db.Rebind(fmt.Sprintf(`
+		SELECT %s FROM authors
+		%s
+		ORDER BY lower(name) ASC, id ASC
+		LIMIT %d OFFSET %d
+	`, authorColumns, where, req.PageSize, (req.Page-1)*req.PageSize))
+	if err := r.db.SelectContext(ctx, &authors, query, args...); err != nil {
+		return nil, 0, err
+	}
+
+	return authors, total, nil
+}
+
+func (r *authorRepositoryImpl) GetAuthorByID(ctx context.Context, id string) (*models.Author, error) {
+
+	// Validate UUID format
+	if err := validateUUIDOrNotFound(id); err != nil {
+		return nil, err
+	}
+
+	// Execute query
+	var author models.Author
+	err := r.db.GetContext(ctx, &author, `
+		SELECT `+authorColumns+` FROM authors
+		WHERE id = $1
+	`, id)
+
+	// Check for not found error
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, utils.ErrNotFound
+	}
+	return &author, err
+}
@@ -0,0 +1,65 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/repositories"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListAuthors_FilterByName(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewAuthorRepository(sqlxDB)
+
+	ctx := context.Background()
+	req := models.ListAuthorsRequest{Name: "marquez", Page: 2, PageSize: 5}
+
+	mock.ExpectQuery(`(?i)^SELECT COUNT\(\*\) FROM authors WHERE lower\(f_unaccent\(name\)\) LIKE lower\(f_unaccent\(\$1\)\)$`).
+		WithArgs("%marquez%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(6))
+
+	mock.ExpectQuery(`(?i)^SELECT id, name, created_at, updated_at, .+ AS book_count FROM authors WHERE .+ ORDER BY lower\(name\) ASC, id ASC LIMIT 5 OFFSET 5$`).
+		WithArgs("%marquez%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "book_count"}).AddRow("a-6", "Gabriel García Márquez", 7))
+
+	authors, total, err := repo.ListAuthors(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 6, total)
+	if assert.Len(t, authors, 1) {
+		assert.Equal(t, "Gabriel García Márquez", authors[0].Name)
+		assert.Equal(t, 7, authors[0].BookCount)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAuthorByID_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewAuthorRepository(sqlxDB)
+
+	ctx := context.Background()
+	authorID := "8a1c8f7e-3f0b-4d5e-9b1a-2c3d4e5f6a7b"
+
+	mock.ExpectQuery(`(?i)^SELECT .+ FROM authors WHERE id = \$1$`).
+		WithArgs(authorID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"})) // No rows
+
+	author, err := repo.GetAuthorByID(ctx, authorID)
+
+	assert.Nil(t, author)
+	assert.ErrorIs(t, err, utils.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
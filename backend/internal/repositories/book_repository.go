@@ -6,31 +6,128 @@ import (
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	"html"
 	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/santiago-buildit/code-challenge/backend/internal/i18n"
 	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/query"
 	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
 )
 
 type BookRepository interface {
 
 	// CRUD operations
-	CreateBook(ctx context.Context, book *models.Book) error
+	CreateBook(ctx context.Context, tx *sqlx.Tx, book *models.Book) error // External TX
 	ListBooks(ctx context.Context, req models.ListBooksRequest) ([]models.Book, int /* total */, error)
+	ListBooksAfter(ctx context.Context, req models.ListBooksRequest, cursor *models.Cursor, limit int) ([]models.Book, error) // Keyset pagination
+	CountBooks(ctx context.Context, req models.ListBooksRequest) (int, error)
 	GetBookByID(ctx context.Context, id string) (*models.Book, error)
-	UpdateBook(ctx context.Context, book *models.Book) error
-	DeleteBook(ctx context.Context, id string) error
+	UpdateBook(ctx context.Context, tx *sqlx.Tx, book *models.Book) error              // External TX
+	DeleteBook(ctx context.Context, tx *sqlx.Tx, id string, timestamp time.Time) error // External TX
+	LastUpdatedAt(ctx context.Context) (time.Time, error)                              // Latest change of any book (deleted included)
+
+	// Cover image
+	SetBookCover(ctx context.Context, tx *sqlx.Tx, id string, timestamp time.Time) error // External TX
+
+	// Tag and contributor assignment
+	SetBookTags(ctx context.Context, tx *sqlx.Tx, id string, tags []string) ([]string /* assigned */, error)                                       // External TX
+	SetBookContributors(ctx context.Context, tx *sqlx.Tx, id string, contributors models.Contributors) (models.Contributors /* assigned */, error) // External TX
+	TouchBooksByTag(ctx context.Context, tx *sqlx.Tx, tagID string, timestamp time.Time) ([]string /* book IDs */, error)                          // External TX
+	GetBooksByIDs(ctx context.Context, tx *sqlx.Tx, ids []string) ([]models.Book, error)                                                           // External TX
+
+	// Search
+	SuggestTerms(ctx context.Context, term string, limit int) ([]string, error)
+	ListFacets(ctx context.Context, req models.ListBooksRequest) (map[string][]models.FacetBucket, error)
 
 	// Status operations
-	UpdateBookStatus(ctx context.Context, tx *sqlx.Tx, id string, status models.BookStatus, timestamp time.Time) error   // External TX
-	AppendStatusChange(ctx context.Context, tx *sqlx.Tx, id string, status models.BookStatus, timestamp time.Time) error // External TX
+	UpdateBookStatus(ctx context.Context, tx *sqlx.Tx, id string, from, status models.BookStatus, timestamp time.Time) error // External TX
+	AppendStatusChange(ctx context.Context, tx *sqlx.Tx, change *models.BookStatusChange) error                              // External TX
 
 	// History
-	GetBookWithHistory(ctx context.Context, id string) (*models.Book, []models.BookStatusChange, error)
+	GetBookWithHistory(ctx context.Context, id string, limit int) (*models.Book, []models.BookStatusChange, error) // Latest changes only
+	ListHistory(ctx context.Context, id string, req models.ListHistoryRequest) ([]models.BookStatusChange, int /* total */, error)
+	ListHistoryByBookIDs(ctx context.Context, ids []string, limit int) ([]models.BookStatusChange, error) // Latest changes of each book only
+
+	// Maintenance (ops tooling)
+	GetDeletedBookByID(ctx context.Context, id string) (*models.Book, error)
+	RestoreBook(ctx context.Context, tx *sqlx.Tx, id string, timestamp time.Time) error  // External TX
+	ListStatusDrift(ctx context.Context, ids []string) ([]models.BookStatusDrift, error) // All books when no IDs are given
 }
 
+// Persisted book columns (avoids SELECT * so generated columns like search_vector are not scanned),
+// plus the sorted tag names and the ordered contributors (JSON) of each book
+const bookColumns = `id, isbn, title, author, description, status, created_at, updated_at, deleted,
+	publisher, publication_year, edition, language, page_count, format, cover_updated_at,
+	ARRAY(
+		SELECT t.name FROM book_tags bt JOIN tags t ON t.id = bt.tag_id
+		WHERE bt.book_id = books.id ORDER BY lower(t.name)
+	) AS tags,
+	COALESCE((
+		SELECT json_agg(json_build_object('author_id', a.id, 'name', a.name, 'role', bc.role) ORDER BY bc.position)
+		FROM book_contributors bc JOIN authors a ON a.id = bc.author_id
+		WHERE bc.book_id = books.id
+	), '[]') AS contributors`
+
+// Full-text search settings
+const (
+	searchConfig        = "english_unaccent" // Text search configuration (must match the search_vector generated column)
+	titleHeadline       = `StartSel="` + highlightStart + `", StopSel="` + highlightStop + `", HighlightAll=true`
+	descriptionHeadline = `StartSel="` + highlightStart + `", StopSel="` + highlightStop + `", MaxWords=35, MinWords=15, MaxFragments=2`
+)
+
+// Delimiters of the ts_headline matches: control characters (removed from the source text), so the text
+// can be HTML-escaped before they are replaced with <mark> tags
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+// Facet fields (API name -> column) and maximum number of buckets per facet
+var facetColumns = map[string]string{
+	"status":   "status",
+	"author":   "author",
+	"language": "language",
+	"format":   "format",
+}
+
+const maxFacetBuckets = 20
+
+// Accent- and case-insensitive text matching: filters compare the normalized generated columns
+// (lowercase, unaccented) against the value normalized with the same expression
+const normalizeValue = "lower(f_unaccent(%s))"
+
+// Fields available to query-language expressions (ListBooksRequest.Q)
+var querySchema = query.Schema{
+	Fields: map[string]query.Field{
+		"title":       {Column: "title_search"},
+		"author":      {Column: "author_search"},
+		"isbn":        {Column: "lower(isbn)"},
+		"description": {Column: "description_search"},
+		"status":      {Column: "status", Exact: true, Values: enumValues(models.BookStatuses)},
+		"publisher":   {Column: fmt.Sprintf(normalizeValue, "publisher")},
+		"language":    {Column: "language", Exact: true},
+		"format":      {Column: "format", Exact: true, Values: enumValues(models.BookFormats)},
+	},
+	Default:   []string{"title_search", "author_search", "description_search"},
+	Normalize: normalizeValue,
+}
+
+// enumValues lists the values of a model enum for the query schema
+func enumValues[T ~string](values []T) []string {
+	res := make([]string, len(values))
+	for i, v := range values {
+		res[i] = string(v)
+	}
+	return res
+}
+
+// Minimum pg_trgm word similarity (0..1) for typo-tolerant matches and suggestions
+const similarityThreshold = 0.4
+
 type bookRepositoryImpl struct {
 	db *sqlx.DB
 }
@@ -41,16 +138,18 @@ func NewBookRepository(db *sqlx.DB) BookRepository {
 	}
 }
 
-func (r *bookRepositoryImpl) CreateBook(ctx context.Context, book *models.Book) error {
+func (r *bookRepositoryImpl) CreateBook(ctx context.Context, tx *sqlx.Tx, book *models.Book) error {
 
 	// Execute insert
-	_, err := r.db.NamedExecContext(ctx, `
+	_, err := tx.NamedExecContext(ctx, `
 		INSERT INTO books (
 			id, isbn, title, author, description, status,
-			created_at, updated_at, deleted
+			created_at, updated_at, deleted,
+			publisher, publication_year, edition, language, page_count, format
 		) VALUES (
 			:id, :isbn, :title, :author, :description, :status,
-			:created_at, :updated_at, :deleted
+			:created_at, :updated_at, :deleted,
+			:publisher, :publication_year, :edition, :language, :page_count, :format
 		)
 	`, book)
 	return err
@@ -58,82 +157,153 @@ func (r *bookRepositoryImpl) CreateBook(ctx context.Context, book *models.Book)
 
 func (r *bookRepositoryImpl) ListBooks(ctx context.Context, req models.ListBooksRequest) ([]models.Book, int, error) {
 
-	var (
-		books      []models.Book
-		args       []interface{}
-		conditions []string
-	)
+	var books []models.Book
 
-	// Collect dynamic WHERE conditions (filters)
-	if req.ISBN != "" {
-		conditions = append(conditions, "isbn ILIKE ?")
-		args = append(args, "%"+req.ISBN+"%")
-	}
-	if req.Title != "" {
-		conditions = append(conditions, "title ILIKE ?")
-		args = append(args, "%"+req.Title+"%")
-	}
-	if req.Author != "" {
-		conditions = append(conditions, "author ILIKE ?")
-		args = append(args, "%"+req.Author+"%")
-	}
-	if req.Status != "" {
-		conditions = append(conditions, "status = ?")
-		args = append(args, req.Status)
+	// Execute count query (for pagination)
+	total, err := r.CountBooks(ctx, req)
+	if err != nil {
+		return nil, 0, err
 	}
-	if req.Text != "" {
-		conditions = append(conditions, "(title ILIKE ? OR description ILIKE ?)")
-		args = append(args, "%"+req.Text+"%", "%"+req.Text+"%")
+
+	// Build FROM, columns and WHERE clause from the request filters
+	f, err := buildListFilter(req)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	// Exclude deleted
-	conditions = append(conditions, "deleted = false")
+	// Build ORDER BY clause (id as tiebreaker for a stable order)
+	orderBy := orderByClause(orderTerms(req, f))
 
-	// Build WHERE clause
-	where := ""
-	if len(conditions) > 0 {
-		where = "WHERE " + strings.Join(conditions, " AND ")
-	}
+	// Pagination
+	offset := (req.Page - 1) * req.PageSize
 
-	// Execute count query (for pagination)
-	var total int
-	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM books %s`, where)
-	countQuery = r.db.Rebind(countQuery) // Rebind converts '?' placeholders to PostgreSQL-style ($1, $2, ...)
+	// Build final query
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s
+		%s
+		ORDER BY %s
+		LIMIT %d OFFSET %d
+	`, f.columns, f.from, f.where, orderBy, req.PageSize, offset)
+	query = r.db.Rebind(query) // Rebind converts '?' placeholders to PostgreSQL-style ($1, $2, ...)
 
-	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+	// Execute query
+	if err := r.db.SelectContext(ctx, &books, query, f.args...); err != nil {
 		return nil, 0, err
 	}
+	escapeHighlights(books)
+
+	return books, total, nil
+}
+
+func (r *bookRepositoryImpl) ListBooksAfter(ctx context.Context, req models.ListBooksRequest, cursor *models.Cursor, limit int) ([]models.Book, error) {
 
-	// Sanitize sort by
-	sortBy := "title" // default
-	if m := map[string]bool{"isbn": true, "title": true, "author": true, "status": true}; m[req.SortBy] {
-		sortBy = req.SortBy
+	var books []models.Book
+
+	// Build FROM, columns and WHERE clause from the request filters
+	f, err := buildListFilter(req)
+	if err != nil {
+		return nil, err
 	}
+	where, args := f.where, f.args
 
-	// Sanitize sort order
-	sortOrder := "ASC"
-	if strings.ToUpper(req.SortOrder) == "DESC" {
-		sortOrder = "DESC"
+	// Resolve sort (backward cursors walk the order in reverse)
+	terms := orderTerms(req, f)
+	if cursor != nil && cursor.Backward {
+		terms = reverseTerms(terms)
 	}
 
-	// Pagination
-	offset := (req.Page - 1) * req.PageSize
+	// Keyset condition: rows strictly after the cursor position (sort keys, then id)
+	if cursor != nil {
+		values := append(append([]interface{}{}, cursor.Keys...), cursor.ID)
+		if len(values) != len(terms) {
+			return nil, i18n.NewError(utils.ErrBadRequest, i18n.ErrorInvalidCursor, nil)
+		}
+		condition, keyArgs := keysetCondition(terms, values)
+		where += " AND " + condition
+		args = append(args, keyArgs...)
+	}
 
 	// Build final query
 	query := fmt.Sprintf(`
-		SELECT * FROM books
+		SELECT %s FROM %s
 		%s
-		ORDER BY %s %s
-		LIMIT %d OFFSET %d
-	`, where, sortBy, sortOrder, req.PageSize, offset)
+		ORDER BY %s
+		LIMIT %d
+	`, f.columns, f.from, where, orderByClause(terms), limit)
 	query = r.db.Rebind(query) // Rebind converts '?' placeholders to PostgreSQL-style ($1, $2, ...)
 
 	// Execute query
 	if err := r.db.SelectContext(ctx, &books, query, args...); err != nil {
-		return nil, 0, err
+		return nil, err
 	}
+	escapeHighlights(books)
 
-	return books, total, nil
+	return books, nil
+}
+
+// escapeHighlights turns the ts_headline fragments into safe markup: the text (titles and descriptions
+// are user input) is HTML-escaped and only the match delimiters become <mark> tags
+func escapeHighlights(books []models.Book) {
+	marks := strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>")
+	for i := range books {
+		books[i].TitleHighlight = marks.Replace(html.EscapeString(books[i].TitleHighlight))
+		books[i].DescriptionHighlight = marks.Replace(html.EscapeString(books[i].DescriptionHighlight))
+	}
+}
+
+func (r *bookRepositoryImpl) CountBooks(ctx context.Context, req models.ListBooksRequest) (int, error) {
+
+	// Build FROM and WHERE clause from the request filters
+	f, err := buildListFilter(req)
+	if err != nil {
+		return 0, err
+	}
+
+	// Execute count query
+	var total int
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, f.from, f.where)
+	query = r.db.Rebind(query) // Rebind converts '?' placeholders to PostgreSQL-style ($1, $2, ...)
+
+	err = r.db.GetContext(ctx, &total, query, f.args...)
+	return total, err
+}
+
+func (r *bookRepositoryImpl) ListFacets(ctx context.Context, req models.ListBooksRequest) (map[string][]models.FacetBucket, error) {
+
+	// Build FROM and WHERE clause from the request filters (same as ListBooks)
+	f, err := buildListFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	facets := map[string][]models.FacetBucket{}
+	for _, name := range req.Facets {
+
+		// Sanitize facet field
+		column, ok := facetColumns[name]
+		if !ok {
+			continue
+		}
+
+		// Build facet query
+		query := fmt.Sprintf(`
+			SELECT %[1]s AS value, COUNT(*) AS count FROM %[2]s
+			%[3]s
+			GROUP BY %[1]s
+			ORDER BY count DESC, value ASC
+			LIMIT %[4]d
+		`, column, f.from, f.where, maxFacetBuckets)
+		query = r.db.Rebind(query) // Rebind converts '?' placeholders to PostgreSQL-style ($1, $2, ...)
+
+		// Execute query
+		buckets := []models.FacetBucket{}
+		if err := r.db.SelectContext(ctx, &buckets, query, f.args...); err != nil {
+			return nil, err
+		}
+		facets[name] = buckets
+	}
+
+	return facets, nil
 }
 
 func (r *bookRepositoryImpl) GetBookByID(ctx context.Context, id string) (*models.Book, error) {
@@ -146,7 +316,7 @@ func (r *bookRepositoryImpl) GetBookByID(ctx context.Context, id string) (*model
 	// Execute query
 	var book models.Book
 	err := r.db.GetContext(ctx, &book, `
-		SELECT * FROM books
+		SELECT `+bookColumns+` FROM books
 		WHERE id = $1 AND deleted = false
 	`, id)
 
@@ -157,7 +327,7 @@ func (r *bookRepositoryImpl) GetBookByID(ctx context.Context, id string) (*model
 	return &book, err
 }
 
-func (r *bookRepositoryImpl) UpdateBook(ctx context.Context, book *models.Book) error {
+func (r *bookRepositoryImpl) UpdateBook(ctx context.Context, tx *sqlx.Tx, book *models.Book) error {
 
 	// Validate UUID format
 	if err := validateUUIDOrNotFound(book.ID); err != nil {
@@ -165,12 +335,18 @@ func (r *bookRepositoryImpl) UpdateBook(ctx context.Context, book *models.Book)
 	}
 
 	// Execute update
-	res, err := r.db.NamedExecContext(ctx, `
+	res, err := tx.NamedExecContext(ctx, `
 		UPDATE books SET
 			isbn = :isbn,
 			title = :title,
 			author = :author,
 			description = :description,
+			publisher = :publisher,
+			publication_year = :publication_year,
+			edition = :edition,
+			language = :language,
+			page_count = :page_count,
+			format = :format,
 			status = :status,
 			updated_at = :updated_at
 		WHERE id = :id AND deleted = false
@@ -183,7 +359,8 @@ func (r *bookRepositoryImpl) UpdateBook(ctx context.Context, book *models.Book)
 	return utils.CheckRowsAffected(res)
 }
 
-func (r *bookRepositoryImpl) DeleteBook(ctx context.Context, id string) error {
+// External TX
+func (r *bookRepositoryImpl) DeleteBook(ctx context.Context, tx *sqlx.Tx, id string, timestamp time.Time) error {
 
 	// Validate UUID format
 	if err := validateUUIDOrNotFound(id); err != nil {
@@ -191,9 +368,9 @@ func (r *bookRepositoryImpl) DeleteBook(ctx context.Context, id string) error {
 	}
 
 	// Execute update (logical delete)
-	res, err := r.db.ExecContext(ctx, `
-		UPDATE books SET deleted = true WHERE id = $1 AND deleted = false
-	`, id)
+	res, err := tx.ExecContext(ctx, `
+		UPDATE books SET deleted = true, updated_at = $1 WHERE id = $2 AND deleted = false
+	`, timestamp, id)
 	if err != nil {
 		return err
 	}
@@ -202,18 +379,25 @@ func (r *bookRepositoryImpl) DeleteBook(ctx context.Context, id string) error {
 	return utils.CheckRowsAffected(res)
 }
 
-func (r *bookRepositoryImpl) UpdateBookStatus(ctx context.Context, tx *sqlx.Tx, id string, status models.BookStatus, timestamp time.Time) error {
+func (r *bookRepositoryImpl) LastUpdatedAt(ctx context.Context) (time.Time, error) {
+
+	// Execute query (deletes bump updated_at too, so they are seen)
+	var last sql.NullTime
+	err := r.db.GetContext(ctx, &last, `SELECT MAX(updated_at) FROM books`)
+	return last.Time, err
+}
+
+func (r *bookRepositoryImpl) SetBookCover(ctx context.Context, tx *sqlx.Tx, id string, timestamp time.Time) error {
 
 	// Validate UUID format
 	if err := validateUUIDOrNotFound(id); err != nil {
 		return err
 	}
 
-	// Execute update
+	// Execute update (the cover changes the book version)
 	res, err := tx.ExecContext(ctx, `
-		UPDATE books SET status = $1, updated_at = $2
-		WHERE id = $3 AND deleted = false
-	`, status, timestamp, id)
+		UPDATE books SET cover_updated_at = $1, updated_at = $1 WHERE id = $2 AND deleted = false
+	`, timestamp, id)
 	if err != nil {
 		return err
 	}
@@ -222,22 +406,183 @@ func (r *bookRepositoryImpl) UpdateBookStatus(ctx context.Context, tx *sqlx.Tx,
 	return utils.CheckRowsAffected(res)
 }
 
-func (r *bookRepositoryImpl) AppendStatusChange(ctx context.Context, tx *sqlx.Tx, id string, status models.BookStatus, timestamp time.Time) error {
+func (r *bookRepositoryImpl) SetBookTags(ctx context.Context, tx *sqlx.Tx, id string, tags []string) ([]string, error) {
+
+	// Create unknown tags (names are unique case-insensitively)
+	now := time.Now()
+	for _, name := range tags {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO tags (id, name, created_at, updated_at)
+			VALUES ($1, $2, $3, $3)
+			ON CONFLICT ((lower(name))) DO NOTHING
+		`, uuid.New().String(), name, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Replace assignments
+	if _, err := tx.ExecContext(ctx, `DELETE FROM book_tags WHERE book_id = $1`, id); err != nil {
+		return nil, err
+	}
+	assigned := []string{}
+	if len(tags) == 0 {
+		return assigned, nil
+	}
+	lowered := make([]string, 0, len(tags))
+	for _, name := range tags {
+		lowered = append(lowered, strings.ToLower(name))
+	}
+
+	// Execute insert (returns the stored spelling of the assigned tags, sorted)
+	err := tx.SelectContext(ctx, &assigned, `
+		WITH assigned AS (
+			INSERT INTO book_tags (book_id, tag_id)
+			SELECT $1, id FROM tags WHERE lower(name) = ANY($2)
+			RETURNING tag_id
+		)
+		SELECT t.name FROM assigned a JOIN tags t ON t.id = a.tag_id
+		ORDER BY lower(t.name)
+	`, id, pq.Array(lowered))
+	return assigned, err
+}
+
+func (r *bookRepositoryImpl) SetBookContributors(ctx context.Context, tx *sqlx.Tx, id string, contributors models.Contributors) (models.Contributors, error) {
+
+	// Replace credits
+	if _, err := tx.ExecContext(ctx, `DELETE FROM book_contributors WHERE book_id = $1`, id); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	assigned := make(models.Contributors, 0, len(contributors))
+	for position, contributor := range contributors {
+
+		// Find or create author (names are unique case-insensitively, the stored spelling wins)
+		var author models.Author
+		err := tx.GetContext(ctx, &author, `
+			INSERT INTO authors (id, name, created_at, updated_at)
+			VALUES ($1, $2, $3, $3)
+			ON CONFLICT ((lower(name))) DO UPDATE SET name = authors.name
+			RETURNING id, name
+		`, uuid.New().String(), contributor.Name, now)
+		if err != nil {
+			return nil, err
+		}
+
+		// Credit author (a repeated author and role is credited once, at its first position)
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO book_contributors (book_id, author_id, role, position)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT DO NOTHING
+		`, id, author.ID, contributor.Role, position)
+		if err != nil {
+			return nil, err
+		}
+		if inserted, err := res.RowsAffected(); err != nil {
+			return nil, err
+		} else if inserted == 0 {
+			continue
+		}
+		assigned = append(assigned, models.Contributor{AuthorID: author.ID, Name: author.Name, Role: contributor.Role})
+	}
+
+	return assigned, nil
+}
+
+// External TX
+func (r *bookRepositoryImpl) TouchBooksByTag(ctx context.Context, tx *sqlx.Tx, tagID string, timestamp time.Time) ([]string, error) {
+
+	// Validate UUID format
+	if err := validateUUIDOrNotFound(tagID); err != nil {
+		return nil, err
+	}
+
+	// Execute update (tag renames and deletes change the books' state)
+	ids := []string{}
+	err := tx.SelectContext(ctx, &ids, `
+		UPDATE books SET updated_at = $1
+		WHERE deleted = false AND id IN (SELECT book_id FROM book_tags WHERE tag_id = $2)
+		RETURNING id
+	`, timestamp, tagID)
+	return ids, err
+}
+
+// External TX
+func (r *bookRepositoryImpl) GetBooksByIDs(ctx context.Context, tx *sqlx.Tx, ids []string) ([]models.Book, error) {
+
+	// Execute query (non-deleted books only, seen from the transaction)
+	books := []models.Book{}
+	if len(ids) == 0 {
+		return books, nil
+	}
+	err := tx.SelectContext(ctx, &books, `
+		SELECT `+bookColumns+` FROM books
+		WHERE id = ANY($1) AND deleted = false
+		ORDER BY id
+	`, pq.Array(ids))
+	return books, err
+}
+
+func (r *bookRepositoryImpl) SuggestTerms(ctx context.Context, term string, limit int) ([]string, error) {
+
+	// Execute query (closest known titles and authors, compared accent- and case-insensitively)
+	suggestions := []string{}
+	err := r.db.SelectContext(ctx, &suggestions, `
+		SELECT candidate FROM (
+			SELECT title AS candidate, word_similarity(lower(f_unaccent($1)), title_search) AS score FROM books WHERE deleted = false
+			UNION ALL
+			SELECT author AS candidate, word_similarity(lower(f_unaccent($1)), author_search) AS score FROM books WHERE deleted = false
+		) AS candidates
+		WHERE score >= $2
+		GROUP BY candidate
+		ORDER BY MAX(score) DESC, candidate ASC
+		LIMIT $3
+	`, term, similarityThreshold, limit)
+	return suggestions, err
+}
+
+// UpdateBookStatus changes the status only while it is still from, so concurrent changes of the same book
+// can't both apply (ErrConflict when it was changed or deleted since it was read)
+func (r *bookRepositoryImpl) UpdateBookStatus(ctx context.Context, tx *sqlx.Tx, id string, from, status models.BookStatus, timestamp time.Time) error {
 
 	// Validate UUID format
 	if err := validateUUIDOrNotFound(id); err != nil {
 		return err
 	}
 
+	// Execute update
+	res, err := tx.ExecContext(ctx, `
+		UPDATE books SET status = $1, updated_at = $2
+		WHERE id = $3 AND deleted = false AND status = $4
+	`, status, timestamp, id, from)
+	if err != nil {
+		return err
+	}
+
+	// Check for concurrent change
+	if err := utils.CheckRowsAffected(res); err != nil {
+		return utils.ErrConflict
+	}
+	return nil
+}
+
+func (r *bookRepositoryImpl) AppendStatusChange(ctx context.Context, tx *sqlx.Tx, change *models.BookStatusChange) error {
+
+	// Validate UUID format
+	if err := validateUUIDOrNotFound(change.BookID); err != nil {
+		return err
+	}
+
 	// Execute insert
 	_, err := tx.ExecContext(ctx, `
-		INSERT INTO book_status_changes (book_id, status, timestamp)
-		VALUES ($1, $2, $3)
-	`, id, status, time.Now())
+		INSERT INTO book_status_changes (book_id, status, timestamp, actor, source, note)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, change.BookID, change.Status, change.Timestamp, change.Actor, change.Source, change.Note)
 	return err
 }
 
-func (r *bookRepositoryImpl) GetBookWithHistory(ctx context.Context, id string) (*models.Book, []models.BookStatusChange, error) {
+func (r *bookRepositoryImpl) GetBookWithHistory(ctx context.Context, id string, limit int) (*models.Book, []models.BookStatusChange, error) {
 
 	// Validate UUID format
 	if err := validateUUIDOrNotFound(id); err != nil {
@@ -250,14 +595,15 @@ func (r *bookRepositoryImpl) GetBookWithHistory(ctx context.Context, id string)
 		return nil, nil, err
 	}
 
-	// Execute query (book status changes)
+	// Execute query (latest book status changes, same order as ListHistory)
 	var history []models.BookStatusChange
 	err = r.db.SelectContext(ctx, &history, `
-		SELECT status, timestamp
+		SELECT status, timestamp, actor, source, note
 		FROM book_status_changes
 		WHERE book_id = $1
-		ORDER BY timestamp DESC
-	`, id)
+		ORDER BY timestamp DESC, id DESC
+		LIMIT $2
+	`, id, limit)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -265,6 +611,431 @@ func (r *bookRepositoryImpl) GetBookWithHistory(ctx context.Context, id string)
 	return book, history, nil
 }
 
+func (r *bookRepositoryImpl) ListHistory(ctx context.Context, id string, req models.ListHistoryRequest) ([]models.BookStatusChange, int, error) {
+
+	// Validate UUID format
+	if err := validateUUIDOrNotFound(id); err != nil {
+		return nil, 0, err
+	}
+
+	// Build WHERE clause (date window)
+	where := "WHERE book_id = ?"
+	args := []interface{}{id}
+	if !req.From.IsZero() {
+		where += " AND timestamp >= ?"
+		args = append(args, req.From)
+	}
+	if !req.To.IsZero() {
+		where += " AND timestamp < ?"
+		args = append(args, req.To)
+	}
+
+	// Execute count query (for pagination)
+	var total int
+	query := r.db.Rebind(`SELECT COUNT(*) FROM book_status_changes ` + where)
+	if err := r.db.GetContext(ctx, &total, query, args...); err != nil {
+		return nil, 0, err
+	}
+
+	// Execute query (newest first)
+	history := []models.BookStatusChange{}
+	query = r.db.Rebind(fmt.Sprintf(`
+		SELECT book_id, status, timestamp, actor, source, note
+		FROM book_status_changes
+		%s
+		ORDER BY timestamp DESC, id DESC
+		LIMIT %d OFFSET %d
+	`, where, req.PageSize, (req.Page-1)*req.PageSize))
+	if err := r.db.SelectContext(ctx, &history, query, args...); err != nil {
+		return nil, 0, err
+	}
+
+	return history, total, nil
+}
+
+func (r *bookRepositoryImpl) ListHistoryByBookIDs(ctx context.Context, ids []string, limit int) ([]models.BookStatusChange, error) {
+
+	// Skip invalid UUIDs (no history)
+	valid := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if validateUUIDOrNotFound(id) == nil {
+			valid = append(valid, id)
+		}
+	}
+	history := []models.BookStatusChange{}
+	if len(valid) == 0 {
+		return history, nil
+	}
+
+	// Execute query (latest status changes of each book, newest first per book)
+	err := r.db.SelectContext(ctx, &history, `
+		SELECT book_id, status, timestamp, actor, source, note
+		FROM (
+			SELECT *, row_number() OVER (PARTITION BY book_id ORDER BY timestamp DESC, id DESC) AS position
+			FROM book_status_changes
+			WHERE book_id = ANY($1)
+		) latest
+		WHERE position <= $2
+		ORDER BY book_id, timestamp DESC, id DESC
+	`, pq.Array(valid), limit)
+	return history, err
+}
+
+func (r *bookRepositoryImpl) GetDeletedBookByID(ctx context.Context, id string) (*models.Book, error) {
+
+	// Validate UUID format
+	if err := validateUUIDOrNotFound(id); err != nil {
+		return nil, err
+	}
+
+	// Execute query (logically deleted books only)
+	var book models.Book
+	err := r.db.GetContext(ctx, &book, `
+		SELECT `+bookColumns+` FROM books
+		WHERE id = $1 AND deleted = true
+	`, id)
+
+	// Check for not found error
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, utils.ErrNotFound
+	}
+	return &book, err
+}
+
+// External TX
+func (r *bookRepositoryImpl) RestoreBook(ctx context.Context, tx *sqlx.Tx, id string, timestamp time.Time) error {
+
+	// Validate UUID format
+	if err := validateUUIDOrNotFound(id); err != nil {
+		return err
+	}
+
+	// Execute update (undo logical delete)
+	res, err := tx.ExecContext(ctx, `
+		UPDATE books SET deleted = false, updated_at = $1 WHERE id = $2 AND deleted = true
+	`, timestamp, id)
+	if err != nil {
+		return err
+	}
+
+	// Check for not found error
+	return utils.CheckRowsAffected(res)
+}
+
+func (r *bookRepositoryImpl) ListStatusDrift(ctx context.Context, ids []string) ([]models.BookStatusDrift, error) {
+
+	// Validate UUID format
+	for _, id := range ids {
+		if err := validateUUIDOrNotFound(id); err != nil {
+			return nil, err
+		}
+	}
+
+	// Restrict to the given books
+	filter := ""
+	args := []interface{}{models.BookStatusAvailable}
+	if len(ids) > 0 {
+		filter = "AND b.id = ANY($2)"
+		args = append(args, pq.Array(ids))
+	}
+
+	// Execute query (books whose status differs from their latest status change)
+	drift := []models.BookStatusDrift{}
+	err := r.db.SelectContext(ctx, &drift, `
+		SELECT b.id AS book_id, b.title, b.status, COALESCE(h.status, $1) AS expected
+		FROM books b
+		LEFT JOIN LATERAL (
+			SELECT status FROM book_status_changes
+			WHERE book_id = b.id
+			ORDER BY timestamp DESC, id DESC
+			LIMIT 1
+		) h ON true
+		WHERE b.deleted = false AND b.status <> COALESCE(h.status, $1) `+filter+`
+		ORDER BY b.title, b.id
+	`, args...)
+	return drift, err
+}
+
+// listFilter holds the query parts shared by the list, count and facet queries
+type listFilter struct {
+	from    string
+	columns string
+	rank    string // Relevance expression (only with text search)
+	where   string
+	args    []interface{} // Positional arguments for '?' placeholders (FROM first, then WHERE)
+}
+
+// buildListFilter translates the request filters into FROM, selected columns and WHERE clause.
+// Fails (wrapping utils.ErrBadRequest) when the query expression is invalid.
+func buildListFilter(req models.ListBooksRequest) (listFilter, error) {
+
+	var (
+		args       []interface{}
+		conditions []string
+	)
+
+	// Base FROM clause and selected columns
+	from := "books"
+	columns := bookColumns
+	rank := ""
+
+	// Full-text search (parsed once as a FROM item so it can be reused for ranking and highlighting)
+	if req.Text != "" && !req.Fuzzy {
+		from = fmt.Sprintf("books, websearch_to_tsquery('%s', ?) AS query", searchConfig)
+		args = append(args, req.Text)
+		rank = "ts_rank(search_vector, query)"
+		columns += fmt.Sprintf(`,
+			%[4]s AS rank,
+			ts_headline('%[1]s', translate(title, chr(2) || chr(3), ''), query, '%[2]s') AS title_highlight,
+			ts_headline('%[1]s', translate(coalesce(description, ''), chr(2) || chr(3), ''), query, '%[3]s') AS description_highlight`,
+			searchConfig, titleHeadline, descriptionHeadline, rank)
+		conditions = append(conditions, "search_vector @@ query")
+	}
+
+	// Typo-tolerant search (trigram similarity against title and author)
+	if req.Text != "" && req.Fuzzy {
+		from = fmt.Sprintf("books, (SELECT %s AS term) AS fuzzy", fmt.Sprintf(normalizeValue, "?::text"))
+		args = append(args, req.Text)
+		rank = "GREATEST(word_similarity(fuzzy.term, title_search), word_similarity(fuzzy.term, author_search))"
+		columns += fmt.Sprintf(", %s AS rank", rank)
+		conditions = append(conditions, fmt.Sprintf("%s >= %v", rank, similarityThreshold))
+	}
+
+	// Collect dynamic WHERE conditions (filters)
+	if req.ISBN != "" {
+		condition, arg := matchCondition("lower(isbn)", req.ISBN, req.ISBNMatch, false) // ISBNs are never matched approximately
+		conditions = append(conditions, condition)
+		args = append(args, arg)
+	}
+	if req.Title != "" {
+		condition, arg := matchCondition("title_search", req.Title, req.TitleMatch, req.Fuzzy)
+		conditions = append(conditions, condition)
+		args = append(args, arg)
+	}
+	if req.Author != "" {
+		condition, arg := matchCondition("author_search", req.Author, req.AuthorMatch, req.Fuzzy)
+		conditions = append(conditions, condition)
+		args = append(args, arg)
+	}
+	if req.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, req.Status)
+	}
+	if len(req.Statuses) > 0 {
+		conditions = append(conditions, "status IN (?"+strings.Repeat(", ?", len(req.Statuses)-1)+")")
+		for _, status := range req.Statuses {
+			args = append(args, status)
+		}
+	}
+
+	// Bibliographic details
+	if req.Publisher != "" {
+		condition, arg := matchCondition(fmt.Sprintf(normalizeValue, "publisher"), req.Publisher, models.MatchContains, false)
+		conditions = append(conditions, condition)
+		args = append(args, arg)
+	}
+	if req.Language != "" {
+		conditions = append(conditions, "language = lower(?)")
+		args = append(args, req.Language)
+	}
+	if len(req.Formats) > 0 {
+		conditions = append(conditions, "format IN (?"+strings.Repeat(", ?", len(req.Formats)-1)+")")
+		for _, format := range req.Formats {
+			args = append(args, format)
+		}
+	}
+	if req.YearFrom > 0 {
+		conditions = append(conditions, "publication_year >= ?")
+		args = append(args, req.YearFrom)
+	}
+	if req.YearTo > 0 {
+		conditions = append(conditions, "publication_year BETWEEN 1 AND ?") // Unknown years (0) excluded
+		args = append(args, req.YearTo)
+	}
+
+	// Tags (any / all of the given names, case-insensitive)
+	if tags := models.NormalizeTags(req.Tags); len(tags) > 0 {
+		placeholders := "lower(?)" + strings.Repeat(", lower(?)", len(tags)-1)
+		join := "FROM book_tags bt JOIN tags t ON t.id = bt.tag_id WHERE bt.book_id = books.id AND lower(t.name) IN (" + placeholders + ")"
+		if req.TagsMatch == models.MatchAll {
+			conditions = append(conditions, fmt.Sprintf("(SELECT COUNT(*) %s) = %d", join, len(tags)))
+		} else {
+			conditions = append(conditions, fmt.Sprintf("EXISTS (SELECT 1 %s)", join))
+		}
+		for _, tag := range tags {
+			args = append(args, tag)
+		}
+	}
+
+	// Contributors (author and/or role)
+	if req.AuthorID != "" || req.ContributorRole != "" {
+		condition := "EXISTS (SELECT 1 FROM book_contributors bc WHERE bc.book_id = books.id"
+		if req.AuthorID != "" {
+			condition += " AND bc.author_id = ?"
+			args = append(args, req.AuthorID)
+		}
+		if req.ContributorRole != "" {
+			condition += " AND bc.role = ?"
+			args = append(args, req.ContributorRole)
+		}
+		conditions = append(conditions, condition+")")
+	}
+
+	// Date ranges (from inclusive, to exclusive)
+	for _, r := range []struct {
+		condition string
+		value     *time.Time
+	}{
+		{"created_at >= ?", req.CreatedFrom},
+		{"created_at < ?", req.CreatedTo},
+		{"updated_at >= ?", req.UpdatedFrom},
+		{"updated_at < ?", req.UpdatedTo},
+	} {
+		if r.value != nil {
+			conditions = append(conditions, r.condition)
+			args = append(args, *r.value)
+		}
+	}
+
+	// Query-language expression (e.g. author:tolkien AND (title:"ring" OR status:available))
+	if req.Q != "" {
+		condition, queryArgs, err := querySchema.Compile(req.Q)
+		if err != nil {
+			return listFilter{}, err
+		}
+		conditions = append(conditions, condition)
+		args = append(args, queryArgs...)
+	}
+
+	// Exclude deleted
+	conditions = append(conditions, "deleted = false")
+
+	// Build WHERE clause
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	return listFilter{from: from, columns: columns, rank: rank, where: where, args: args}, nil
+}
+
+// orderTerm is one resolved ORDER BY expression and direction
+type orderTerm struct {
+	expr  string
+	order string // ASC / DESC
+}
+
+// orderTerms resolves the request sorts into ORDER BY terms, ending with the id tiebreaker.
+// Only whitelisted fields are used (handlers reject the others before reaching the repository).
+func orderTerms(req models.ListBooksRequest, f listFilter) []orderTerm {
+
+	var terms []orderTerm
+	for _, spec := range req.Sorts() {
+		if !models.SortFields[spec.Field] {
+			continue
+		}
+
+		// Field name is the column, except relevance (only makes sense with a text query)
+		expr := spec.Field
+		if spec.Field == "relevance" {
+			if f.rank == "" {
+				continue
+			}
+			expr = f.rank
+		}
+
+		order := "ASC"
+		if spec.Order == models.SortDesc {
+			order = "DESC"
+		}
+		terms = append(terms, orderTerm{expr: expr, order: order})
+	}
+	if len(terms) == 0 {
+		terms = append(terms, orderTerm{expr: "title", order: "ASC"}) // default
+	}
+
+	// Deterministic tiebreaker (same direction as the last term)
+	return append(terms, orderTerm{expr: "id", order: terms[len(terms)-1].order})
+}
+
+// orderByClause joins ORDER BY terms (e.g. "author ASC, id ASC")
+func orderByClause(terms []orderTerm) string {
+	parts := make([]string, 0, len(terms))
+	for _, t := range terms {
+		parts = append(parts, t.expr+" "+t.order)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// reverseTerms flips the direction of every ORDER BY term
+func reverseTerms(terms []orderTerm) []orderTerm {
+	reversed := make([]orderTerm, 0, len(terms))
+	for _, t := range terms {
+		order := "DESC"
+		if t.order == "DESC" {
+			order = "ASC"
+		}
+		reversed = append(reversed, orderTerm{expr: t.expr, order: order})
+	}
+	return reversed
+}
+
+// keysetCondition builds the condition for rows strictly after the given values (one per term) in terms order.
+func keysetCondition(terms []orderTerm, values []interface{}) (string, []interface{}) {
+
+	// Same direction for all terms: row value comparison (index friendly)
+	sameOrder := true
+	for _, t := range terms {
+		sameOrder = sameOrder && t.order == terms[0].order
+	}
+	if sameOrder {
+		exprs := make([]string, 0, len(terms))
+		for _, t := range terms {
+			exprs = append(exprs, t.expr)
+		}
+		operator := ">"
+		if terms[0].order == "DESC" {
+			operator = "<"
+		}
+		placeholders := "?" + strings.Repeat(", ?", len(terms)-1)
+		return fmt.Sprintf("(%s) %s (%s)", strings.Join(exprs, ", "), operator, placeholders), values
+	}
+
+	// Mixed directions: expanded lexicographic comparison (a > x) OR (a = x AND b < y) OR ...
+	var (
+		alternatives []string
+		args         []interface{}
+	)
+	for i, t := range terms {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, terms[j].expr+" = ?")
+			args = append(args, values[j])
+		}
+		operator := ">"
+		if t.order == "DESC" {
+			operator = "<"
+		}
+		parts = append(parts, t.expr+" "+operator+" ?")
+		args = append(args, values[i])
+		alternatives = append(alternatives, "("+strings.Join(parts, " AND ")+")")
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")", args
+}
+
+// matchCondition builds a substring, exact or trigram similarity condition for a normalized text column
+// (lowercase, unaccented). The value is normalized in SQL with the same expression.
+func matchCondition(column, value, mode string, fuzzy bool) (string, interface{}) {
+	normalized := fmt.Sprintf(normalizeValue, "?")
+	if fuzzy {
+		return fmt.Sprintf("word_similarity(%s, %s) >= %v", normalized, column, similarityThreshold), value
+	}
+	if mode == models.MatchExact {
+		return fmt.Sprintf("%s = %s", column, normalized), value
+	}
+	return fmt.Sprintf("%s LIKE %s", column, normalized), "%" + value + "%"
+}
+
 // validateUUIDOrNotFound checks if the given ID is a valid UUID.
 func validateUUIDOrNotFound(id string) error {
 	if _, err := uuid.Parse(id); err != nil {
@@ -9,6 +9,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/santiago-buildit/code-challenge/backend/internal/models"
 	"github.com/santiago-buildit/code-challenge/backend/internal/repositories"
 	"github.com/stretchr/testify/assert"
@@ -33,12 +34,12 @@ func TestListBooks_FilterByTitle(t *testing.T) {
 	}
 
 	// Count query
-	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM books WHERE title ILIKE \$1 AND deleted = false`).
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM books WHERE title_search LIKE lower\(f_unaccent\(\$1\)\) AND deleted = false`).
 		WithArgs("%The Lord of the Rings%").
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 
 	// Data query
-	mock.ExpectQuery(`(?i)^SELECT \* FROM books WHERE title ILIKE .+ AND deleted = false ORDER BY title ASC LIMIT 10 OFFSET 0$`).
+	mock.ExpectQuery(`(?i)^SELECT .+ FROM books WHERE title_search LIKE .+ AND deleted = false ORDER BY title ASC, id ASC LIMIT 10 OFFSET 0$`).
 		WithArgs("%The Lord of the Rings%").
 		WillReturnRows(sqlmock.NewRows([]string{
 			"id", "isbn", "title", "author", "description", "status", "created_at", "updated_at", "deleted",
@@ -78,11 +79,11 @@ func TestListBooks_FilterByTitleAndStatus(t *testing.T) {
 		SortOrder: "asc",
 	}
 
-	mock.ExpectQuery(`(?i)^SELECT COUNT\(\*\) FROM books WHERE title ILIKE .+ AND status = .+ AND deleted = false$`).
+	mock.ExpectQuery(`(?i)^SELECT COUNT\(\*\) FROM books WHERE title_search LIKE .+ AND status = .+ AND deleted = false$`).
 		WithArgs("%The Lord of the Rings%", "available").
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 
-	mock.ExpectQuery(`(?i)^SELECT \* FROM books WHERE title ILIKE .+ AND status = .+ AND deleted = false ORDER BY title ASC LIMIT 10 OFFSET 0$`).
+	mock.ExpectQuery(`(?i)^SELECT .+ FROM books WHERE title_search LIKE .+ AND status = .+ AND deleted = false ORDER BY title ASC, id ASC LIMIT 10 OFFSET 0$`).
 		WithArgs("%The Lord of the Rings%", "available").
 		WillReturnRows(sqlmock.NewRows([]string{
 			"id", "isbn", "title", "author", "description", "status", "created_at", "updated_at", "deleted",
@@ -121,7 +122,7 @@ func TestListBooks_Pagination(t *testing.T) {
 	mock.ExpectQuery(`(?i)^SELECT COUNT\(\*\) FROM books WHERE deleted = false$`).
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(25))
 
-	mock.ExpectQuery(`(?i)^SELECT \* FROM books WHERE deleted = false ORDER BY title ASC LIMIT 10 OFFSET 10$`).
+	mock.ExpectQuery(`(?i)^SELECT .+ FROM books WHERE deleted = false ORDER BY title ASC, id ASC LIMIT 10 OFFSET 10$`).
 		WillReturnRows(sqlmock.NewRows([]string{
 			"id", "isbn", "title", "author", "description", "status", "created_at", "updated_at", "deleted",
 		}).AddRow(
@@ -140,6 +141,222 @@ func TestListBooks_Pagination(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestListBooks_DateRangesStatusesAndExactMatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	req := models.ListBooksRequest{
+		Author:      "J.R.R. Tolkien",
+		AuthorMatch: models.MatchExact,
+		Statuses:    []string{"available", "checked_out"},
+		CreatedFrom: &from,
+		CreatedTo:   &to,
+		Page:        1,
+		PageSize:    10,
+		SortBy:      "created_at",
+		SortOrder:   "desc",
+	}
+
+	mock.ExpectQuery(`(?i)^SELECT COUNT\(\*\) FROM books WHERE author_search = lower\(f_unaccent\(\$1\)\) AND status IN \(\$2, \$3\) AND created_at >= \$4 AND created_at < \$5 AND deleted = false$`).
+		WithArgs("J.R.R. Tolkien", "available", "checked_out", from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	mock.ExpectQuery(`(?i)^SELECT .+ FROM books WHERE .+ ORDER BY created_at DESC, id DESC LIMIT 10 OFFSET 0$`).
+		WithArgs("J.R.R. Tolkien", "available", "checked_out", from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	books, total, err := repo.ListBooks(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Empty(t, books)
+	assert.Equal(t, 0, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListBooks_BibliographicFiltersAndSort(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	req := models.ListBooksRequest{
+		Publisher: "Minotauro",
+		Language:  "es",
+		Formats:   []string{"hardcover", "paperback"},
+		YearFrom:  1950,
+		YearTo:    1999,
+		Sort:      []models.SortSpec{{Field: "publication_year", Order: "desc"}, {Field: "page_count"}},
+		Page:      1,
+		PageSize:  10,
+	}
+
+	mock.ExpectQuery(`(?i)^SELECT COUNT\(\*\) FROM books WHERE lower\(f_unaccent\(publisher\)\) LIKE lower\(f_unaccent\(\$1\)\) AND language = lower\(\$2\) AND format IN \(\$3, \$4\) AND publication_year >= \$5 AND publication_year BETWEEN 1 AND \$6 AND deleted = false$`).
+		WithArgs("%Minotauro%", "es", "hardcover", "paperback", 1950, 1999).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	mock.ExpectQuery(`(?i)^SELECT .+ publisher, publication_year, edition, language, page_count, format, .+ FROM books WHERE .+ ORDER BY publication_year DESC, page_count ASC, id ASC LIMIT 10 OFFSET 0$`).
+		WithArgs("%Minotauro%", "es", "hardcover", "paperback", 1950, 1999).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "publisher", "publication_year", "language", "page_count", "format"}).
+			AddRow("1", "El Señor de los Anillos", "Minotauro", 1991, "es", 1392, "hardcover"))
+
+	books, total, err := repo.ListBooks(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	if assert.Len(t, books, 1) {
+		assert.Equal(t, 1991, books[0].PublicationYear)
+		assert.Equal(t, models.BookFormatHardcover, books[0].Format)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListBooks_Tags(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	req := models.ListBooksRequest{
+		Tags:      []string{"Fantasy", " classics ", "fantasy"}, // Normalized to 2 distinct tags
+		TagsMatch: models.MatchAll,
+		Page:      1,
+		PageSize:  10,
+	}
+
+	mock.ExpectQuery(`(?i)^SELECT COUNT\(\*\) FROM books WHERE \(SELECT COUNT\(\*\) FROM book_tags bt JOIN tags t ON t.id = bt.tag_id WHERE bt.book_id = books.id AND lower\(t.name\) IN \(lower\(\$1\), lower\(\$2\)\)\) = 2 AND deleted = false$`).
+		WithArgs("Fantasy", "classics").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	mock.ExpectQuery(`(?i)^SELECT .+ AS tags, .+ AS contributors FROM books WHERE .+ ORDER BY title ASC, id ASC LIMIT 10 OFFSET 0$`).
+		WithArgs("Fantasy", "classics").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "tags"}).AddRow("1", "The Hobbit", "{Classics,Fantasy}"))
+
+	books, total, err := repo.ListBooks(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	if assert.Len(t, books, 1) {
+		assert.Equal(t, pq.StringArray{"Classics", "Fantasy"}, books[0].Tags)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// Any (default): EXISTS
+	req.TagsMatch = ""
+	mock.ExpectQuery(`(?i)^SELECT COUNT\(\*\) FROM books WHERE EXISTS \(SELECT 1 FROM book_tags .+ IN \(lower\(\$1\), lower\(\$2\)\)\) AND deleted = false$`).
+		WithArgs("Fantasy", "classics").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	total, err = repo.CountBooks(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListBooks_Contributors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	authorID := "8a1c8f7e-3f0b-4d5e-9b1a-2c3d4e5f6a7b"
+	req := models.ListBooksRequest{
+		AuthorID:        authorID,
+		ContributorRole: "translator",
+		Page:            1,
+		PageSize:        10,
+	}
+
+	mock.ExpectQuery(`(?i)^SELECT COUNT\(\*\) FROM books WHERE EXISTS \(SELECT 1 FROM book_contributors bc WHERE bc.book_id = books.id AND bc.author_id = \$1 AND bc.role = \$2\) AND deleted = false$`).
+		WithArgs(authorID, "translator").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	mock.ExpectQuery(`(?i)^SELECT .+ AS contributors FROM books WHERE .+ ORDER BY title ASC, id ASC LIMIT 10 OFFSET 0$`).
+		WithArgs(authorID, "translator").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "author", "contributors"}).AddRow(
+			"1", "Homer", `[{"author_id":"a-1","name":"Homer","role":"author"},{"author_id":"`+authorID+`","name":"Emily Wilson","role":"translator"}]`,
+		))
+
+	books, total, err := repo.ListBooks(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	if assert.Len(t, books, 1) {
+		assert.Equal(t, models.Contributors{
+			{AuthorID: "a-1", Name: "Homer", Role: models.ContributorRoleAuthor},
+			{AuthorID: authorID, Name: "Emily Wilson", Role: models.ContributorRoleTranslator},
+		}, books[0].Contributors)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListBooks_QueryExpression(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	req := models.ListBooksRequest{
+		Q:        `author:tolkien AND (title:"ring" OR status:available)`,
+		Status:   "available",
+		Page:     1,
+		PageSize: 10,
+	}
+
+	mock.ExpectQuery(`(?i)^SELECT COUNT\(\*\) FROM books WHERE status = \$1 AND \(\(coalesce\(author_search, ''\) ILIKE lower\(f_unaccent\(\$2\)\)\) AND \(\(coalesce\(title_search, ''\) ILIKE lower\(f_unaccent\(\$3\)\)\) OR \(status = \$4\)\)\) AND deleted = false$`).
+		WithArgs("available", "%tolkien%", "%ring%", "available").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	mock.ExpectQuery(`(?i)^SELECT .+ FROM books WHERE .+ ORDER BY title ASC, id ASC LIMIT 10 OFFSET 0$`).
+		WithArgs("available", "%tolkien%", "%ring%", "available").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	books, total, err := repo.ListBooks(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Empty(t, books)
+	assert.Equal(t, 0, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListBooks_InvalidQueryExpression(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	req := models.ListBooksRequest{Q: `title:"ring`, Page: 1, PageSize: 10}
+
+	books, _, err := repo.ListBooks(ctx, req)
+
+	assert.ErrorIs(t, err, utils.ErrBadRequest)
+	assert.EqualError(t, err, "invalid query at position 7: unterminated quoted phrase")
+	assert.Nil(t, books)
+	assert.NoError(t, mock.ExpectationsWereMet()) // No query executed
+}
+
 func TestListBooks_FullTextSearch(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -150,34 +367,307 @@ func TestListBooks_FullTextSearch(t *testing.T) {
 
 	ctx := context.Background()
 	req := models.ListBooksRequest{
-		Text:      "concurrency",
+		Text:      "ring",
 		Page:      1,
 		PageSize:  10,
 		SortBy:    "title",
 		SortOrder: "asc",
 	}
 
-	mock.ExpectQuery(`(?i)^SELECT COUNT\(\*\) FROM books WHERE \(title ILIKE .+ OR description ILIKE .+\) AND deleted = false$`).
-		WithArgs("%concurrency%", "%concurrency%").
+	mock.ExpectQuery(`(?i)^SELECT COUNT\(\*\) FROM books, websearch_to_tsquery\('english_unaccent', \$1\) AS query WHERE search_vector @@ query AND deleted = false$`).
+		WithArgs("ring").
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 
-	mock.ExpectQuery(`(?i)^SELECT \* FROM books WHERE \(title ILIKE .+ OR description ILIKE .+\) AND deleted = false ORDER BY title ASC LIMIT 10 OFFSET 0$`).
-		WithArgs("%concurrency%", "%concurrency%").
+	mock.ExpectQuery(`(?i)^SELECT .+ts_rank\(search_vector, query\) AS rank.+ FROM books, websearch_to_tsquery\('english_unaccent', \$1\) AS query WHERE search_vector @@ query AND deleted = false ORDER BY title ASC, id ASC LIMIT 10 OFFSET 0$`).
+		WithArgs("ring").
 		WillReturnRows(sqlmock.NewRows([]string{
 			"id", "isbn", "title", "author", "description", "status", "created_at", "updated_at", "deleted",
+			"rank", "title_highlight", "description_highlight",
 		}).AddRow(
 			"3", "000999", "The Lord of the Rings", "J.R.R. Tolkien", "One Ring to rule them all, One Ring to find them, One Ring to bring them all and in the darkness bind them", "available",
 			time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 			time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 			false,
+			0.6, "The Lord of the \x02Rings\x03", "One \x02Ring\x03 to rule them all", // ts_headline delimiters
+		))
+
+	books, total, err := repo.ListBooks(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Len(t, books, 1)
+	assert.Equal(t, total, 1)
+	assert.Equal(t, "The Lord of the Rings", books[0].Title)
+	assert.Equal(t, "The Lord of the <mark>Rings</mark>", books[0].TitleHighlight)
+	assert.Equal(t, "One <mark>Ring</mark> to rule them all", books[0].DescriptionHighlight)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListBooks_FullTextSearchEscapesHighlights(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	req := models.ListBooksRequest{Text: "script", Page: 1, PageSize: 10, SortBy: "title", SortOrder: "asc"}
+
+	mock.ExpectQuery(`(?i)^SELECT COUNT\(\*\) FROM books, websearch_to_tsquery`).
+		WithArgs("script").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	title := `<script>alert("x")</script> & <img src=x onerror=alert(1)>`
+	mock.ExpectQuery(`(?i)^SELECT .+translate\(title, chr\(2\) \|\| chr\(3\), ''\).+ FROM books, websearch_to_tsquery`).
+		WithArgs("script").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "isbn", "title", "author", "status", "created_at", "updated_at", "deleted",
+			"rank", "title_highlight", "description_highlight",
+		}).AddRow(
+			"4", "000998", title, "Mallory", "available",
+			time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			false,
+			0.4, "<\x02script\x03>alert(\"x\")</\x02script\x03> & <img src=x onerror=alert(1)>", "",
+		))
+
+	books, _, err := repo.ListBooks(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Len(t, books, 1)
+	assert.Equal(t, title, books[0].Title) // Raw fields are JSON-encoded, not markup
+	assert.Equal(t, "&lt;<mark>script</mark>&gt;alert(&#34;x&#34;)&lt;/<mark>script</mark>&gt; &amp; &lt;img src=x onerror=alert(1)&gt;", books[0].TitleHighlight)
+	assert.Empty(t, books[0].DescriptionHighlight)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListBooks_SortByRelevance(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	req := models.ListBooksRequest{
+		Text:      "ring",
+		Status:    "available",
+		Page:      1,
+		PageSize:  10,
+		SortBy:    "relevance",
+		SortOrder: "asc",
+	}
+
+	// Text query argument goes first (FROM clause), then the WHERE filters
+	mock.ExpectQuery(`(?i)^SELECT COUNT\(\*\) FROM books, websearch_to_tsquery\('english_unaccent', \$1\) AS query WHERE search_vector @@ query AND status = \$2 AND deleted = false$`).
+		WithArgs("ring", "available").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	mock.ExpectQuery(`(?i)^SELECT .+ FROM books, .+ ORDER BY ts_rank\(search_vector, query\) DESC, id DESC LIMIT 10 OFFSET 0$`).
+		WithArgs("ring", "available").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	books, total, err := repo.ListBooks(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Empty(t, books)
+	assert.Equal(t, 0, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListBooks_Fuzzy(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	req := models.ListBooksRequest{
+		Text:      "lord of the rigns",
+		Author:    "Tolkein",
+		Page:      1,
+		PageSize:  10,
+		SortBy:    "relevance",
+		SortOrder: "asc",
+		Fuzzy:     true,
+	}
+
+	mock.ExpectQuery(`(?i)^SELECT COUNT\(\*\) FROM books, \(SELECT lower\(f_unaccent\(\$1::text\)\) AS term\) AS fuzzy WHERE GREATEST\(word_similarity\(fuzzy.term, title_search\), word_similarity\(fuzzy.term, author_search\)\) >= 0.4 AND word_similarity\(lower\(f_unaccent\(\$2\)\), author_search\) >= 0.4 AND deleted = false$`).
+		WithArgs("lord of the rigns", "Tolkein").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	mock.ExpectQuery(`(?i)^SELECT .+ AS rank FROM books, \(SELECT lower\(f_unaccent\(\$1::text\)\) AS term\) AS fuzzy WHERE .+ ORDER BY GREATEST\(.+\) DESC, id DESC LIMIT 10 OFFSET 0$`).
+		WithArgs("lord of the rigns", "Tolkein").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "isbn", "title", "author", "description", "status", "created_at", "updated_at", "deleted", "rank",
+		}).AddRow(
+			"1", "123456", "The Lord of the Rings", "J.R.R. Tolkien", "", "available",
+			time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			false, 0.7,
+		))
+
+	books, total, err := repo.ListBooks(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Len(t, books, 1)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "The Lord of the Rings", books[0].Title)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListBooksAfter_Backward(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	req := models.ListBooksRequest{
+		Status:     "available",
+		Pagination: models.PaginationCursor,
+		PageSize:   10,
+		SortBy:     "author",
+		SortOrder:  "asc",
+	}
+	cursor := &models.Cursor{Sort: "author:asc", Keys: []interface{}{"Tolkien"}, ID: "fac2b19c-e857-4d40-8233-8132b9759b55", Backward: true}
+
+	// Backward walk: reversed comparison and order
+	mock.ExpectQuery(`(?i)^SELECT .+ FROM books WHERE status = \$1 AND deleted = false AND \(author, id\) < \(\$2, \$3\) ORDER BY author DESC, id DESC LIMIT 11$`).
+		WithArgs("available", "Tolkien", "fac2b19c-e857-4d40-8233-8132b9759b55").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "isbn", "title", "author", "description", "status", "created_at", "updated_at", "deleted",
+		}).AddRow(
+			"1", "123456", "The Hobbit", "J.R.R. Tolkien", "", "available",
+			time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			false,
 		))
 
-	books, total, err := repo.ListBooks(ctx, req)
+	books, err := repo.ListBooksAfter(ctx, req, cursor, 11)
+
+	assert.NoError(t, err)
+	assert.Len(t, books, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListBooksAfter_MultiColumnMixedOrder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	req := models.ListBooksRequest{
+		Pagination: models.PaginationCursor,
+		PageSize:   10,
+		Sort: []models.SortSpec{
+			{Field: "author", Order: "asc"},
+			{Field: "created_at", Order: "desc"},
+		},
+	}
+	cursor := &models.Cursor{Sort: "author:asc,created_at:desc", Keys: []interface{}{"Tolkien", "2024-01-01T00:00:00Z"}, ID: "fac2b19c-e857-4d40-8233-8132b9759b55"}
+
+	// Mixed directions: expanded lexicographic comparison
+	mock.ExpectQuery(`(?i)^SELECT .+ FROM books WHERE deleted = false AND \(\(author > \$1\) OR \(author = \$2 AND created_at < \$3\) OR \(author = \$4 AND created_at = \$5 AND id < \$6\)\) ORDER BY author ASC, created_at DESC, id DESC LIMIT 11$`).
+		WithArgs("Tolkien", "Tolkien", "2024-01-01T00:00:00Z", "Tolkien", "2024-01-01T00:00:00Z", "fac2b19c-e857-4d40-8233-8132b9759b55").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	books, err := repo.ListBooksAfter(ctx, req, cursor, 11)
+
+	assert.NoError(t, err)
+	assert.Empty(t, books)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListBooks_MultiColumnSort(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	req := models.ListBooksRequest{
+		Page:     1,
+		PageSize: 10,
+		SortBy:   "isbn", // Ignored when Sort is given
+		Sort: []models.SortSpec{
+			{Field: "status"},
+			{Field: "updated_at", Order: "desc"},
+		},
+	}
+
+	mock.ExpectQuery(`(?i)^SELECT COUNT\(\*\) FROM books WHERE deleted = false$`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	mock.ExpectQuery(`(?i)^SELECT .+ FROM books WHERE deleted = false ORDER BY status ASC, updated_at DESC, id DESC LIMIT 10 OFFSET 0$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, _, err = repo.ListBooks(ctx, req)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListFacets(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	req := models.ListBooksRequest{
+		Title:  "Rings",
+		Facets: []string{"status", "unknown", "author"},
+	}
+
+	// Same WHERE clause as the list query, one query per valid facet
+	mock.ExpectQuery(`(?i)^SELECT status AS value, COUNT\(\*\) AS count FROM books WHERE title_search LIKE lower\(f_unaccent\(\$1\)\) AND deleted = false GROUP BY status ORDER BY count DESC, value ASC LIMIT 20$`).
+		WithArgs("%Rings%").
+		WillReturnRows(sqlmock.NewRows([]string{"value", "count"}).AddRow("available", 2).AddRow("checked_out", 1))
+
+	mock.ExpectQuery(`(?i)^SELECT author AS value, COUNT\(\*\) AS count FROM books WHERE title_search LIKE lower\(f_unaccent\(\$1\)\) AND deleted = false GROUP BY author ORDER BY count DESC, value ASC LIMIT 20$`).
+		WithArgs("%Rings%").
+		WillReturnRows(sqlmock.NewRows([]string{"value", "count"}).AddRow("J.R.R. Tolkien", 3))
+
+	facets, err := repo.ListFacets(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Len(t, facets, 2)
+	assert.Equal(t, []models.FacetBucket{{Value: "available", Count: 2}, {Value: "checked_out", Count: 1}}, facets["status"])
+	assert.Equal(t, []models.FacetBucket{{Value: "J.R.R. Tolkien", Count: 3}}, facets["author"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSuggestTerms(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+
+	mock.ExpectQuery(`(?i)^SELECT candidate FROM .+ WHERE score >= \$2 GROUP BY candidate ORDER BY MAX\(score\) DESC, candidate ASC LIMIT \$3$`).
+		WithArgs("Tolkein", 0.4, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"candidate"}).AddRow("J.R.R. Tolkien"))
+
+	suggestions, err := repo.SuggestTerms(ctx, "Tolkein", 5)
 
 	assert.NoError(t, err)
-	assert.Len(t, books, 1)
-	assert.Equal(t, total, 1)
-	assert.Equal(t, "The Lord of the Rings", books[0].Title)
+	assert.Equal(t, []string{"J.R.R. Tolkien"}, suggestions)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -192,7 +682,7 @@ func TestGetBookByID_Found(t *testing.T) {
 	ctx := context.Background()
 	bookID := "fac2b19c-e857-4d40-8233-8132b9759b55"
 
-	mock.ExpectQuery(`(?i)^SELECT \* FROM books WHERE id = \$1 AND deleted = false$`).
+	mock.ExpectQuery(`(?i)^SELECT .+ FROM books WHERE id = \$1 AND deleted = false$`).
 		WithArgs(bookID).
 		WillReturnRows(sqlmock.NewRows([]string{
 			"id", "isbn", "title", "author", "description", "status", "created_at", "updated_at", "deleted",
@@ -224,7 +714,7 @@ func TestGetBookByID_NotFound(t *testing.T) {
 	bookID := "fac2b19c-e857-4d40-8233-8132b9759b55"
 
 	// Simulate no rows for that ID
-	mock.ExpectQuery(`(?i)^SELECT \* FROM books WHERE id = \$1 AND deleted = false$`).
+	mock.ExpectQuery(`(?i)^SELECT .+ FROM books WHERE id = \$1 AND deleted = false$`).
 		WithArgs(bookID).
 		WillReturnError(sql.ErrNoRows)
 
@@ -252,13 +742,24 @@ func TestUpdateBook_Success(t *testing.T) {
 		Description: "One Ring to rule them all, One Ring to find them, One Ring to bring them all and in the darkness bind them",
 		Status:      models.BookStatusAvailable,
 		UpdatedAt:   time.Now(),
+
+		Publisher:       "Houghton Mifflin",
+		PublicationYear: 1954,
+		Edition:         "1st",
+		Language:        "en",
+		PageCount:       1216,
+		Format:          models.BookFormatHardcover,
 	}
 
+	mock.ExpectBegin()
 	mock.ExpectExec(`(?i)^UPDATE books SET`).
-		WithArgs(book.ISBN, book.Title, book.Author, book.Description, book.Status, book.UpdatedAt, book.ID).
+		WithArgs(book.ISBN, book.Title, book.Author, book.Description,
+			book.Publisher, book.PublicationYear, book.Edition, book.Language, book.PageCount, book.Format,
+								book.Status, book.UpdatedAt, book.ID).
 		WillReturnResult(sqlmock.NewResult(0, 1)) // 1 row affected
 
-	err = repo.UpdateBook(ctx, book)
+	tx := sqlxDB.MustBegin()
+	err = repo.UpdateBook(ctx, tx, book)
 
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -283,17 +784,140 @@ func TestUpdateBook_NotFound(t *testing.T) {
 		UpdatedAt:   time.Now(),
 	}
 
+	mock.ExpectBegin()
 	mock.ExpectExec(`(?i)^UPDATE books SET`).
-		WithArgs(book.ISBN, book.Title, book.Author, book.Description, book.Status, book.UpdatedAt, book.ID).
+		WithArgs(book.ISBN, book.Title, book.Author, book.Description, "", 0, "", "", 0, models.BookFormat(""), book.Status, book.UpdatedAt, book.ID).
 		WillReturnResult(sqlmock.NewResult(0, 0)) // 0 rows affected
 
-	err = repo.UpdateBook(ctx, book)
+	tx := sqlxDB.MustBegin()
+	err = repo.UpdateBook(ctx, tx, book)
 
 	assert.ErrorIs(t, err, utils.ErrNotFound)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestDeleteBook_Success(t *testing.T) {
+func TestSetBookCover(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	bookID := "fac2b19c-e857-4d40-8233-8132b9759b55"
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`(?i)^UPDATE books SET cover_updated_at = \$1, updated_at = \$1 WHERE id = \$2 AND deleted = false$`).
+		WithArgs(now, bookID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`(?i)^UPDATE books SET cover_updated_at`).
+		WithArgs(now, bookID).
+		WillReturnResult(sqlmock.NewResult(0, 0)) // Deleted meanwhile
+
+	tx := sqlxDB.MustBegin()
+	assert.NoError(t, repo.SetBookCover(ctx, tx, bookID, now))
+	assert.ErrorIs(t, repo.SetBookCover(ctx, tx, bookID, now), utils.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetBookTags(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	bookID := "fac2b19c-e857-4d40-8233-8132b9759b55"
+
+	mock.ExpectBegin()
+	for _, name := range []string{"Fantasy", "classics"} {
+		mock.ExpectExec(`(?i)^INSERT INTO tags .+ ON CONFLICT \(\(lower\(name\)\)\) DO NOTHING$`).
+			WithArgs(sqlmock.AnyArg(), name, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectExec(`(?i)^DELETE FROM book_tags WHERE book_id = \$1$`).
+		WithArgs(bookID).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectQuery(`(?i)^WITH assigned AS \( INSERT INTO book_tags .+ WHERE lower\(name\) = ANY\(\$2\) RETURNING tag_id \) SELECT t.name .+$`).
+		WithArgs(bookID, pq.Array([]string{"fantasy", "classics"})).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Classics").AddRow("Fantasy"))
+
+	tx := sqlxDB.MustBegin()
+	assigned, err := repo.SetBookTags(ctx, tx, bookID, []string{"Fantasy", "classics"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Classics", "Fantasy"}, assigned)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetBookTags_Clear(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	bookID := "fac2b19c-e857-4d40-8233-8132b9759b55"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`(?i)^DELETE FROM book_tags WHERE book_id = \$1$`).
+		WithArgs(bookID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	tx := sqlxDB.MustBegin()
+	assigned, err := repo.SetBookTags(ctx, tx, bookID, []string{})
+
+	assert.NoError(t, err)
+	assert.Empty(t, assigned)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetBookContributors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	bookID := "fac2b19c-e857-4d40-8233-8132b9759b55"
+	contributors := models.Contributors{
+		{Name: "j.r.r. tolkien", Role: models.ContributorRoleAuthor},
+		{Name: "Christopher Tolkien", Role: models.ContributorRoleEditor},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`(?i)^DELETE FROM book_contributors WHERE book_id = \$1$`).
+		WithArgs(bookID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	for i, author := range []struct{ id, name string }{{"author-1", "J.R.R. Tolkien"}, {"author-2", "Christopher Tolkien"}} {
+		mock.ExpectQuery(`(?i)^INSERT INTO authors .+ ON CONFLICT \(\(lower\(name\)\)\) DO UPDATE SET name = authors.name RETURNING id, name$`).
+			WithArgs(sqlmock.AnyArg(), contributors[i].Name, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(author.id, author.name))
+		mock.ExpectExec(`(?i)^INSERT INTO book_contributors`).
+			WithArgs(bookID, author.id, contributors[i].Role, i).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+
+	tx := sqlxDB.MustBegin()
+	assigned, err := repo.SetBookContributors(ctx, tx, bookID, contributors)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.Contributors{
+		{AuthorID: "author-1", Name: "J.R.R. Tolkien", Role: models.ContributorRoleAuthor}, // Stored spelling
+		{AuthorID: "author-2", Name: "Christopher Tolkien", Role: models.ContributorRoleEditor},
+	}, assigned)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetBookContributors_SkipsDuplicates(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
@@ -303,14 +927,122 @@ func TestDeleteBook_Success(t *testing.T) {
 
 	ctx := context.Background()
 	bookID := "fac2b19c-e857-4d40-8233-8132b9759b55"
+	contributors := models.Contributors{
+		{Name: "Ursula K. Le Guin", Role: models.ContributorRoleAuthor},
+		{Name: "ursula k. le guin", Role: models.ContributorRoleAuthor}, // Same author and role
+	}
 
-	mock.ExpectExec(`(?i)^UPDATE books SET deleted = true WHERE id = \$1 AND deleted = false$`).
+	mock.ExpectBegin()
+	mock.ExpectExec(`(?i)^DELETE FROM book_contributors WHERE book_id = \$1$`).
 		WithArgs(bookID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	for i, inserted := range []int64{1, 0} {
+		mock.ExpectQuery(`(?i)^INSERT INTO authors`).
+			WithArgs(sqlmock.AnyArg(), contributors[i].Name, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("author-1", "Ursula K. Le Guin"))
+		mock.ExpectExec(`(?i)^INSERT INTO book_contributors .+ ON CONFLICT DO NOTHING$`).
+			WithArgs(bookID, "author-1", models.ContributorRoleAuthor, i).
+			WillReturnResult(sqlmock.NewResult(0, inserted))
+	}
+
+	tx := sqlxDB.MustBegin()
+	assigned, err := repo.SetBookContributors(ctx, tx, bookID, contributors)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.Contributors{
+		{AuthorID: "author-1", Name: "Ursula K. Le Guin", Role: models.ContributorRoleAuthor},
+	}, assigned)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteBook_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	bookID := "fac2b19c-e857-4d40-8233-8132b9759b55"
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`(?i)^UPDATE books SET deleted = true, updated_at = \$1 WHERE id = \$2 AND deleted = false$`).
+		WithArgs(now, bookID).
 		WillReturnResult(sqlmock.NewResult(0, 1)) // 1 row affected
 
-	err = repo.DeleteBook(ctx, bookID)
+	tx := sqlxDB.MustBegin()
+	err = repo.DeleteBook(ctx, tx, bookID, now)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTouchBooksByTag(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	tagID := "8a1c8f7e-3f0b-4d5e-9b1a-2c3d4e5f6a7b"
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`(?i)^UPDATE books SET updated_at = \$1 WHERE deleted = false AND id IN \(SELECT book_id FROM book_tags WHERE tag_id = \$2\) RETURNING id$`).
+		WithArgs(now, tagID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("book-1").AddRow("book-2"))
+	mock.ExpectQuery(`(?i)^SELECT .+ FROM books WHERE id = ANY\(\$1\) AND deleted = false ORDER BY id$`).
+		WithArgs(pq.Array([]string{"book-1", "book-2"})).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "updated_at"}).
+			AddRow("book-1", "Earthsea", now).
+			AddRow("book-2", "Dune", now))
+
+	tx := sqlxDB.MustBegin()
+	ids, err := repo.TouchBooksByTag(ctx, tx, tagID, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"book-1", "book-2"}, ids)
+
+	books, err := repo.GetBooksByIDs(ctx, tx, ids)
+	assert.NoError(t, err)
+	if assert.Len(t, books, 2) {
+		assert.Equal(t, "Earthsea", books[0].Title)
+		assert.Equal(t, now, books[1].UpdatedAt)
+	}
+
+	// No books, no query
+	books, err = repo.GetBooksByIDs(ctx, tx, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, books)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLastUpdatedAt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	now := time.Now()
+
+	mock.ExpectQuery(`(?i)^SELECT MAX\(updated_at\) FROM books$`).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(now))
+	mock.ExpectQuery(`(?i)^SELECT MAX\(updated_at\) FROM books$`).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(nil)) // No books
+
+	last, err := repo.LastUpdatedAt(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, now, last)
 
+	last, err = repo.LastUpdatedAt(ctx)
 	assert.NoError(t, err)
+	assert.True(t, last.IsZero())
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -324,13 +1056,230 @@ func TestDeleteBook_NotFound(t *testing.T) {
 
 	ctx := context.Background()
 	bookID := "fac2b19c-e857-4d40-8233-8132b9759b55"
+	now := time.Now()
 
-	mock.ExpectExec(`(?i)^UPDATE books SET deleted = true WHERE id = \$1 AND deleted = false$`).
-		WithArgs(bookID).
+	mock.ExpectBegin()
+	mock.ExpectExec(`(?i)^UPDATE books SET deleted = true, updated_at = \$1 WHERE id = \$2 AND deleted = false$`).
+		WithArgs(now, bookID).
 		WillReturnResult(sqlmock.NewResult(0, 0)) // 0 rows affected
 
-	err = repo.DeleteBook(ctx, bookID)
+	tx := sqlxDB.MustBegin()
+	err = repo.DeleteBook(ctx, tx, bookID, now)
+
+	assert.ErrorIs(t, err, utils.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateBookStatus_ConcurrentChange(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	bookID := "fac2b19c-e857-4d40-8233-8132b9759b55"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`(?i)^UPDATE books SET status = \$1, updated_at = \$2 WHERE id = \$3 AND deleted = false AND status = \$4$`).
+		WithArgs(models.BookStatusCheckedOut, sqlmock.AnyArg(), bookID, models.BookStatusAvailable).
+		WillReturnResult(sqlmock.NewResult(0, 0)) // No longer available
+
+	tx := sqlxDB.MustBegin()
+	err = repo.UpdateBookStatus(ctx, tx, bookID, models.BookStatusAvailable, models.BookStatusCheckedOut, time.Now())
+
+	assert.ErrorIs(t, err, utils.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBookWithHistory_Latest(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	bookID := "fac2b19c-e857-4d40-8233-8132b9759b55"
+	now := time.Now()
+
+	mock.ExpectQuery(`(?i)^SELECT .+ FROM books WHERE id = \$1 AND deleted = false$`).
+		WithArgs(bookID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "status"}).AddRow(bookID, "Ficciones", "checked_out"))
+
+	// Latest changes only, same order as the paginated history (ID breaks timestamp ties)
+	mock.ExpectQuery(`(?i)^SELECT status, timestamp, actor, source, note FROM book_status_changes WHERE book_id = \$1 ORDER BY timestamp DESC, id DESC LIMIT \$2$`).
+		WithArgs(bookID, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"status", "timestamp", "actor", "source", "note"}).
+			AddRow("checked_out", now, "ana", "desk", "").
+			AddRow("available", now, "", "", ""))
+
+	book, history, err := repo.GetBookWithHistory(ctx, bookID, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Ficciones", book.Title)
+	assert.Len(t, history, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListHistoryByBookIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	bookID := "fac2b19c-e857-4d40-8233-8132b9759b55"
+	now := time.Now()
+
+	// Invalid IDs are skipped (no history)
+	mock.ExpectQuery(`(?i)^SELECT book_id, status, timestamp, actor, source, note FROM \( SELECT \*, row_number\(\) OVER \(PARTITION BY book_id ORDER BY timestamp DESC, id DESC\) AS position FROM book_status_changes WHERE book_id = ANY\(\$1\) \) latest WHERE position <= \$2 ORDER BY book_id, timestamp DESC, id DESC$`).
+		WithArgs(pq.Array([]string{bookID}), 20).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "status", "timestamp", "actor", "source", "note"}).
+			AddRow(bookID, "checked_out", now, "ana", "desk", "").
+			AddRow(bookID, "available", now.Add(-time.Hour), "", "", ""))
+
+	history, err := repo.ListHistoryByBookIDs(ctx, []string{bookID, "not-a-uuid"}, 20)
+
+	assert.NoError(t, err)
+	if assert.Len(t, history, 2) {
+		assert.Equal(t, bookID, history[0].BookID)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAppendStatusChange(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	bookID := "fac2b19c-e857-4d40-8233-8132b9759b55"
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`(?i)^INSERT INTO book_status_changes \(book_id, status, timestamp, actor, source, note\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6\)$`).
+		WithArgs(bookID, models.BookStatusCheckedOut, now, "ana", models.StatusChangeSourceDesk, "Damaged cover").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	tx := sqlxDB.MustBegin()
+	err = repo.AppendStatusChange(ctx, tx, &models.BookStatusChange{
+		BookID: bookID, Status: models.BookStatusCheckedOut, Timestamp: now,
+		Actor: "ana", Source: models.StatusChangeSourceDesk, Note: "Damaged cover",
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListHistory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	bookID := "fac2b19c-e857-4d40-8233-8132b9759b55"
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`(?i)^SELECT COUNT\(\*\) FROM book_status_changes WHERE book_id = \$1 AND timestamp >= \$2 AND timestamp < \$3$`).
+		WithArgs(bookID, from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(25))
+	mock.ExpectQuery(`(?i)^SELECT book_id, status, timestamp, actor, source, note FROM book_status_changes WHERE book_id = \$1 AND timestamp >= \$2 AND timestamp < \$3 ORDER BY timestamp DESC, id DESC LIMIT 10 OFFSET 20$`).
+		WithArgs(bookID, from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "status", "timestamp", "actor", "source", "note"}).
+			AddRow(bookID, "available", from.Add(time.Hour), "kiosk-3", "kiosk", ""))
+
+	history, total, err := repo.ListHistory(ctx, bookID, models.ListHistoryRequest{Page: 3, PageSize: 10, From: from, To: to})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 25, total)
+	if assert.Len(t, history, 1) {
+		assert.Equal(t, models.StatusChangeSourceKiosk, history[0].Source)
+		assert.Equal(t, "kiosk-3", history[0].Actor)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListHistory_InvalidID(t *testing.T) {
+	db, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewBookRepository(sqlx.NewDb(db, "postgres"))
+
+	_, _, err = repo.ListHistory(context.Background(), "not-a-uuid", models.ListHistoryRequest{Page: 1, PageSize: 10})
+	assert.ErrorIs(t, err, utils.ErrNotFound)
+}
+
+func TestRestoreBook(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	bookID := "fac2b19c-e857-4d40-8233-8132b9759b55"
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`(?i)^UPDATE books SET deleted = false, updated_at = \$1 WHERE id = \$2 AND deleted = true$`).
+		WithArgs(now, bookID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`(?i)^UPDATE books SET deleted = false`).
+		WithArgs(now, bookID).
+		WillReturnResult(sqlmock.NewResult(0, 0)) // Not deleted
+
+	tx := sqlxDB.MustBegin()
+	assert.NoError(t, repo.RestoreBook(ctx, tx, bookID, now))
+	assert.ErrorIs(t, repo.RestoreBook(ctx, tx, bookID, now), utils.ErrNotFound)
+	assert.ErrorIs(t, repo.RestoreBook(ctx, tx, "not-a-uuid", now), utils.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListStatusDrift(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewBookRepository(sqlxDB)
+
+	ctx := context.Background()
+	bookID := "fac2b19c-e857-4d40-8233-8132b9759b55"
+	columns := []string{"book_id", "title", "status", "expected"}
+
+	// All books (latest status change, available without history)
+	mock.ExpectQuery(`(?i)^SELECT b\.id AS book_id, b\.title, b\.status, COALESCE\(h\.status, \$1\) AS expected FROM books b LEFT JOIN LATERAL \(.+ORDER BY timestamp DESC, id DESC LIMIT 1 \) h ON true WHERE b\.deleted = false AND b\.status <> COALESCE\(h\.status, \$1\) ORDER BY b\.title, b\.id$`).
+		WithArgs(models.BookStatusAvailable).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(bookID, "The Hobbit", "available", "checked_out"))
+
+	// Given books
+	mock.ExpectQuery(`(?i)WHERE b\.deleted = false AND b\.status <> COALESCE\(h\.status, \$1\) AND b\.id = ANY\(\$2\)`).
+		WithArgs(models.BookStatusAvailable, pq.Array([]string{bookID})).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	drift, err := repo.ListStatusDrift(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.BookStatusDrift{{BookID: bookID, Title: "The Hobbit", Status: models.BookStatusAvailable, Expected: models.BookStatusCheckedOut}}, drift)
+
+	drift, err = repo.ListStatusDrift(ctx, []string{bookID})
+	assert.NoError(t, err)
+	assert.Empty(t, drift)
 
+	_, err = repo.ListStatusDrift(ctx, []string{bookID, "not-a-uuid"})
 	assert.ErrorIs(t, err, utils.ErrNotFound)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -0,0 +1,171 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+)
+
+// OutboxNotifyChannel is the Postgres NOTIFY channel that carries the seq of every committed outbox event
+const OutboxNotifyChannel = "outbox_events"
+
+type OutboxRepository interface {
+
+	// Producers (in the transaction of the state change)
+	AppendEvent(ctx context.Context, tx *sqlx.Tx, event *models.OutboxEvent) error
+
+	// Dispatcher (claimed rows stay locked until the transaction ends)
+	ClaimPendingEvents(ctx context.Context, tx *sqlx.Tx, now time.Time, limit int) ([]models.OutboxEvent, error)
+	MarkEventProcessed(ctx context.Context, tx *sqlx.Tx, id string, timestamp time.Time) error
+	ScheduleEventRetry(ctx context.Context, tx *sqlx.Tx, event *models.OutboxEvent) error
+	DeleteProcessedEvents(ctx context.Context, before time.Time) (int64, error)
+
+	// Event stream (by seq, the insert order: a transaction may commit after one with a higher seq)
+	GetLatestEventSeq(ctx context.Context) (int64, error)
+	GetResumeSeq(ctx context.Context, seq int64, grace time.Duration) (int64, error)
+	ListEventsAfter(ctx context.Context, seq int64, limit int) ([]models.OutboxEvent, error)
+	ListEventsBySeq(ctx context.Context, seqs []int64) ([]models.OutboxEvent, error)
+}
+
+const outboxColumns = `id, seq, event_type, aggregate_type, aggregate_id, payload, attempts,
+	next_attempt_at, processed_at, last_error, created_at`
+
+type outboxRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+func NewOutboxRepository(db *sqlx.DB) OutboxRepository {
+	return &outboxRepositoryImpl{
+		db: db,
+	}
+}
+
+// External TX
+func (r *outboxRepositoryImpl) AppendEvent(ctx context.Context, tx *sqlx.Tx, event *models.OutboxEvent) error {
+
+	// Execute insert
+	_, err := tx.NamedExecContext(ctx, `
+		INSERT INTO outbox (id, event_type, aggregate_type, aggregate_id, payload, attempts,
+			next_attempt_at, processed_at, last_error, created_at)
+		VALUES (:id, :event_type, :aggregate_type, :aggregate_id, :payload, :attempts,
+			:next_attempt_at, :processed_at, :last_error, :created_at)
+	`, event)
+	return err
+}
+
+// External TX
+func (r *outboxRepositoryImpl) ClaimPendingEvents(ctx context.Context, tx *sqlx.Tx, now time.Time, limit int) ([]models.OutboxEvent, error) {
+
+	// Execute query (rows locked by another dispatcher are skipped, not waited for)
+	events := []models.OutboxEvent{}
+	err := tx.SelectContext(ctx, &events, `
+		SELECT `+outboxColumns+` FROM outbox
+		WHERE processed_at IS NULL AND next_attempt_at <= $1
+		ORDER BY created_at ASC, id ASC
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`, now, limit)
+	return events, err
+}
+
+// External TX
+func (r *outboxRepositoryImpl) MarkEventProcessed(ctx context.Context, tx *sqlx.Tx, id string, timestamp time.Time) error {
+
+	// Execute update
+	res, err := tx.ExecContext(ctx, `
+		UPDATE outbox SET processed_at = $1, attempts = attempts + 1, last_error = '' WHERE id = $2
+	`, timestamp, id)
+	if err != nil {
+		return err
+	}
+
+	// Check for not found error
+	return utils.CheckRowsAffected(res)
+}
+
+// External TX
+func (r *outboxRepositoryImpl) ScheduleEventRetry(ctx context.Context, tx *sqlx.Tx, event *models.OutboxEvent) error {
+
+	// Execute update
+	res, err := tx.NamedExecContext(ctx, `
+		UPDATE outbox SET
+			attempts = :attempts,
+			next_attempt_at = :next_attempt_at,
+			last_error = :last_error
+		WHERE id = :id
+	`, event)
+	if err != nil {
+		return err
+	}
+
+	// Check for not found error
+	return utils.CheckRowsAffected(res)
+}
+
+func (r *outboxRepositoryImpl) DeleteProcessedEvents(ctx context.Context, before time.Time) (int64, error) {
+
+	// Execute delete
+	res, err := r.db.ExecContext(ctx, `
+		DELETE FROM outbox WHERE processed_at < $1
+	`, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *outboxRepositoryImpl) GetLatestEventSeq(ctx context.Context) (int64, error) {
+
+	// Execute query (0 when the outbox is empty)
+	var seq int64
+	err := r.db.GetContext(ctx, &seq, `SELECT COALESCE(MAX(seq), 0) FROM outbox`)
+	return seq, err
+}
+
+// GetResumeSeq returns the seq to list from so that no event committed after the one at seq is missed:
+// the events below it created up to grace (the longest write transaction) before it may have committed later
+func (r *outboxRepositoryImpl) GetResumeSeq(ctx context.Context, seq int64, grace time.Duration) (int64, error) {
+
+	// Execute query (seq itself when no event precedes it within the grace period)
+	var resumeSeq int64
+	err := r.db.GetContext(ctx, &resumeSeq, `
+		SELECT COALESCE(MIN(seq) - 1, $1) FROM outbox
+		WHERE seq < $1
+		AND created_at > (SELECT COALESCE(MAX(created_at), now()) FROM outbox WHERE seq = $1) - $2 * INTERVAL '1 second'
+	`, seq, grace.Seconds())
+	return resumeSeq, err
+}
+
+func (r *outboxRepositoryImpl) ListEventsAfter(ctx context.Context, seq int64, limit int) ([]models.OutboxEvent, error) {
+
+	// Execute query
+	events := []models.OutboxEvent{}
+	err := r.db.SelectContext(ctx, &events, `
+		SELECT `+outboxColumns+` FROM outbox
+		WHERE seq > $1
+		ORDER BY seq ASC
+		LIMIT $2
+	`, seq, limit)
+	return events, err
+}
+
+func (r *outboxRepositoryImpl) ListEventsBySeq(ctx context.Context, seqs []int64) ([]models.OutboxEvent, error) {
+
+	// Check for empty input
+	events := []models.OutboxEvent{}
+	if len(seqs) == 0 {
+		return events, nil
+	}
+
+	// Execute query
+	err := r.db.SelectContext(ctx, &events, `
+		SELECT `+outboxColumns+` FROM outbox
+		WHERE seq = ANY($1)
+		ORDER BY seq ASC
+	`, pq.Array(seqs))
+	return events, err
+}
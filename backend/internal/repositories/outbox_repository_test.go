@@ -0,0 +1,124 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewOutboxRepository(sqlxDB)
+
+	ctx := context.Background()
+	now := time.Now()
+	event := &models.OutboxEvent{
+		ID: "event-1", EventType: "book.created", AggregateType: models.AggregateBook, AggregateID: "book-1",
+		Payload: []byte(`{}`), NextAttemptAt: now, CreatedAt: now,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`(?i)^INSERT INTO outbox`).
+		WithArgs("event-1", "book.created", "book", "book-1", []byte(`{}`), 0, now, nil, "", now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	tx := sqlxDB.MustBegin()
+	err = repo.AppendEvent(ctx, tx, event)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClaimPendingEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewOutboxRepository(sqlxDB)
+
+	ctx := context.Background()
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`(?i)^SELECT .+ FROM outbox WHERE processed_at IS NULL AND next_attempt_at <= \$1 ORDER BY created_at ASC, id ASC LIMIT \$2 FOR UPDATE SKIP LOCKED$`).
+		WithArgs(now, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_type", "attempts"}).
+			AddRow("event-1", "book.created", 0).
+			AddRow("event-2", "book.deleted", 3))
+
+	tx := sqlxDB.MustBegin()
+	events, err := repo.ClaimPendingEvents(ctx, tx, now, 20)
+
+	assert.NoError(t, err)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, 3, events[1].Attempts)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListEventsAfter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewOutboxRepository(sqlxDB)
+
+	mock.ExpectQuery(`(?i)^SELECT .+ FROM outbox WHERE seq > \$1 ORDER BY seq ASC LIMIT \$2$`).
+		WithArgs(int64(41), 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "seq", "event_type"}).
+			AddRow("event-1", 42, "book.created").
+			AddRow("event-2", 44, "book.updated"))
+
+	events, err := repo.ListEventsAfter(context.Background(), 41, 100)
+
+	assert.NoError(t, err)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, int64(44), events[1].Seq)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetResumeSeq(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewOutboxRepository(sqlx.NewDb(db, "postgres"))
+
+	// Event 40 was inserted before 42 but committed after it
+	mock.ExpectQuery(`(?i)^SELECT COALESCE\(MIN\(seq\) - 1, \$1\) FROM outbox WHERE seq < \$1 AND created_at > .+ - \$2 \* INTERVAL '1 second'$`).
+		WithArgs(int64(42), float64(60)).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(39))
+
+	seq, err := repo.GetResumeSeq(context.Background(), 42, time.Minute)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(39), seq)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListEventsBySeq_Empty(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewOutboxRepository(sqlx.NewDb(db, "postgres"))
+
+	events, err := repo.ListEventsBySeq(context.Background(), nil)
+
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
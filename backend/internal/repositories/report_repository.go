@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+)
+
+// ReportRepository computes circulation statistics from book_status_changes (non-deleted books only).
+// Windows are [from, to). Withdrawn books are not counted in the current stock (checked-out ratio, never borrowed).
+type ReportRepository interface {
+	TopBooks(ctx context.Context, from, to time.Time, limit int) ([]models.BookCheckouts, error)
+	TopAuthors(ctx context.Context, from, to time.Time, limit int) ([]models.AuthorCheckouts, error)
+	CheckoutSeries(ctx context.Context, from, to time.Time, interval models.ReportInterval) ([]models.CheckoutsPoint, error)
+	LoanDuration(ctx context.Context, from, to time.Time) (*models.LoanDuration, error) // Loans returned within the window
+	CountCheckedOut(ctx context.Context) (*models.CheckedOutCount, error)
+	NeverBorrowed(ctx context.Context, limit int) ([]models.NeverBorrowedBook, int /* total */, error)
+}
+
+// Checkouts of non-deleted books within the window ($1, $2)
+const windowCheckouts = `
+	SELECT c.book_id, c.timestamp FROM book_status_changes c
+	JOIN books b ON b.id = c.book_id AND b.deleted = false
+	WHERE c.status = 'checked_out' AND c.timestamp >= $1 AND c.timestamp < $2`
+
+type reportRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+func NewReportRepository(db *sqlx.DB) ReportRepository {
+	return &reportRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *reportRepositoryImpl) TopBooks(ctx context.Context, from, to time.Time, limit int) ([]models.BookCheckouts, error) {
+	books := []models.BookCheckouts{}
+	err := r.db.SelectContext(ctx, &books, `
+		SELECT b.id AS book_id, b.isbn, b.title, b.author, w.checkouts
+		FROM (
+			SELECT book_id, COUNT(*) AS checkouts FROM (`+windowCheckouts+`) c
+			GROUP BY book_id
+		) w
+		JOIN books b ON b.id = w.book_id
+		ORDER BY w.checkouts DESC, lower(b.title) ASC, b.id ASC
+		LIMIT $3
+	`, from, to, limit)
+	return books, err
+}
+
+func (r *reportRepositoryImpl) TopAuthors(ctx context.Context, from, to time.Time, limit int) ([]models.AuthorCheckouts, error) {
+
+	// Checkouts of the books crediting each author (as author, not as translator etc.)
+	authors := []models.AuthorCheckouts{}
+	err := r.db.SelectContext(ctx, &authors, `
+		SELECT a.id AS author_id, a.name, COUNT(*) AS checkouts
+		FROM (`+windowCheckouts+`) c
+		JOIN book_contributors bc ON bc.book_id = c.book_id AND bc.role = 'author'
+		JOIN authors a ON a.id = bc.author_id
+		GROUP BY a.id, a.name
+		ORDER BY checkouts DESC, lower(a.name) ASC, a.id ASC
+		LIMIT $3
+	`, from, to, limit)
+	return authors, err
+}
+
+func (r *reportRepositoryImpl) CheckoutSeries(ctx context.Context, from, to time.Time, interval models.ReportInterval) ([]models.CheckoutsPoint, error) {
+
+	// Every interval of the window (UTC), with the checkouts started in it
+	points := []models.CheckoutsPoint{}
+	err := r.db.SelectContext(ctx, &points, `
+		SELECT p.period, COUNT(c.book_id) AS checkouts
+		FROM generate_series(
+			date_trunc($3, $1::timestamptz, 'UTC'),
+			$2::timestamptz - interval '1 microsecond',
+			('1 ' || $3)::interval
+		) AS p(period)
+		LEFT JOIN (`+windowCheckouts+`) c ON date_trunc($3, c.timestamp, 'UTC') = p.period
+		GROUP BY p.period
+		ORDER BY p.period ASC
+	`, from, to, string(interval))
+	return points, err
+}
+
+func (r *reportRepositoryImpl) LoanDuration(ctx context.Context, from, to time.Time) (*models.LoanDuration, error) {
+
+	// A loan is a checkout followed by a checkin of the same book (loans still open are not counted)
+	var duration models.LoanDuration
+	err := r.db.GetContext(ctx, &duration, `
+		SELECT
+			COUNT(*) AS loans,
+			COALESCE(AVG(days), 0) AS average_days,
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY days), 0) AS median_days
+		FROM (
+			SELECT EXTRACT(EPOCH FROM l.returned_at - l.timestamp) / 86400 AS days
+			FROM (
+				SELECT c.book_id, c.status, c.timestamp,
+					LEAD(c.status) OVER (PARTITION BY c.book_id ORDER BY c.timestamp, c.id) AS next_status,
+					LEAD(c.timestamp) OVER (PARTITION BY c.book_id ORDER BY c.timestamp, c.id) AS returned_at
+				FROM book_status_changes c
+			) l
+			JOIN books b ON b.id = l.book_id AND b.deleted = false
+			WHERE l.status = 'checked_out' AND l.next_status = 'available'
+				AND l.returned_at >= $1 AND l.returned_at < $2
+		) loans
+	`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &duration, nil
+}
+
+func (r *reportRepositoryImpl) CountCheckedOut(ctx context.Context) (*models.CheckedOutCount, error) {
+	var count models.CheckedOutCount
+	err := r.db.GetContext(ctx, &count, `
+		SELECT COUNT(*) AS books, COUNT(*) FILTER (WHERE status = 'checked_out') AS checked_out
+		FROM books
+		WHERE deleted = false AND status <> 'withdrawn'
+	`)
+	if err != nil {
+		return nil, err
+	}
+	return &count, nil
+}
+
+func (r *reportRepositoryImpl) NeverBorrowed(ctx context.Context, limit int) ([]models.NeverBorrowedBook, int, error) {
+
+	// Books without any checkout, oldest first (longest on the shelf)
+	where := `
+		WHERE b.deleted = false AND b.status <> 'withdrawn' AND NOT EXISTS (
+			SELECT 1 FROM book_status_changes c WHERE c.book_id = b.id AND c.status = 'checked_out'
+		)`
+
+	// Execute count query
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM books b`+where); err != nil {
+		return nil, 0, err
+	}
+
+	// Execute query
+	books := []models.NeverBorrowedBook{}
+	err := r.db.SelectContext(ctx, &books, `
+		SELECT b.id AS book_id, b.isbn, b.title, b.author, b.created_at
+		FROM books b`+where+`
+		ORDER BY b.created_at ASC, b.id ASC
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return books, total, nil
+}
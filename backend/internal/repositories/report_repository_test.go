@@ -0,0 +1,107 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	reportFrom = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	reportTo   = time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func TestReportTopBooks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewReportRepository(sqlxDB)
+
+	mock.ExpectQuery(`(?s)SELECT book_id, COUNT\(\*\) AS checkouts FROM .*c\.status = 'checked_out' AND c\.timestamp >= \$1 AND c\.timestamp < \$2.*ORDER BY w\.checkouts DESC.*LIMIT \$3`).
+		WithArgs(reportFrom, reportTo, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "isbn", "title", "author", "checkouts"}).
+			AddRow("b1", "9780261103344", "The Hobbit", "J.R.R. Tolkien", 12).
+			AddRow("b2", "9780306406157", "Dune", "Frank Herbert", 7))
+
+	books, err := repo.TopBooks(context.Background(), reportFrom, reportTo, 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.BookCheckouts{
+		{BookID: "b1", ISBN: "9780261103344", Title: "The Hobbit", Author: "J.R.R. Tolkien", Checkouts: 12},
+		{BookID: "b2", ISBN: "9780306406157", Title: "Dune", Author: "Frank Herbert", Checkouts: 7},
+	}, books)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReportCheckoutSeries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewReportRepository(sqlxDB)
+
+	mock.ExpectQuery(`(?s)FROM generate_series\(\s+date_trunc\(\$3, \$1::timestamptz, 'UTC'\).*LEFT JOIN .*GROUP BY p\.period`).
+		WithArgs(reportFrom, reportTo, "week").
+		WillReturnRows(sqlmock.NewRows([]string{"period", "checkouts"}).
+			AddRow(time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), 3).
+			AddRow(time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), 0))
+
+	points, err := repo.CheckoutSeries(context.Background(), reportFrom, reportTo, models.ReportIntervalWeek)
+
+	assert.NoError(t, err)
+	assert.Len(t, points, 2)
+	assert.Equal(t, 3, points[0].Checkouts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReportLoanDuration(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewReportRepository(sqlxDB)
+
+	mock.ExpectQuery(`(?s)LEAD\(c\.status\) OVER \(PARTITION BY c\.book_id ORDER BY c\.timestamp, c\.id\).*l\.status = 'checked_out' AND l\.next_status = 'available'`).
+		WithArgs(reportFrom, reportTo).
+		WillReturnRows(sqlmock.NewRows([]string{"loans", "average_days", "median_days"}).AddRow(4, 12.5, 10.0))
+
+	duration, err := repo.LoanDuration(context.Background(), reportFrom, reportTo)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &models.LoanDuration{Loans: 4, AverageDays: 12.5, MedianDays: 10}, duration)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReportNeverBorrowed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewReportRepository(sqlxDB)
+	createdAt := time.Now()
+
+	mock.ExpectQuery(`(?s)SELECT COUNT\(\*\) FROM books b\s+WHERE b\.deleted = false AND b\.status <> 'withdrawn' AND NOT EXISTS`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+	mock.ExpectQuery(`(?s)NOT EXISTS .*ORDER BY b\.created_at ASC, b\.id ASC\s+LIMIT \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "isbn", "title", "author", "created_at"}).
+			AddRow("b1", "9780261103344", "The Hobbit", "J.R.R. Tolkien", createdAt))
+
+	books, total, err := repo.NeverBorrowed(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 42, total)
+	assert.Equal(t, "b1", books[0].BookID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+)
+
+// SeedRepository bulk-loads synthetic data with COPY (all operations run in an external TX)
+type SeedRepository interface {
+	UpsertAuthors(ctx context.Context, tx *sqlx.Tx, authors []models.Author) (map[string]string /* generated ID -> stored ID */, error)
+	UpsertTags(ctx context.Context, tx *sqlx.Tx, tags []models.Tag) (map[string]string /* generated ID -> stored ID */, error)
+	CopyBooks(ctx context.Context, tx *sqlx.Tx, books []models.Book) error
+	CopyBookContributors(ctx context.Context, tx *sqlx.Tx, books []models.Book) error                   // Contributors with stored author IDs
+	CopyBookTags(ctx context.Context, tx *sqlx.Tx, books []models.Book, tagIDs map[string]string) error // Tag name -> stored ID
+	CopyStatusChanges(ctx context.Context, tx *sqlx.Tx, history []models.BookStatusChange) error
+}
+
+type seedRepositoryImpl struct{}
+
+func NewSeedRepository() SeedRepository {
+	return &seedRepositoryImpl{}
+}
+
+// External TX
+func (r *seedRepositoryImpl) UpsertAuthors(ctx context.Context, tx *sqlx.Tx, authors []models.Author) (map[string]string, error) {
+	return upsertByName(ctx, tx, "authors", len(authors), func(i int) []interface{} {
+		return []interface{}{authors[i].ID, authors[i].Name, authors[i].CreatedAt, authors[i].UpdatedAt}
+	})
+}
+
+// External TX
+func (r *seedRepositoryImpl) UpsertTags(ctx context.Context, tx *sqlx.Tx, tags []models.Tag) (map[string]string, error) {
+	return upsertByName(ctx, tx, "tags", len(tags), func(i int) []interface{} {
+		return []interface{}{tags[i].ID, tags[i].Name, tags[i].CreatedAt, tags[i].UpdatedAt}
+	})
+}
+
+// External TX
+func (r *seedRepositoryImpl) CopyBooks(ctx context.Context, tx *sqlx.Tx, books []models.Book) error {
+	columns := []string{"id", "isbn", "title", "author", "description", "status", "created_at", "updated_at",
+		"publisher", "publication_year", "edition", "language", "page_count", "format"}
+	err := copyIn(ctx, tx, "books", columns, len(books), func(i int) []interface{} {
+		b := &books[i]
+		return []interface{}{b.ID, b.ISBN, b.Title, b.Author, b.Description, string(b.Status), b.CreatedAt, b.UpdatedAt,
+			b.Publisher, b.PublicationYear, b.Edition, b.Language, b.PageCount, string(b.Format)}
+	})
+
+	// Check for already loaded books (same seed)
+	if utils.IsUniqueViolation(err) {
+		return utils.ErrConflict
+	}
+	return err
+}
+
+// External TX
+func (r *seedRepositoryImpl) CopyBookContributors(ctx context.Context, tx *sqlx.Tx, books []models.Book) error {
+	type credit struct {
+		bookID   string
+		position int
+		models.Contributor
+	}
+	var credits []credit
+	for _, book := range books {
+		for position, contributor := range book.Contributors {
+			credits = append(credits, credit{book.ID, position, contributor})
+		}
+	}
+	return copyIn(ctx, tx, "book_contributors", []string{"book_id", "author_id", "role", "position"}, len(credits), func(i int) []interface{} {
+		return []interface{}{credits[i].bookID, credits[i].AuthorID, string(credits[i].Role), credits[i].position}
+	})
+}
+
+// External TX
+func (r *seedRepositoryImpl) CopyBookTags(ctx context.Context, tx *sqlx.Tx, books []models.Book, tagIDs map[string]string) error {
+	var pairs [][2]string
+	for _, book := range books {
+		for _, tag := range book.Tags {
+			pairs = append(pairs, [2]string{book.ID, tagIDs[tag]})
+		}
+	}
+	return copyIn(ctx, tx, "book_tags", []string{"book_id", "tag_id"}, len(pairs), func(i int) []interface{} {
+		return []interface{}{pairs[i][0], pairs[i][1]}
+	})
+}
+
+// External TX
+func (r *seedRepositoryImpl) CopyStatusChanges(ctx context.Context, tx *sqlx.Tx, history []models.BookStatusChange) error {
+	return copyIn(ctx, tx, "book_status_changes", []string{"book_id", "status", "timestamp"}, len(history), func(i int) []interface{} {
+		return []interface{}{history[i].BookID, string(history[i].Status), history[i].Timestamp}
+	})
+}
+
+/* Helper functions */
+
+// copyIn loads rows into a table with COPY FROM STDIN (rows are streamed, constraint errors surface on flush)
+func copyIn(ctx context.Context, tx *sqlx.Tx, table string, columns []string, n int, row func(i int) []interface{}) error {
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(table, columns...))
+	if err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		if _, err := stmt.ExecContext(ctx, row(i)...); err != nil {
+			stmt.Close()
+			return err
+		}
+	}
+
+	// Flush
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		stmt.Close()
+		return err
+	}
+	return stmt.Close()
+}
+
+// upsertByName loads named rows (authors or tags, unique by lower(name)) through a staging table:
+// new names are inserted, existing names keep their row. Returns the stored ID of each loaded ID.
+func upsertByName(ctx context.Context, tx *sqlx.Tx, table string, n int, row func(i int) []interface{}) (map[string]string, error) {
+	staging := "seed_" + table
+
+	// Stage rows (dropped on commit)
+	if _, err := tx.ExecContext(ctx, `CREATE TEMP TABLE `+staging+` (LIKE `+table+`) ON COMMIT DROP`); err != nil {
+		return nil, err
+	}
+	if err := copyIn(ctx, tx, staging, []string{"id", "name", "created_at", "updated_at"}, n, row); err != nil {
+		return nil, err
+	}
+
+	// Insert new names
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO `+table+` (id, name, created_at, updated_at)
+		SELECT id, name, created_at, updated_at FROM `+staging+`
+		ON CONFLICT ((lower(name))) DO NOTHING
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map loaded IDs to stored IDs
+	var pairs []struct {
+		SeedID string `db:"seed_id"`
+		ID     string `db:"id"`
+	}
+	err = sqlx.SelectContext(ctx, tx, &pairs, `
+		SELECT s.id AS seed_id, t.id FROM `+staging+` s
+		JOIN `+table+` t ON lower(t.name) = lower(s.name)
+	`)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		ids[pair.SeedID] = pair.ID
+	}
+	return ids, nil
+}
@@ -0,0 +1,89 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/repositories"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyStatusChanges(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewSeedRepository()
+	now := time.Now()
+
+	// One COPY statement: a call per row, then the flush
+	mock.ExpectBegin()
+	copyStmt := mock.ExpectPrepare(`^COPY "book_status_changes" \("book_id", "status", "timestamp"\) FROM STDIN$`)
+	copyStmt.ExpectExec().WithArgs("b1", "checked_out", now).WillReturnResult(sqlmock.NewResult(0, 0))
+	copyStmt.ExpectExec().WithArgs("b1", "available", now.Add(time.Hour)).WillReturnResult(sqlmock.NewResult(0, 0))
+	copyStmt.ExpectExec().WithoutArgs().WillReturnResult(sqlmock.NewResult(0, 2))
+
+	tx := sqlxDB.MustBegin()
+	err = repo.CopyStatusChanges(context.Background(), tx, []models.BookStatusChange{
+		{BookID: "b1", Status: models.BookStatusCheckedOut, Timestamp: now},
+		{BookID: "b1", Status: models.BookStatusAvailable, Timestamp: now.Add(time.Hour)},
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCopyBooks_AlreadyLoaded(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewSeedRepository()
+
+	mock.ExpectBegin()
+	copyStmt := mock.ExpectPrepare(`^COPY "books" \("id", "isbn", "title", "author", "description", "status", "created_at", "updated_at", "publisher", "publication_year", "edition", "language", "page_count", "format"\) FROM STDIN$`)
+	copyStmt.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 0))
+	copyStmt.ExpectExec().WithoutArgs().WillReturnError(&pq.Error{Code: "23505"}) // unique_violation
+
+	tx := sqlxDB.MustBegin()
+	err = repo.CopyBooks(context.Background(), tx, []models.Book{{ID: "b1", Status: models.BookStatusAvailable}})
+
+	assert.ErrorIs(t, err, utils.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertAuthors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewSeedRepository()
+	now := time.Now()
+
+	// Staged, inserted when new, mapped to the stored rows by name
+	mock.ExpectBegin()
+	mock.ExpectExec(`^CREATE TEMP TABLE seed_authors \(LIKE authors\) ON COMMIT DROP$`).WillReturnResult(sqlmock.NewResult(0, 0))
+	copyStmt := mock.ExpectPrepare(`^COPY "seed_authors" \("id", "name", "created_at", "updated_at"\) FROM STDIN$`)
+	copyStmt.ExpectExec().WithArgs("a1", "Ana Silva", now, now).WillReturnResult(sqlmock.NewResult(0, 0))
+	copyStmt.ExpectExec().WithoutArgs().WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`(?i)^INSERT INTO authors \(id, name, created_at, updated_at\) SELECT id, name, created_at, updated_at FROM seed_authors ON CONFLICT \(\(lower\(name\)\)\) DO NOTHING$`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`(?i)^SELECT s\.id AS seed_id, t\.id FROM seed_authors s JOIN authors t ON lower\(t\.name\) = lower\(s\.name\)$`).
+		WillReturnRows(sqlmock.NewRows([]string{"seed_id", "id"}).AddRow("a1", "existing"))
+
+	tx := sqlxDB.MustBegin()
+	ids, err := repo.UpsertAuthors(context.Background(), tx, []models.Author{{ID: "a1", Name: "Ana Silva", CreatedAt: now, UpdatedAt: now}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"a1": "existing"}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
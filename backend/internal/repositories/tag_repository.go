@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+)
+
+type TagRepository interface {
+
+	// CRUD operations
+	CreateTag(ctx context.Context, tag *models.Tag) error
+	ListTags(ctx context.Context) ([]models.Tag, error)
+	GetTagByID(ctx context.Context, id string) (*models.Tag, error)
+	UpdateTag(ctx context.Context, tx *sqlx.Tx, tag *models.Tag) error // External TX
+	DeleteTag(ctx context.Context, tx *sqlx.Tx, id string) error       // External TX
+}
+
+// Tag columns with the number of (non-deleted) books assigned
+const tagColumns = `id, name, created_at, updated_at,
+	(SELECT COUNT(*) FROM book_tags bt JOIN books b ON b.id = bt.book_id
+		WHERE bt.tag_id = tags.id AND b.deleted = false) AS book_count`
+
+type tagRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+func NewTagRepository(db *sqlx.DB) TagRepository {
+	return &tagRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *tagRepositoryImpl) CreateTag(ctx context.Context, tag *models.Tag) error {
+
+	// Execute insert
+	_, err := r.db.NamedExecContext(ctx, `
+		INSERT INTO tags (id, name, created_at, updated_at)
+		VALUES (:id, :name, :created_at, :updated_at)
+	`, tag)
+
+	// Check for duplicate name
+	if utils.IsUniqueViolation(err) {
+		return utils.ErrConflict
+	}
+	return err
+}
+
+func (r *tagRepositoryImpl) ListTags(ctx context.Context) ([]models.Tag, error) {
+
+	// Execute query
+	tags := []models.Tag{}
+	err := r.db.SelectContext(ctx, &tags, `
+		SELECT `+tagColumns+` FROM tags
+		ORDER BY lower(name) ASC
+	`)
+	return tags, err
+}
+
+func (r *tagRepositoryImpl) GetTagByID(ctx context.Context, id string) (*models.Tag, error) {
+
+	// Validate UUID format
+	if err := validateUUIDOrNotFound(id); err != nil {
+		return nil, err
+	}
+
+	// Execute query
+	var tag models.Tag
+	err := r.db.GetContext(ctx, &tag, `
+		SELECT `+tagColumns+` FROM tags
+		WHERE id = $1
+	`, id)
+
+	// Check for not found error
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, utils.ErrNotFound
+	}
+	return &tag, err
+}
+
+// External TX
+func (r *tagRepositoryImpl) UpdateTag(ctx context.Context, tx *sqlx.Tx, tag *models.Tag) error {
+
+	// Validate UUID format
+	if err := validateUUIDOrNotFound(tag.ID); err != nil {
+		return err
+	}
+
+	// Execute update
+	res, err := tx.NamedExecContext(ctx, `
+		UPDATE tags SET
+			name = :name,
+			updated_at = :updated_at
+		WHERE id = :id
+	`, tag)
+
+	// Check for duplicate name
+	if utils.IsUniqueViolation(err) {
+		return utils.ErrConflict
+	}
+	if err != nil {
+		return err
+	}
+
+	// Check for not found error
+	return utils.CheckRowsAffected(res)
+}
+
+// External TX
+func (r *tagRepositoryImpl) DeleteTag(ctx context.Context, tx *sqlx.Tx, id string) error {
+
+	// Validate UUID format
+	if err := validateUUIDOrNotFound(id); err != nil {
+		return err
+	}
+
+	// Execute delete (assignments are removed by cascade)
+	res, err := tx.ExecContext(ctx, `DELETE FROM tags WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	// Check for not found error
+	return utils.CheckRowsAffected(res)
+}
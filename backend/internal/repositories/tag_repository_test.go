@@ -0,0 +1,107 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/repositories"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTag_DuplicateName(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewTagRepository(sqlxDB)
+
+	ctx := context.Background()
+	now := time.Now()
+	tag := &models.Tag{ID: "8a1c8f7e-3f0b-4d5e-9b1a-2c3d4e5f6a7b", Name: "Fantasy", CreatedAt: now, UpdatedAt: now}
+
+	mock.ExpectExec(`(?i)^INSERT INTO tags`).
+		WithArgs(tag.ID, tag.Name, tag.CreatedAt, tag.UpdatedAt).
+		WillReturnError(&pq.Error{Code: "23505"}) // unique_violation
+
+	err = repo.CreateTag(ctx, tag)
+
+	assert.ErrorIs(t, err, utils.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListTags(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewTagRepository(sqlxDB)
+
+	ctx := context.Background()
+
+	mock.ExpectQuery(`(?i)^SELECT id, name, created_at, updated_at, \(SELECT COUNT\(\*\) .+\) AS book_count FROM tags ORDER BY lower\(name\) ASC$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "book_count"}).
+			AddRow("1", "Classics", 3).
+			AddRow("2", "Fantasy", 5))
+
+	tags, err := repo.ListTags(ctx)
+
+	assert.NoError(t, err)
+	if assert.Len(t, tags, 2) {
+		assert.Equal(t, "Classics", tags[0].Name)
+		assert.Equal(t, 5, tags[1].BookCount)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTagByID_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewTagRepository(sqlxDB)
+
+	ctx := context.Background()
+	tagID := "8a1c8f7e-3f0b-4d5e-9b1a-2c3d4e5f6a7b"
+
+	mock.ExpectQuery(`(?i)^SELECT .+ FROM tags WHERE id = \$1$`).
+		WithArgs(tagID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"})) // No rows
+
+	tag, err := repo.GetTagByID(ctx, tagID)
+
+	assert.Nil(t, tag)
+	assert.ErrorIs(t, err, utils.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteTag_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewTagRepository(sqlxDB)
+
+	ctx := context.Background()
+	tagID := "8a1c8f7e-3f0b-4d5e-9b1a-2c3d4e5f6a7b"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`(?i)^DELETE FROM tags WHERE id = \$1$`).
+		WithArgs(tagID).
+		WillReturnResult(sqlmock.NewResult(0, 0)) // 0 rows affected
+
+	tx := sqlxDB.MustBegin()
+	err = repo.DeleteTag(ctx, tx, tagID)
+
+	assert.ErrorIs(t, err, utils.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
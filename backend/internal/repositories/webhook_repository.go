@@ -0,0 +1,280 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+)
+
+type WebhookRepository interface {
+
+	// CRUD operations
+	CreateWebhook(ctx context.Context, webhook *models.Webhook) error
+	ListWebhooks(ctx context.Context) ([]models.Webhook, error)
+	GetWebhookByID(ctx context.Context, id string) (*models.Webhook, error)
+	UpdateWebhook(ctx context.Context, webhook *models.Webhook) error
+	DeleteWebhook(ctx context.Context, id string) error
+
+	// Subscriptions
+	ListWebhooksForEvent(ctx context.Context, eventType models.BookEventType) ([]models.Webhook, error)
+
+	// Deliveries
+	CreateDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error
+	ListEventWebhookIDs(ctx context.Context, eventID string) ([]string, error) // Webhooks with a delivery of the event
+	ListDeliveries(ctx context.Context, webhookID string, req models.ListWebhookDeliveriesRequest) ([]models.WebhookDelivery, int /* total */, error)
+	GetDelivery(ctx context.Context, webhookID string, id string) (*models.WebhookDelivery, error)
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error)
+	UpdateDeliveryAttempt(ctx context.Context, delivery *models.WebhookDelivery) error
+}
+
+const webhookColumns = `id, url, secret, events, active, created_at, updated_at`
+
+const webhookDeliveryColumns = `id, webhook_id, event_id, event_type, payload, status, attempts,
+	next_attempt_at, last_attempt_at, response_status, last_error, created_at, updated_at`
+
+type webhookRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+func NewWebhookRepository(db *sqlx.DB) WebhookRepository {
+	return &webhookRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *webhookRepositoryImpl) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+
+	// Execute insert
+	_, err := r.db.NamedExecContext(ctx, `
+		INSERT INTO webhooks (id, url, secret, events, active, created_at, updated_at)
+		VALUES (:id, :url, :secret, :events, :active, :created_at, :updated_at)
+	`, webhook)
+	return err
+}
+
+func (r *webhookRepositoryImpl) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+
+	// Execute query
+	webhooks := []models.Webhook{}
+	err := r.db.SelectContext(ctx, &webhooks, `
+		SELECT `+webhookColumns+` FROM webhooks
+		ORDER BY created_at ASC, id ASC
+	`)
+	return webhooks, err
+}
+
+func (r *webhookRepositoryImpl) GetWebhookByID(ctx context.Context, id string) (*models.Webhook, error) {
+
+	// Validate UUID format
+	if err := validateUUIDOrNotFound(id); err != nil {
+		return nil, err
+	}
+
+	// Execute query
+	var webhook models.Webhook
+	err := r.db.GetContext(ctx, &webhook, `
+		SELECT `+webhookColumns+` FROM webhooks
+		WHERE id = $1
+	`, id)
+
+	// Check for not found error
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, utils.ErrNotFound
+	}
+	return &webhook, err
+}
+
+func (r *webhookRepositoryImpl) UpdateWebhook(ctx context.Context, webhook *models.Webhook) error {
+
+	// Validate UUID format
+	if err := validateUUIDOrNotFound(webhook.ID); err != nil {
+		return err
+	}
+
+	// Execute update
+	res, err := r.db.NamedExecContext(ctx, `
+		UPDATE webhooks SET
+			url = :url,
+			secret = :secret,
+			events = :events,
+			active = :active,
+			updated_at = :updated_at
+		WHERE id = :id
+	`, webhook)
+	if err != nil {
+		return err
+	}
+
+	// Check for not found error
+	return utils.CheckRowsAffected(res)
+}
+
+func (r *webhookRepositoryImpl) DeleteWebhook(ctx context.Context, id string) error {
+
+	// Validate UUID format
+	if err := validateUUIDOrNotFound(id); err != nil {
+		return err
+	}
+
+	// Execute delete (deliveries are removed by cascade)
+	res, err := r.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	// Check for not found error
+	return utils.CheckRowsAffected(res)
+}
+
+func (r *webhookRepositoryImpl) ListWebhooksForEvent(ctx context.Context, eventType models.BookEventType) ([]models.Webhook, error) {
+
+	// Execute query (active webhooks subscribed to the event)
+	webhooks := []models.Webhook{}
+	err := r.db.SelectContext(ctx, &webhooks, `
+		SELECT `+webhookColumns+` FROM webhooks
+		WHERE active = true AND events @> ARRAY[$1]::TEXT[]
+		ORDER BY created_at ASC, id ASC
+	`, string(eventType))
+	return webhooks, err
+}
+
+func (r *webhookRepositoryImpl) CreateDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+
+	// Nothing to insert
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	// Execute batch insert
+	_, err := r.db.NamedExecContext(ctx, `
+		INSERT INTO webhook_deliveries (id, webhook_id, event_id, event_type, payload, status, attempts,
+			next_attempt_at, last_attempt_at, response_status, last_error, created_at, updated_at)
+		VALUES (:id, :webhook_id, :event_id, :event_type, :payload, :status, :attempts,
+			:next_attempt_at, :last_attempt_at, :response_status, :last_error, :created_at, :updated_at)
+	`, deliveries)
+	return err
+}
+
+func (r *webhookRepositoryImpl) ListEventWebhookIDs(ctx context.Context, eventID string) ([]string, error) {
+
+	// Execute query
+	ids := []string{}
+	err := r.db.SelectContext(ctx, &ids, `
+		SELECT DISTINCT webhook_id FROM webhook_deliveries WHERE event_id = $1
+	`, eventID)
+	return ids, err
+}
+
+func (r *webhookRepositoryImpl) ListDeliveries(ctx context.Context, webhookID string, req models.ListWebhookDeliveriesRequest) ([]models.WebhookDelivery, int, error) {
+
+	// Validate UUID format
+	if err := validateUUIDOrNotFound(webhookID); err != nil {
+		return nil, 0, err
+	}
+
+	// Build WHERE clause
+	where := "WHERE webhook_id = ?"
+	args := []interface{}{webhookID}
+	if req.Status != "" {
+		where += " AND status = ?"
+		args = append(args, req.Status)
+	}
+
+	// Execute count query (for pagination)
+	var total int
+	query := r.db.Rebind(fmt.Sprintf(`SELECT COUNT(*) FROM webhook_deliveries %s`, where))
+	if err := r.db.GetContext(ctx, &total, query, args...); err != nil {
+		return nil, 0, err
+	}
+
+	// Execute query (newest first)
+	deliveries := []models.WebhookDelivery{}
+	query = r.db.Rebind(fmt.Sprintf(`
+		SELECT %s FROM webhook_deliveries
+		%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT %d OFFSET %d
+	`, webhookDeliveryColumns, where, req.PageSize, (req.Page-1)*req.PageSize))
+	if err := r.db.SelectContext(ctx, &deliveries, query, args...); err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
+
+func (r *webhookRepositoryImpl) GetDelivery(ctx context.Context, webhookID string, id string) (*models.WebhookDelivery, error) {
+
+	// Validate UUID format
+	if err := validateUUIDOrNotFound(webhookID); err != nil {
+		return nil, err
+	}
+	if err := validateUUIDOrNotFound(id); err != nil {
+		return nil, err
+	}
+
+	// Execute query (with webhook target)
+	var delivery models.WebhookDelivery
+	err := r.db.GetContext(ctx, &delivery, `
+		SELECT d.*, w.url, w.secret FROM webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.id = $1 AND d.webhook_id = $2
+	`, id, webhookID)
+
+	// Check for not found error
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, utils.ErrNotFound
+	}
+	return &delivery, err
+}
+
+func (r *webhookRepositoryImpl) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+
+	// Execute claim: due deliveries are leased by pushing next_attempt_at forward, so concurrent
+	// dispatchers (other instances) skip them; an attempt that never reports back is retried after the lease
+	deliveries := []models.WebhookDelivery{}
+	err := r.db.SelectContext(ctx, &deliveries, `
+		WITH due AS (
+			UPDATE webhook_deliveries SET next_attempt_at = $2
+			WHERE id IN (
+				SELECT id FROM webhook_deliveries
+				WHERE status = 'pending' AND next_attempt_at <= $1
+				ORDER BY next_attempt_at ASC
+				LIMIT $3
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *
+		)
+		SELECT due.*, w.url, w.secret FROM due
+		JOIN webhooks w ON w.id = due.webhook_id
+		ORDER BY due.created_at ASC
+	`, now, now.Add(lease), limit)
+	return deliveries, err
+}
+
+func (r *webhookRepositoryImpl) UpdateDeliveryAttempt(ctx context.Context, delivery *models.WebhookDelivery) error {
+
+	// Execute update
+	res, err := r.db.NamedExecContext(ctx, `
+		UPDATE webhook_deliveries SET
+			status = :status,
+			attempts = :attempts,
+			next_attempt_at = :next_attempt_at,
+			last_attempt_at = :last_attempt_at,
+			response_status = :response_status,
+			last_error = :last_error,
+			updated_at = :updated_at
+		WHERE id = :id
+	`, delivery)
+	if err != nil {
+		return err
+	}
+
+	// Check for not found error
+	return utils.CheckRowsAffected(res)
+}
@@ -0,0 +1,112 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/repositories"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListWebhooksForEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewWebhookRepository(sqlxDB)
+
+	ctx := context.Background()
+
+	mock.ExpectQuery(`(?i)^SELECT .+ FROM webhooks WHERE active = true AND events @> ARRAY\[\$1\]::TEXT\[\]`).
+		WithArgs("book.created").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url", "events", "active"}).
+			AddRow("webhook-1", "https://example.com/hooks", "{book.created,book.deleted}", true))
+
+	webhooks, err := repo.ListWebhooksForEvent(ctx, models.BookEventCreated)
+
+	assert.NoError(t, err)
+	if assert.Len(t, webhooks, 1) {
+		assert.Equal(t, []string{"book.created", "book.deleted"}, []string(webhooks[0].Events))
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateDeliveries_BatchInsert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewWebhookRepository(sqlxDB)
+
+	ctx := context.Background()
+	now := time.Now()
+	deliveries := []models.WebhookDelivery{
+		{ID: "delivery-1", WebhookID: "webhook-1", EventID: "event-1", Status: models.WebhookDeliveryPending, NextAttemptAt: &now},
+		{ID: "delivery-2", WebhookID: "webhook-2", EventID: "event-1", Status: models.WebhookDeliveryPending, NextAttemptAt: &now},
+	}
+
+	// One statement with a VALUES row per delivery
+	mock.ExpectExec(`(?i)^INSERT INTO webhook_deliveries .+ VALUES \(.+\),\(.+\)$`).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err = repo.CreateDeliveries(ctx, deliveries)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClaimDueDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewWebhookRepository(sqlxDB)
+
+	ctx := context.Background()
+	now := time.Now()
+
+	mock.ExpectQuery(`(?i)^WITH due AS \( UPDATE webhook_deliveries SET next_attempt_at = \$2 .+ FOR UPDATE SKIP LOCKED \) RETURNING \* \) SELECT due\.\*, w\.url, w\.secret FROM due JOIN webhooks w`).
+		WithArgs(now, now.Add(time.Minute), 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "webhook_id", "status", "url", "secret"}).
+			AddRow("delivery-1", "webhook-1", "pending", "https://example.com/hooks", "secret"))
+
+	deliveries, err := repo.ClaimDueDeliveries(ctx, now, time.Minute, 20)
+
+	assert.NoError(t, err)
+	if assert.Len(t, deliveries, 1) {
+		assert.Equal(t, "https://example.com/hooks", deliveries[0].URL)
+		assert.Equal(t, "secret", deliveries[0].Secret)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDelivery_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := repositories.NewWebhookRepository(sqlxDB)
+
+	ctx := context.Background()
+	webhookID := "8a1c8f7e-3f0b-4d5e-9b1a-2c3d4e5f6a7b"
+	deliveryID := "5d0f4b1e-7c2a-4e8b-9f3d-1a2b3c4d5e6f"
+
+	mock.ExpectQuery(`(?i)^SELECT d\.\*, w\.url, w\.secret FROM webhook_deliveries d .+ WHERE d\.id = \$1 AND d\.webhook_id = \$2$`).
+		WithArgs(deliveryID, webhookID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"})) // No rows
+
+	delivery, err := repo.GetDelivery(ctx, webhookID, deliveryID)
+
+	assert.Nil(t, delivery)
+	assert.ErrorIs(t, err, utils.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/santiago-buildit/code-challenge/backend/internal/handlers"
+)
+
+func RegisterAuthorRoutes(router *gin.Engine, handler *handlers.AuthorHandler) {
+	group := router.Group("/authors")
+	{
+		group.GET("", handler.ListAuthors)
+		group.GET("/:id/books", handler.ListAuthorBooks)
+	}
+}
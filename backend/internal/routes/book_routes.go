@@ -10,6 +10,7 @@ func RegisterBookRoutes(router *gin.Engine, handler *handlers.BookHandler) {
 	{
 		// CRUD operations
 		group.POST("", handler.CreateBook)
+		group.GET("", handler.ListBooksQuery)  // GET with query-string filters (cacheable, bookmarkable)
 		group.POST("/list", handler.ListBooks) // POST to support pagination and filtering
 		group.GET("/:id", handler.GetBook)
 		group.PUT("/:id", handler.UpdateBook)
@@ -18,8 +19,13 @@ func RegisterBookRoutes(router *gin.Engine, handler *handlers.BookHandler) {
 		// Status operations
 		group.PUT("/:id/checkout", handler.CheckoutBook)
 		group.PUT("/:id/checkin", handler.CheckinBook)
+		group.PUT("/:id/lost", handler.MarkBookLost)
+		group.PUT("/:id/damaged", handler.MarkBookDamaged)
+		group.PUT("/:id/found", handler.MarkBookFound)
+		group.PUT("/:id/status", handler.SetBookStatus) // Any allowed transition
 
 		// History
 		group.GET("/:id/details", handler.GetBookWithHistory)
+		group.GET("/:id/history", handler.ListBookHistory)
 	}
 }
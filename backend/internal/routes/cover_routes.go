@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/santiago-buildit/code-challenge/backend/internal/handlers"
+)
+
+func RegisterCoverRoutes(router *gin.Engine, handler *handlers.CoverHandler) {
+	group := router.Group("/books")
+	{
+		// Cover image (multipart upload, original or thumbnail download)
+		group.PUT("/:id/cover", handler.UploadCover)
+		group.GET("/:id/cover", handler.GetCover)
+	}
+}
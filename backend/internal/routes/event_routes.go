@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/santiago-buildit/code-challenge/backend/internal/handlers"
+)
+
+func RegisterEventRoutes(router *gin.Engine, handler *handlers.EventHandler) {
+	group := router.Group("/events")
+	{
+		// Live book events (Server-Sent Events)
+		group.GET("/stream", handler.StreamEvents)
+	}
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/santiago-buildit/code-challenge/backend/internal/handlers"
+)
+
+func RegisterGraphQLRoutes(router *gin.Engine, handler *handlers.GraphQLHandler) {
+	group := router.Group("/graphql")
+	{
+		// Queries and mutations over books
+		group.POST("", handler.Execute)
+
+		// Schema (SDL)
+		group.GET("/schema", handler.Schema)
+	}
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/santiago-buildit/code-challenge/backend/internal/handlers"
+)
+
+func RegisterReportRoutes(router *gin.Engine, handler *handlers.ReportHandler) {
+	group := router.Group("/reports")
+	{
+		group.GET("/top-books", handler.TopBooks)
+		group.GET("/top-authors", handler.TopAuthors)
+		group.GET("/checkouts", handler.Checkouts)
+		group.GET("/loan-duration", handler.LoanDuration)
+		group.GET("/checked-out", handler.CheckedOut)
+		group.GET("/never-borrowed", handler.NeverBorrowed)
+	}
+}
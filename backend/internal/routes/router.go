@@ -5,6 +5,7 @@ import (
 	"github.com/gin-gonic/gin"
 	_ "github.com/santiago-buildit/code-challenge/backend/docs" // Swagger docs (autogenerated from Makefile)
 	"github.com/santiago-buildit/code-challenge/backend/internal/config"
+	"github.com/santiago-buildit/code-challenge/backend/internal/handlers"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"net/http"
@@ -17,6 +18,9 @@ func SetupRouter() *gin.Engine {
 
 	r := gin.Default()
 
+	// Identify every request (X-Request-ID, also returned in error responses)
+	r.Use(handlers.RequestIDMiddleware())
+
 	// Avoid CloudFront or browser Cache
 	r.Use(NoCacheMiddleware())
 
@@ -34,15 +38,28 @@ func SetupRouter() *gin.Engine {
 		})
 	}
 
-	// Get all dependencies
-	deps := config.InitDependencies()
+	// Get all dependencies (API Lambda: no live event stream, frozen between invocations and cut by API
+	// Gateway; the dispatchers run from the scheduled worker, cmd/worker)
+	deps := config.InitDependencies(config.Options{})
+
+	// Require an API key on every route registered below (opt-in, keys are issued with cmd/libctl)
+	if os.Getenv("API_KEY_AUTH") == "required" {
+		r.Use(deps.APIKeyMiddleware)
+	}
 
 	// Register Routes
 	RegisterBookRoutes(r, deps.BookHandler)
+	RegisterTagRoutes(r, deps.TagHandler)
+	RegisterAuthorRoutes(r, deps.AuthorHandler)
+	RegisterCoverRoutes(r, deps.CoverHandler)
+	RegisterWebhookRoutes(r, deps.WebhookHandler)
+	RegisterEventRoutes(r, deps.EventHandler)
+	RegisterGraphQLRoutes(r, deps.GraphQLHandler)
+	RegisterReportRoutes(r, deps.ReportHandler)
 	// (.. more routes here)
 
 	// Register global 404 handler
-	r.NoRoute(notFoundHandler)
+	r.NoRoute(handlers.RouteNotFound)
 
 	return r
 }
@@ -62,13 +79,8 @@ func CORSMiddleware() gin.HandlerFunc {
 			return strings.HasPrefix(origin, "http://localhost:") || strings.Contains(origin, "cloudfront.net")
 		},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "If-None-Match", "If-Modified-Since", "X-API-Key", "X-Request-ID", "Accept-Language"},
+		ExposeHeaders:    []string{"Content-Length", "Content-Disposition", "ETag", "Last-Modified", "X-Request-ID", "Content-Language"},
 		AllowCredentials: true,
 	})
 }
-
-// Generic 404 handler
-func notFoundHandler(c *gin.Context) {
-	c.JSON(404, gin.H{"error": "Route not found"})
-}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/santiago-buildit/code-challenge/backend/internal/handlers"
+)
+
+func RegisterTagRoutes(router *gin.Engine, handler *handlers.TagHandler) {
+	group := router.Group("/tags")
+	{
+		// CRUD operations
+		group.POST("", handler.CreateTag)
+		group.GET("", handler.ListTags)
+		group.GET("/:id", handler.GetTag)
+		group.PUT("/:id", handler.UpdateTag)
+		group.DELETE("/:id", handler.DeleteTag)
+	}
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/santiago-buildit/code-challenge/backend/internal/handlers"
+)
+
+func RegisterWebhookRoutes(router *gin.Engine, handler *handlers.WebhookHandler) {
+	group := router.Group("/webhooks")
+	{
+		// CRUD operations
+		group.POST("", handler.CreateWebhook)
+		group.GET("", handler.ListWebhooks)
+		group.GET("/:id", handler.GetWebhook)
+		group.PUT("/:id", handler.UpdateWebhook)
+		group.DELETE("/:id", handler.DeleteWebhook)
+
+		// Delivery log
+		group.GET("/:id/deliveries", handler.ListWebhookDeliveries)
+		group.POST("/:id/deliveries/:delivery_id/redeliver", handler.RedeliverWebhook)
+	}
+}
@@ -0,0 +1,267 @@
+// Package seed generates synthetic, reproducible library data (books with contributors, tags and
+// checkout/checkin history) for load and pagination testing.
+package seed
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+)
+
+// MaxBooks bounds a single generated dataset (kept in memory before loading)
+const MaxBooks = 1_000_000
+
+// Loan behavior of the generated history
+const (
+	meanLoanGap     = 30 * 24 * time.Hour // Mean time between loans of a book with average popularity
+	meanLoanLength  = 14 * 24 * time.Hour
+	maxLoanLength   = 90 * 24 * time.Hour
+	neverBorrowedPc = 15 // Percentage of books without loans
+)
+
+// Options of a synthetic dataset. The same options always generate the same dataset (IDs included).
+type Options struct {
+	Books int
+	Seed  int64
+	From  time.Time // History window (books are created and borrowed within it)
+	To    time.Time
+}
+
+// Dataset is a synthetic catalog ready to be loaded
+type Dataset struct {
+	Authors []models.Author
+	Tags    []models.Tag
+	Books   []models.Book             // With contributors (IDs of Authors) and tag names
+	History []models.BookStatusChange // Per book, oldest first
+}
+
+// weighted is a value with its relative weight
+type weighted[T any] struct {
+	value  T
+	weight int
+}
+
+// generator draws every random value (UUIDs included) from a single seeded source
+type generator struct {
+	src   *rand.ChaCha8
+	rng   *rand.Rand
+	isbns map[string]bool
+}
+
+// Generate builds the dataset of the options.
+// Fails (wrapping utils.ErrBadRequest) when the options are invalid.
+func Generate(opts Options) (*Dataset, error) {
+
+	// Validate options
+	if opts.Books < 1 || opts.Books > MaxBooks {
+		return nil, fmt.Errorf("%w: books must be between 1 and %d", utils.ErrBadRequest, MaxBooks)
+	}
+	if !opts.From.Before(opts.To) {
+		return nil, fmt.Errorf("%w: history window start must be before its end", utils.ErrBadRequest)
+	}
+
+	var seed [32]byte
+	binary.LittleEndian.PutUint64(seed[:], uint64(opts.Seed))
+	src := rand.NewChaCha8(seed)
+	g := &generator{src: src, rng: rand.New(src), isbns: map[string]bool{}}
+
+	// Authors (credited with Zipf popularity, so a few are prolific) and tags (genres)
+	data := &Dataset{}
+	for _, name := range g.authorNames(min(max(20, opts.Books/8), len(firstNames)*len(lastNames))) {
+		data.Authors = append(data.Authors, models.Author{ID: g.uuid(), Name: name, CreatedAt: opts.From, UpdatedAt: opts.From})
+	}
+	for _, genre := range genres {
+		data.Tags = append(data.Tags, models.Tag{ID: g.uuid(), Name: genre, CreatedAt: opts.From, UpdatedAt: opts.From})
+	}
+	authorRank := rand.NewZipf(g.rng, 1.2, 1, uint64(len(data.Authors)-1))
+
+	// Books and their history
+	for range opts.Books {
+		book := g.book(opts, data.Authors, authorRank)
+		history := g.history(book.ID, book.CreatedAt, opts.To)
+		if len(history) > 0 {
+			last := history[len(history)-1]
+			book.Status = last.Status
+			book.UpdatedAt = last.Timestamp
+		}
+		data.Books = append(data.Books, book)
+		data.History = append(data.History, history...)
+	}
+
+	return data, nil
+}
+
+// book generates a book created within the window (earlier dates are likelier, so books have longer histories)
+func (g *generator) book(opts Options, authors []models.Author, authorRank *rand.Zipf) models.Book {
+	window := opts.To.Sub(opts.From)
+	createdAt := opts.From.Add(time.Duration(math.Pow(g.rng.Float64(), 2) * float64(window))).Truncate(time.Second)
+	language := pick(g.rng, languages)
+
+	// Contributors: main author, sometimes a co-author, translator (translated editions) or illustrator
+	main := authors[authorRank.Uint64()]
+	contributors := models.Contributors{{AuthorID: main.ID, Name: main.Name, Role: models.ContributorRoleAuthor}}
+	credit := func(role models.ContributorRole) {
+		other := authors[g.rng.IntN(len(authors))]
+		for _, c := range contributors {
+			if c.AuthorID == other.ID {
+				return // Credited once
+			}
+		}
+		contributors = append(contributors, models.Contributor{AuthorID: other.ID, Name: other.Name, Role: role})
+	}
+	if g.rng.IntN(100) < 10 {
+		credit(models.ContributorRoleAuthor)
+	}
+	if language != "en" && g.rng.IntN(100) < 20 {
+		credit(models.ContributorRoleTranslator)
+	}
+	if g.rng.IntN(100) < 5 {
+		credit(models.ContributorRoleIllustrator)
+	}
+
+	// Tags: 1 to 3 distinct genres
+	tags := []string{}
+	for _, i := range g.rng.Perm(len(genres))[:1+g.rng.IntN(3)] {
+		tags = append(tags, genres[i])
+	}
+
+	edition := ""
+	if g.rng.IntN(100) < 10 {
+		edition = []string{"2nd", "3rd", "Revised", "Anniversary"}[g.rng.IntN(4)]
+	}
+
+	return models.Book{
+		ID:          g.uuid(),
+		ISBN:        g.isbn(language),
+		Title:       g.title(),
+		Author:      contributors.AuthorDisplay(),
+		Description: g.description(),
+		Status:      models.BookStatusAvailable,
+		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+
+		Publisher:       publishers[g.rng.IntN(len(publishers))],
+		PublicationYear: max(1900, createdAt.Year()-int(g.rng.ExpFloat64()*15)),
+		Edition:         edition,
+		Language:        language,
+		PageCount:       min(max(48, int(g.rng.NormFloat64()*120+320)), 1500),
+		Format: pick(g.rng, []weighted[models.BookFormat]{
+			{models.BookFormatPaperback, 45}, {models.BookFormatHardcover, 30}, {models.BookFormatEbook, 20}, {models.BookFormatAudiobook, 5},
+		}),
+
+		Tags:         tags,
+		Contributors: contributors,
+	}
+}
+
+// history generates alternating checkouts and checkins from creation to the end of the window.
+// Popularity varies per book (exponential), some books are never borrowed and the last loan may still be open.
+func (g *generator) history(bookID string, createdAt time.Time, to time.Time) []models.BookStatusChange {
+	if g.rng.IntN(100) < neverBorrowedPc {
+		return nil
+	}
+	popularity := g.rng.ExpFloat64() + 0.1
+	gap := func() time.Duration {
+		return time.Hour + time.Duration(g.rng.ExpFloat64()*float64(meanLoanGap)/popularity)
+	}
+
+	var history []models.BookStatusChange
+	for at := createdAt.Add(gap()); at.Before(to); at = at.Add(gap()) {
+		history = append(history, models.BookStatusChange{BookID: bookID, Status: models.BookStatusCheckedOut, Timestamp: at.Truncate(time.Second)})
+
+		loan := min(time.Hour+time.Duration(g.rng.ExpFloat64()*float64(meanLoanLength)), maxLoanLength)
+		if at = at.Add(loan); !at.Before(to) {
+			break // Still checked out
+		}
+		history = append(history, models.BookStatusChange{BookID: bookID, Status: models.BookStatusAvailable, Timestamp: at.Truncate(time.Second)})
+	}
+	return history
+}
+
+// authorNames returns n distinct "first last" names
+func (g *generator) authorNames(n int) []string {
+	names := make([]string, 0, n)
+	for _, i := range g.rng.Perm(len(firstNames) * len(lastNames))[:n] {
+		names = append(names, firstNames[i%len(firstNames)]+" "+lastNames[i/len(firstNames)])
+	}
+	return names
+}
+
+func (g *generator) title() string {
+	word := func(words []string) string { return words[g.rng.IntN(len(words))] }
+	switch g.rng.IntN(6) {
+	case 0:
+		return "The " + word(titleAdjectives) + " " + word(titleNouns)
+	case 1:
+		return "The " + word(titleNouns) + " of " + word(titlePlaces)
+	case 2:
+		return word(titleAbstracts) + " and " + word(titleAbstracts)
+	case 3:
+		return "A " + word(titleNouns) + " in " + word(titlePlaces)
+	case 4:
+		return "The " + word(titleNouns) + "'s " + word(titleAbstracts)
+	default:
+		return word(titleAdjectives) + " " + word(titleAbstracts)
+	}
+}
+
+func (g *generator) description() string {
+	return descriptionOpenings[g.rng.IntN(len(descriptionOpenings))] + " " +
+		descriptionSubjects[g.rng.IntN(len(descriptionSubjects))] + "."
+}
+
+// isbn returns a unique ISBN-13 (978 prefix, registration group of the language, valid check digit)
+func (g *generator) isbn(language string) string {
+	for {
+		digits := "978" + isbnGroups[language]
+		for len(digits) < 12 {
+			digits += strconv.Itoa(g.rng.IntN(10))
+		}
+		isbn := digits + strconv.Itoa(ISBN13CheckDigit(digits))
+		if !g.isbns[isbn] {
+			g.isbns[isbn] = true
+			return isbn
+		}
+	}
+}
+
+// uuid returns a version 4 UUID drawn from the seeded source
+func (g *generator) uuid() string {
+	return uuid.Must(uuid.NewRandomFromReader(g.src)).String()
+}
+
+// ISBN13CheckDigit computes the check digit of the first 12 digits of an ISBN-13 (weights 1 and 3)
+func ISBN13CheckDigit(digits string) int {
+	sum := 0
+	for i, d := range digits[:12] {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(d-'0') * weight
+	}
+	return (10 - sum%10) % 10
+}
+
+// pick returns a value with probability proportional to its weight
+func pick[T any](rng *rand.Rand, values []weighted[T]) T {
+	total := 0
+	for _, v := range values {
+		total += v.weight
+	}
+	n := rng.IntN(total)
+	for _, v := range values {
+		if n < v.weight {
+			return v.value
+		}
+		n -= v.weight
+	}
+	return values[len(values)-1].value
+}
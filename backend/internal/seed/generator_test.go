@@ -0,0 +1,109 @@
+package seed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var testWindow = Options{
+	From: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	To:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+}
+
+func generate(t *testing.T, books int, seed int64) *Dataset {
+	opts := testWindow
+	opts.Books, opts.Seed = books, seed
+	data, err := Generate(opts)
+	assert.NoError(t, err)
+	return data
+}
+
+func TestGenerate_Reproducible(t *testing.T) {
+	a := generate(t, 200, 42)
+	b := generate(t, 200, 42)
+	c := generate(t, 200, 43)
+
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a.Books[0].ID, c.Books[0].ID)
+	assert.Len(t, a.Books, 200)
+}
+
+func TestGenerate_ValidISBNs(t *testing.T) {
+	data := generate(t, 500, 1)
+
+	seen := map[string]bool{}
+	for _, book := range data.Books {
+		assert.Len(t, book.ISBN, 13)
+		assert.Equal(t, "978", book.ISBN[:3])
+		assert.Equal(t, int(book.ISBN[12]-'0'), ISBN13CheckDigit(book.ISBN), book.ISBN)
+		assert.False(t, seen[book.ISBN], "duplicate ISBN %s", book.ISBN)
+		seen[book.ISBN] = true
+	}
+
+	// Known ISBNs
+	assert.Equal(t, 4, ISBN13CheckDigit("978026110334"))
+	assert.Equal(t, 7, ISBN13CheckDigit("978030640615"))
+}
+
+func TestGenerate_PlausibleBooksAndHistory(t *testing.T) {
+	data := generate(t, 500, 7)
+
+	authors := map[string]string{}
+	for _, author := range data.Authors {
+		authors[author.ID] = author.Name
+	}
+	history := map[string][]models.BookStatusChange{}
+	for _, change := range data.History {
+		history[change.BookID] = append(history[change.BookID], change)
+	}
+
+	borrowed, checkedOut := 0, 0
+	for _, book := range data.Books {
+		assert.NotEmpty(t, book.Title)
+		assert.NotEmpty(t, book.Tags)
+		assert.False(t, book.CreatedAt.Before(testWindow.From) || !book.CreatedAt.Before(testWindow.To), book.CreatedAt)
+		if assert.NotEmpty(t, book.Contributors) {
+			assert.Equal(t, authors[book.Contributors[0].AuthorID], book.Contributors[0].Name)
+			assert.Equal(t, book.Author, book.Contributors.AuthorDisplay())
+		}
+
+		// Alternating checkouts and checkins after creation, within the window; status matches the last change
+		changes := history[book.ID]
+		expected, at := models.BookStatusAvailable, book.CreatedAt
+		for i, change := range changes {
+			next := models.BookStatusCheckedOut
+			if i%2 == 1 {
+				next = models.BookStatusAvailable
+			}
+			assert.Equal(t, next, change.Status)
+			assert.True(t, change.Timestamp.After(at))
+			assert.True(t, change.Timestamp.Before(testWindow.To))
+			expected, at = change.Status, change.Timestamp
+		}
+		assert.Equal(t, expected, book.Status)
+		if len(changes) > 0 {
+			borrowed++
+			assert.Equal(t, at, book.UpdatedAt)
+		}
+		if book.Status == models.BookStatusCheckedOut {
+			checkedOut++
+		}
+	}
+
+	// Most books are borrowed, some are out at the end of the window
+	assert.Greater(t, borrowed, 300)
+	assert.Less(t, borrowed, 500)
+	assert.Greater(t, checkedOut, 0)
+}
+
+func TestGenerate_InvalidOptions(t *testing.T) {
+	_, err := Generate(Options{Books: 0, From: testWindow.From, To: testWindow.To})
+	assert.ErrorIs(t, err, utils.ErrBadRequest)
+
+	_, err = Generate(Options{Books: 10, From: testWindow.To, To: testWindow.From})
+	assert.ErrorIs(t, err, utils.ErrBadRequest)
+}
@@ -0,0 +1,81 @@
+package seed
+
+// Word pools of the synthetic catalog (titles, people, publishers and descriptions)
+
+var firstNames = []string{
+	"Ada", "Alan", "Alice", "Amara", "Ana", "Andrés", "Anne", "Arthur", "Beatriz", "Benjamin",
+	"Carmen", "Charles", "Chloé", "Clara", "Daniel", "David", "Diego", "Elena", "Eliza", "Emily",
+	"Ethan", "Felipe", "Frances", "Gabriel", "George", "Grace", "Hannah", "Henry", "Hugo", "Ingrid",
+	"Isabel", "Jack", "James", "Jane", "Javier", "Joan", "José", "Julia", "Kenji", "Laura",
+	"Leo", "Lucía", "Margaret", "María", "Mark", "Marta", "Mary", "Mateo", "Maya", "Michael",
+	"Naomi", "Nora", "Oliver", "Oscar", "Pablo", "Paul", "Rafael", "Rosa", "Ruth", "Samuel",
+	"Sara", "Sofía", "Thomas", "Toni", "Ursula", "Valentina", "Victor", "Virginia", "William", "Yuki",
+}
+
+var lastNames = []string{
+	"Abbott", "Alvarez", "Atwood", "Baker", "Barnes", "Bennett", "Blanco", "Brooks", "Castillo", "Chen",
+	"Clarke", "Cortázar", "Díaz", "Dubois", "Ellis", "Fernández", "Fischer", "Fleming", "García", "Gómez",
+	"Grant", "Hale", "Harper", "Hayes", "Herrera", "Ishiguro", "Jensen", "Keller", "Kim", "Lambert",
+	"Le Guin", "López", "Marsh", "Martín", "Meyer", "Moreau", "Morrison", "Muñoz", "Nakamura", "Navarro",
+	"Novak", "O'Brien", "Ortega", "Parker", "Pérez", "Quinn", "Ramírez", "Reyes", "Rossi", "Russo",
+	"Sánchez", "Schmidt", "Silva", "Sterling", "Suárez", "Tanaka", "Torres", "Vargas", "Walsh", "Weber",
+}
+
+var titleAdjectives = []string{
+	"Silent", "Hidden", "Broken", "Golden", "Last", "Forgotten", "Burning", "Distant", "Secret", "Endless",
+	"Crimson", "Northern", "Quiet", "Lost", "Wandering", "Hollow", "Bright", "Winter", "Ancient", "Shattered",
+	"Invisible", "Midnight", "Little", "Eternal", "Wild",
+}
+
+var titleNouns = []string{
+	"River", "Garden", "City", "House", "Kingdom", "Library", "Mountain", "Ocean", "Archive", "Empire",
+	"Forest", "Harbor", "Island", "Lighthouse", "Map", "Mirror", "Orchard", "Road", "Sea", "Star",
+	"Storm", "Tower", "Valley", "Voyage", "Window", "Clockmaker", "Cartographer", "Daughter", "Stranger", "Machine",
+}
+
+var titlePlaces = []string{
+	"Avalon", "Babylon", "Castile", "Dublin", "Eldoria", "Granada", "Kyoto", "Lisbon", "Madrid", "Marrakesh",
+	"Patagonia", "Prague", "Samarkand", "Seville", "Tangier", "Valparaíso", "Venice", "Vienna", "Yorkshire", "Zanzibar",
+}
+
+var titleAbstracts = []string{
+	"Memory", "Silence", "Time", "Light", "Ashes", "Salt", "Dreams", "Shadows", "Glass", "Thunder",
+	"Promises", "Secrets", "Echoes", "Bones", "Wolves",
+}
+
+var publishers = []string{
+	"Penguin Random House", "HarperCollins", "Simon & Schuster", "Macmillan", "Hachette",
+	"Anagrama", "Alfaguara", "Gallimard", "Suhrkamp", "Faber & Faber", "Vintage", "Tor Books",
+}
+
+// ISBN registration groups (by publication language)
+var isbnGroups = map[string]string{
+	"en": "0",
+	"es": "84",
+	"fr": "2",
+	"de": "3",
+	"it": "88",
+	"pt": "85",
+}
+
+// Languages weighted by catalog share
+var languages = []weighted[string]{
+	{"en", 55}, {"es", 25}, {"fr", 7}, {"de", 6}, {"it", 4}, {"pt", 3},
+}
+
+var genres = []string{
+	"fantasy", "science fiction", "mystery", "thriller", "romance", "historical", "biography",
+	"poetry", "horror", "classics", "young adult", "essays", "travel", "philosophy", "history",
+}
+
+var descriptionOpenings = []string{
+	"A sweeping story about", "An intimate portrait of", "A thrilling journey through", "A haunting meditation on",
+	"A witty chronicle of", "A lyrical account of", "A gripping investigation into", "A tender tale of",
+}
+
+var descriptionSubjects = []string{
+	"a family torn apart by war", "the last days of an empire", "an unlikely friendship", "a city that never forgets",
+	"love and betrayal across generations", "a scientist racing against time", "a detective haunted by an old case",
+	"exile and belonging", "the sea and those who cross it", "a forgotten manuscript", "a village with a secret",
+	"the limits of memory",
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/santiago-buildit/code-challenge/backend/internal/database"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/repositories"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+)
+
+// API key format: prefix + 32 random bytes (hex); the first characters identify the key in listings
+const (
+	apiKeyPrefix       = "lib_"
+	apiKeyPrefixLength = len(apiKeyPrefix) + 8
+)
+
+// APIKeyService defines the interface for API key operations
+type APIKeyService interface {
+	RotateAPIKey(ctx context.Context, req models.RotateAPIKeyRequest) (*models.RotateAPIKeyResponse, error)
+	ListAPIKeys(ctx context.Context, name string) ([]models.APIKeyResponse, error)
+	VerifyAPIKey(ctx context.Context, key string) (*models.APIKeyResponse, error) // utils.ErrNotFound when unknown or expired
+}
+
+type apiKeyServiceImpl struct {
+	db   *sqlx.DB
+	repo repositories.APIKeyRepository
+	now  func() time.Time
+}
+
+func NewAPIKeyService(db *sqlx.DB, repo repositories.APIKeyRepository) APIKeyService {
+	return &apiKeyServiceImpl{
+		db:   db,
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// RotateAPIKey issues a new key for the client and expires its previous keys after the grace period
+func (s *apiKeyServiceImpl) RotateAPIKey(ctx context.Context, req models.RotateAPIKeyRequest) (*models.RotateAPIKeyResponse, error) {
+
+	// Validate input
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name required", utils.ErrBadRequest)
+	}
+	if req.GracePeriod < 0 {
+		return nil, fmt.Errorf("%w: grace period must not be negative", utils.ErrBadRequest)
+	}
+
+	now := s.now()
+	expiresAt := now.Add(req.GracePeriod)
+
+	// Dry run: report the keys that would expire (same condition as the repository update)
+	if req.DryRun {
+		keys, err := s.repo.ListAPIKeys(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		expiring := []models.APIKey{}
+		for _, key := range keys {
+			if key.ExpiresAt == nil || key.ExpiresAt.After(expiresAt) {
+				key.ExpiresAt = &expiresAt
+				expiring = append(expiring, key)
+			}
+		}
+		return &models.RotateAPIKeyResponse{Expiring: models.ToAPIKeyResponseList(expiring), DryRun: true}, nil
+	}
+
+	// Generate key
+	secret, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	key := models.APIKey{
+		ID:        uuid.New().String(),
+		Name:      name,
+		Prefix:    secret[:apiKeyPrefixLength],
+		KeyHash:   hashAPIKey(secret),
+		CreatedAt: now,
+	}
+
+	// Transactional block (previous keys expired before the new one is created)
+	var expiring []models.APIKey
+	err = database.WithTransaction(ctx, s.db, func(tx *sqlx.Tx) error {
+		if expiring, err = s.repo.ExpireAPIKeys(ctx, tx, name, expiresAt); err != nil {
+			return err
+		}
+		return s.repo.CreateAPIKey(ctx, tx, &key)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RotateAPIKeyResponse{
+		Key:      models.ToAPIKeyResponse(&key),
+		Secret:   secret,
+		Expiring: models.ToAPIKeyResponseList(expiring),
+	}, nil
+}
+
+func (s *apiKeyServiceImpl) ListAPIKeys(ctx context.Context, name string) ([]models.APIKeyResponse, error) {
+
+	// List with repository
+	keys, err := s.repo.ListAPIKeys(ctx, strings.TrimSpace(name))
+	if err != nil {
+		return nil, err
+	}
+
+	// Map response
+	return models.ToAPIKeyResponseList(keys), nil
+}
+
+func (s *apiKeyServiceImpl) VerifyAPIKey(ctx context.Context, key string) (*models.APIKeyResponse, error) {
+
+	// Reject malformed keys without a lookup
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, utils.ErrNotFound
+	}
+
+	// Get with repository (by hash)
+	apiKey, err := s.repo.GetAPIKeyByHash(ctx, hashAPIKey(key))
+	if err != nil {
+		return nil, err
+	}
+
+	// Check expiry (rotated keys are valid during their grace period)
+	if !apiKey.Active(s.now()) {
+		return nil, utils.ErrNotFound
+	}
+
+	// Map response
+	return models.ToAPIKeyResponse(apiKey), nil
+}
+
+/* Helper functions */
+
+// generateAPIKey returns a random 32-byte hex key
+func generateAPIKey() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + hex.EncodeToString(buf), nil
+}
+
+// hashAPIKey returns the stored form of a key (SHA-256, hex). Keys are random, so no salt is needed.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// --- Mock definition ---
+
+type mockAPIKeyRepo struct {
+	mock.Mock
+}
+
+func (m *mockAPIKeyRepo) CreateAPIKey(ctx context.Context, tx *sqlx.Tx, key *models.APIKey) error {
+	args := m.Called(ctx, tx, key)
+	return args.Error(0)
+}
+
+func (m *mockAPIKeyRepo) ListAPIKeys(ctx context.Context, name string) ([]models.APIKey, error) {
+	args := m.Called(ctx, name)
+	return args.Get(0).([]models.APIKey), args.Error(1)
+}
+
+func (m *mockAPIKeyRepo) GetAPIKeyByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	args := m.Called(ctx, hash)
+
+	var key *models.APIKey
+	if k := args.Get(0); k != nil {
+		key = k.(*models.APIKey)
+	}
+	return key, args.Error(1)
+}
+
+func (m *mockAPIKeyRepo) ExpireAPIKeys(ctx context.Context, tx *sqlx.Tx, name string, expiresAt time.Time) ([]models.APIKey, error) {
+	args := m.Called(ctx, tx, name, expiresAt)
+	return args.Get(0).([]models.APIKey), args.Error(1)
+}
+
+// --- Test ---
+
+func TestRotateAPIKey_Success(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	grace := 24 * time.Hour
+
+	repo := new(mockAPIKeyRepo)
+	db, sqlMock := newMockDB(t)
+	service := NewAPIKeyService(db, repo).(*apiKeyServiceImpl)
+	service.now = func() time.Time { return now }
+
+	expiresAt := now.Add(grace)
+	old := models.APIKey{ID: "k1", Name: "kiosk", Prefix: "lib_00000000", ExpiresAt: &expiresAt}
+	var created *models.APIKey
+	sqlMock.ExpectBegin()
+	repo.On("ExpireAPIKeys", ctx, mock.AnythingOfType("*sqlx.Tx"), "kiosk", expiresAt).Return([]models.APIKey{old}, nil)
+	repo.On("CreateAPIKey", ctx, mock.AnythingOfType("*sqlx.Tx"), mock.MatchedBy(func(key *models.APIKey) bool {
+		created = key
+		return key.Name == "kiosk" && key.ExpiresAt == nil
+	})).Return(nil)
+	sqlMock.ExpectCommit()
+
+	resp, err := service.RotateAPIKey(ctx, models.RotateAPIKeyRequest{Name: " kiosk ", GracePeriod: grace})
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(resp.Secret, "lib_"))
+	assert.Len(t, resp.Secret, 4+64)
+	assert.Equal(t, resp.Secret[:12], resp.Key.Prefix)
+	assert.Equal(t, hashAPIKey(resp.Secret), created.KeyHash) // Only the hash is stored
+	assert.NotContains(t, created.KeyHash, resp.Secret)
+	assert.Equal(t, []models.APIKeyResponse{*models.ToAPIKeyResponse(&old)}, resp.Expiring)
+	assert.False(t, resp.DryRun)
+	repo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestRotateAPIKey_DryRun(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	soon := now.Add(time.Minute)
+
+	repo := new(mockAPIKeyRepo)
+	db, sqlMock := newMockDB(t)
+	service := NewAPIKeyService(db, repo).(*apiKeyServiceImpl)
+	service.now = func() time.Time { return now }
+
+	// Keys expiring before the new expiry keep their own
+	repo.On("ListAPIKeys", ctx, "kiosk").Return([]models.APIKey{
+		{ID: "k2", Name: "kiosk"},
+		{ID: "k1", Name: "kiosk", ExpiresAt: &soon},
+	}, nil)
+
+	resp, err := service.RotateAPIKey(ctx, models.RotateAPIKeyRequest{Name: "kiosk", GracePeriod: time.Hour, DryRun: true})
+
+	assert.NoError(t, err)
+	assert.True(t, resp.DryRun)
+	assert.Nil(t, resp.Key)
+	assert.Empty(t, resp.Secret)
+	if assert.Len(t, resp.Expiring, 1) {
+		assert.Equal(t, "k2", resp.Expiring[0].ID)
+		assert.Equal(t, now.Add(time.Hour), *resp.Expiring[0].ExpiresAt)
+	}
+	repo.AssertNotCalled(t, "CreateAPIKey", mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestRotateAPIKey_Invalid(t *testing.T) {
+	service := NewAPIKeyService(nil, new(mockAPIKeyRepo))
+
+	_, err := service.RotateAPIKey(context.Background(), models.RotateAPIKeyRequest{Name: " "})
+	assert.ErrorIs(t, err, utils.ErrBadRequest)
+
+	_, err = service.RotateAPIKey(context.Background(), models.RotateAPIKeyRequest{Name: "kiosk", GracePeriod: -time.Second})
+	assert.ErrorIs(t, err, utils.ErrBadRequest)
+}
+
+func TestVerifyAPIKey(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Second)
+	future := now.Add(time.Hour)
+
+	repo := new(mockAPIKeyRepo)
+	service := NewAPIKeyService(nil, repo).(*apiKeyServiceImpl)
+	service.now = func() time.Time { return now }
+
+	repo.On("GetAPIKeyByHash", ctx, hashAPIKey("lib_current")).Return(&models.APIKey{ID: "k1"}, nil)
+	repo.On("GetAPIKeyByHash", ctx, hashAPIKey("lib_grace")).Return(&models.APIKey{ID: "k2", ExpiresAt: &future}, nil)
+	repo.On("GetAPIKeyByHash", ctx, hashAPIKey("lib_expired")).Return(&models.APIKey{ID: "k3", ExpiresAt: &past}, nil)
+	repo.On("GetAPIKeyByHash", ctx, hashAPIKey("lib_unknown")).Return(nil, utils.ErrNotFound)
+
+	key, err := service.VerifyAPIKey(ctx, "lib_current")
+	assert.NoError(t, err)
+	assert.Equal(t, "k1", key.ID)
+
+	_, err = service.VerifyAPIKey(ctx, "lib_grace")
+	assert.NoError(t, err)
+
+	for _, secret := range []string{"lib_expired", "lib_unknown", "whsec_other"} {
+		_, err = service.VerifyAPIKey(ctx, secret)
+		assert.ErrorIs(t, err, utils.ErrNotFound, secret)
+	}
+	repo.AssertNumberOfCalls(t, "GetAPIKeyByHash", 4) // Malformed key not looked up
+}
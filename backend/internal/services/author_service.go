@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/repositories"
+)
+
+// AuthorService defines the interface for author-related operations
+type AuthorService interface {
+	ListAuthors(ctx context.Context, req models.ListAuthorsRequest) (*models.ListAuthorsResponse, error)
+	ListAuthorBooks(ctx context.Context, id string, req models.ListBooksRequest) (*models.ListBooksResponse, error)
+}
+
+type authorServiceImpl struct {
+	repo        repositories.AuthorRepository
+	bookService BookService // Book listing (filters, sorting, pagination)
+}
+
+func NewAuthorService(repo repositories.AuthorRepository, bookService BookService) AuthorService {
+	return &authorServiceImpl{
+		repo:        repo,
+		bookService: bookService,
+	}
+}
+
+func (s *authorServiceImpl) ListAuthors(ctx context.Context, req models.ListAuthorsRequest) (*models.ListAuthorsResponse, error) {
+
+	// List with repository
+	authors, totalItems, err := s.repo.ListAuthors(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map response
+	return &models.ListAuthorsResponse{
+		Authors:     models.ToAuthorResponseList(authors),
+		TotalItems:  totalItems,
+		TotalPages:  totalPages(totalItems, req.PageSize),
+		CurrentPage: req.Page,
+		PageSize:    req.PageSize,
+	}, nil
+}
+
+func (s *authorServiceImpl) ListAuthorBooks(ctx context.Context, id string, req models.ListBooksRequest) (*models.ListBooksResponse, error) {
+
+	// Check author exists (not found otherwise)
+	if _, err := s.repo.GetAuthorByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	// List books credited to the author
+	req.AuthorID = id
+	return s.bookService.ListBooks(ctx, req)
+}
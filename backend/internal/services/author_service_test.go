@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// --- Mock definition ---
+
+type mockAuthorRepo struct {
+	mock.Mock
+}
+
+func (m *mockAuthorRepo) ListAuthors(ctx context.Context, req models.ListAuthorsRequest) ([]models.Author, int, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).([]models.Author), args.Int(1), args.Error(2)
+}
+
+func (m *mockAuthorRepo) GetAuthorByID(ctx context.Context, id string) (*models.Author, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.Author), args.Error(1)
+}
+
+// --- Test ---
+
+func TestListAuthors_Success(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockAuthorRepo)
+	service := NewAuthorService(mockedRepo, nil)
+
+	req := models.ListAuthorsRequest{Page: 1, PageSize: 2}
+	authors := []models.Author{{ID: "a-1", Name: "Homer", BookCount: 2}, {ID: "a-2", Name: "Ovid"}}
+
+	mockedRepo.On("ListAuthors", ctx, req).Return(authors, 3, nil)
+
+	resp, err := service.ListAuthors(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, resp.TotalItems)
+	assert.Equal(t, 2, resp.TotalPages)
+	assert.Len(t, resp.Authors, 2)
+	assert.Equal(t, 2, resp.Authors[0].BookCount)
+	mockedRepo.AssertExpectations(t)
+}
+
+func TestListAuthorBooks_Success(t *testing.T) {
+	ctx := context.Background()
+	mockedAuthorRepo := new(mockAuthorRepo)
+	mockedBookRepo := new(mockRepo)
+	service := NewAuthorService(mockedAuthorRepo, NewBookService(&sqlx.DB{}, mockedBookRepo, nopOutbox{}))
+
+	authorID := "author-1"
+	req := models.ListBooksRequest{ContributorRole: "translator", Page: 1, PageSize: 10}
+
+	mockedAuthorRepo.On("GetAuthorByID", ctx, authorID).Return(&models.Author{ID: authorID, Name: "Emily Wilson"}, nil)
+	mockedBookRepo.On("LastUpdatedAt", ctx).Return(time.Time{}, nil)
+	mockedBookRepo.On("ListBooks", ctx, mock.MatchedBy(func(r models.ListBooksRequest) bool {
+		return r.AuthorID == authorID && r.ContributorRole == "translator" // Restricted to the author
+	})).Return([]models.Book{{ID: "book-1", Title: "The Odyssey", Author: "Homer"}}, 1, nil)
+
+	resp, err := service.ListAuthorBooks(ctx, authorID, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resp.TotalItems)
+	assert.Equal(t, "The Odyssey", resp.Books[0].Title)
+	mockedAuthorRepo.AssertExpectations(t)
+	mockedBookRepo.AssertExpectations(t)
+}
+
+func TestListAuthorBooks_NotFound(t *testing.T) {
+	ctx := context.Background()
+	mockedAuthorRepo := new(mockAuthorRepo)
+	mockedBookRepo := new(mockRepo)
+	service := NewAuthorService(mockedAuthorRepo, NewBookService(&sqlx.DB{}, mockedBookRepo, nopOutbox{}))
+
+	mockedAuthorRepo.On("GetAuthorByID", ctx, "missing").Return((*models.Author)(nil), utils.ErrNotFound)
+
+	resp, err := service.ListAuthorBooks(ctx, "missing", models.ListBooksRequest{Page: 1, PageSize: 10})
+
+	assert.ErrorIs(t, err, utils.ErrNotFound)
+	assert.Nil(t, resp)
+	mockedBookRepo.AssertNotCalled(t, "ListBooks", mock.Anything, mock.Anything)
+}
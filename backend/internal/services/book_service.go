@@ -2,16 +2,34 @@ package services
 
 import (
 	"context"
+	"errors"
 	"math"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/santiago-buildit/code-challenge/backend/internal/database"
+	"github.com/santiago-buildit/code-challenge/backend/internal/i18n"
 	"github.com/santiago-buildit/code-challenge/backend/internal/models"
 	"github.com/santiago-buildit/code-challenge/backend/internal/repositories"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
 )
 
+// Maximum number of "did you mean" suggestions in list responses
+const maxSuggestions = 5
+
+// Allowed status transitions (withdrawn is final)
+var bookStatusTransitions = map[models.BookStatus][]models.BookStatus{
+	models.BookStatusAvailable:  {models.BookStatusCheckedOut, models.BookStatusLost, models.BookStatusDamaged, models.BookStatusMissing, models.BookStatusWithdrawn},
+	models.BookStatusCheckedOut: {models.BookStatusAvailable, models.BookStatusLost, models.BookStatusDamaged},
+	models.BookStatusLost:       {models.BookStatusAvailable, models.BookStatusWithdrawn},
+	models.BookStatusDamaged:    {models.BookStatusInRepair, models.BookStatusWithdrawn},
+	models.BookStatusInRepair:   {models.BookStatusAvailable, models.BookStatusWithdrawn},
+	models.BookStatusMissing:    {models.BookStatusAvailable, models.BookStatusLost, models.BookStatusWithdrawn},
+	models.BookStatusWithdrawn:  {},
+}
+
 // ProductService defines the interface for product-related operations
 type BookService interface {
 
@@ -23,22 +41,28 @@ type BookService interface {
 	DeleteBook(ctx context.Context, id string) error
 
 	// Status operations
-	CheckoutBook(ctx context.Context, id string) error
-	CheckinBook(ctx context.Context, id string) error
+	CheckoutBook(ctx context.Context, id string, req models.StatusChangeRequest) error
+	CheckinBook(ctx context.Context, id string, req models.StatusChangeRequest) error
+	MarkBookFound(ctx context.Context, id string, req models.StatusChangeRequest) error                              // Lost or missing book back to available
+	ChangeBookStatus(ctx context.Context, id string, status models.BookStatus, req models.StatusChangeRequest) error // Any allowed transition
 
 	// History
 	GetBookWithHistory(ctx context.Context, id string) (*models.BookDetailResponse, error)
+	ListBookHistory(ctx context.Context, id string, req models.ListHistoryRequest) (*models.ListHistoryResponse, error)
+	GetBooksHistory(ctx context.Context, ids []string) (map[string][]models.StatusChangeResponse, error) // Batch, keyed by book ID
 }
 
 type bookServiceImpl struct {
-	db   *sqlx.DB
-	repo repositories.BookRepository
+	db     *sqlx.DB
+	repo   repositories.BookRepository
+	events BookEventOutbox // Lifecycle events (recorded with each change)
 }
 
-func NewBookService(db *sqlx.DB, repo repositories.BookRepository) BookService {
+func NewBookService(db *sqlx.DB, repo repositories.BookRepository, events BookEventOutbox) BookService {
 	return &bookServiceImpl{
-		db:   db,
-		repo: repo,
+		db:     db,
+		repo:   repo,
+		events: events,
 	}
 }
 
@@ -46,48 +70,105 @@ func (s *bookServiceImpl) CreateBook(ctx context.Context, req models.CreateBookR
 
 	now := time.Now()
 
+	// Validate language code
+	if err := validateLanguage(req.Language); err != nil {
+		return nil, err
+	}
+
+	// Resolve contributors (author is their display string)
+	contributors := req.ResolveContributors()
+	if len(contributors) == 0 {
+		return nil, i18n.NewError(utils.ErrBadRequest, i18n.ErrorAuthorRequired, nil)
+	}
+
 	// Map request
 	book := models.Book{
 		ID:          uuid.New().String(), // Generate unique ID
 		ISBN:        req.ISBN,
 		Title:       req.Title,
-		Author:      req.Author,
+		Author:      contributors.AuthorDisplay(),
 		Description: req.Description,
 		Status:      models.BookStatusAvailable,
 		CreatedAt:   now,
 		UpdatedAt:   now,
+
+		Publisher:       req.Publisher,
+		PublicationYear: req.PublicationYear,
+		Edition:         req.Edition,
+		Language:        req.Language,
+		PageCount:       req.PageCount,
+		Format:          req.Format,
 	}
 
-	// Create with repository
-	err := s.repo.CreateBook(ctx, &book)
+	// Transactional block (book, tag assignment and event)
+	var res *models.BookResponse
+	err := database.WithTransaction(ctx, s.db, func(tx *sqlx.Tx) error {
+
+		// Create with repository
+		if err := s.repo.CreateBook(ctx, tx, &book); err != nil {
+			return err
+		}
+
+		// Credit contributors with repository
+		if err := s.setContributors(ctx, tx, &book, contributors); err != nil {
+			return err
+		}
+
+		// Assign tags with repository
+		if err := s.setTags(ctx, tx, &book, req.Tags); err != nil {
+			return err
+		}
+
+		// Map response and record event
+		res = models.ToBookResponse(&book)
+		return s.events.AppendBookEvent(ctx, tx, models.BookEventCreated, book.ID, res)
+	})
 	if err != nil {
 		return nil, err
 	}
+	s.events.Notify()
 
-	// Map response
-	return models.ToBookResponse(&book), nil
+	return res, nil
 }
 
 func (s *bookServiceImpl) ListBooks(ctx context.Context, req models.ListBooksRequest) (*models.ListBooksResponse, error) {
 
-	// List with repository
-	books, totalItems, err := s.repo.ListBooks(ctx, req)
+	// Latest change of the library (read first, so a concurrent change is never hidden behind it)
+	lastModified, err := s.repo.LastUpdatedAt(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	// Map response
-	totalPages := int(math.Ceil(float64(totalItems) / float64(req.PageSize)))
-	res := &models.ListBooksResponse{
-		Books:       models.ToBookResponseList(books),
-		TotalItems:  totalItems,
-		TotalPages:  totalPages,
-		CurrentPage: req.Page,
-		PageSize:    req.PageSize,
+	// List page with repository (offset or cursor mode)
+	res, err := s.listPage(ctx, req)
+	if err != nil {
+		return nil, err
 	}
-	if res.TotalPages == 0 {
-		res.TotalPages = 1 // 1 empty page
+	res.LastModified = lastModified
+
+	// Nothing matched exactly: retry with typo-tolerant matching and build "did you mean" suggestions
+	matchedReq := req
+	if res.TotalItems == 0 && len(res.Books) == 0 && req.Cursor == "" && req.HasSearchTerms() {
+		matchedReq.Fuzzy = true
+		res, err = s.listPage(ctx, matchedReq)
+		if err != nil {
+			return nil, err
+		}
+		res.FuzzyMatch = res.TotalItems > 0 || len(res.Books) > 0
+		res.Suggestions, err = s.suggestTerms(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Facet counts (over the same filters that produced the results)
+	if len(req.Facets) > 0 {
+		res.Facets, err = s.repo.ListFacets(ctx, matchedReq)
+		if err != nil {
+			return nil, err
+		}
 	}
+
 	return res, nil
 }
 
@@ -105,64 +186,395 @@ func (s *bookServiceImpl) GetBook(ctx context.Context, id string) (*models.BookR
 
 func (s *bookServiceImpl) UpdateBook(ctx context.Context, id string, req models.UpdateBookRequest) (*models.BookResponse, error) {
 
+	// Validate language code
+	if err := validateLanguage(req.Language); err != nil {
+		return nil, err
+	}
+
 	// Get with repository
 	book, err := s.repo.GetBookByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	// Resolve contributors (only when given, or when a legacy payload changes the author)
+	var contributors models.Contributors
+	if len(req.Contributors) > 0 || req.Author != book.Author {
+		contributors = req.ResolveContributors()
+		if len(contributors) == 0 {
+			return nil, i18n.NewError(utils.ErrBadRequest, i18n.ErrorAuthorRequired, nil)
+		}
+		book.Author = contributors.AuthorDisplay()
+	}
+
 	// Map request
 	book.ISBN = req.ISBN
 	book.Title = req.Title
-	book.Author = req.Author
 	book.Description = req.Description
+	book.Publisher = req.Publisher
+	book.PublicationYear = req.PublicationYear
+	book.Edition = req.Edition
+	book.Language = req.Language
+	book.PageCount = req.PageCount
+	book.Format = req.Format
+	book.UpdatedAt = time.Now()
+
+	// Transactional block (book, tag assignment and event)
+	var res *models.BookResponse
+	err = database.WithTransaction(ctx, s.db, func(tx *sqlx.Tx) error {
+
+		// Update with repository
+		if err := s.repo.UpdateBook(ctx, tx, book); err != nil {
+			return err
+		}
 
-	// Update with repository
-	err = s.repo.UpdateBook(ctx, book)
+		// Replace contributors with repository (only when changed)
+		if contributors != nil {
+			if err := s.setContributors(ctx, tx, book, contributors); err != nil {
+				return err
+			}
+		}
+
+		// Replace tags with repository (only when given)
+		if err := s.setTags(ctx, tx, book, req.Tags); err != nil {
+			return err
+		}
+
+		// Map response and record event
+		res = models.ToBookResponse(book)
+		return s.events.AppendBookEvent(ctx, tx, models.BookEventUpdated, book.ID, res)
+	})
 	if err != nil {
 		return nil, err
 	}
+	s.events.Notify()
 
-	// Map response
-	return models.ToBookResponse(book), nil
+	return res, nil
 }
 
 func (s *bookServiceImpl) DeleteBook(ctx context.Context, id string) error {
 
-	// Delete with repository
-	return s.repo.DeleteBook(ctx, id)
+	// Transactional block (book and event)
+	err := database.WithTransaction(ctx, s.db, func(tx *sqlx.Tx) error {
+
+		// Delete with repository
+		if err := s.repo.DeleteBook(ctx, tx, id, time.Now()); err != nil {
+			return err
+		}
+
+		// Record event (without book state)
+		return s.events.AppendBookEvent(ctx, tx, models.BookEventDeleted, id, nil)
+	})
+	if err != nil {
+		return err
+	}
+	s.events.Notify()
+
+	return nil
 }
 
-func (s *bookServiceImpl) CheckoutBook(ctx context.Context, id string) error {
+func (s *bookServiceImpl) CheckoutBook(ctx context.Context, id string, req models.StatusChangeRequest) error {
 
 	// Change status to checked out
-	return s.changeBookStatus(ctx, id, models.BookStatusCheckedOut)
+	return s.changeBookStatus(ctx, id, models.BookStatusCheckedOut, req, nil)
 }
 
-func (s *bookServiceImpl) CheckinBook(ctx context.Context, id string) error {
+func (s *bookServiceImpl) CheckinBook(ctx context.Context, id string, req models.StatusChangeRequest) error {
 
-	// Change status to available
-	return s.changeBookStatus(ctx, id, models.BookStatusAvailable)
+	// Change status to available (only from checked out)
+	return s.changeBookStatus(ctx, id, models.BookStatusAvailable, req, []models.BookStatus{models.BookStatusCheckedOut})
+}
+
+func (s *bookServiceImpl) MarkBookFound(ctx context.Context, id string, req models.StatusChangeRequest) error {
+
+	// Change status to available (only from lost or missing)
+	return s.changeBookStatus(ctx, id, models.BookStatusAvailable, req, []models.BookStatus{models.BookStatusLost, models.BookStatusMissing})
+}
+
+func (s *bookServiceImpl) ChangeBookStatus(ctx context.Context, id string, status models.BookStatus, req models.StatusChangeRequest) error {
+
+	// Validate status
+	if _, ok := bookStatusTransitions[status]; !ok {
+		return i18n.NewError(utils.ErrBadRequest, i18n.ErrorUnknownStatus, i18n.Params{"status": string(status)})
+	}
+
+	// Change status
+	return s.changeBookStatus(ctx, id, status, req, nil)
 }
 
 func (s *bookServiceImpl) GetBookWithHistory(ctx context.Context, id string) (*models.BookDetailResponse, error) {
 
-	// Get with repository
-	book, history, err := s.repo.GetBookWithHistory(ctx, id)
+	// Get with repository (one change over the limit tells whether there are older ones)
+	book, history, err := s.repo.GetBookWithHistory(ctx, id, models.EmbeddedHistoryLimit+1)
 	if err != nil {
 		return nil, err
 	}
+	truncated := len(history) > models.EmbeddedHistoryLimit
+	if truncated {
+		history = history[:models.EmbeddedHistoryLimit]
+	}
 
 	// Map response
 	return &models.BookDetailResponse{
-		Book:    *models.ToBookResponse(book),
-		History: models.ToStatusChangeResponseList(history),
+		Book:             *models.ToBookResponse(book),
+		History:          models.ToStatusChangeResponseList(history),
+		HistoryTruncated: truncated,
 	}, nil
 }
 
+func (s *bookServiceImpl) ListBookHistory(ctx context.Context, id string, req models.ListHistoryRequest) (*models.ListHistoryResponse, error) {
+
+	// Validate date window
+	if !req.From.IsZero() && !req.To.IsZero() && !req.From.Before(req.To) {
+		return nil, i18n.NewError(utils.ErrBadRequest, i18n.ErrorRangeOrder, nil)
+	}
+
+	// Check book exists (not found otherwise)
+	if _, err := s.repo.GetBookByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	// List with repository
+	history, totalItems, err := s.repo.ListHistory(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map response
+	return &models.ListHistoryResponse{
+		History:     models.ToStatusChangeResponseList(history),
+		TotalItems:  totalItems,
+		TotalPages:  totalPages(totalItems, req.PageSize),
+		CurrentPage: req.Page,
+		PageSize:    req.PageSize,
+	}, nil
+}
+
+func (s *bookServiceImpl) GetBooksHistory(ctx context.Context, ids []string) (map[string][]models.StatusChangeResponse, error) {
+
+	// List with repository (latest changes of each book)
+	history, err := s.repo.ListHistoryByBookIDs(ctx, ids, models.EmbeddedHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map response (every requested book, empty history when it has no changes)
+	result := make(map[string][]models.StatusChangeResponse, len(ids))
+	for _, id := range ids {
+		result[id] = []models.StatusChangeResponse{}
+	}
+	for _, change := range history {
+		result[change.BookID] = append(result[change.BookID], models.ToStatusChangeResponse(change))
+	}
+	return result, nil
+}
+
 /* Helper functions */
 
-func (s *bookServiceImpl) changeBookStatus(ctx context.Context, id string, status models.BookStatus) error {
+// listPage lists one page of books in the request pagination mode and maps the response
+func (s *bookServiceImpl) listPage(ctx context.Context, req models.ListBooksRequest) (*models.ListBooksResponse, error) {
+
+	// Keyset pagination
+	if req.IsCursorMode() {
+		return s.listCursorPage(ctx, req)
+	}
+
+	// List with repository
+	books, totalItems, err := s.repo.ListBooks(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map response
+	res := &models.ListBooksResponse{
+		Books:       models.ToBookResponseList(books),
+		TotalItems:  totalItems,
+		TotalPages:  totalPages(totalItems, req.PageSize),
+		CurrentPage: req.Page,
+		PageSize:    req.PageSize,
+	}
+	return res, nil
+}
+
+// listCursorPage lists the page after (or before) the request cursor and builds the next/prev cursors
+func (s *bookServiceImpl) listCursorPage(ctx context.Context, req models.ListBooksRequest) (*models.ListBooksResponse, error) {
+
+	// Decode cursor (must have been issued for the same sort)
+	var cursor *models.Cursor
+	if req.Cursor != "" {
+		c, err := models.DecodeCursor(req.Cursor)
+		if err != nil || c.Sort != req.SortSignature() {
+			return nil, i18n.NewError(utils.ErrBadRequest, i18n.ErrorInvalidCursor, nil)
+		}
+		if _, err := uuid.Parse(c.ID); err != nil {
+			return nil, i18n.NewError(utils.ErrBadRequest, i18n.ErrorInvalidCursor, nil)
+		}
+		cursor = c
+		req.Fuzzy = c.Fuzzy // Keep walking the same result set
+	}
+	backward := cursor != nil && cursor.Backward
+
+	// List with repository (one extra book tells whether there are more in the walking direction)
+	books, err := s.repo.ListBooksAfter(ctx, req, cursor, req.PageSize+1)
+	if err != nil {
+		return nil, err
+	}
+	hasMore := len(books) > req.PageSize
+	if hasMore {
+		books = books[:req.PageSize]
+	}
+	if backward {
+		slices.Reverse(books) // Backward pages are fetched in reverse order
+	}
+
+	// Map response
+	res := &models.ListBooksResponse{
+		Books:    models.ToBookResponseList(books),
+		PageSize: req.PageSize,
+	}
+
+	// Build cursors from the page boundaries
+	if len(books) > 0 {
+		if hasMore || backward {
+			res.NextCursor = encodeCursor(req, books[len(books)-1], false)
+		}
+		if (hasMore && backward) || (cursor != nil && !backward) {
+			res.PrevCursor = encodeCursor(req, books[0], true)
+		}
+	}
+
+	// Count with repository (optional, the expensive part on large catalogs)
+	if !req.SkipTotal {
+		res.TotalItems, err = s.repo.CountBooks(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		res.TotalPages = totalPages(res.TotalItems, req.PageSize)
+	}
+
+	return res, nil
+}
+
+// encodeCursor builds an opaque cursor pointing at a page boundary book
+func encodeCursor(req models.ListBooksRequest, book models.Book, backward bool) string {
+
+	// Sort key values of the book, one per sort spec
+	var keys []interface{}
+	for _, spec := range req.Sorts() {
+		keys = append(keys, sortValue(book, spec.Field))
+	}
+
+	return models.EncodeCursor(models.Cursor{
+		Sort:     req.SortSignature(),
+		Fuzzy:    req.Fuzzy,
+		Keys:     keys,
+		ID:       book.ID,
+		Backward: backward,
+	})
+}
+
+// sortValue returns the value of a sortable field of a book
+func sortValue(book models.Book, field string) interface{} {
+	switch field {
+	case "isbn":
+		return book.ISBN
+	case "author":
+		return book.Author
+	case "status":
+		return string(book.Status)
+	case "created_at":
+		return book.CreatedAt
+	case "updated_at":
+		return book.UpdatedAt
+	case "publisher":
+		return book.Publisher
+	case "publication_year":
+		return book.PublicationYear
+	case "page_count":
+		return book.PageCount
+	case "relevance":
+		return book.Rank
+	default:
+		return book.Title
+	}
+}
+
+// validateLanguage checks an optional ISO 639-1 language code
+func validateLanguage(code string) error {
+	if code != "" && !models.IsLanguageCode(code) {
+		return i18n.NewError(utils.ErrBadRequest, i18n.ErrorUnknownLanguage, i18n.Params{"code": code})
+	}
+	return nil
+}
+
+// transitionError builds the invalid transition error of a book status change
+func transitionError(from, to models.BookStatus, allowed []models.BookStatus) error {
+	err := &utils.TransitionError{From: string(from), To: string(to), Allowed: []string{}}
+	for _, status := range allowed {
+		err.Allowed = append(err.Allowed, string(status))
+	}
+	return err
+}
+
+// totalPages computes the number of pages for a total (at least 1 empty page)
+func totalPages(totalItems, pageSize int) int {
+	pages := int(math.Ceil(float64(totalItems) / float64(pageSize)))
+	if pages == 0 {
+		return 1 // 1 empty page
+	}
+	return pages
+}
+
+// suggestTerms collects the closest known titles and authors for each search term of the request (deduplicated)
+func (s *bookServiceImpl) suggestTerms(ctx context.Context, req models.ListBooksRequest) ([]string, error) {
+
+	suggestions := []string{}
+	seen := map[string]bool{}
+	for _, term := range []string{req.Text, req.Title, req.Author} {
+		if term == "" {
+			continue
+		}
+
+		// Get with repository
+		candidates, err := s.repo.SuggestTerms(ctx, term, maxSuggestions)
+		if err != nil {
+			return nil, err
+		}
+		for _, candidate := range candidates {
+			if !seen[candidate] && len(suggestions) < maxSuggestions {
+				seen[candidate] = true
+				suggestions = append(suggestions, candidate)
+			}
+		}
+	}
+	return suggestions, nil
+}
+
+// setContributors replaces the book contributors, keeping the assigned authors (IDs, stored spelling) in the book
+func (s *bookServiceImpl) setContributors(ctx context.Context, tx *sqlx.Tx, book *models.Book, contributors models.Contributors) error {
+	assigned, err := s.repo.SetBookContributors(ctx, tx, book.ID, contributors)
+	if err != nil {
+		return err
+	}
+	book.Contributors = assigned
+	return nil
+}
+
+// setTags replaces the book tags when tags is not nil, keeping the stored spelling in the book
+func (s *bookServiceImpl) setTags(ctx context.Context, tx *sqlx.Tx, book *models.Book, tags []string) error {
+	if tags == nil {
+		return nil
+	}
+	assigned, err := s.repo.SetBookTags(ctx, tx, book.ID, tags)
+	if err != nil {
+		return err
+	}
+	book.Tags = assigned
+	return nil
+}
+
+// changeBookStatus moves a book to the given status if the transition is allowed (and, when given, the current status is in from)
+func (s *bookServiceImpl) changeBookStatus(ctx context.Context, id string, status models.BookStatus, req models.StatusChangeRequest, from []models.BookStatus) error {
 
 	// Get with repository
 	book, err := s.repo.GetBookByID(ctx, id)
@@ -175,18 +587,60 @@ func (s *bookServiceImpl) changeBookStatus(ctx context.Context, id string, statu
 		return nil
 	}
 
+	// Check transition
+	allowed := bookStatusTransitions[book.Status]
+	if !slices.Contains(allowed, status) || (from != nil && !slices.Contains(from, book.Status)) {
+		return transitionError(book.Status, status, allowed)
+	}
+
 	now := time.Now() // Use same timestamp for book updated-at and status change timestamp
 
-	// Transactional block
-	return database.WithTransaction(ctx, s.db, func(tx *sqlx.Tx) error {
+	// Event of the change (loans, or any other status change)
+	eventType := models.BookEventInventoryChanged
+	if status == models.BookStatusCheckedOut {
+		eventType = models.BookEventCheckedOut
+	} else if book.Status == models.BookStatusCheckedOut && status == models.BookStatusAvailable {
+		eventType = models.BookEventCheckedIn
+	}
+	current := book.Status
+	book.Status = status
+	book.UpdatedAt = now
+
+	// Transactional block (status, history and event)
+	err = database.WithTransaction(ctx, s.db, func(tx *sqlx.Tx) error {
 
-		// Update status with repository
-		err = s.repo.UpdateBookStatus(ctx, tx, id, status, now)
+		// Update status with repository (only if still in the status checked above)
+		err = s.repo.UpdateBookStatus(ctx, tx, id, current, status, now)
 		if err != nil {
 			return err
 		}
 
-		// Append status change with repository
-		return s.repo.AppendStatusChange(ctx, tx, id, status, now)
+		// Append status change (with actor, source and note) with repository
+		req.Sanitize()
+		change := &models.BookStatusChange{BookID: id, Status: status, Timestamp: now, Actor: req.Actor, Source: req.Source, Note: req.Note}
+		if err := s.repo.AppendStatusChange(ctx, tx, change); err != nil {
+			return err
+		}
+
+		// Record event
+		return s.events.AppendBookEvent(ctx, tx, eventType, id, models.ToBookResponse(book))
 	})
+	if errors.Is(err, utils.ErrConflict) {
+
+		// Changed by a concurrent request since it was read: answer as if it had been read in the new status
+		book, err = s.repo.GetBookByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if book.Status == status {
+			return nil // Already in desired status (e.g. a double-submitted checkout), idempotence as above
+		}
+		return transitionError(book.Status, status, bookStatusTransitions[book.Status])
+	}
+	if err != nil {
+		return err
+	}
+	s.events.Notify()
+
+	return nil
 }
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -18,8 +19,8 @@ type mockRepo struct {
 	mock.Mock
 }
 
-func (m *mockRepo) CreateBook(ctx context.Context, book *models.Book) error {
-	args := m.Called(ctx, book)
+func (m *mockRepo) CreateBook(ctx context.Context, tx *sqlx.Tx, book *models.Book) error {
+	args := m.Called(ctx, tx, book)
 	return args.Error(0)
 }
 
@@ -28,33 +29,83 @@ func (m *mockRepo) ListBooks(ctx context.Context, req models.ListBooksRequest) (
 	return args.Get(0).([]models.Book), args.Int(1), args.Error(2)
 }
 
+func (m *mockRepo) ListBooksAfter(ctx context.Context, req models.ListBooksRequest, cursor *models.Cursor, limit int) ([]models.Book, error) {
+	args := m.Called(ctx, req, cursor, limit)
+	return args.Get(0).([]models.Book), args.Error(1)
+}
+
+func (m *mockRepo) CountBooks(ctx context.Context, req models.ListBooksRequest) (int, error) {
+	args := m.Called(ctx, req)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *mockRepo) GetBookByID(ctx context.Context, id string) (*models.Book, error) {
 	args := m.Called(ctx, id)
 	return args.Get(0).(*models.Book), args.Error(1)
 }
 
-func (m *mockRepo) UpdateBook(ctx context.Context, book *models.Book) error {
-	args := m.Called(ctx, book)
+func (m *mockRepo) UpdateBook(ctx context.Context, tx *sqlx.Tx, book *models.Book) error {
+	args := m.Called(ctx, tx, book)
 	return args.Error(0)
 }
 
-func (m *mockRepo) DeleteBook(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *mockRepo) SetBookContributors(ctx context.Context, tx *sqlx.Tx, id string, contributors models.Contributors) (models.Contributors, error) {
+	args := m.Called(ctx, tx, id, contributors)
+	return args.Get(0).(models.Contributors), args.Error(1)
+}
+
+func (m *mockRepo) SetBookCover(ctx context.Context, tx *sqlx.Tx, id string, timestamp time.Time) error {
+	args := m.Called(ctx, tx, id, timestamp)
+	return args.Error(0)
+}
+
+func (m *mockRepo) SetBookTags(ctx context.Context, tx *sqlx.Tx, id string, tags []string) ([]string, error) {
+	args := m.Called(ctx, tx, id, tags)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockRepo) TouchBooksByTag(ctx context.Context, tx *sqlx.Tx, tagID string, ts time.Time) ([]string, error) {
+	args := m.Called(ctx, tx, tagID, ts)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockRepo) GetBooksByIDs(ctx context.Context, tx *sqlx.Tx, ids []string) ([]models.Book, error) {
+	args := m.Called(ctx, tx, ids)
+	return args.Get(0).([]models.Book), args.Error(1)
+}
+
+func (m *mockRepo) DeleteBook(ctx context.Context, tx *sqlx.Tx, id string, ts time.Time) error {
+	args := m.Called(ctx, tx, id, ts)
 	return args.Error(0)
 }
 
-func (m *mockRepo) UpdateBookStatus(ctx context.Context, tx *sqlx.Tx, id string, status models.BookStatus, ts time.Time) error {
-	args := m.Called(ctx, tx, id, status, ts)
+func (m *mockRepo) LastUpdatedAt(ctx context.Context) (time.Time, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (m *mockRepo) SuggestTerms(ctx context.Context, term string, limit int) ([]string, error) {
+	args := m.Called(ctx, term, limit)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockRepo) ListFacets(ctx context.Context, req models.ListBooksRequest) (map[string][]models.FacetBucket, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(map[string][]models.FacetBucket), args.Error(1)
+}
+
+func (m *mockRepo) UpdateBookStatus(ctx context.Context, tx *sqlx.Tx, id string, from, status models.BookStatus, ts time.Time) error {
+	args := m.Called(ctx, tx, id, from, status, ts)
 	return args.Error(0)
 }
 
-func (m *mockRepo) AppendStatusChange(ctx context.Context, tx *sqlx.Tx, id string, status models.BookStatus, ts time.Time) error {
-	args := m.Called(ctx, tx, id, status, ts)
+func (m *mockRepo) AppendStatusChange(ctx context.Context, tx *sqlx.Tx, change *models.BookStatusChange) error {
+	args := m.Called(ctx, tx, change)
 	return args.Error(0)
 }
 
-func (m *mockRepo) GetBookWithHistory(ctx context.Context, id string) (*models.Book, []models.BookStatusChange, error) {
-	args := m.Called(ctx, id)
+func (m *mockRepo) GetBookWithHistory(ctx context.Context, id string, limit int) (*models.Book, []models.BookStatusChange, error) {
+	args := m.Called(ctx, id, limit)
 
 	var book *models.Book
 	if b := args.Get(0); b != nil {
@@ -69,6 +120,67 @@ func (m *mockRepo) GetBookWithHistory(ctx context.Context, id string) (*models.B
 	return book, history, args.Error(2)
 }
 
+func (m *mockRepo) ListHistory(ctx context.Context, id string, req models.ListHistoryRequest) ([]models.BookStatusChange, int, error) {
+	args := m.Called(ctx, id, req)
+	history, _ := args.Get(0).([]models.BookStatusChange)
+	return history, args.Int(1), args.Error(2)
+}
+
+func (m *mockRepo) ListHistoryByBookIDs(ctx context.Context, ids []string, limit int) ([]models.BookStatusChange, error) {
+	args := m.Called(ctx, ids, limit)
+	return args.Get(0).([]models.BookStatusChange), args.Error(1)
+}
+
+func (m *mockRepo) GetDeletedBookByID(ctx context.Context, id string) (*models.Book, error) {
+	args := m.Called(ctx, id)
+
+	var book *models.Book
+	if b := args.Get(0); b != nil {
+		book = b.(*models.Book)
+	}
+	return book, args.Error(1)
+}
+
+func (m *mockRepo) RestoreBook(ctx context.Context, tx *sqlx.Tx, id string, ts time.Time) error {
+	args := m.Called(ctx, tx, id, ts)
+	return args.Error(0)
+}
+
+func (m *mockRepo) ListStatusDrift(ctx context.Context, ids []string) ([]models.BookStatusDrift, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]models.BookStatusDrift), args.Error(1)
+}
+
+// newMockDB returns a mocked DB for services that open transactions
+func newMockDB(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return sqlx.NewDb(db, "postgres"), mock
+}
+
+// nopOutbox discards book events
+type nopOutbox struct{}
+
+func (nopOutbox) AppendBookEvent(ctx context.Context, tx *sqlx.Tx, eventType models.BookEventType, bookID string, book *models.BookResponse) error {
+	return nil
+}
+
+func (nopOutbox) Notify() {}
+
+type mockOutbox struct {
+	mock.Mock
+}
+
+func (m *mockOutbox) AppendBookEvent(ctx context.Context, tx *sqlx.Tx, eventType models.BookEventType, bookID string, book *models.BookResponse) error {
+	args := m.Called(ctx, tx, eventType, bookID, book)
+	return args.Error(0)
+}
+
+func (m *mockOutbox) Notify() {
+	m.Called()
+}
+
 // --- Test ---
 
 func TestCreateBook_Success(t *testing.T) {
@@ -76,8 +188,8 @@ func TestCreateBook_Success(t *testing.T) {
 
 	// Setup
 	mockedRepo := new(mockRepo)
-	db := &sqlx.DB{}
-	service := NewBookService(db, mockedRepo)
+	db, sqlMock := newMockDB(t)
+	service := NewBookService(db, mockedRepo, nopOutbox{})
 
 	// Request payload
 	req := models.CreateBookRequest{
@@ -89,10 +201,15 @@ func TestCreateBook_Success(t *testing.T) {
 
 	// Capture the book received by CreateBook to validate
 	var capturedBook *models.Book
-	mockedRepo.On("CreateBook", ctx, mock.MatchedBy(func(b *models.Book) bool {
+	sqlMock.ExpectBegin()
+	mockedRepo.On("CreateBook", ctx, mock.AnythingOfType("*sqlx.Tx"), mock.MatchedBy(func(b *models.Book) bool {
 		capturedBook = b
 		return true
 	})).Return(nil)
+	mockedRepo.On("SetBookContributors", ctx, mock.AnythingOfType("*sqlx.Tx"), mock.AnythingOfType("string"),
+		models.Contributors{{Name: "J.R.R. Tolkien", Role: models.ContributorRoleAuthor}}).
+		Return(models.Contributors{{AuthorID: "author-1", Name: "J.R.R. Tolkien", Role: models.ContributorRoleAuthor}}, nil)
+	sqlMock.ExpectCommit()
 
 	// Execute
 	resp, err := service.CreateBook(ctx, req)
@@ -108,6 +225,10 @@ func TestCreateBook_Success(t *testing.T) {
 	assert.WithinDuration(t, resp.CreatedAt, resp.UpdatedAt, time.Second)
 
 	mockedRepo.AssertExpectations(t)
+	mockedRepo.AssertNotCalled(t, "SetBookTags", mock.Anything, mock.Anything, mock.Anything, mock.Anything) // No tags given
+	assert.Equal(t, []string{}, resp.Tags)
+	assert.Equal(t, []models.ContributorResponse{{AuthorID: "author-1", Name: "J.R.R. Tolkien", Role: models.ContributorRoleAuthor}}, resp.Contributors)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
 
 	// Additional: validate that what was passed to the repo has the expected data
 	assert.Equal(t, resp.ID, capturedBook.ID)
@@ -118,8 +239,8 @@ func TestCreateBook_RepositoryError(t *testing.T) {
 	ctx := context.Background()
 
 	mockedRepo := new(mockRepo)
-	db := &sqlx.DB{}
-	service := NewBookService(db, mockedRepo)
+	db, sqlMock := newMockDB(t)
+	service := NewBookService(db, mockedRepo, nopOutbox{})
 
 	req := models.CreateBookRequest{
 		ISBN:        "123456",
@@ -128,20 +249,92 @@ func TestCreateBook_RepositoryError(t *testing.T) {
 		Description: "This should fail",
 	}
 
-	mockedRepo.On("CreateBook", ctx, mock.AnythingOfType("*models.Book")).Return(assert.AnError)
+	sqlMock.ExpectBegin()
+	mockedRepo.On("CreateBook", ctx, mock.AnythingOfType("*sqlx.Tx"), mock.AnythingOfType("*models.Book")).Return(assert.AnError)
+	sqlMock.ExpectRollback()
 
 	resp, err := service.CreateBook(ctx, req)
 
 	assert.Nil(t, resp)
 	assert.Equal(t, assert.AnError, err)
 	mockedRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCreateBook_WithContributors(t *testing.T) {
+	ctx := context.Background()
+
+	mockedRepo := new(mockRepo)
+	db, sqlMock := newMockDB(t)
+	service := NewBookService(db, mockedRepo, nopOutbox{})
+
+	req := models.CreateBookRequest{
+		ISBN:  "9780261102385",
+		Title: "The Silmarillion",
+		Contributors: []models.ContributorPayload{
+			{Name: "J.R.R. Tolkien", Role: models.ContributorRoleAuthor},
+			{Name: "Christopher Tolkien", Role: models.ContributorRoleEditor},
+			{Name: "Ted Nasmith", Role: models.ContributorRoleIllustrator},
+		},
+	}
+	contributors := models.Contributors{
+		{Name: "J.R.R. Tolkien", Role: models.ContributorRoleAuthor},
+		{Name: "Christopher Tolkien", Role: models.ContributorRoleEditor},
+		{Name: "Ted Nasmith", Role: models.ContributorRoleIllustrator},
+	}
+
+	sqlMock.ExpectBegin()
+	mockedRepo.On("CreateBook", ctx, mock.AnythingOfType("*sqlx.Tx"), mock.MatchedBy(func(b *models.Book) bool {
+		return b.Author == "J.R.R. Tolkien" // Display string: only the names credited as authors
+	})).Return(nil)
+	mockedRepo.On("SetBookContributors", ctx, mock.AnythingOfType("*sqlx.Tx"), mock.AnythingOfType("string"), contributors).
+		Return(contributors, nil)
+	sqlMock.ExpectCommit()
+
+	resp, err := service.CreateBook(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "J.R.R. Tolkien", resp.Author)
+	assert.Len(t, resp.Contributors, 3)
+	mockedRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCreateBook_MissingAuthor(t *testing.T) {
+	ctx := context.Background()
+
+	mockedRepo := new(mockRepo)
+	db, sqlMock := newMockDB(t)
+	service := NewBookService(db, mockedRepo, nopOutbox{})
+
+	resp, err := service.CreateBook(ctx, models.CreateBookRequest{ISBN: "123456", Title: "Anonymous", Contributors: []models.ContributorPayload{}})
+
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, utils.ErrBadRequest)
+	mockedRepo.AssertNotCalled(t, "CreateBook", mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(t, sqlMock.ExpectationsWereMet()) // No transaction
+}
+
+func TestCreateBook_UnknownLanguage(t *testing.T) {
+	ctx := context.Background()
+
+	mockedRepo := new(mockRepo)
+	db, sqlMock := newMockDB(t)
+	service := NewBookService(db, mockedRepo, nopOutbox{})
+
+	resp, err := service.CreateBook(ctx, models.CreateBookRequest{ISBN: "123456", Title: "Ficciones", Author: "Jorge Luis Borges", Language: "xx"})
+
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, utils.ErrBadRequest)
+	mockedRepo.AssertNotCalled(t, "CreateBook", mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(t, sqlMock.ExpectationsWereMet()) // No transaction
 }
 
 func TestListBooks_Success(t *testing.T) {
 	ctx := context.Background()
 	mockedRepo := new(mockRepo)
 	db := &sqlx.DB{}
-	service := NewBookService(db, mockedRepo)
+	service := NewBookService(db, mockedRepo, nopOutbox{})
 
 	req := models.ListBooksRequest{
 		Title:     "The Lord of the Rings",
@@ -161,6 +354,9 @@ func TestListBooks_Success(t *testing.T) {
 	books := []models.Book{book}
 	total := 1
 
+	lastModified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	mockedRepo.On("LastUpdatedAt", ctx).Return(lastModified, nil)
 	mockedRepo.On("ListBooks", ctx, req).Return(books, total, nil)
 
 	resp, err := service.ListBooks(ctx, req)
@@ -169,6 +365,7 @@ func TestListBooks_Success(t *testing.T) {
 	assert.Equal(t, total, resp.TotalItems)
 	assert.Len(t, resp.Books, 1)
 	assert.Equal(t, book.ID, resp.Books[0].ID)
+	assert.Equal(t, lastModified, resp.LastModified)
 
 	mockedRepo.AssertExpectations(t)
 }
@@ -177,12 +374,13 @@ func TestListBooks_RepoError(t *testing.T) {
 	ctx := context.Background()
 	mockedRepo := new(mockRepo)
 	db := &sqlx.DB{}
-	service := NewBookService(db, mockedRepo)
+	service := NewBookService(db, mockedRepo, nopOutbox{})
 
 	req := models.ListBooksRequest{
 		Page: 1, PageSize: 10,
 	}
 
+	mockedRepo.On("LastUpdatedAt", ctx).Return(time.Time{}, nil)
 	mockedRepo.On("ListBooks", ctx, req).Return([]models.Book(nil), 0, assert.AnError)
 
 	resp, err := service.ListBooks(ctx, req)
@@ -193,11 +391,158 @@ func TestListBooks_RepoError(t *testing.T) {
 	mockedRepo.AssertExpectations(t)
 }
 
+func TestListBooks_FuzzyFallback(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockRepo)
+	db := &sqlx.DB{}
+	service := NewBookService(db, mockedRepo, nopOutbox{})
+
+	req := models.ListBooksRequest{
+		Author:   "Tolkein",
+		Page:     1,
+		PageSize: 10,
+	}
+	fuzzyReq := req
+	fuzzyReq.Fuzzy = true
+
+	book := models.Book{
+		ID:     "book-1",
+		Title:  "The Lord of the Rings",
+		Author: "J.R.R. Tolkien",
+		Status: models.BookStatusAvailable,
+	}
+
+	mockedRepo.On("LastUpdatedAt", ctx).Return(time.Time{}, nil)
+	// Exact match finds nothing, fuzzy match finds the book
+	mockedRepo.On("ListBooks", ctx, req).Return([]models.Book{}, 0, nil)
+	mockedRepo.On("ListBooks", ctx, fuzzyReq).Return([]models.Book{book}, 1, nil)
+	mockedRepo.On("SuggestTerms", ctx, "Tolkein", maxSuggestions).Return([]string{"J.R.R. Tolkien"}, nil)
+
+	resp, err := service.ListBooks(ctx, req)
+
+	assert.NoError(t, err)
+	assert.True(t, resp.FuzzyMatch)
+	assert.Equal(t, 1, resp.TotalItems)
+	assert.Equal(t, book.ID, resp.Books[0].ID)
+	assert.Equal(t, []string{"J.R.R. Tolkien"}, resp.Suggestions)
+
+	mockedRepo.AssertExpectations(t)
+}
+
+func TestListBooks_WithFacets(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockRepo)
+	db := &sqlx.DB{}
+	service := NewBookService(db, mockedRepo, nopOutbox{})
+
+	req := models.ListBooksRequest{
+		Page:     1,
+		PageSize: 10,
+		Facets:   []string{"status"},
+	}
+
+	facets := map[string][]models.FacetBucket{
+		"status": {{Value: "available", Count: 2}, {Value: "checked_out", Count: 1}},
+	}
+
+	mockedRepo.On("LastUpdatedAt", ctx).Return(time.Time{}, nil)
+	mockedRepo.On("ListBooks", ctx, req).Return([]models.Book{{ID: "book-1"}, {ID: "book-2"}, {ID: "book-3"}}, 3, nil)
+	mockedRepo.On("ListFacets", ctx, req).Return(facets, nil)
+
+	resp, err := service.ListBooks(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, resp.TotalItems)
+	assert.Equal(t, facets, resp.Facets)
+
+	mockedRepo.AssertExpectations(t)
+}
+
+func TestListBooks_CursorPagination(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockRepo)
+	db := &sqlx.DB{}
+	service := NewBookService(db, mockedRepo, nopOutbox{})
+
+	// First page (no cursor), total skipped
+	req := models.ListBooksRequest{
+		Pagination: models.PaginationCursor,
+		PageSize:   2,
+		SortBy:     "title",
+		SortOrder:  "asc",
+		SkipTotal:  true,
+	}
+	books := []models.Book{
+		{ID: "fac2b19c-e857-4d40-8233-8132b9759b51", Title: "A"},
+		{ID: "fac2b19c-e857-4d40-8233-8132b9759b52", Title: "B"},
+		{ID: "fac2b19c-e857-4d40-8233-8132b9759b53", Title: "C"}, // Extra book: there is a next page
+	}
+	mockedRepo.On("LastUpdatedAt", ctx).Return(time.Time{}, nil)
+	mockedRepo.On("ListBooksAfter", ctx, req, (*models.Cursor)(nil), 3).Return(books, nil)
+
+	resp, err := service.ListBooks(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Books, 2)
+	assert.Empty(t, resp.PrevCursor)
+	assert.NotEmpty(t, resp.NextCursor)
+	assert.Equal(t, 0, resp.TotalItems)
+
+	// Next page decodes the cursor of the last book
+	cursor, err := models.DecodeCursor(resp.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, "title:asc", cursor.Sort)
+	assert.Equal(t, []interface{}{"B"}, cursor.Keys)
+	assert.Equal(t, books[1].ID, cursor.ID)
+	assert.False(t, cursor.Backward)
+
+	nextReq := req
+	nextReq.Cursor = resp.NextCursor
+	nextReq.SkipTotal = false
+	mockedRepo.On("ListBooksAfter", ctx, nextReq, cursor, 3).Return(books[2:], nil)
+	mockedRepo.On("CountBooks", ctx, nextReq).Return(3, nil)
+
+	resp, err = service.ListBooks(ctx, nextReq)
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Books, 1)
+	assert.Empty(t, resp.NextCursor)
+	assert.NotEmpty(t, resp.PrevCursor)
+	assert.Equal(t, 3, resp.TotalItems)
+	assert.Equal(t, 2, resp.TotalPages)
+
+	mockedRepo.AssertExpectations(t)
+}
+
+func TestListBooks_InvalidCursor(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockRepo)
+	db := &sqlx.DB{}
+	service := NewBookService(db, mockedRepo, nopOutbox{})
+
+	// Cursor issued for another sort
+	cursor := models.EncodeCursor(models.Cursor{Sort: "author:asc", Keys: []interface{}{"X"}, ID: "fac2b19c-e857-4d40-8233-8132b9759b51"})
+	req := models.ListBooksRequest{
+		Pagination: models.PaginationCursor,
+		Cursor:     cursor,
+		PageSize:   2,
+		SortBy:     "title",
+		SortOrder:  "asc",
+	}
+	mockedRepo.On("LastUpdatedAt", ctx).Return(time.Time{}, nil)
+
+	resp, err := service.ListBooks(ctx, req)
+
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, utils.ErrBadRequest)
+	mockedRepo.AssertExpectations(t)
+}
+
 func TestGetBook_Success(t *testing.T) {
 	ctx := context.Background()
 	mockedRepo := new(mockRepo)
 	db := &sqlx.DB{}
-	service := NewBookService(db, mockedRepo)
+	service := NewBookService(db, mockedRepo, nopOutbox{})
 
 	expected := &models.Book{
 		ID:     "book-1",
@@ -221,7 +566,7 @@ func TestGetBook_NotFound(t *testing.T) {
 	ctx := context.Background()
 	mockedRepo := new(mockRepo)
 	db := &sqlx.DB{}
-	service := NewBookService(db, mockedRepo)
+	service := NewBookService(db, mockedRepo, nopOutbox{})
 
 	mockedRepo.On("GetBookByID", ctx, "missing-id").Return((*models.Book)(nil), utils.ErrNotFound)
 
@@ -235,8 +580,8 @@ func TestGetBook_NotFound(t *testing.T) {
 func TestUpdateBook_Success(t *testing.T) {
 	ctx := context.Background()
 	mockedRepo := new(mockRepo)
-	db := &sqlx.DB{}
-	service := NewBookService(db, mockedRepo)
+	db, sqlMock := newMockDB(t)
+	service := NewBookService(db, mockedRepo, nopOutbox{})
 
 	bookID := "book-1"
 	existing := &models.Book{
@@ -255,22 +600,102 @@ func TestUpdateBook_Success(t *testing.T) {
 	}
 
 	mockedRepo.On("GetBookByID", ctx, bookID).Return(existing, nil)
-	mockedRepo.On("UpdateBook", ctx, mock.MatchedBy(func(b *models.Book) bool {
+	sqlMock.ExpectBegin()
+	mockedRepo.On("UpdateBook", ctx, mock.AnythingOfType("*sqlx.Tx"), mock.MatchedBy(func(b *models.Book) bool {
 		return b.ISBN == req.ISBN && b.Title == req.Title && b.Author == req.Author && b.Description == req.Description
 	})).Return(nil)
+	mockedRepo.On("SetBookContributors", ctx, mock.AnythingOfType("*sqlx.Tx"), bookID,
+		models.Contributors{{Name: "New Author", Role: models.ContributorRoleAuthor}}). // Legacy payload changed the author
+		Return(models.Contributors{{AuthorID: "author-2", Name: "New Author", Role: models.ContributorRoleAuthor}}, nil)
+	sqlMock.ExpectCommit()
 
 	result, err := service.UpdateBook(ctx, bookID, req)
 
 	assert.NoError(t, err)
 	assert.Equal(t, req.Title, result.Title)
 	mockedRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestUpdateBook_SetsUpdatedAt(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockRepo)
+	db, sqlMock := newMockDB(t)
+	service := NewBookService(db, mockedRepo, nopOutbox{})
+
+	lastUpdate := time.Now().Add(-time.Hour)
+	existing := &models.Book{ID: "book-1", Title: "Dune", Author: "Frank Herbert", Status: models.BookStatusAvailable, UpdatedAt: lastUpdate}
+
+	mockedRepo.On("GetBookByID", ctx, "book-1").Return(existing, nil)
+	sqlMock.ExpectBegin()
+	mockedRepo.On("UpdateBook", ctx, mock.AnythingOfType("*sqlx.Tx"), mock.MatchedBy(func(b *models.Book) bool {
+		return b.UpdatedAt.After(lastUpdate) // Written to updated_at (list validators, updated_at filters and events)
+	})).Return(nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.UpdateBook(ctx, "book-1", models.UpdateBookRequest{Title: "Dune Messiah", Author: "Frank Herbert"})
+
+	assert.NoError(t, err)
+	assert.True(t, result.UpdatedAt.After(lastUpdate))
+	mockedRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestUpdateBook_WithTags(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockRepo)
+	db, sqlMock := newMockDB(t)
+	service := NewBookService(db, mockedRepo, nopOutbox{})
+
+	bookID := "book-1"
+	existing := &models.Book{ID: bookID, Title: "The Hobbit", Tags: []string{"Old"}}
+	req := models.UpdateBookRequest{Title: "The Hobbit", Tags: []string{"fantasy", "Classics"}}
+
+	mockedRepo.On("GetBookByID", ctx, bookID).Return(existing, nil)
+	sqlMock.ExpectBegin()
+	mockedRepo.On("UpdateBook", ctx, mock.AnythingOfType("*sqlx.Tx"), existing).Return(nil)
+	mockedRepo.On("SetBookTags", ctx, mock.AnythingOfType("*sqlx.Tx"), bookID, req.Tags).
+		Return([]string{"Classics", "Fantasy"}, nil) // Stored spelling, sorted
+	sqlMock.ExpectCommit()
+
+	result, err := service.UpdateBook(ctx, bookID, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Classics", "Fantasy"}, result.Tags)
+	mockedRepo.AssertExpectations(t)
+	mockedRepo.AssertNotCalled(t, "SetBookContributors", mock.Anything, mock.Anything, mock.Anything, mock.Anything) // Author unchanged
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestUpdateBook_TagsErrorRollsBack(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockRepo)
+	db, sqlMock := newMockDB(t)
+	service := NewBookService(db, mockedRepo, nopOutbox{})
+
+	bookID := "book-1"
+	existing := &models.Book{ID: bookID, Title: "The Hobbit"}
+	req := models.UpdateBookRequest{Title: "The Hobbit", Tags: []string{}}
+
+	mockedRepo.On("GetBookByID", ctx, bookID).Return(existing, nil)
+	sqlMock.ExpectBegin()
+	mockedRepo.On("UpdateBook", ctx, mock.AnythingOfType("*sqlx.Tx"), existing).Return(nil)
+	mockedRepo.On("SetBookTags", ctx, mock.AnythingOfType("*sqlx.Tx"), bookID, []string{}).Return([]string(nil), assert.AnError)
+	sqlMock.ExpectRollback()
+
+	result, err := service.UpdateBook(ctx, bookID, req)
+
+	assert.Nil(t, result)
+	assert.Equal(t, assert.AnError, err)
+	mockedRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
 }
 
 func TestUpdateBook_NotFound(t *testing.T) {
 	ctx := context.Background()
 	mockedRepo := new(mockRepo)
 	db := &sqlx.DB{}
-	service := NewBookService(db, mockedRepo)
+	service := NewBookService(db, mockedRepo, nopOutbox{})
 
 	req := models.UpdateBookRequest{
 		ISBN:        "222",
@@ -291,38 +716,255 @@ func TestUpdateBook_NotFound(t *testing.T) {
 func TestDeleteBook_Success(t *testing.T) {
 	ctx := context.Background()
 	mockedRepo := new(mockRepo)
-	db := &sqlx.DB{}
-	service := NewBookService(db, mockedRepo)
+	mockedEvents := new(mockOutbox)
+	db, sqlMock := newMockDB(t)
+	service := NewBookService(db, mockedRepo, mockedEvents)
 
 	bookID := "book-123"
-	mockedRepo.On("DeleteBook", ctx, bookID).Return(nil)
+	sqlMock.ExpectBegin()
+	mockedRepo.On("DeleteBook", ctx, mock.AnythingOfType("*sqlx.Tx"), bookID, mock.AnythingOfType("time.Time")).Return(nil)
+	mockedEvents.On("AppendBookEvent", ctx, mock.AnythingOfType("*sqlx.Tx"), models.BookEventDeleted, bookID, (*models.BookResponse)(nil)).Return(nil)
+	sqlMock.ExpectCommit()
+	mockedEvents.On("Notify").Return()
 
 	err := service.DeleteBook(ctx, bookID)
 
 	assert.NoError(t, err)
 	mockedRepo.AssertExpectations(t)
+	mockedEvents.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
 }
 
 func TestDeleteBook_NotFound(t *testing.T) {
 	ctx := context.Background()
 	mockedRepo := new(mockRepo)
-	db := &sqlx.DB{}
-	service := NewBookService(db, mockedRepo)
+	mockedEvents := new(mockOutbox)
+	db, sqlMock := newMockDB(t)
+	service := NewBookService(db, mockedRepo, mockedEvents)
 
 	bookID := "missing-book"
-	mockedRepo.On("DeleteBook", ctx, bookID).Return(utils.ErrNotFound)
+	sqlMock.ExpectBegin()
+	mockedRepo.On("DeleteBook", ctx, mock.AnythingOfType("*sqlx.Tx"), bookID, mock.AnythingOfType("time.Time")).Return(utils.ErrNotFound)
+	sqlMock.ExpectRollback()
 
 	err := service.DeleteBook(ctx, bookID)
 
 	assert.Equal(t, utils.ErrNotFound, err)
 	mockedRepo.AssertExpectations(t)
+	mockedEvents.AssertNotCalled(t, "AppendBookEvent", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything) // Nothing happened
+	mockedEvents.AssertNotCalled(t, "Notify")
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCheckoutBook_RecordsEvent(t *testing.T) {
+	ctx := context.Background()
+
+	mockedRepo := new(mockRepo)
+	mockedEvents := new(mockOutbox)
+	db, sqlMock := newMockDB(t)
+	service := NewBookService(db, mockedRepo, mockedEvents)
+
+	bookID := "book-123"
+	book := &models.Book{ID: bookID, Title: "Dune", Status: models.BookStatusAvailable}
+	mockedRepo.On("GetBookByID", ctx, bookID).Return(book, nil)
+	sqlMock.ExpectBegin()
+	mockedRepo.On("UpdateBookStatus", ctx, mock.AnythingOfType("*sqlx.Tx"), bookID, models.BookStatusAvailable, models.BookStatusCheckedOut, mock.AnythingOfType("time.Time")).Return(nil)
+	mockedRepo.On("AppendStatusChange", ctx, mock.AnythingOfType("*sqlx.Tx"), mock.MatchedBy(func(c *models.BookStatusChange) bool {
+		return c.BookID == bookID && c.Status == models.BookStatusCheckedOut && !c.Timestamp.IsZero() &&
+			c.Actor == "ana" && c.Source == models.StatusChangeSourceDesk && c.Note == "Reserved for Friday"
+	})).Return(nil)
+	mockedEvents.On("AppendBookEvent", ctx, mock.AnythingOfType("*sqlx.Tx"), models.BookEventCheckedOut, bookID, mock.MatchedBy(func(b *models.BookResponse) bool {
+		return b.Status == models.BookStatusCheckedOut // State after the change
+	})).Return(nil)
+	sqlMock.ExpectCommit()
+	mockedEvents.On("Notify").Return()
+
+	err := service.CheckoutBook(ctx, bookID, models.StatusChangeRequest{Actor: " ana ", Source: models.StatusChangeSourceDesk, Note: "Reserved for Friday "})
+
+	assert.NoError(t, err)
+	mockedRepo.AssertExpectations(t)
+	mockedEvents.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCheckoutBook_EventErrorRollsBack(t *testing.T) {
+	ctx := context.Background()
+
+	mockedRepo := new(mockRepo)
+	mockedEvents := new(mockOutbox)
+	db, sqlMock := newMockDB(t)
+	service := NewBookService(db, mockedRepo, mockedEvents)
+
+	bookID := "book-123"
+	mockedRepo.On("GetBookByID", ctx, bookID).Return(&models.Book{ID: bookID, Status: models.BookStatusAvailable}, nil)
+	sqlMock.ExpectBegin()
+	mockedRepo.On("UpdateBookStatus", ctx, mock.Anything, bookID, models.BookStatusAvailable, models.BookStatusCheckedOut, mock.Anything).Return(nil)
+	mockedRepo.On("AppendStatusChange", ctx, mock.Anything, mock.MatchedBy(func(c *models.BookStatusChange) bool {
+		return c.Source == models.StatusChangeSourceAPI // Default source
+	})).Return(nil)
+	mockedEvents.On("AppendBookEvent", ctx, mock.Anything, models.BookEventCheckedOut, bookID, mock.Anything).Return(assert.AnError)
+	sqlMock.ExpectRollback() // The change is not committed without its event
+
+	err := service.CheckoutBook(ctx, bookID, models.StatusChangeRequest{})
+
+	assert.Equal(t, assert.AnError, err)
+	mockedEvents.AssertNotCalled(t, "Notify")
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCheckoutBook_InvalidTransition(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockRepo)
+	mockedEvents := new(mockOutbox)
+	service := NewBookService(&sqlx.DB{}, mockedRepo, mockedEvents)
+
+	bookID := "book-123"
+	mockedRepo.On("GetBookByID", ctx, bookID).Return(&models.Book{ID: bookID, Status: models.BookStatusLost}, nil)
+
+	err := service.CheckoutBook(ctx, bookID, models.StatusChangeRequest{})
+
+	var transition *utils.TransitionError
+	assert.ErrorIs(t, err, utils.ErrInvalidTransition)
+	if assert.ErrorAs(t, err, &transition) {
+		assert.Equal(t, "lost", transition.From)
+		assert.Equal(t, []string{"available", "withdrawn"}, transition.Allowed)
+	}
+	mockedRepo.AssertNotCalled(t, "UpdateBookStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockedEvents.AssertNotCalled(t, "AppendBookEvent", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCheckoutBook_ConcurrentChangeConflicts(t *testing.T) {
+	ctx := context.Background()
+
+	mockedRepo := new(mockRepo)
+	mockedEvents := new(mockOutbox)
+	db, sqlMock := newMockDB(t)
+	service := NewBookService(db, mockedRepo, mockedEvents)
+
+	// Read as available, but marked lost by a concurrent request before the update
+	bookID := "book-123"
+	mockedRepo.On("GetBookByID", ctx, bookID).Return(&models.Book{ID: bookID, Status: models.BookStatusAvailable}, nil).Once()
+	sqlMock.ExpectBegin()
+	mockedRepo.On("UpdateBookStatus", ctx, mock.Anything, bookID, models.BookStatusAvailable, models.BookStatusCheckedOut, mock.Anything).Return(utils.ErrConflict)
+	sqlMock.ExpectRollback()
+	mockedRepo.On("GetBookByID", ctx, bookID).Return(&models.Book{ID: bookID, Status: models.BookStatusLost}, nil).Once()
+
+	err := service.CheckoutBook(ctx, bookID, models.StatusChangeRequest{})
+
+	var transition *utils.TransitionError
+	if assert.ErrorAs(t, err, &transition) { // Same error as when read lost
+		assert.Equal(t, "lost", transition.From)
+		assert.Equal(t, "checked_out", transition.To)
+		assert.Equal(t, []string{"available", "withdrawn"}, transition.Allowed)
+	}
+	mockedRepo.AssertNotCalled(t, "AppendStatusChange", mock.Anything, mock.Anything, mock.Anything)
+	mockedEvents.AssertNotCalled(t, "AppendBookEvent", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockedEvents.AssertNotCalled(t, "Notify")
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCheckoutBook_ConcurrentSameChange(t *testing.T) {
+	ctx := context.Background()
+
+	mockedRepo := new(mockRepo)
+	mockedEvents := new(mockOutbox)
+	db, sqlMock := newMockDB(t)
+	service := NewBookService(db, mockedRepo, mockedEvents)
+
+	// Double-submitted checkout: the other request checked it out before the update
+	bookID := "book-123"
+	mockedRepo.On("GetBookByID", ctx, bookID).Return(&models.Book{ID: bookID, Status: models.BookStatusAvailable}, nil).Once()
+	sqlMock.ExpectBegin()
+	mockedRepo.On("UpdateBookStatus", ctx, mock.Anything, bookID, models.BookStatusAvailable, models.BookStatusCheckedOut, mock.Anything).Return(utils.ErrConflict)
+	sqlMock.ExpectRollback()
+	mockedRepo.On("GetBookByID", ctx, bookID).Return(&models.Book{ID: bookID, Status: models.BookStatusCheckedOut}, nil).Once()
+
+	err := service.CheckoutBook(ctx, bookID, models.StatusChangeRequest{})
+
+	assert.NoError(t, err) // Same as when read checked out (no conflict)
+	mockedRepo.AssertNotCalled(t, "AppendStatusChange", mock.Anything, mock.Anything, mock.Anything)
+	mockedEvents.AssertNotCalled(t, "AppendBookEvent", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockedEvents.AssertNotCalled(t, "Notify")
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCheckinBook_OnlyFromCheckedOut(t *testing.T) {
+	for _, status := range []models.BookStatus{models.BookStatusLost, models.BookStatusInRepair} {
+		t.Run(string(status), func(t *testing.T) {
+			ctx := context.Background()
+			mockedRepo := new(mockRepo)
+			service := NewBookService(&sqlx.DB{}, mockedRepo, nopOutbox{})
+
+			// Available is allowed from these statuses, but they are not loans
+			mockedRepo.On("GetBookByID", ctx, "book-123").Return(&models.Book{ID: "book-123", Status: status}, nil)
+
+			err := service.CheckinBook(ctx, "book-123", models.StatusChangeRequest{})
+
+			var transition *utils.TransitionError
+			if assert.ErrorAs(t, err, &transition) { // 409 with the current status
+				assert.Equal(t, string(status), transition.From)
+			}
+			mockedRepo.AssertNotCalled(t, "UpdateBookStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestChangeBookStatus_RecordsInventoryEvent(t *testing.T) {
+	ctx := context.Background()
+
+	mockedRepo := new(mockRepo)
+	mockedEvents := new(mockOutbox)
+	db, sqlMock := newMockDB(t)
+	service := NewBookService(db, mockedRepo, mockedEvents)
+
+	bookID := "book-123"
+	mockedRepo.On("GetBookByID", ctx, bookID).Return(&models.Book{ID: bookID, Status: models.BookStatusCheckedOut}, nil)
+	sqlMock.ExpectBegin()
+	mockedRepo.On("UpdateBookStatus", ctx, mock.Anything, bookID, models.BookStatusCheckedOut, models.BookStatusLost, mock.Anything).Return(nil)
+	mockedRepo.On("AppendStatusChange", ctx, mock.Anything, mock.MatchedBy(func(c *models.BookStatusChange) bool {
+		return c.Status == models.BookStatusLost
+	})).Return(nil)
+	mockedEvents.On("AppendBookEvent", ctx, mock.Anything, models.BookEventInventoryChanged, bookID, mock.Anything).Return(nil)
+	sqlMock.ExpectCommit()
+	mockedEvents.On("Notify").Return()
+
+	err := service.ChangeBookStatus(ctx, bookID, models.BookStatusLost, models.StatusChangeRequest{})
+
+	assert.NoError(t, err)
+	mockedRepo.AssertExpectations(t)
+	mockedEvents.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestChangeBookStatus_UnknownStatus(t *testing.T) {
+	mockedRepo := new(mockRepo)
+	service := NewBookService(&sqlx.DB{}, mockedRepo, nopOutbox{})
+
+	err := service.ChangeBookStatus(context.Background(), "book-123", "stolen", models.StatusChangeRequest{})
+
+	assert.ErrorIs(t, err, utils.ErrBadRequest)
+	mockedRepo.AssertNotCalled(t, "GetBookByID", mock.Anything, mock.Anything)
+}
+
+func TestMarkBookFound_OnlyFromLostOrMissing(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockRepo)
+	service := NewBookService(&sqlx.DB{}, mockedRepo, nopOutbox{})
+
+	// In repair -> available is allowed, but it is not a found book
+	mockedRepo.On("GetBookByID", ctx, "book-123").Return(&models.Book{ID: "book-123", Status: models.BookStatusInRepair}, nil)
+
+	err := service.MarkBookFound(ctx, "book-123", models.StatusChangeRequest{})
+
+	assert.ErrorIs(t, err, utils.ErrInvalidTransition)
+	mockedRepo.AssertNotCalled(t, "UpdateBookStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestGetBookWithHistory_Success(t *testing.T) {
 	ctx := context.Background()
 	mockedRepo := new(mockRepo)
 	db := &sqlx.DB{}
-	service := NewBookService(db, mockedRepo)
+	service := NewBookService(db, mockedRepo, nopOutbox{})
 
 	bookID := "book-1"
 	book := &models.Book{
@@ -337,13 +979,50 @@ func TestGetBookWithHistory_Success(t *testing.T) {
 		{Status: models.BookStatusCheckedOut, Timestamp: time.Now()},
 	}
 
-	mockedRepo.On("GetBookWithHistory", ctx, bookID).Return(book, history, nil)
+	mockedRepo.On("GetBookWithHistory", ctx, bookID, models.EmbeddedHistoryLimit+1).Return(book, history, nil)
 
 	result, err := service.GetBookWithHistory(ctx, bookID)
 
 	assert.NoError(t, err)
 	assert.Equal(t, book.ID, result.Book.ID)
 	assert.Len(t, result.History, 2)
+	assert.False(t, result.HistoryTruncated)
+	mockedRepo.AssertExpectations(t)
+}
+
+func TestGetBookWithHistory_Truncated(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockRepo)
+	service := NewBookService(&sqlx.DB{}, mockedRepo, nopOutbox{})
+
+	// One change over the limit
+	history := make([]models.BookStatusChange, models.EmbeddedHistoryLimit+1)
+	mockedRepo.On("GetBookWithHistory", ctx, "book-1", models.EmbeddedHistoryLimit+1).Return(&models.Book{ID: "book-1"}, history, nil)
+
+	result, err := service.GetBookWithHistory(ctx, "book-1")
+
+	assert.NoError(t, err)
+	assert.Len(t, result.History, models.EmbeddedHistoryLimit)
+	assert.True(t, result.HistoryTruncated)
+}
+
+func TestGetBooksHistory_GroupsByBook(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockRepo)
+	service := NewBookService(&sqlx.DB{}, mockedRepo, nopOutbox{})
+
+	ids := []string{"book-1", "book-2"}
+	mockedRepo.On("ListHistoryByBookIDs", ctx, ids, models.EmbeddedHistoryLimit).Return([]models.BookStatusChange{
+		{BookID: "book-1", Status: models.BookStatusAvailable, Timestamp: time.Now()},
+		{BookID: "book-1", Status: models.BookStatusCheckedOut, Timestamp: time.Now()},
+	}, nil)
+
+	result, err := service.GetBooksHistory(ctx, ids)
+
+	assert.NoError(t, err)
+	assert.Len(t, result["book-1"], 2)
+	assert.NotNil(t, result["book-2"]) // Books without changes get an empty history
+	assert.Empty(t, result["book-2"])
 	mockedRepo.AssertExpectations(t)
 }
 
@@ -351,10 +1030,10 @@ func TestGetBookWithHistory_NotFound(t *testing.T) {
 	ctx := context.Background()
 	mockedRepo := new(mockRepo)
 	db := &sqlx.DB{}
-	service := NewBookService(db, mockedRepo)
+	service := NewBookService(db, mockedRepo, nopOutbox{})
 
 	bookID := "missing"
-	mockedRepo.On("GetBookWithHistory", ctx, bookID).Return(nil, []models.BookStatusChange(nil), utils.ErrNotFound)
+	mockedRepo.On("GetBookWithHistory", ctx, bookID, models.EmbeddedHistoryLimit+1).Return(nil, []models.BookStatusChange(nil), utils.ErrNotFound)
 
 	result, err := service.GetBookWithHistory(ctx, bookID)
 
@@ -362,3 +1041,48 @@ func TestGetBookWithHistory_NotFound(t *testing.T) {
 	assert.Equal(t, utils.ErrNotFound, err)
 	mockedRepo.AssertExpectations(t)
 }
+
+func TestListBookHistory_Success(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockRepo)
+	service := NewBookService(&sqlx.DB{}, mockedRepo, nopOutbox{})
+
+	bookID := "book-123"
+	now := time.Now()
+	req := models.ListHistoryRequest{Page: 2, PageSize: 2, From: now.Add(-48 * time.Hour), To: now}
+	mockedRepo.On("GetBookByID", ctx, bookID).Return(&models.Book{ID: bookID}, nil)
+	mockedRepo.On("ListHistory", ctx, bookID, req).Return([]models.BookStatusChange{
+		{BookID: bookID, Status: models.BookStatusAvailable, Timestamp: now, Actor: "kiosk-3", Source: models.StatusChangeSourceKiosk},
+		{BookID: bookID, Status: models.BookStatusCheckedOut, Timestamp: now.Add(-time.Hour), Note: "Reserved"},
+	}, 5, nil)
+
+	result, err := service.ListBookHistory(ctx, bookID, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, result.TotalItems)
+	assert.Equal(t, 3, result.TotalPages)
+	assert.Equal(t, 2, result.CurrentPage)
+	if assert.Len(t, result.History, 2) {
+		assert.Equal(t, "kiosk-3", result.History[0].Actor)
+		assert.Equal(t, models.StatusChangeSourceKiosk, result.History[0].Source)
+		assert.Equal(t, "Reserved", result.History[1].Note)
+	}
+	mockedRepo.AssertExpectations(t)
+}
+
+func TestListBookHistory_Invalid(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockRepo)
+	service := NewBookService(&sqlx.DB{}, mockedRepo, nopOutbox{})
+
+	// Empty window
+	now := time.Now()
+	_, err := service.ListBookHistory(ctx, "book-123", models.ListHistoryRequest{Page: 1, PageSize: 10, From: now, To: now})
+	assert.ErrorIs(t, err, utils.ErrBadRequest)
+
+	// Unknown book
+	mockedRepo.On("GetBookByID", ctx, "missing").Return((*models.Book)(nil), utils.ErrNotFound)
+	_, err = service.ListBookHistory(ctx, "missing", models.ListHistoryRequest{Page: 1, PageSize: 10})
+	assert.ErrorIs(t, err, utils.ErrNotFound)
+	mockedRepo.AssertNotCalled(t, "ListHistory", mock.Anything, mock.Anything, mock.Anything)
+}
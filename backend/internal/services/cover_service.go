@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/santiago-buildit/code-challenge/backend/internal/database"
+	"github.com/santiago-buildit/code-challenge/backend/internal/i18n"
+	"github.com/santiago-buildit/code-challenge/backend/internal/imaging"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/repositories"
+	"github.com/santiago-buildit/code-challenge/backend/internal/storage"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+)
+
+// Lifetime of the signed cover download URLs (stores implementing storage.URLSigner)
+const coverURLTTL = 15 * time.Minute
+
+// CoverService defines the interface for book cover image operations
+type CoverService interface {
+	UploadCover(ctx context.Context, id string, data []byte) (*models.BookResponse, error)
+	GetCover(ctx context.Context, id string, size string) (*models.Cover, error)
+}
+
+type coverServiceImpl struct {
+	db     *sqlx.DB
+	repo   repositories.BookRepository
+	store  storage.BlobStore
+	events BookEventOutbox
+}
+
+func NewCoverService(db *sqlx.DB, repo repositories.BookRepository, store storage.BlobStore, events BookEventOutbox) CoverService {
+	return &coverServiceImpl{
+		db:     db,
+		repo:   repo,
+		store:  store,
+		events: events,
+	}
+}
+
+func (s *coverServiceImpl) UploadCover(ctx context.Context, id string, data []byte) (*models.BookResponse, error) {
+
+	// Check size
+	if len(data) > models.MaxCoverSize {
+		return nil, i18n.NewError(utils.ErrBadRequest, i18n.ErrorCoverTooLarge, i18n.Params{"max": strconv.Itoa(models.MaxCoverSize)})
+	}
+
+	// Get with repository (not found otherwise)
+	book, err := s.repo.GetBookByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sniff content type and decode (the client declared type is not trusted)
+	contentType, err := imaging.DetectContentType(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", utils.ErrUnsupportedMediaType, err)
+	}
+	img, err := imaging.Decode(data)
+	if errors.Is(err, imaging.ErrTooLarge) {
+		return nil, i18n.NewError(utils.ErrBadRequest, i18n.ErrorImageTooLarge, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", utils.ErrUnsupportedMediaType, err)
+	}
+
+	// Generate thumbnails (before storing anything, so a failure leaves the previous cover intact)
+	thumbnails := make(map[string][]byte, len(models.CoverThumbnails))
+	for _, thumbnail := range models.CoverThumbnails {
+		if thumbnails[thumbnail.Size], err = imaging.Thumbnail(img, thumbnail.Width); err != nil {
+			return nil, err
+		}
+	}
+
+	// Store original and thumbnails
+	if err := s.store.Put(ctx, models.CoverKey(id, models.CoverSizeOriginal), data, contentType); err != nil {
+		return nil, err
+	}
+	for _, thumbnail := range models.CoverThumbnails {
+		if err := s.store.Put(ctx, models.CoverKey(id, thumbnail.Size), thumbnails[thumbnail.Size], "image/jpeg"); err != nil {
+			return nil, err
+		}
+	}
+
+	// Transactional block (upload and event)
+	now := time.Now()
+	var res *models.BookResponse
+	err = database.WithTransaction(ctx, s.db, func(tx *sqlx.Tx) error {
+
+		// Record upload with repository
+		if err := s.repo.SetBookCover(ctx, tx, id, now); err != nil {
+			return err
+		}
+		book.CoverUpdatedAt = &now
+		book.UpdatedAt = now
+
+		// Map response and record event (the cover URL changes)
+		res = models.ToBookResponse(book)
+		return s.events.AppendBookEvent(ctx, tx, models.BookEventUpdated, id, res)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.events.Notify()
+
+	return res, nil
+}
+
+func (s *coverServiceImpl) GetCover(ctx context.Context, id string, size string) (*models.Cover, error) {
+
+	// Validate size
+	if size == "" {
+		size = models.CoverSizeOriginal
+	}
+	if !models.IsCoverSize(size) {
+		return nil, i18n.NewError(utils.ErrBadRequest, i18n.ErrorUnknownCoverSize, i18n.Params{"size": size})
+	}
+
+	// Get with repository (deleted books have no cover)
+	book, err := s.repo.GetBookByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if book.CoverUpdatedAt == nil {
+		return nil, utils.ErrNotFound
+	}
+
+	// Sign a download URL when the store supports it (the client fetches the image from the store)
+	key := models.CoverKey(id, size)
+	if signer, ok := s.store.(storage.URLSigner); ok {
+		url, err := signer.SignedURL(ctx, key, coverURLTTL)
+		if err != nil {
+			return nil, err
+		}
+		return &models.Cover{URL: url, URLExpiresIn: coverURLTTL}, nil
+	}
+
+	// Get from blob store otherwise
+	blob, err := s.store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Cover{Data: blob.Data, ContentType: blob.ContentType, LastModified: blob.LastModified}, nil
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/storage"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// --- Fake definition ---
+
+// memoryBlobStore is an in-memory BlobStore
+type memoryBlobStore struct {
+	blobs map[string]storage.Blob
+}
+
+func newMemoryBlobStore() *memoryBlobStore {
+	return &memoryBlobStore{blobs: map[string]storage.Blob{}}
+}
+
+func (s *memoryBlobStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	s.blobs[key] = storage.Blob{Data: data, ContentType: contentType, LastModified: time.Now()}
+	return nil
+}
+
+func (s *memoryBlobStore) Get(ctx context.Context, key string) (*storage.Blob, error) {
+	blob, ok := s.blobs[key]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	return &blob, nil
+}
+
+func (s *memoryBlobStore) Delete(ctx context.Context, key string) error {
+	delete(s.blobs, key)
+	return nil
+}
+
+// signingBlobStore is an in-memory BlobStore that also signs download URLs
+type signingBlobStore struct {
+	*memoryBlobStore
+}
+
+func (s signingBlobStore) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return "https://blobs.example.com/" + key + "?expires=" + ttl.String(), nil
+}
+
+func pngImage(t *testing.T, width, height int) []byte {
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))))
+	return buf.Bytes()
+}
+
+// --- Test ---
+
+func TestUploadCover_Success(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockRepo)
+	mockedEvents := new(mockOutbox)
+	db, sqlMock := newMockDB(t)
+	store := newMemoryBlobStore()
+	service := NewCoverService(db, mockedRepo, store, mockedEvents)
+
+	bookID := "book-1"
+	data := pngImage(t, 600, 900)
+
+	mockedRepo.On("GetBookByID", ctx, bookID).Return(&models.Book{ID: bookID, Title: "Ficciones"}, nil)
+	sqlMock.ExpectBegin()
+	mockedRepo.On("SetBookCover", ctx, mock.AnythingOfType("*sqlx.Tx"), bookID, mock.AnythingOfType("time.Time")).Return(nil)
+	mockedEvents.On("AppendBookEvent", ctx, mock.AnythingOfType("*sqlx.Tx"), models.BookEventUpdated, bookID,
+		mock.MatchedBy(func(book *models.BookResponse) bool { return book.CoverURL != "" })).Return(nil)
+	sqlMock.ExpectCommit()
+	mockedEvents.On("Notify").Return()
+
+	resp, err := service.UploadCover(ctx, bookID, data)
+
+	assert.NoError(t, err)
+	assert.Regexp(t, `^/books/book-1/cover\?v=\d+$`, resp.CoverURL)
+
+	// Original as is, thumbnails as JPEG with the configured widths
+	assert.Equal(t, data, store.blobs["covers/book-1/original"].Data)
+	assert.Equal(t, "image/png", store.blobs["covers/book-1/original"].ContentType)
+	for _, thumbnail := range models.CoverThumbnails {
+		blob := store.blobs[models.CoverKey(bookID, thumbnail.Size)]
+		config, format, err := image.DecodeConfig(bytes.NewReader(blob.Data))
+		assert.NoError(t, err)
+		assert.Equal(t, "jpeg", format)
+		assert.Equal(t, thumbnail.Width, config.Width)
+	}
+	mockedRepo.AssertExpectations(t)
+	mockedEvents.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestUploadCover_DeletedMeanwhile(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockRepo)
+	mockedEvents := new(mockOutbox)
+	db, sqlMock := newMockDB(t)
+	service := NewCoverService(db, mockedRepo, newMemoryBlobStore(), mockedEvents)
+
+	mockedRepo.On("GetBookByID", ctx, "book-1").Return(&models.Book{ID: "book-1"}, nil)
+	sqlMock.ExpectBegin()
+	mockedRepo.On("SetBookCover", ctx, mock.AnythingOfType("*sqlx.Tx"), "book-1", mock.AnythingOfType("time.Time")).Return(utils.ErrNotFound)
+	sqlMock.ExpectRollback()
+
+	_, err := service.UploadCover(ctx, "book-1", pngImage(t, 10, 10))
+
+	assert.ErrorIs(t, err, utils.ErrNotFound)
+	mockedEvents.AssertNotCalled(t, "AppendBookEvent", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything) // No event
+	mockedEvents.AssertNotCalled(t, "Notify")
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestUploadCover_UnsupportedType(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockRepo)
+	store := newMemoryBlobStore()
+	service := NewCoverService(nil, mockedRepo, store, nopOutbox{})
+
+	mockedRepo.On("GetBookByID", ctx, "book-1").Return(&models.Book{ID: "book-1"}, nil)
+
+	resp, err := service.UploadCover(ctx, "book-1", []byte("%PDF-1.7"))
+
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, utils.ErrUnsupportedMediaType)
+	assert.Empty(t, store.blobs)
+	mockedRepo.AssertNotCalled(t, "SetBookCover", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUploadCover_BookNotFound(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockRepo)
+	store := newMemoryBlobStore()
+	service := NewCoverService(nil, mockedRepo, store, nopOutbox{})
+
+	mockedRepo.On("GetBookByID", ctx, "missing").Return((*models.Book)(nil), utils.ErrNotFound)
+
+	_, err := service.UploadCover(ctx, "missing", pngImage(t, 10, 10))
+
+	assert.ErrorIs(t, err, utils.ErrNotFound)
+	assert.Empty(t, store.blobs)
+}
+
+func TestGetCover(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockRepo)
+	store := newMemoryBlobStore()
+	service := NewCoverService(nil, mockedRepo, store, nopOutbox{})
+
+	uploadedAt := time.Now()
+	_ = store.Put(ctx, "covers/book-1/small.jpg", []byte("jpeg"), "image/jpeg")
+	mockedRepo.On("GetBookByID", ctx, "book-1").Return(&models.Book{ID: "book-1", CoverUpdatedAt: &uploadedAt}, nil)
+	mockedRepo.On("GetBookByID", ctx, "book-2").Return(&models.Book{ID: "book-2"}, nil) // No cover
+
+	cover, err := service.GetCover(ctx, "book-1", "small")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("jpeg"), cover.Data)
+	assert.Empty(t, cover.URL)
+
+	_, err = service.GetCover(ctx, "book-1", "huge")
+	assert.ErrorIs(t, err, utils.ErrBadRequest)
+
+	_, err = service.GetCover(ctx, "book-2", "")
+	assert.ErrorIs(t, err, utils.ErrNotFound)
+}
+
+func TestGetCover_SignedURL(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockRepo)
+	store := signingBlobStore{newMemoryBlobStore()}
+	service := NewCoverService(nil, mockedRepo, store, nopOutbox{})
+
+	uploadedAt := time.Now()
+	mockedRepo.On("GetBookByID", ctx, "book-1").Return(&models.Book{ID: "book-1", CoverUpdatedAt: &uploadedAt}, nil)
+
+	cover, err := service.GetCover(ctx, "book-1", "medium")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://blobs.example.com/covers/book-1/medium.jpg?expires=15m0s", cover.URL)
+	assert.Equal(t, 15*time.Minute, cover.URLExpiresIn)
+	assert.Nil(t, cover.Data) // Not downloaded through the API
+}
@@ -0,0 +1,308 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/repositories"
+	"go.uber.org/zap"
+)
+
+// Stream policy
+const (
+	streamBufferSize   = 64               // Live events queued per subscriber before it is dropped as too slow
+	streamPageSize     = 500              // Events read per query on replay and catch-up
+	streamNotifyBatch  = 100              // Notifications coalesced into one query
+	streamPingInterval = 90 * time.Second // Listener connection health check
+	streamCommitGrace  = time.Minute      // Longest write transaction: events below a position committed up to this late are re-read
+)
+
+// EventListener is the source of committed outbox event notifications (Postgres LISTEN, e.g. *pq.Listener).
+// Each notification carries the event seq; nil is sent after the connection was re-established.
+type EventListener interface {
+	NotificationChannel() <-chan *pq.Notification
+	Ping() error
+}
+
+type EventStreamService interface {
+	Subscribe(ctx context.Context, req *models.BookEventStreamRequest) (*BookEventSubscription, error)
+}
+
+// BookEventSubscription is a stream client: the events missed since its Last-Event-ID, then the live ones
+type BookEventSubscription struct {
+	Replay   []models.OutboxEvent       // Matching events after Last-Event-ID (oldest first, may overlap Events)
+	Position int64                      // Stream position after the replay (the client resumes from here without newer events)
+	Events   <-chan *models.OutboxEvent // Matching live events, closed when the subscriber is dropped or the stream stops
+
+	events chan *models.OutboxEvent
+	filter *models.BookEventStreamRequest
+	stream *BookEventStream
+}
+
+// Close stops the subscription (safe to call more than once)
+func (s *BookEventSubscription) Close() {
+	if s.stream != nil {
+		s.stream.unsubscribe(s)
+	}
+}
+
+// BookEventStream broadcasts committed book events to live subscribers. Every server instance listens
+// to the outbox notifications, so all of them stream the same events whichever instance made the change.
+type BookEventStream struct {
+	repo        repositories.OutboxRepository
+	listener    EventListener
+	logger      *zap.Logger
+	mu          sync.Mutex
+	subscribers map[*BookEventSubscription]struct{}
+	lastSeq     int64               // Highest seq broadcast (catch-up position after a reconnection)
+	broadcasted map[int64]time.Time // Recently broadcast seqs (not repeated on catch-up)
+}
+
+func NewBookEventStream(repo repositories.OutboxRepository, listener EventListener, logger *zap.Logger) *BookEventStream {
+	return &BookEventStream{
+		repo:        repo,
+		listener:    listener,
+		logger:      logger,
+		subscribers: map[*BookEventSubscription]struct{}{},
+		broadcasted: map[int64]time.Time{},
+	}
+}
+
+// Start broadcasts notified events until the context is cancelled (run in its own goroutine)
+func (s *BookEventStream) Start(ctx context.Context) {
+	ticker := time.NewTicker(streamPingInterval)
+	defer ticker.Stop()
+	defer s.closeAll()
+
+	// Start from the latest committed event
+	seq, err := s.repo.GetLatestEventSeq(ctx)
+	if err != nil {
+		s.logger.Error("Failed to get latest event position", zap.Error(err))
+	}
+	s.setLastSeq(seq)
+
+	notifications := s.listener.NotificationChannel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.listener.Ping(); err != nil {
+				s.logger.Warn("Event listener ping failed", zap.Error(err))
+			}
+			s.forgetBroadcasted(time.Now().Add(-2 * streamCommitGrace))
+		case notification, ok := <-notifications:
+			if !ok {
+				return
+			}
+
+			// Coalesce queued notifications and publish
+			reconnected := notification == nil
+			if !reconnected {
+				var seqs []int64
+				seqs, reconnected = drainSeqs(notification, notifications, streamNotifyBatch)
+				if err := s.publish(ctx, seqs); err != nil && ctx.Err() == nil {
+					s.logger.Error("Failed to publish stream events", zap.Error(err))
+				}
+			}
+
+			// Reconnected: notifications may have been lost meanwhile
+			if reconnected {
+				if err := s.catchUp(ctx); err != nil && ctx.Err() == nil {
+					s.logger.Error("Failed to catch up event stream", zap.Error(err))
+				}
+			}
+		}
+	}
+}
+
+func (s *BookEventStream) Subscribe(ctx context.Context, req *models.BookEventStreamRequest) (*BookEventSubscription, error) {
+
+	// Register first, so events committed while replaying are not missed (the client skips duplicates)
+	events := make(chan *models.OutboxEvent, streamBufferSize)
+	sub := &BookEventSubscription{
+		Replay: []models.OutboxEvent{},
+		Events: events,
+		events: events,
+		filter: req,
+		stream: s,
+	}
+	s.mu.Lock()
+	s.subscribers[sub] = struct{}{}
+	sub.Position = s.lastSeq
+	s.mu.Unlock()
+
+	// Replay with repository (only when resuming; retained events only). Seqs follow insert order, so the
+	// events just below Last-Event-ID that may have committed after it are replayed too (the client skips the ones seen)
+	if req.LastEventID == nil {
+		return sub, nil
+	}
+	lastEventID := *req.LastEventID
+	seq, err := s.repo.GetResumeSeq(ctx, lastEventID, streamCommitGrace)
+	if err != nil {
+		sub.Close()
+		return nil, err
+	}
+	defer func() { sub.Position = max(sub.Position, lastEventID, seq) }()
+	for {
+		page, err := s.repo.ListEventsAfter(ctx, seq, streamPageSize)
+		if err != nil {
+			sub.Close()
+			return nil, err
+		}
+		for i := range page {
+			if page[i].Seq != lastEventID && req.Matches(&page[i]) {
+				sub.Replay = append(sub.Replay, page[i])
+			}
+			seq = page[i].Seq
+		}
+
+		// Last page
+		if len(page) < streamPageSize {
+			return sub, nil
+		}
+	}
+}
+
+/* Helper functions */
+
+// publish loads the notified events and broadcasts them
+func (s *BookEventStream) publish(ctx context.Context, seqs []int64) error {
+	events, err := s.repo.ListEventsBySeq(ctx, seqs)
+	if err != nil {
+		return err
+	}
+	for i := range events {
+		s.broadcast(&events[i])
+	}
+	return nil
+}
+
+// catchUp broadcasts the events committed since the last one broadcast: the ones after it, and the ones
+// below it that committed later (within the commit grace period), skipping those already broadcast
+func (s *BookEventStream) catchUp(ctx context.Context) error {
+	s.mu.Lock()
+	lastSeq := s.lastSeq
+	s.mu.Unlock()
+
+	seq, err := s.repo.GetResumeSeq(ctx, lastSeq, streamCommitGrace)
+	if err != nil {
+		return err
+	}
+	for {
+		events, err := s.repo.ListEventsAfter(ctx, seq, streamPageSize)
+		if err != nil {
+			return err
+		}
+		for i := range events {
+			if !s.wasBroadcast(events[i].Seq) {
+				s.broadcast(&events[i])
+			}
+			seq = events[i].Seq
+		}
+
+		// Last page
+		if len(events) < streamPageSize {
+			return nil
+		}
+	}
+}
+
+// broadcast queues the event to every matching subscriber, dropping the ones that fall behind
+// (their stream ends and the client resumes from its Last-Event-ID)
+func (s *BookEventStream) broadcast(event *models.OutboxEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastSeq = max(s.lastSeq, event.Seq)
+	s.broadcasted[event.Seq] = time.Now()
+	for sub := range s.subscribers {
+		if !sub.filter.Matches(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			s.logger.Warn("Dropping slow event stream subscriber", zap.Int64("seq", event.Seq))
+			delete(s.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+func (s *BookEventStream) unsubscribe(sub *BookEventSubscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subscribers[sub]; ok {
+		delete(s.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+func (s *BookEventStream) closeAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sub := range s.subscribers {
+		delete(s.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+func (s *BookEventStream) wasBroadcast(seq int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.broadcasted[seq]
+	return ok
+}
+
+// forgetBroadcasted drops the seqs broadcast before a time (events committed this late are not expected)
+func (s *BookEventStream) forgetBroadcasted(before time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for seq, at := range s.broadcasted {
+		if at.Before(before) {
+			delete(s.broadcasted, seq)
+		}
+	}
+}
+
+func (s *BookEventStream) setLastSeq(seq int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastSeq = max(s.lastSeq, seq)
+}
+
+// drainSeqs returns the seq of the first notification plus the ones already queued (up to limit),
+// and whether a reconnection was notified meanwhile
+func drainSeqs(first *pq.Notification, queued <-chan *pq.Notification, limit int) (seqs []int64, reconnected bool) {
+	notifications := []*pq.Notification{first}
+	for len(notifications) < limit && !reconnected {
+		select {
+		case n, ok := <-queued:
+			if !ok || n == nil {
+				reconnected = true
+			} else {
+				notifications = append(notifications, n)
+			}
+		default:
+			return toSeqs(notifications), false
+		}
+	}
+	return toSeqs(notifications), reconnected
+}
+
+func toSeqs(notifications []*pq.Notification) []int64 {
+	seqs := make([]int64, 0, len(notifications))
+	for _, n := range notifications {
+		if seq, err := strconv.ParseInt(n.Extra, 10, 64); err == nil {
+			seqs = append(seqs, seq)
+		}
+	}
+	return seqs
+}
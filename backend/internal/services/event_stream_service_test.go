@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+// --- Mock definition ---
+
+// fakeListener feeds notifications to the stream
+type fakeListener struct {
+	notifications chan *pq.Notification
+}
+
+func (l *fakeListener) NotificationChannel() <-chan *pq.Notification {
+	return l.notifications
+}
+
+func (l *fakeListener) Ping() error {
+	return nil
+}
+
+// --- Test ---
+
+func TestBookEventStream_BroadcastsMatchingEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mockedRepo := new(mockOutboxRepo)
+	listener := &fakeListener{notifications: make(chan *pq.Notification)}
+	stream := NewBookEventStream(mockedRepo, listener, zaptest.NewLogger(t))
+
+	mockedRepo.On("GetLatestEventSeq", mock.Anything).Return(int64(10), nil)
+	mockedRepo.On("ListEventsBySeq", mock.Anything, []int64{11}).Return([]models.OutboxEvent{
+		{Seq: 11, EventType: "book.updated", AggregateType: models.AggregateBook, AggregateID: "book-2"},
+	}, nil)
+	mockedRepo.On("ListEventsBySeq", mock.Anything, []int64{12}).Return([]models.OutboxEvent{
+		{Seq: 12, EventType: "book.checked_out", AggregateType: models.AggregateBook, AggregateID: "book-1"},
+	}, nil)
+
+	req := &models.BookEventStreamRequest{BookID: "book-1", Types: []string{string(models.BookEventStatusChanged)}}
+	req.Sanitize()
+	sub, err := stream.Subscribe(ctx, req)
+	assert.NoError(t, err)
+	defer sub.Close()
+	go stream.Start(ctx)
+
+	listener.notifications <- &pq.Notification{Extra: "11"} // Other book, filtered out
+	listener.notifications <- &pq.Notification{Extra: "12"}
+
+	select {
+	case event := <-sub.Events:
+		assert.Equal(t, int64(12), event.Seq)
+	case <-time.After(time.Second):
+		t.Fatal("event not received")
+	}
+}
+
+func TestBookEventStream_SubscribeReplaysAfterLastEventID(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockOutboxRepo)
+	stream := NewBookEventStream(mockedRepo, &fakeListener{}, zaptest.NewLogger(t))
+
+	lastEventID := int64(5)
+	mockedRepo.On("GetResumeSeq", ctx, int64(5), streamCommitGrace).Return(int64(5), nil)
+	mockedRepo.On("ListEventsAfter", ctx, int64(5), streamPageSize).Return([]models.OutboxEvent{
+		{Seq: 6, EventType: "book.created", AggregateType: models.AggregateBook, AggregateID: "book-1"},
+		{Seq: 8, EventType: "book.deleted", AggregateType: models.AggregateBook, AggregateID: "book-1"},
+	}, nil)
+
+	sub, err := stream.Subscribe(ctx, &models.BookEventStreamRequest{Types: []string{"book.deleted"}, LastEventID: &lastEventID})
+
+	assert.NoError(t, err)
+	defer sub.Close()
+	if assert.Len(t, sub.Replay, 1) {
+		assert.Equal(t, int64(8), sub.Replay[0].Seq)
+	}
+	assert.Equal(t, int64(8), sub.Position)
+}
+
+func TestBookEventStream_SubscribeReplaysEventsCommittedOutOfOrder(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockOutboxRepo)
+	stream := NewBookEventStream(mockedRepo, &fakeListener{}, zaptest.NewLogger(t))
+
+	// Event 11 was inserted before 12 but committed after the client received 12
+	lastEventID := int64(12)
+	mockedRepo.On("GetResumeSeq", ctx, int64(12), streamCommitGrace).Return(int64(10), nil)
+	mockedRepo.On("ListEventsAfter", ctx, int64(10), streamPageSize).Return([]models.OutboxEvent{
+		{Seq: 11, EventType: "book.updated", AggregateType: models.AggregateBook, AggregateID: "book-1"},
+		{Seq: 12, EventType: "book.updated", AggregateType: models.AggregateBook, AggregateID: "book-1"},
+	}, nil)
+
+	sub, err := stream.Subscribe(ctx, &models.BookEventStreamRequest{LastEventID: &lastEventID})
+
+	assert.NoError(t, err)
+	defer sub.Close()
+	if assert.Len(t, sub.Replay, 1) {
+		assert.Equal(t, int64(11), sub.Replay[0].Seq)
+	}
+	assert.Equal(t, int64(12), sub.Position)
+}
+
+func TestBookEventStream_CatchUpBroadcastsEventsCommittedOutOfOrder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mockedRepo := new(mockOutboxRepo)
+	listener := &fakeListener{notifications: make(chan *pq.Notification)}
+	stream := NewBookEventStream(mockedRepo, listener, zaptest.NewLogger(t))
+
+	mockedRepo.On("GetLatestEventSeq", mock.Anything).Return(int64(10), nil)
+	mockedRepo.On("ListEventsBySeq", mock.Anything, []int64{12}).Return([]models.OutboxEvent{
+		{Seq: 12, EventType: "book.updated", AggregateType: models.AggregateBook, AggregateID: "book-1"},
+	}, nil)
+
+	// Event 11 was inserted before 12 but committed after it, while the listener was reconnecting
+	mockedRepo.On("GetResumeSeq", mock.Anything, int64(12), streamCommitGrace).Return(int64(10), nil)
+	mockedRepo.On("ListEventsAfter", mock.Anything, int64(10), streamPageSize).Return([]models.OutboxEvent{
+		{Seq: 11, EventType: "book.updated", AggregateType: models.AggregateBook, AggregateID: "book-1"},
+		{Seq: 12, EventType: "book.updated", AggregateType: models.AggregateBook, AggregateID: "book-1"},
+	}, nil)
+
+	sub, err := stream.Subscribe(ctx, &models.BookEventStreamRequest{})
+	assert.NoError(t, err)
+	defer sub.Close()
+	go stream.Start(ctx)
+
+	listener.notifications <- &pq.Notification{Extra: "12"}
+	listener.notifications <- nil // Reconnected
+
+	// 12 once (not repeated by the catch-up), then 11
+	var seqs []int64
+	for len(seqs) < 2 {
+		select {
+		case event := <-sub.Events:
+			seqs = append(seqs, event.Seq)
+		case <-time.After(time.Second):
+			t.Fatalf("events not received (got %v)", seqs)
+		}
+	}
+	assert.Equal(t, []int64{12, 11}, seqs)
+	select {
+	case event := <-sub.Events:
+		t.Fatalf("unexpected event %d", event.Seq)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestBookEventStream_DropsSlowSubscriber(t *testing.T) {
+	stream := NewBookEventStream(new(mockOutboxRepo), &fakeListener{}, zaptest.NewLogger(t))
+
+	sub, err := stream.Subscribe(context.Background(), &models.BookEventStreamRequest{})
+	assert.NoError(t, err)
+
+	for i := 1; i <= streamBufferSize+1; i++ {
+		stream.broadcast(&models.OutboxEvent{Seq: int64(i), AggregateType: models.AggregateBook})
+	}
+
+	// Buffered events are still readable, then the channel is closed
+	received := 0
+	for range sub.Events {
+		received++
+	}
+	assert.Equal(t, streamBufferSize, received)
+	sub.Close() // No-op once dropped
+}
+
+func TestDrainSeqs(t *testing.T) {
+	queued := make(chan *pq.Notification, 3)
+	queued <- &pq.Notification{Extra: "2"}
+	queued <- nil
+	queued <- &pq.Notification{Extra: "3"}
+
+	seqs, reconnected := drainSeqs(&pq.Notification{Extra: "1"}, queued, 10)
+
+	assert.Equal(t, []int64{1, 2}, seqs)
+	assert.True(t, reconnected)
+	assert.Len(t, queued, 1)
+}
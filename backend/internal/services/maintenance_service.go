@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/santiago-buildit/code-challenge/backend/internal/database"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/repositories"
+)
+
+// MaintenanceService defines the interface for operational book repairs (admin CLI).
+// With dryRun, operations only report what they would change.
+type MaintenanceService interface {
+	RestoreBook(ctx context.Context, id string, dryRun bool) (*models.BookResponse, error)
+	RecomputeBookStatuses(ctx context.Context, ids []string, dryRun bool) ([]models.BookStatusDrift, error) // All books when no IDs are given
+}
+
+type maintenanceServiceImpl struct {
+	db     *sqlx.DB
+	repo   repositories.BookRepository
+	events BookEventOutbox
+}
+
+func NewMaintenanceService(db *sqlx.DB, repo repositories.BookRepository, events BookEventOutbox) MaintenanceService {
+	return &maintenanceServiceImpl{
+		db:     db,
+		repo:   repo,
+		events: events,
+	}
+}
+
+// RestoreBook undoes the logical delete of a book
+func (s *maintenanceServiceImpl) RestoreBook(ctx context.Context, id string, dryRun bool) (*models.BookResponse, error) {
+
+	// Get with repository (deleted books only)
+	book, err := s.repo.GetDeletedBookByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	book.Deleted = false
+	if dryRun {
+		return models.ToBookResponse(book), nil
+	}
+	book.UpdatedAt = time.Now()
+
+	// Transactional block (book and event)
+	res := models.ToBookResponse(book)
+	err = database.WithTransaction(ctx, s.db, func(tx *sqlx.Tx) error {
+
+		// Restore with repository
+		if err := s.repo.RestoreBook(ctx, tx, id, book.UpdatedAt); err != nil {
+			return err
+		}
+
+		// Record event (full state, so consumers that dropped the book add it back)
+		return s.events.AppendBookEvent(ctx, tx, models.BookEventUpdated, id, res)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.events.Notify()
+
+	return res, nil
+}
+
+// RecomputeBookStatuses sets the status of the books that drifted from their history to their latest status change.
+// History is not appended (the status is corrected, not changed).
+func (s *maintenanceServiceImpl) RecomputeBookStatuses(ctx context.Context, ids []string, dryRun bool) ([]models.BookStatusDrift, error) {
+
+	// List with repository
+	drift, err := s.repo.ListStatusDrift(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun || len(drift) == 0 {
+		return drift, nil
+	}
+
+	// Fix each book in its own transaction (status and event)
+	for _, d := range drift {
+		book, err := s.repo.GetBookByID(ctx, d.BookID)
+		if err != nil {
+			return nil, err
+		}
+		book.Status = d.Expected
+		book.UpdatedAt = time.Now()
+
+		err = database.WithTransaction(ctx, s.db, func(tx *sqlx.Tx) error {
+
+			// Update status with repository
+			if err := s.repo.UpdateBookStatus(ctx, tx, d.BookID, d.Status, d.Expected, book.UpdatedAt); err != nil {
+				return err
+			}
+
+			// Record event
+			return s.events.AppendBookEvent(ctx, tx, models.BookEventUpdated, d.BookID, models.ToBookResponse(book))
+		})
+		if err != nil {
+			return nil, err
+		}
+		s.events.Notify()
+	}
+
+	return drift, nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRestoreBook_Success(t *testing.T) {
+	ctx := context.Background()
+
+	mockedRepo := new(mockRepo)
+	outbox := new(mockOutbox)
+	db, sqlMock := newMockDB(t)
+	service := NewMaintenanceService(db, mockedRepo, outbox)
+
+	mockedRepo.On("GetDeletedBookByID", ctx, "b1").Return(&models.Book{ID: "b1", Title: "The Hobbit", Deleted: true}, nil)
+	sqlMock.ExpectBegin()
+	mockedRepo.On("RestoreBook", ctx, mock.AnythingOfType("*sqlx.Tx"), "b1", mock.AnythingOfType("time.Time")).Return(nil)
+	outbox.On("AppendBookEvent", ctx, mock.AnythingOfType("*sqlx.Tx"), models.BookEventUpdated, "b1", mock.MatchedBy(func(book *models.BookResponse) bool {
+		return book.Title == "The Hobbit"
+	})).Return(nil)
+	outbox.On("Notify").Return()
+	sqlMock.ExpectCommit()
+
+	resp, err := service.RestoreBook(ctx, "b1", false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "b1", resp.ID)
+	mockedRepo.AssertExpectations(t)
+	outbox.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestRestoreBook_DryRun(t *testing.T) {
+	ctx := context.Background()
+
+	mockedRepo := new(mockRepo)
+	db, sqlMock := newMockDB(t)
+	service := NewMaintenanceService(db, mockedRepo, nopOutbox{})
+
+	mockedRepo.On("GetDeletedBookByID", ctx, "b1").Return(&models.Book{ID: "b1", Deleted: true}, nil)
+	mockedRepo.On("GetDeletedBookByID", ctx, "missing").Return(nil, utils.ErrNotFound)
+
+	resp, err := service.RestoreBook(ctx, "b1", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "b1", resp.ID)
+
+	_, err = service.RestoreBook(ctx, "missing", true)
+	assert.ErrorIs(t, err, utils.ErrNotFound)
+
+	mockedRepo.AssertNotCalled(t, "RestoreBook", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(t, sqlMock.ExpectationsWereMet()) // No transaction
+}
+
+func TestRecomputeBookStatuses(t *testing.T) {
+	ctx := context.Background()
+
+	mockedRepo := new(mockRepo)
+	outbox := new(mockOutbox)
+	db, sqlMock := newMockDB(t)
+	service := NewMaintenanceService(db, mockedRepo, outbox)
+
+	drift := []models.BookStatusDrift{
+		{BookID: "b1", Title: "The Hobbit", Status: models.BookStatusAvailable, Expected: models.BookStatusCheckedOut},
+		{BookID: "b2", Title: "The Silmarillion", Status: models.BookStatusCheckedOut, Expected: models.BookStatusAvailable},
+	}
+	mockedRepo.On("ListStatusDrift", ctx, []string(nil)).Return(drift, nil)
+	for _, d := range drift {
+		mockedRepo.On("GetBookByID", ctx, d.BookID).Return(&models.Book{ID: d.BookID, Status: d.Status}, nil)
+		sqlMock.ExpectBegin()
+		mockedRepo.On("UpdateBookStatus", ctx, mock.AnythingOfType("*sqlx.Tx"), d.BookID, d.Status, d.Expected, mock.AnythingOfType("time.Time")).Return(nil)
+		outbox.On("AppendBookEvent", ctx, mock.AnythingOfType("*sqlx.Tx"), models.BookEventUpdated, d.BookID, mock.MatchedBy(func(book *models.BookResponse) bool {
+			return book.Status == d.Expected
+		})).Return(nil)
+		sqlMock.ExpectCommit()
+	}
+	outbox.On("Notify").Return()
+
+	fixed, err := service.RecomputeBookStatuses(ctx, nil, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, drift, fixed)
+	mockedRepo.AssertExpectations(t)
+	mockedRepo.AssertNotCalled(t, "AppendStatusChange", mock.Anything, mock.Anything, mock.Anything) // History untouched
+	outbox.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestRecomputeBookStatuses_DryRun(t *testing.T) {
+	ctx := context.Background()
+
+	mockedRepo := new(mockRepo)
+	db, sqlMock := newMockDB(t)
+	service := NewMaintenanceService(db, mockedRepo, nopOutbox{})
+
+	drift := []models.BookStatusDrift{{BookID: "b1", Status: models.BookStatusAvailable, Expected: models.BookStatusCheckedOut}}
+	mockedRepo.On("ListStatusDrift", ctx, []string{"b1"}).Return(drift, nil)
+
+	fixed, err := service.RecomputeBookStatuses(ctx, []string{"b1"}, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, drift, fixed)
+	mockedRepo.AssertNotCalled(t, "UpdateBookStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
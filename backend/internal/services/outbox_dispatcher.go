@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/santiago-buildit/code-challenge/backend/internal/database"
+	"github.com/santiago-buildit/code-challenge/backend/internal/events"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/repositories"
+	"go.uber.org/zap"
+)
+
+// Dispatch policy
+const (
+	outboxBaseBackoff     = 5 * time.Second    // Delay after the first failed attempt (doubled on each retry)
+	outboxMaxBackoff      = 5 * time.Minute    // Retry delay cap (events are retried until delivered)
+	outboxPollInterval    = 5 * time.Second    // Pending events polling interval
+	outboxBatchSize       = 20                 // Events claimed per transaction
+	outboxRetention       = 7 * 24 * time.Hour // Processed events kept for inspection
+	outboxCleanupInterval = time.Hour
+)
+
+// BookEventOutbox records book lifecycle events in the transaction of the change and dispatches them after commit
+type BookEventOutbox interface {
+	AppendBookEvent(ctx context.Context, tx *sqlx.Tx, eventType models.BookEventType, bookID string, book *models.BookResponse) error // External TX
+	Notify()
+}
+
+// OutboxDispatcher implements the transactional outbox: producers append events within their transaction
+// and the dispatcher delivers committed events to every sink, at least once (retried until all sinks accept)
+type OutboxDispatcher struct {
+	db          *sqlx.DB
+	repo        repositories.OutboxRepository
+	sinks       []events.Sink
+	logger      *zap.Logger
+	wake        chan struct{}
+	now         func() time.Time
+	lastCleanup time.Time
+}
+
+func NewOutboxDispatcher(db *sqlx.DB, repo repositories.OutboxRepository, sinks []events.Sink, logger *zap.Logger) *OutboxDispatcher {
+	return &OutboxDispatcher{
+		db:     db,
+		repo:   repo,
+		sinks:  sinks,
+		logger: logger,
+		wake:   make(chan struct{}, 1),
+		now:    time.Now,
+	}
+}
+
+// External TX
+func (d *OutboxDispatcher) AppendBookEvent(ctx context.Context, tx *sqlx.Tx, eventType models.BookEventType, bookID string, book *models.BookResponse) error {
+
+	// Build payload (the event ID is the deduplication ID for consumers)
+	now := d.now()
+	event := models.BookEvent{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		OccurredAt: now.UTC(),
+		BookID:     bookID,
+		Book:       book,
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	// Append with repository (due now)
+	return d.repo.AppendEvent(ctx, tx, &models.OutboxEvent{
+		ID:            event.ID,
+		EventType:     string(eventType),
+		AggregateType: models.AggregateBook,
+		AggregateID:   bookID,
+		Payload:       payload,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	})
+}
+
+// Notify wakes the dispatcher up (new events were committed); it never blocks
+func (d *OutboxDispatcher) Notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start dispatches pending events on every poll interval and on Notify, until the context is cancelled
+func (d *OutboxDispatcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			d.logger.Error("Failed to dispatch outbox events", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// RunOnce dispatches the pending events and cleans up the processed ones (a scheduled run, or one loop of Start)
+func (d *OutboxDispatcher) RunOnce(ctx context.Context) (int, error) {
+	processed, err := d.DispatchPending(ctx)
+	d.cleanup(ctx)
+	return processed, err
+}
+
+// DispatchPending delivers the due events in batches and returns the number of events processed
+func (d *OutboxDispatcher) DispatchPending(ctx context.Context) (int, error) {
+	processed := 0
+	for {
+		claimed := 0
+
+		// Transactional block (claimed rows stay locked, so concurrent dispatchers skip them)
+		err := database.WithTransaction(ctx, d.db, func(tx *sqlx.Tx) error {
+
+			// Claim batch with repository
+			pending, err := d.repo.ClaimPendingEvents(ctx, tx, d.now(), outboxBatchSize)
+			if err != nil {
+				return err
+			}
+			claimed = len(pending)
+
+			// Deliver each event and record the result
+			for i := range pending {
+				delivered, err := d.dispatch(ctx, tx, &pending[i])
+				if err != nil {
+					return err
+				}
+				if delivered {
+					processed++
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return processed, err
+		}
+
+		// Last batch
+		if claimed < outboxBatchSize {
+			return processed, nil
+		}
+	}
+}
+
+/* Helper functions */
+
+// dispatch sends the event to all sinks, then marks it processed or schedules a retry (only recording errors are returned)
+func (d *OutboxDispatcher) dispatch(ctx context.Context, tx *sqlx.Tx, event *models.OutboxEvent) (bool, error) {
+
+	// Send to every sink (a retry sends to all of them again)
+	var sendErr error
+	for _, sink := range d.sinks {
+		if err := sink.Send(ctx, event); err != nil {
+			sendErr = fmt.Errorf("%s: %w", sink.Name(), err)
+			break
+		}
+	}
+
+	// Delivered
+	now := d.now()
+	if sendErr == nil {
+		return true, d.repo.MarkEventProcessed(ctx, tx, event.ID, now)
+	}
+
+	// Schedule retry
+	d.logger.Warn("Outbox event dispatch failed",
+		zap.String("id", event.ID),
+		zap.String("type", event.EventType),
+		zap.Int("attempts", event.Attempts+1),
+		zap.Error(sendErr))
+	event.Attempts++
+	event.NextAttemptAt = now.Add(retryBackoff(event.Attempts, outboxBaseBackoff, outboxMaxBackoff))
+	event.LastError = truncate(sendErr.Error(), webhookMaxErrorLen)
+	return false, d.repo.ScheduleEventRetry(ctx, tx, event)
+}
+
+// cleanup deletes processed events past the retention period (at most once per cleanup interval)
+func (d *OutboxDispatcher) cleanup(ctx context.Context) {
+	now := d.now()
+	if now.Sub(d.lastCleanup) < outboxCleanupInterval {
+		return
+	}
+	d.lastCleanup = now
+	if _, err := d.repo.DeleteProcessedEvents(ctx, now.Add(-outboxRetention)); err != nil && ctx.Err() == nil {
+		d.logger.Error("Failed to clean up outbox", zap.Error(err))
+	}
+}
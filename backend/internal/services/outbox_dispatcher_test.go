@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/santiago-buildit/code-challenge/backend/internal/events"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+// --- Mock definition ---
+
+type mockOutboxRepo struct {
+	mock.Mock
+}
+
+func (m *mockOutboxRepo) AppendEvent(ctx context.Context, tx *sqlx.Tx, event *models.OutboxEvent) error {
+	args := m.Called(ctx, tx, event)
+	return args.Error(0)
+}
+
+func (m *mockOutboxRepo) ClaimPendingEvents(ctx context.Context, tx *sqlx.Tx, now time.Time, limit int) ([]models.OutboxEvent, error) {
+	args := m.Called(ctx, tx, now, limit)
+	return args.Get(0).([]models.OutboxEvent), args.Error(1)
+}
+
+func (m *mockOutboxRepo) MarkEventProcessed(ctx context.Context, tx *sqlx.Tx, id string, timestamp time.Time) error {
+	args := m.Called(ctx, tx, id, timestamp)
+	return args.Error(0)
+}
+
+func (m *mockOutboxRepo) ScheduleEventRetry(ctx context.Context, tx *sqlx.Tx, event *models.OutboxEvent) error {
+	args := m.Called(ctx, tx, event)
+	return args.Error(0)
+}
+
+func (m *mockOutboxRepo) DeleteProcessedEvents(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockOutboxRepo) GetLatestEventSeq(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockOutboxRepo) GetResumeSeq(ctx context.Context, seq int64, grace time.Duration) (int64, error) {
+	args := m.Called(ctx, seq, grace)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockOutboxRepo) ListEventsAfter(ctx context.Context, seq int64, limit int) ([]models.OutboxEvent, error) {
+	args := m.Called(ctx, seq, limit)
+	return args.Get(0).([]models.OutboxEvent), args.Error(1)
+}
+
+func (m *mockOutboxRepo) ListEventsBySeq(ctx context.Context, seqs []int64) ([]models.OutboxEvent, error) {
+	args := m.Called(ctx, seqs)
+	return args.Get(0).([]models.OutboxEvent), args.Error(1)
+}
+
+// recordingSink records the events sent, failing while err is set
+type recordingSink struct {
+	sent []string
+	err  error
+}
+
+func (s *recordingSink) Name() string {
+	return "recording"
+}
+
+func (s *recordingSink) Send(ctx context.Context, event *models.OutboxEvent) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, event.ID)
+	return nil
+}
+
+// --- Test ---
+
+func TestAppendBookEvent(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockOutboxRepo)
+	dispatcher := NewOutboxDispatcher(&sqlx.DB{}, mockedRepo, nil, zaptest.NewLogger(t))
+
+	tx := &sqlx.Tx{}
+	var captured *models.OutboxEvent
+	mockedRepo.On("AppendEvent", ctx, tx, mock.MatchedBy(func(e *models.OutboxEvent) bool {
+		captured = e
+		return true
+	})).Return(nil)
+
+	err := dispatcher.AppendBookEvent(ctx, tx, models.BookEventCheckedOut, "book-1", &models.BookResponse{ID: "book-1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "book.checked_out", captured.EventType)
+	assert.Equal(t, models.AggregateBook, captured.AggregateType)
+	assert.Equal(t, "book-1", captured.AggregateID)
+
+	var event models.BookEvent
+	assert.NoError(t, json.Unmarshal(captured.Payload, &event))
+	assert.Equal(t, captured.ID, event.ID) // Payload carries the deduplication ID
+	assert.Equal(t, "book-1", event.Book.ID)
+}
+
+func TestDispatchPending_MarksProcessed(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockOutboxRepo)
+	db, sqlMock := newMockDB(t)
+	first, second := &recordingSink{}, &recordingSink{}
+	dispatcher := NewOutboxDispatcher(db, mockedRepo, []events.Sink{first, second}, zaptest.NewLogger(t))
+
+	pending := []models.OutboxEvent{{ID: "event-1"}, {ID: "event-2"}}
+	sqlMock.ExpectBegin()
+	mockedRepo.On("ClaimPendingEvents", ctx, mock.AnythingOfType("*sqlx.Tx"), mock.AnythingOfType("time.Time"), outboxBatchSize).Return(pending, nil)
+	mockedRepo.On("MarkEventProcessed", ctx, mock.AnythingOfType("*sqlx.Tx"), "event-1", mock.AnythingOfType("time.Time")).Return(nil)
+	mockedRepo.On("MarkEventProcessed", ctx, mock.AnythingOfType("*sqlx.Tx"), "event-2", mock.AnythingOfType("time.Time")).Return(nil)
+	sqlMock.ExpectCommit()
+
+	processed, err := dispatcher.DispatchPending(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, processed)
+	assert.Equal(t, []string{"event-1", "event-2"}, first.sent)
+	assert.Equal(t, []string{"event-1", "event-2"}, second.sent)
+	mockedRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestDispatchPending_SinkFailureSchedulesRetry(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockOutboxRepo)
+	db, sqlMock := newMockDB(t)
+	failing := &recordingSink{err: assert.AnError}
+	dispatcher := NewOutboxDispatcher(db, mockedRepo, []events.Sink{failing}, zaptest.NewLogger(t))
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	dispatcher.now = func() time.Time { return now }
+
+	var captured *models.OutboxEvent
+	sqlMock.ExpectBegin()
+	mockedRepo.On("ClaimPendingEvents", ctx, mock.Anything, now, outboxBatchSize).Return([]models.OutboxEvent{{ID: "event-1", Attempts: 1}}, nil)
+	mockedRepo.On("ScheduleEventRetry", ctx, mock.Anything, mock.MatchedBy(func(e *models.OutboxEvent) bool {
+		captured = e
+		return true
+	})).Return(nil)
+	sqlMock.ExpectCommit()
+
+	processed, err := dispatcher.DispatchPending(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, processed)
+	assert.Equal(t, 2, captured.Attempts)
+	assert.Equal(t, now.Add(10*time.Second), captured.NextAttemptAt) // 5s doubled once
+	assert.Contains(t, captured.LastError, "recording: ")
+	mockedRepo.AssertNotCalled(t, "MarkEventProcessed", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/santiago-buildit/code-challenge/backend/internal/i18n"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/repositories"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+)
+
+// Report windows: default length, and maximum number of points of a time series
+const (
+	defaultReportWindow = 30 * 24 * time.Hour
+	maxReportPoints     = 1000
+)
+
+// ReportService defines the interface for circulation reports
+type ReportService interface {
+	TopBooks(ctx context.Context, req models.TopReportRequest) (*models.TopBooksReport, error)
+	TopAuthors(ctx context.Context, req models.TopReportRequest) (*models.TopAuthorsReport, error)
+	Checkouts(ctx context.Context, req models.CheckoutsReportRequest) (*models.CheckoutsReport, error)
+	LoanDuration(ctx context.Context, req models.ReportWindowRequest) (*models.LoanDurationReport, error)
+	CheckedOut(ctx context.Context) (*models.CheckedOutReport, error)
+	NeverBorrowed(ctx context.Context, req models.NeverBorrowedReportRequest) (*models.NeverBorrowedReport, error)
+}
+
+type reportServiceImpl struct {
+	repo repositories.ReportRepository
+	now  func() time.Time
+}
+
+func NewReportService(repo repositories.ReportRepository) ReportService {
+	return &reportServiceImpl{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+func (s *reportServiceImpl) TopBooks(ctx context.Context, req models.TopReportRequest) (*models.TopBooksReport, error) {
+	from, to, err := s.window(req.ReportWindowRequest)
+	if err != nil {
+		return nil, err
+	}
+	books, err := s.repo.TopBooks(ctx, from, to, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TopBooksReport{From: from, To: to, Books: books}, nil
+}
+
+func (s *reportServiceImpl) TopAuthors(ctx context.Context, req models.TopReportRequest) (*models.TopAuthorsReport, error) {
+	from, to, err := s.window(req.ReportWindowRequest)
+	if err != nil {
+		return nil, err
+	}
+	authors, err := s.repo.TopAuthors(ctx, from, to, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TopAuthorsReport{From: from, To: to, Authors: authors}, nil
+}
+
+func (s *reportServiceImpl) Checkouts(ctx context.Context, req models.CheckoutsReportRequest) (*models.CheckoutsReport, error) {
+	from, to, err := s.window(req.ReportWindowRequest)
+	if err != nil {
+		return nil, err
+	}
+	if req.Interval == "" {
+		req.Interval = models.ReportIntervalDay
+	}
+
+	// Bound the series (e.g. daily points over years)
+	points := int(to.Sub(from) / (24 * time.Hour))
+	switch req.Interval {
+	case models.ReportIntervalWeek:
+		points /= 7
+	case models.ReportIntervalMonth:
+		points /= 28
+	}
+	if points > maxReportPoints {
+		return nil, i18n.NewError(utils.ErrBadRequest, i18n.ErrorTooManyIntervals, i18n.Params{"interval": string(req.Interval), "max": strconv.Itoa(maxReportPoints)})
+	}
+
+	// Query series
+	series, err := s.repo.CheckoutSeries(ctx, from, to, req.Interval)
+	if err != nil {
+		return nil, err
+	}
+	total := 0
+	for _, point := range series {
+		total += point.Checkouts
+	}
+	return &models.CheckoutsReport{From: from, To: to, Interval: req.Interval, Total: total, Points: series}, nil
+}
+
+func (s *reportServiceImpl) LoanDuration(ctx context.Context, req models.ReportWindowRequest) (*models.LoanDurationReport, error) {
+	from, to, err := s.window(req)
+	if err != nil {
+		return nil, err
+	}
+	duration, err := s.repo.LoanDuration(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &models.LoanDurationReport{From: from, To: to, LoanDuration: *duration}, nil
+}
+
+func (s *reportServiceImpl) CheckedOut(ctx context.Context) (*models.CheckedOutReport, error) {
+	count, err := s.repo.CountCheckedOut(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report := &models.CheckedOutReport{CheckedOutCount: *count}
+	if count.Books > 0 {
+		report.Ratio = float64(count.CheckedOut) / float64(count.Books)
+	}
+	return report, nil
+}
+
+func (s *reportServiceImpl) NeverBorrowed(ctx context.Context, req models.NeverBorrowedReportRequest) (*models.NeverBorrowedReport, error) {
+	books, total, err := s.repo.NeverBorrowed(ctx, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return &models.NeverBorrowedReport{Total: total, Books: books}, nil
+}
+
+/* Helper functions */
+
+// window applies the defaults of a report window (the last 30 days, today included) and validates it
+func (s *reportServiceImpl) window(req models.ReportWindowRequest) (time.Time, time.Time, error) {
+	from, to := req.From, req.To
+	if to.IsZero() {
+		to = s.now().UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+	}
+	if from.IsZero() {
+		from = to.Add(-defaultReportWindow)
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, i18n.NewError(utils.ErrBadRequest, i18n.ErrorRangeOrder, nil)
+	}
+	return from, to, nil
+}
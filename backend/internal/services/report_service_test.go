@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockReportRepo struct {
+	mock.Mock
+}
+
+func (m *mockReportRepo) TopBooks(ctx context.Context, from, to time.Time, limit int) ([]models.BookCheckouts, error) {
+	args := m.Called(ctx, from, to, limit)
+	books, _ := args.Get(0).([]models.BookCheckouts)
+	return books, args.Error(1)
+}
+
+func (m *mockReportRepo) TopAuthors(ctx context.Context, from, to time.Time, limit int) ([]models.AuthorCheckouts, error) {
+	args := m.Called(ctx, from, to, limit)
+	authors, _ := args.Get(0).([]models.AuthorCheckouts)
+	return authors, args.Error(1)
+}
+
+func (m *mockReportRepo) CheckoutSeries(ctx context.Context, from, to time.Time, interval models.ReportInterval) ([]models.CheckoutsPoint, error) {
+	args := m.Called(ctx, from, to, interval)
+	points, _ := args.Get(0).([]models.CheckoutsPoint)
+	return points, args.Error(1)
+}
+
+func (m *mockReportRepo) LoanDuration(ctx context.Context, from, to time.Time) (*models.LoanDuration, error) {
+	args := m.Called(ctx, from, to)
+	duration, _ := args.Get(0).(*models.LoanDuration)
+	return duration, args.Error(1)
+}
+
+func (m *mockReportRepo) CountCheckedOut(ctx context.Context) (*models.CheckedOutCount, error) {
+	args := m.Called(ctx)
+	count, _ := args.Get(0).(*models.CheckedOutCount)
+	return count, args.Error(1)
+}
+
+func (m *mockReportRepo) NeverBorrowed(ctx context.Context, limit int) ([]models.NeverBorrowedBook, int, error) {
+	args := m.Called(ctx, limit)
+	books, _ := args.Get(0).([]models.NeverBorrowedBook)
+	return books, args.Int(1), args.Error(2)
+}
+
+func newTestReportService(repo *mockReportRepo) *reportServiceImpl {
+	service := NewReportService(repo).(*reportServiceImpl)
+	service.now = func() time.Time { return time.Date(2025, 3, 10, 15, 30, 0, 0, time.UTC) }
+	return service
+}
+
+func TestReportTopBooks_DefaultWindow(t *testing.T) {
+	ctx := context.Background()
+	repo := new(mockReportRepo)
+	service := newTestReportService(repo)
+
+	// Last 30 days, today included
+	from := time.Date(2025, 2, 9, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC)
+	repo.On("TopBooks", ctx, from, to, 10).Return([]models.BookCheckouts{{BookID: "b1", Checkouts: 3}}, nil)
+
+	report, err := service.TopBooks(ctx, models.TopReportRequest{Limit: 10})
+
+	assert.NoError(t, err)
+	assert.Equal(t, from, report.From)
+	assert.Equal(t, to, report.To)
+	assert.Equal(t, 3, report.Books[0].Checkouts)
+	repo.AssertExpectations(t)
+}
+
+func TestReportWindow_Invalid(t *testing.T) {
+	repo := new(mockReportRepo)
+	service := newTestReportService(repo)
+
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err := service.LoanDuration(context.Background(), models.ReportWindowRequest{From: day, To: day})
+
+	assert.ErrorIs(t, err, utils.ErrBadRequest)
+	repo.AssertNotCalled(t, "LoanDuration", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReportCheckouts(t *testing.T) {
+	ctx := context.Background()
+	repo := new(mockReportRepo)
+	service := newTestReportService(repo)
+
+	window := models.ReportWindowRequest{
+		From: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC),
+	}
+	repo.On("CheckoutSeries", ctx, window.From, window.To, models.ReportIntervalDay).Return([]models.CheckoutsPoint{
+		{Period: window.From, Checkouts: 2},
+		{Period: window.From.AddDate(0, 0, 1), Checkouts: 0},
+		{Period: window.From.AddDate(0, 0, 2), Checkouts: 5},
+	}, nil)
+
+	report, err := service.Checkouts(ctx, models.CheckoutsReportRequest{ReportWindowRequest: window}) // Default day
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.ReportIntervalDay, report.Interval)
+	assert.Equal(t, 7, report.Total)
+	assert.Len(t, report.Points, 3)
+	repo.AssertExpectations(t)
+}
+
+func TestReportCheckouts_TooManyPoints(t *testing.T) {
+	repo := new(mockReportRepo)
+	service := newTestReportService(repo)
+
+	// Daily points over 5 years, fine monthly
+	window := models.ReportWindowRequest{
+		From: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	_, err := service.Checkouts(context.Background(), models.CheckoutsReportRequest{ReportWindowRequest: window, Interval: models.ReportIntervalDay})
+	assert.ErrorIs(t, err, utils.ErrBadRequest)
+
+	repo.On("CheckoutSeries", mock.Anything, window.From, window.To, models.ReportIntervalMonth).Return([]models.CheckoutsPoint{}, nil)
+	_, err = service.Checkouts(context.Background(), models.CheckoutsReportRequest{ReportWindowRequest: window, Interval: models.ReportIntervalMonth})
+	assert.NoError(t, err)
+}
+
+func TestReportCheckedOut(t *testing.T) {
+	ctx := context.Background()
+	repo := new(mockReportRepo)
+	service := newTestReportService(repo)
+
+	repo.On("CountCheckedOut", ctx).Return(&models.CheckedOutCount{Books: 8, CheckedOut: 2}, nil).Once()
+	report, err := service.CheckedOut(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.25, report.Ratio)
+
+	// No books
+	repo.On("CountCheckedOut", ctx).Return(&models.CheckedOutCount{}, nil).Once()
+	report, err = service.CheckedOut(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, report.Ratio)
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/santiago-buildit/code-challenge/backend/internal/database"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/repositories"
+	"github.com/santiago-buildit/code-challenge/backend/internal/seed"
+)
+
+// SeedService defines the interface for loading synthetic data (admin CLI)
+type SeedService interface {
+	Seed(ctx context.Context, opts seed.Options) (*models.SeedResponse, error)
+}
+
+type seedServiceImpl struct {
+	db   *sqlx.DB
+	repo repositories.SeedRepository
+}
+
+func NewSeedService(db *sqlx.DB, repo repositories.SeedRepository) SeedService {
+	return &seedServiceImpl{
+		db:   db,
+		repo: repo,
+	}
+}
+
+// Seed generates the dataset of the options and bulk-loads it in a single transaction.
+// No book events are recorded (subscribers are not flooded with synthetic books).
+func (s *seedServiceImpl) Seed(ctx context.Context, opts seed.Options) (*models.SeedResponse, error) {
+
+	// Generate dataset
+	data, err := seed.Generate(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Transactional block (authors and tags first, then books with their credits, tags and history)
+	err = database.WithTransaction(ctx, s.db, func(tx *sqlx.Tx) error {
+
+		// Load authors and credit the stored ones (existing authors with the same name are reused)
+		authorIDs, err := s.repo.UpsertAuthors(ctx, tx, data.Authors)
+		if err != nil {
+			return err
+		}
+		for i := range data.Books {
+			for j := range data.Books[i].Contributors {
+				contributor := &data.Books[i].Contributors[j]
+				contributor.AuthorID = authorIDs[contributor.AuthorID]
+			}
+		}
+
+		// Load tags (same reuse by name)
+		storedTagIDs, err := s.repo.UpsertTags(ctx, tx, data.Tags)
+		if err != nil {
+			return err
+		}
+		tagIDs := make(map[string]string, len(data.Tags))
+		for _, tag := range data.Tags {
+			tagIDs[tag.Name] = storedTagIDs[tag.ID]
+		}
+
+		// Load books, credits, tags and history
+		if err := s.repo.CopyBooks(ctx, tx, data.Books); err != nil {
+			return err
+		}
+		if err := s.repo.CopyBookContributors(ctx, tx, data.Books); err != nil {
+			return err
+		}
+		if err := s.repo.CopyBookTags(ctx, tx, data.Books, tagIDs); err != nil {
+			return err
+		}
+		return s.repo.CopyStatusChanges(ctx, tx, data.History)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SeedResponse{
+		Seed:          opts.Seed,
+		From:          opts.From,
+		To:            opts.To,
+		Books:         len(data.Books),
+		Authors:       len(data.Authors),
+		Tags:          len(data.Tags),
+		StatusChanges: len(data.History),
+	}, nil
+}
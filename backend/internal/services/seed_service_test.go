@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/seed"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockSeedRepo struct {
+	mock.Mock
+}
+
+func (m *mockSeedRepo) UpsertAuthors(ctx context.Context, tx *sqlx.Tx, authors []models.Author) (map[string]string, error) {
+	args := m.Called(ctx, tx, authors)
+	ids, _ := args.Get(0).(map[string]string)
+	return ids, args.Error(1)
+}
+
+func (m *mockSeedRepo) UpsertTags(ctx context.Context, tx *sqlx.Tx, tags []models.Tag) (map[string]string, error) {
+	args := m.Called(ctx, tx, tags)
+	ids, _ := args.Get(0).(map[string]string)
+	return ids, args.Error(1)
+}
+
+func (m *mockSeedRepo) CopyBooks(ctx context.Context, tx *sqlx.Tx, books []models.Book) error {
+	return m.Called(ctx, tx, books).Error(0)
+}
+
+func (m *mockSeedRepo) CopyBookContributors(ctx context.Context, tx *sqlx.Tx, books []models.Book) error {
+	return m.Called(ctx, tx, books).Error(0)
+}
+
+func (m *mockSeedRepo) CopyBookTags(ctx context.Context, tx *sqlx.Tx, books []models.Book, tagIDs map[string]string) error {
+	return m.Called(ctx, tx, books, tagIDs).Error(0)
+}
+
+func (m *mockSeedRepo) CopyStatusChanges(ctx context.Context, tx *sqlx.Tx, history []models.BookStatusChange) error {
+	return m.Called(ctx, tx, history).Error(0)
+}
+
+var seedOptions = seed.Options{
+	Books: 50,
+	Seed:  3,
+	From:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	To:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+}
+
+func TestSeed_Success(t *testing.T) {
+	ctx := context.Background()
+	data, err := seed.Generate(seedOptions)
+	assert.NoError(t, err)
+
+	mockedRepo := new(mockSeedRepo)
+	db, sqlMock := newMockDB(t)
+	service := NewSeedService(db, mockedRepo)
+
+	// Every generated author and tag already exists with a stored ID
+	authorIDs, tagIDs, tagsByName := map[string]string{}, map[string]string{}, map[string]string{}
+	for _, author := range data.Authors {
+		authorIDs[author.ID] = "stored-" + author.ID
+	}
+	for _, tag := range data.Tags {
+		tagIDs[tag.ID] = "stored-" + tag.ID
+		tagsByName[tag.Name] = "stored-" + tag.ID
+	}
+	credited := func(books []models.Book) bool {
+		for _, book := range books {
+			for _, contributor := range book.Contributors {
+				if contributor.AuthorID[:7] != "stored-" {
+					return false
+				}
+			}
+		}
+		return len(books) == seedOptions.Books
+	}
+
+	sqlMock.ExpectBegin()
+	mockedRepo.On("UpsertAuthors", ctx, mock.AnythingOfType("*sqlx.Tx"), data.Authors).Return(authorIDs, nil)
+	mockedRepo.On("UpsertTags", ctx, mock.AnythingOfType("*sqlx.Tx"), data.Tags).Return(tagIDs, nil)
+	mockedRepo.On("CopyBooks", ctx, mock.AnythingOfType("*sqlx.Tx"), mock.MatchedBy(credited)).Return(nil)
+	mockedRepo.On("CopyBookContributors", ctx, mock.AnythingOfType("*sqlx.Tx"), mock.MatchedBy(credited)).Return(nil)
+	mockedRepo.On("CopyBookTags", ctx, mock.AnythingOfType("*sqlx.Tx"), mock.MatchedBy(credited), tagsByName).Return(nil)
+	mockedRepo.On("CopyStatusChanges", ctx, mock.AnythingOfType("*sqlx.Tx"), data.History).Return(nil)
+	sqlMock.ExpectCommit()
+
+	resp, err := service.Seed(ctx, seedOptions)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &models.SeedResponse{
+		Seed:          3,
+		From:          seedOptions.From,
+		To:            seedOptions.To,
+		Books:         50,
+		Authors:       len(data.Authors),
+		Tags:          len(data.Tags),
+		StatusChanges: len(data.History),
+	}, resp)
+	mockedRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestSeed_AlreadyLoaded(t *testing.T) {
+	ctx := context.Background()
+
+	mockedRepo := new(mockSeedRepo)
+	db, sqlMock := newMockDB(t)
+	service := NewSeedService(db, mockedRepo)
+
+	sqlMock.ExpectBegin()
+	mockedRepo.On("UpsertAuthors", ctx, mock.Anything, mock.Anything).Return(map[string]string{}, nil)
+	mockedRepo.On("UpsertTags", ctx, mock.Anything, mock.Anything).Return(map[string]string{}, nil)
+	mockedRepo.On("CopyBooks", ctx, mock.Anything, mock.Anything).Return(utils.ErrConflict)
+	sqlMock.ExpectRollback()
+
+	_, err := service.Seed(ctx, seedOptions)
+
+	assert.ErrorIs(t, err, utils.ErrConflict)
+	mockedRepo.AssertNotCalled(t, "CopyStatusChanges", mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestSeed_InvalidOptions(t *testing.T) {
+	mockedRepo := new(mockSeedRepo)
+	db, sqlMock := newMockDB(t)
+	service := NewSeedService(db, mockedRepo)
+
+	_, err := service.Seed(context.Background(), seed.Options{Books: 0})
+
+	assert.ErrorIs(t, err, utils.ErrBadRequest)
+	assert.NoError(t, sqlMock.ExpectationsWereMet()) // No transaction
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/santiago-buildit/code-challenge/backend/internal/database"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/repositories"
+)
+
+// TagService defines the interface for tag-related operations
+type TagService interface {
+
+	// CRUD operations
+	CreateTag(ctx context.Context, req models.CreateTagRequest) (*models.TagResponse, error)
+	ListTags(ctx context.Context) ([]models.TagResponse, error)
+	GetTag(ctx context.Context, id string) (*models.TagResponse, error)
+	UpdateTag(ctx context.Context, id string, req models.UpdateTagRequest) (*models.TagResponse, error)
+	DeleteTag(ctx context.Context, id string) error
+}
+
+type tagServiceImpl struct {
+	db       *sqlx.DB
+	repo     repositories.TagRepository
+	bookRepo repositories.BookRepository // Tagged books (renames and deletes change them)
+	events   BookEventOutbox             // Lifecycle events (recorded with each change)
+}
+
+func NewTagService(db *sqlx.DB, repo repositories.TagRepository, bookRepo repositories.BookRepository, events BookEventOutbox) TagService {
+	return &tagServiceImpl{
+		db:       db,
+		repo:     repo,
+		bookRepo: bookRepo,
+		events:   events,
+	}
+}
+
+func (s *tagServiceImpl) CreateTag(ctx context.Context, req models.CreateTagRequest) (*models.TagResponse, error) {
+
+	now := time.Now()
+
+	// Map request
+	tag := models.Tag{
+		ID:        uuid.New().String(), // Generate unique ID
+		Name:      req.Name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	// Create with repository
+	if err := s.repo.CreateTag(ctx, &tag); err != nil {
+		return nil, err
+	}
+
+	// Map response
+	return models.ToTagResponse(&tag), nil
+}
+
+func (s *tagServiceImpl) ListTags(ctx context.Context) ([]models.TagResponse, error) {
+
+	// List with repository
+	tags, err := s.repo.ListTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map response
+	return models.ToTagResponseList(tags), nil
+}
+
+func (s *tagServiceImpl) GetTag(ctx context.Context, id string) (*models.TagResponse, error) {
+
+	// Get with repository
+	tag, err := s.repo.GetTagByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map response
+	return models.ToTagResponse(tag), nil
+}
+
+func (s *tagServiceImpl) UpdateTag(ctx context.Context, id string, req models.UpdateTagRequest) (*models.TagResponse, error) {
+
+	// Get with repository
+	tag, err := s.repo.GetTagByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map request
+	tag.Name = req.Name
+	tag.UpdatedAt = time.Now()
+
+	// Transactional block (tag, tagged books and their events)
+	err = database.WithTransaction(ctx, s.db, func(tx *sqlx.Tx) error {
+
+		// Update with repository
+		if err := s.repo.UpdateTag(ctx, tx, tag); err != nil {
+			return err
+		}
+
+		// Touch tagged books with repository (their tags changed)
+		ids, err := s.bookRepo.TouchBooksByTag(ctx, tx, tag.ID, tag.UpdatedAt)
+		if err != nil {
+			return err
+		}
+
+		// Record events
+		return s.appendBookUpdates(ctx, tx, ids)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.events.Notify()
+
+	// Map response
+	return models.ToTagResponse(tag), nil
+}
+
+func (s *tagServiceImpl) DeleteTag(ctx context.Context, id string) error {
+
+	// Transactional block (tag, tagged books and their events)
+	err := database.WithTransaction(ctx, s.db, func(tx *sqlx.Tx) error {
+
+		// Touch tagged books with repository (before the cascade removes the assignments)
+		ids, err := s.bookRepo.TouchBooksByTag(ctx, tx, id, time.Now())
+		if err != nil {
+			return err
+		}
+
+		// Delete with repository
+		if err := s.repo.DeleteTag(ctx, tx, id); err != nil {
+			return err
+		}
+
+		// Record events
+		return s.appendBookUpdates(ctx, tx, ids)
+	})
+	if err != nil {
+		return err
+	}
+	s.events.Notify()
+
+	return nil
+}
+
+/* Helper functions */
+
+// appendBookUpdates records a book.updated event with the current state of each book
+func (s *tagServiceImpl) appendBookUpdates(ctx context.Context, tx *sqlx.Tx, ids []string) error {
+
+	// Get with repository (state after the tag change)
+	books, err := s.bookRepo.GetBooksByIDs(ctx, tx, ids)
+	if err != nil {
+		return err
+	}
+
+	// Record events
+	for i := range books {
+		if err := s.events.AppendBookEvent(ctx, tx, models.BookEventUpdated, books[i].ID, models.ToBookResponse(&books[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// --- Mock definition ---
+
+type mockTagRepo struct {
+	mock.Mock
+}
+
+func (m *mockTagRepo) CreateTag(ctx context.Context, tag *models.Tag) error {
+	args := m.Called(ctx, tag)
+	return args.Error(0)
+}
+
+func (m *mockTagRepo) ListTags(ctx context.Context) ([]models.Tag, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Tag), args.Error(1)
+}
+
+func (m *mockTagRepo) GetTagByID(ctx context.Context, id string) (*models.Tag, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.Tag), args.Error(1)
+}
+
+func (m *mockTagRepo) UpdateTag(ctx context.Context, tx *sqlx.Tx, tag *models.Tag) error {
+	args := m.Called(ctx, tx, tag)
+	return args.Error(0)
+}
+
+func (m *mockTagRepo) DeleteTag(ctx context.Context, tx *sqlx.Tx, id string) error {
+	args := m.Called(ctx, tx, id)
+	return args.Error(0)
+}
+
+// --- Test ---
+
+func TestCreateTag_Success(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockTagRepo)
+	service := NewTagService(&sqlx.DB{}, mockedRepo, new(mockRepo), nopOutbox{})
+
+	mockedRepo.On("CreateTag", ctx, mock.MatchedBy(func(tag *models.Tag) bool {
+		return tag.ID != "" && tag.Name == "Fantasy"
+	})).Return(nil)
+
+	resp, err := service.CreateTag(ctx, models.CreateTagRequest{Name: "Fantasy"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Fantasy", resp.Name)
+	assert.WithinDuration(t, time.Now(), resp.CreatedAt, time.Second)
+	mockedRepo.AssertExpectations(t)
+}
+
+func TestUpdateTag_Success(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockTagRepo)
+	mockedBookRepo := new(mockRepo)
+	mockedEvents := new(mockOutbox)
+	db, sqlMock := newMockDB(t)
+	service := NewTagService(db, mockedRepo, mockedBookRepo, mockedEvents)
+
+	existing := &models.Tag{ID: "tag-1", Name: "Fantsy", BookCount: 1}
+	book := models.Book{ID: "book-1", Title: "Earthsea", Tags: []string{"Fantasy"}}
+	mockedRepo.On("GetTagByID", ctx, "tag-1").Return(existing, nil)
+	sqlMock.ExpectBegin()
+	mockedRepo.On("UpdateTag", ctx, mock.AnythingOfType("*sqlx.Tx"), mock.MatchedBy(func(tag *models.Tag) bool {
+		return tag.Name == "Fantasy"
+	})).Return(nil)
+	mockedBookRepo.On("TouchBooksByTag", ctx, mock.AnythingOfType("*sqlx.Tx"), "tag-1", mock.AnythingOfType("time.Time")).Return([]string{"book-1"}, nil)
+	mockedBookRepo.On("GetBooksByIDs", ctx, mock.AnythingOfType("*sqlx.Tx"), []string{"book-1"}).Return([]models.Book{book}, nil)
+	mockedEvents.On("AppendBookEvent", ctx, mock.AnythingOfType("*sqlx.Tx"), models.BookEventUpdated, "book-1", models.ToBookResponse(&book)).Return(nil)
+	sqlMock.ExpectCommit()
+	mockedEvents.On("Notify").Return()
+
+	resp, err := service.UpdateTag(ctx, "tag-1", models.UpdateTagRequest{Name: "Fantasy"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Fantasy", resp.Name)
+	assert.Equal(t, 1, resp.BookCount)
+	mockedRepo.AssertExpectations(t)
+	mockedBookRepo.AssertExpectations(t)
+	mockedEvents.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestUpdateTag_Conflict(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockTagRepo)
+	mockedBookRepo := new(mockRepo)
+	db, sqlMock := newMockDB(t)
+	service := NewTagService(db, mockedRepo, mockedBookRepo, new(mockOutbox))
+
+	mockedRepo.On("GetTagByID", ctx, "tag-1").Return(&models.Tag{ID: "tag-1", Name: "Fantsy"}, nil)
+	sqlMock.ExpectBegin()
+	mockedRepo.On("UpdateTag", ctx, mock.AnythingOfType("*sqlx.Tx"), mock.AnythingOfType("*models.Tag")).Return(utils.ErrConflict)
+	sqlMock.ExpectRollback()
+
+	resp, err := service.UpdateTag(ctx, "tag-1", models.UpdateTagRequest{Name: "Fantasy"})
+
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, utils.ErrConflict)
+	mockedRepo.AssertExpectations(t)
+	mockedBookRepo.AssertNotCalled(t, "TouchBooksByTag", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestDeleteTag_Success(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockTagRepo)
+	mockedBookRepo := new(mockRepo)
+	mockedEvents := new(mockOutbox)
+	db, sqlMock := newMockDB(t)
+	service := NewTagService(db, mockedRepo, mockedBookRepo, mockedEvents)
+
+	// Books are touched before the tag (and its assignments) is deleted, and recorded after
+	books := []models.Book{{ID: "book-1", Title: "Earthsea"}, {ID: "book-2", Title: "Dune"}}
+	sqlMock.ExpectBegin()
+	touch := mockedBookRepo.On("TouchBooksByTag", ctx, mock.AnythingOfType("*sqlx.Tx"), "tag-1", mock.AnythingOfType("time.Time")).Return([]string{"book-1", "book-2"}, nil)
+	remove := mockedRepo.On("DeleteTag", ctx, mock.AnythingOfType("*sqlx.Tx"), "tag-1").Return(nil).NotBefore(touch)
+	mockedBookRepo.On("GetBooksByIDs", ctx, mock.AnythingOfType("*sqlx.Tx"), []string{"book-1", "book-2"}).Return(books, nil).NotBefore(remove)
+	for i := range books {
+		mockedEvents.On("AppendBookEvent", ctx, mock.AnythingOfType("*sqlx.Tx"), models.BookEventUpdated, books[i].ID, models.ToBookResponse(&books[i])).Return(nil)
+	}
+	sqlMock.ExpectCommit()
+	mockedEvents.On("Notify").Return()
+
+	err := service.DeleteTag(ctx, "tag-1")
+
+	assert.NoError(t, err)
+	mockedRepo.AssertExpectations(t)
+	mockedBookRepo.AssertExpectations(t)
+	mockedEvents.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestDeleteTag_NotFound(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockTagRepo)
+	mockedBookRepo := new(mockRepo)
+	mockedEvents := new(mockOutbox)
+	db, sqlMock := newMockDB(t)
+	service := NewTagService(db, mockedRepo, mockedBookRepo, mockedEvents)
+
+	sqlMock.ExpectBegin()
+	mockedBookRepo.On("TouchBooksByTag", ctx, mock.AnythingOfType("*sqlx.Tx"), "missing", mock.AnythingOfType("time.Time")).Return([]string{}, nil)
+	mockedRepo.On("DeleteTag", ctx, mock.AnythingOfType("*sqlx.Tx"), "missing").Return(utils.ErrNotFound)
+	sqlMock.ExpectRollback()
+
+	err := service.DeleteTag(ctx, "missing")
+
+	assert.ErrorIs(t, err, utils.ErrNotFound)
+	mockedEvents.AssertNotCalled(t, "Notify")
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
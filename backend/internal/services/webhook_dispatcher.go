@@ -0,0 +1,291 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/repositories"
+	"go.uber.org/zap"
+)
+
+// Delivery policy
+const (
+	webhookMaxAttempts  = 8                // Attempts before a delivery is marked failed
+	webhookBaseBackoff  = 30 * time.Second // Delay after the first failed attempt (doubled on each retry)
+	webhookMaxBackoff   = time.Hour        // Retry delay cap
+	webhookTimeout      = 10 * time.Second // Subscriber response timeout
+	webhookLease        = time.Minute      // Time a claimed delivery is hidden from other dispatchers
+	webhookPollInterval = 15 * time.Second // Due deliveries polling interval
+	webhookBatchSize    = 20               // Deliveries claimed per poll
+	webhookMaxErrorLen  = 500              // Stored last_error length
+)
+
+// WebhookDispatcher sends pending webhook deliveries and schedules retries with exponential backoff
+type WebhookDispatcher struct {
+	repo   repositories.WebhookRepository
+	client *http.Client
+	logger *zap.Logger
+	wake   chan struct{}
+	now    func() time.Time
+}
+
+func NewWebhookDispatcher(repo repositories.WebhookRepository, client *http.Client, logger *zap.Logger) *WebhookDispatcher {
+	if client == nil {
+		client = NewWebhookClient()
+	}
+	return &WebhookDispatcher{
+		repo:   repo,
+		client: client,
+		logger: logger,
+		wake:   make(chan struct{}, 1),
+		now:    time.Now,
+	}
+}
+
+// Start delivers due deliveries on every poll interval and on Notify, until the context is cancelled
+func (d *WebhookDispatcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+			d.logger.Error("Failed to deliver webhooks", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// Notify wakes the dispatcher up (new deliveries are pending); it never blocks
+func (d *WebhookDispatcher) Notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// DeliverDue claims due deliveries and attempts each of them, returning the number attempted.
+// With a context deadline (scheduled worker), only the deliveries that can be attempted in time
+// are claimed, so none is left claimed (hidden until its lease expires) when the run is cut.
+func (d *WebhookDispatcher) DeliverDue(ctx context.Context) (int, error) {
+	attempted := 0
+	for {
+
+		// Claim next batch with repository
+		limit := deliveryBatchSize(ctx, d.now())
+		if limit == 0 {
+			return attempted, nil
+		}
+		deliveries, err := d.repo.ClaimDueDeliveries(ctx, d.now(), webhookLease, limit)
+		if err != nil {
+			return attempted, err
+		}
+
+		// Attempt each delivery
+		for i := range deliveries {
+			if err := d.Deliver(ctx, &deliveries[i]); err != nil {
+				return attempted, err
+			}
+			attempted++
+		}
+
+		// Last batch
+		if len(deliveries) < limit {
+			return attempted, nil
+		}
+	}
+}
+
+// Deliver makes one attempt of the delivery (with URL and secret filled) and records the result.
+// Only recording errors are returned; subscriber failures schedule a retry or fail the delivery.
+func (d *WebhookDispatcher) Deliver(ctx context.Context, delivery *models.WebhookDelivery) error {
+
+	// Send request
+	now := d.now()
+	status, sendErr := d.send(ctx, delivery, now)
+
+	// Record attempt
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.UpdatedAt = now
+	delivery.ResponseStatus = status
+	delivery.LastError = ""
+	switch {
+	case sendErr == nil:
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.NextAttemptAt = nil
+	case delivery.Attempts >= webhookMaxAttempts:
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+		delivery.LastError = truncate(sendErr.Error(), webhookMaxErrorLen)
+	default:
+		next := now.Add(retryBackoff(delivery.Attempts, webhookBaseBackoff, webhookMaxBackoff))
+		delivery.Status = models.WebhookDeliveryPending
+		delivery.NextAttemptAt = &next
+		delivery.LastError = truncate(sendErr.Error(), webhookMaxErrorLen)
+	}
+	if sendErr != nil {
+		d.logger.Warn("Webhook delivery attempt failed",
+			zap.String("delivery_id", delivery.ID),
+			zap.String("webhook_id", delivery.WebhookID),
+			zap.Int("attempts", delivery.Attempts),
+			zap.Error(sendErr))
+	}
+
+	// Update with repository
+	return d.repo.UpdateDeliveryAttempt(ctx, delivery)
+}
+
+// WebhookSignature returns the X-Webhook-Signature header value for a payload:
+// "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + body))
+func WebhookSignature(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewWebhookClient returns the HTTP client of webhook deliveries. Connections to non-public addresses
+// (loopback, private, link-local such as the instance metadata service) are refused when dialing, so
+// DNS answers cannot bypass the check, and redirects are not followed.
+func NewWebhookClient() *http.Client {
+	return webhookClient(publicAddr)
+}
+
+/* Helper functions */
+
+// errBlockedAddress is returned when dialing a webhook subscriber at a non-public address
+var errBlockedAddress = errors.New("webhook address not allowed")
+
+// Non-public ranges not covered by netip.Addr predicates
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // This network
+	netip.MustParsePrefix("100.64.0.0/10"), // Shared address space (carrier-grade NAT)
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"), // Benchmarking
+	netip.MustParsePrefix("64:ff9b::/96"),  // NAT64 (embeds IPv4 addresses)
+}
+
+// publicAddr reports whether webhooks may be delivered to an address
+func publicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() { // Also loopback, link-local, multicast and unspecified
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// webhookClient builds a delivery client that only dials allowed addresses and does not follow redirects
+func webhookClient(allowed func(netip.Addr) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: webhookTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error { // Called with the resolved address
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !allowed(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", errBlockedAddress, addrPort.Addr())
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil // A proxy would be dialed instead of the subscriber
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   webhookTimeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse // Redirects are failed attempts (unexpected status)
+		},
+	}
+}
+
+// send posts the signed payload and returns the response status (error unless 2xx)
+func (d *WebhookDispatcher) send(ctx context.Context, delivery *models.WebhookDelivery, now time.Time) (int, error) {
+
+	// Build request
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "library-webhooks/1.0")
+	req.Header.Set("X-Webhook-Id", delivery.ID)
+	req.Header.Set("X-Webhook-Event", string(delivery.EventType))
+	req.Header.Set("X-Webhook-Event-Id", delivery.EventID) // Same on retries and redeliveries (for deduplication)
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", WebhookSignature(delivery.Secret, timestamp, delivery.Payload))
+
+	// Execute request (the response body is discarded)
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	// Check status
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("unexpected status %d", res.StatusCode)
+	}
+	return res.StatusCode, nil
+}
+
+// deliveryBatchSize is the number of deliveries to claim: the batch size, or as many sequential
+// attempts (each bounded by the subscriber timeout) as fit before the context deadline
+func deliveryBatchSize(ctx context.Context, now time.Time) int {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return webhookBatchSize
+	}
+	return min(webhookBatchSize, max(0, int(deadline.Sub(now)/webhookTimeout)))
+}
+
+// retryBackoff returns the delay before the retry following the given (1-based) failed attempt:
+// base doubled on each attempt, capped at maxBackoff
+func retryBackoff(attempt int, base, maxBackoff time.Duration) time.Duration {
+	backoff := base
+	for i := 1; i < attempt && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxBackoff)
+}
+
+// truncate cuts s to at most n bytes on a rune boundary, dropping invalid UTF-8 (stored in text columns)
+func truncate(s string, n int) string {
+	if len(s) > n {
+		for n > 0 && !utf8.RuneStart(s[n]) {
+			n--
+		}
+		s = s[:n]
+	}
+	return strings.ToValidUTF8(s, "")
+}
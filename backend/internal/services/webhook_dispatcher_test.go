@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestDeliver_SignedRequest(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockWebhookRepo)
+
+	// Subscriber verifying the signature
+	payload := []byte(`{"id":"event-1","type":"book.created"}`)
+	var verified bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get("X-Webhook-Timestamp"), 10, 64)
+		verified = r.Header.Get("X-Webhook-Signature") == WebhookSignature("s3cr3t-s3cr3t-s3cr3t", timestamp, body) &&
+			r.Header.Get("X-Webhook-Event") == "book.created" &&
+			r.Header.Get("X-Webhook-Id") == "delivery-1"
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	dispatcher := NewWebhookDispatcher(mockedRepo, server.Client(), zaptest.NewLogger(t))
+	delivery := &models.WebhookDelivery{
+		ID: "delivery-1", EventType: models.BookEventCreated, Payload: payload,
+		Status: models.WebhookDeliveryPending, URL: server.URL, Secret: "s3cr3t-s3cr3t-s3cr3t",
+	}
+	mockedRepo.On("UpdateDeliveryAttempt", ctx, delivery).Return(nil)
+
+	err := dispatcher.Deliver(ctx, delivery)
+
+	assert.NoError(t, err)
+	assert.True(t, verified)
+	assert.Equal(t, models.WebhookDeliverySucceeded, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusNoContent, delivery.ResponseStatus)
+	assert.Nil(t, delivery.NextAttemptAt)
+	mockedRepo.AssertExpectations(t)
+}
+
+func TestDeliver_FailureSchedulesRetry(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockWebhookRepo)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	dispatcher := NewWebhookDispatcher(mockedRepo, server.Client(), zaptest.NewLogger(t))
+	dispatcher.now = func() time.Time { return now }
+
+	delivery := &models.WebhookDelivery{ID: "delivery-1", Attempts: 2, Status: models.WebhookDeliveryPending, URL: server.URL, Secret: "secret"}
+	mockedRepo.On("UpdateDeliveryAttempt", ctx, mock.Anything).Return(nil)
+
+	err := dispatcher.Deliver(ctx, delivery)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.WebhookDeliveryPending, delivery.Status)
+	assert.Equal(t, 3, delivery.Attempts)
+	assert.Equal(t, http.StatusServiceUnavailable, delivery.ResponseStatus)
+	assert.Equal(t, "unexpected status 503", delivery.LastError)
+	if assert.NotNil(t, delivery.NextAttemptAt) {
+		assert.Equal(t, now.Add(2*time.Minute), *delivery.NextAttemptAt) // 30s doubled twice
+	}
+}
+
+func TestDeliver_FailsAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockWebhookRepo)
+
+	dispatcher := NewWebhookDispatcher(mockedRepo, nil, zaptest.NewLogger(t))
+	delivery := &models.WebhookDelivery{ID: "delivery-1", Attempts: webhookMaxAttempts - 1, URL: "http://127.0.0.1:0/unreachable", Secret: "secret"}
+	mockedRepo.On("UpdateDeliveryAttempt", ctx, mock.Anything).Return(nil)
+
+	err := dispatcher.Deliver(ctx, delivery)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.WebhookDeliveryFailed, delivery.Status)
+	assert.Nil(t, delivery.NextAttemptAt)
+	assert.NotEmpty(t, delivery.LastError)
+}
+
+func TestDeliverDue_SizesBatchToDeadline(t *testing.T) {
+	mockedRepo := new(mockWebhookRepo)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	dispatcher := NewWebhookDispatcher(mockedRepo, nil, zaptest.NewLogger(t))
+	dispatcher.now = func() time.Time { return now }
+
+	// 35s left fit 3 attempts of up to 10s each
+	ctx, cancel := context.WithDeadline(context.Background(), now.Add(35*time.Second))
+	defer cancel()
+	mockedRepo.On("ClaimDueDeliveries", ctx, now, webhookLease, 3).Return([]models.WebhookDelivery{}, nil).Once()
+
+	attempted, err := dispatcher.DeliverDue(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, attempted)
+	mockedRepo.AssertExpectations(t)
+}
+
+func TestDeliveryBatchSize(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	withDeadline := func(d time.Duration) context.Context {
+		ctx, cancel := context.WithDeadline(context.Background(), now.Add(d))
+		t.Cleanup(cancel)
+		return ctx
+	}
+
+	assert.Equal(t, webhookBatchSize, deliveryBatchSize(context.Background(), now)) // No deadline
+	assert.Equal(t, webhookBatchSize, deliveryBatchSize(withDeadline(time.Hour), now))
+	assert.Equal(t, 4, deliveryBatchSize(withDeadline(45*time.Second), now))
+	assert.Equal(t, 0, deliveryBatchSize(withDeadline(5*time.Second), now)) // No time for an attempt
+	assert.Equal(t, 0, deliveryBatchSize(withDeadline(-time.Second), now))
+}
+
+func TestWebhookClient_RefusesNonPublicAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("loopback subscriber reached")
+	}))
+	defer server.Close()
+
+	// Refused when dialing (after resolution), whatever the URL host
+	client := NewWebhookClient()
+	for _, target := range []string{server.URL, strings.Replace(server.URL, "127.0.0.1", "localhost", 1)} {
+		_, err := client.Post(target, "application/json", strings.NewReader("{}"))
+		assert.ErrorIs(t, err, errBlockedAddress, target)
+	}
+
+	cases := map[string]bool{
+		"93.184.216.34":    true,
+		"2606:4700::1111":  true,
+		"127.0.0.1":        false,
+		"10.0.1.20":        false,
+		"172.16.0.1":       false,
+		"192.168.1.1":      false,
+		"169.254.169.254":  false, // Instance metadata service
+		"100.64.0.1":       false,
+		"0.0.0.0":          false,
+		"::1":              false,
+		"fd00:ec2::254":    false, // Instance metadata service (IPv6)
+		"fe80::1":          false,
+		"::ffff:127.0.0.1": false, // IPv4-mapped
+		"64:ff9b::a00:1":   false, // NAT64 of 10.0.0.1
+	}
+	for addr, public := range cases {
+		assert.Equal(t, public, publicAddr(netip.MustParseAddr(addr)), addr)
+	}
+}
+
+func TestWebhookClient_DoesNotFollowRedirects(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockWebhookRepo)
+
+	var redirected bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/internal" {
+			redirected = true
+			return
+		}
+		http.Redirect(w, r, "/internal", http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	allowAll := func(netip.Addr) bool { return true } // The test subscriber is on loopback
+	dispatcher := NewWebhookDispatcher(mockedRepo, webhookClient(allowAll), zaptest.NewLogger(t))
+	delivery := &models.WebhookDelivery{ID: "delivery-1", URL: server.URL + "/hooks", Secret: "secret"}
+	mockedRepo.On("UpdateDeliveryAttempt", ctx, mock.Anything).Return(nil)
+
+	err := dispatcher.Deliver(ctx, delivery)
+
+	assert.NoError(t, err)
+	assert.False(t, redirected)
+	assert.Equal(t, models.WebhookDeliveryPending, delivery.Status) // Failed attempt (retried)
+	assert.Equal(t, http.StatusTemporaryRedirect, delivery.ResponseStatus)
+}
+
+func TestRetryBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, retryBackoff(1, webhookBaseBackoff, webhookMaxBackoff))
+	assert.Equal(t, time.Minute, retryBackoff(2, webhookBaseBackoff, webhookMaxBackoff))
+	assert.Equal(t, 16*time.Minute, retryBackoff(6, webhookBaseBackoff, webhookMaxBackoff))
+	assert.Equal(t, time.Hour, retryBackoff(20, webhookBaseBackoff, webhookMaxBackoff)) // Capped
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "short", truncate("short", 10))
+	assert.Equal(t, "abc", truncate("abcdef", 3))
+	assert.Equal(t, "a", truncate("añb", 2))      // ñ is 2 bytes, not split
+	assert.Equal(t, "añ", truncate("añb", 3))     // Whole rune kept
+	assert.Equal(t, "ab", truncate("a\xffb", 10)) // Invalid bytes dropped
+}
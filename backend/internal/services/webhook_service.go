@@ -0,0 +1,295 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/santiago-buildit/code-challenge/backend/internal/events"
+	"github.com/santiago-buildit/code-challenge/backend/internal/i18n"
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/repositories"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// WebhookService defines the interface for webhook subscription and delivery operations
+type WebhookService interface {
+
+	// CRUD operations
+	CreateWebhook(ctx context.Context, req models.CreateWebhookRequest) (*models.WebhookResponse, error)
+	ListWebhooks(ctx context.Context) ([]models.WebhookResponse, error)
+	GetWebhook(ctx context.Context, id string) (*models.WebhookResponse, error)
+	UpdateWebhook(ctx context.Context, id string, req models.UpdateWebhookRequest) (*models.WebhookResponse, error)
+	DeleteWebhook(ctx context.Context, id string) error
+
+	// Deliveries
+	ListDeliveries(ctx context.Context, id string, req models.ListWebhookDeliveriesRequest) (*models.ListWebhookDeliveriesResponse, error)
+	Redeliver(ctx context.Context, id string, deliveryID string) (*models.WebhookDeliveryResponse, error)
+
+	// Events (outbox sink creating the deliveries of each book event)
+	events.Sink
+}
+
+type webhookServiceImpl struct {
+	repo       repositories.WebhookRepository
+	dispatcher *WebhookDispatcher
+	logger     *zap.Logger
+}
+
+func NewWebhookService(repo repositories.WebhookRepository, dispatcher *WebhookDispatcher, logger *zap.Logger) WebhookService {
+	return &webhookServiceImpl{
+		repo:       repo,
+		dispatcher: dispatcher,
+		logger:     logger,
+	}
+}
+
+func (s *webhookServiceImpl) CreateWebhook(ctx context.Context, req models.CreateWebhookRequest) (*models.WebhookResponse, error) {
+
+	// Validate URL host
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	// Generate secret (when not given)
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		if secret, err = generateWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Map request
+	webhook := models.Webhook{
+		ID:        uuid.New().String(), // Generate unique ID
+		URL:       req.URL,
+		Secret:    secret,
+		Events:    req.Events,
+		Active:    req.Active == nil || *req.Active,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	// Create with repository
+	if err := s.repo.CreateWebhook(ctx, &webhook); err != nil {
+		return nil, err
+	}
+
+	// Map response (the secret is only returned on create)
+	res := models.ToWebhookResponse(&webhook)
+	res.Secret = webhook.Secret
+	return res, nil
+}
+
+func (s *webhookServiceImpl) ListWebhooks(ctx context.Context) ([]models.WebhookResponse, error) {
+
+	// List with repository
+	webhooks, err := s.repo.ListWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map response
+	return models.ToWebhookResponseList(webhooks), nil
+}
+
+func (s *webhookServiceImpl) GetWebhook(ctx context.Context, id string) (*models.WebhookResponse, error) {
+
+	// Get with repository
+	webhook, err := s.repo.GetWebhookByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map response
+	return models.ToWebhookResponse(webhook), nil
+}
+
+func (s *webhookServiceImpl) UpdateWebhook(ctx context.Context, id string, req models.UpdateWebhookRequest) (*models.WebhookResponse, error) {
+
+	// Validate URL host
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+
+	// Get with repository
+	webhook, err := s.repo.GetWebhookByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map request (secret and active are kept when omitted)
+	webhook.URL = req.URL
+	webhook.Events = req.Events
+	if req.Secret != "" {
+		webhook.Secret = req.Secret
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+	webhook.UpdatedAt = time.Now()
+
+	// Update with repository
+	if err := s.repo.UpdateWebhook(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	// Map response
+	return models.ToWebhookResponse(webhook), nil
+}
+
+func (s *webhookServiceImpl) DeleteWebhook(ctx context.Context, id string) error {
+
+	// Delete with repository
+	return s.repo.DeleteWebhook(ctx, id)
+}
+
+func (s *webhookServiceImpl) ListDeliveries(ctx context.Context, id string, req models.ListWebhookDeliveriesRequest) (*models.ListWebhookDeliveriesResponse, error) {
+
+	// Check webhook exists (not found otherwise)
+	if _, err := s.repo.GetWebhookByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	// List with repository
+	deliveries, totalItems, err := s.repo.ListDeliveries(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map response
+	return &models.ListWebhookDeliveriesResponse{
+		Deliveries:  models.ToWebhookDeliveryResponseList(deliveries),
+		TotalItems:  totalItems,
+		TotalPages:  totalPages(totalItems, req.PageSize),
+		CurrentPage: req.Page,
+		PageSize:    req.PageSize,
+	}, nil
+}
+
+func (s *webhookServiceImpl) Redeliver(ctx context.Context, id string, deliveryID string) (*models.WebhookDeliveryResponse, error) {
+
+	// Get original delivery with repository (with the current webhook URL and secret)
+	original, err := s.repo.GetDelivery(ctx, id, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Copy as a new delivery of the same event (the original keeps its log)
+	now := time.Now()
+	delivery := models.WebhookDelivery{
+		ID:            uuid.New().String(),
+		WebhookID:     original.WebhookID,
+		EventID:       original.EventID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		Status:        models.WebhookDeliveryPending,
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		URL:           original.URL,
+		Secret:        original.Secret,
+	}
+	if err := s.repo.CreateDeliveries(ctx, []models.WebhookDelivery{delivery}); err != nil {
+		return nil, err
+	}
+
+	// Attempt now (a failure schedules retries like any delivery)
+	if err := s.dispatcher.Deliver(ctx, &delivery); err != nil {
+		return nil, err
+	}
+
+	// Map response
+	return models.ToWebhookDeliveryResponse(&delivery), nil
+}
+
+func (s *webhookServiceImpl) Name() string {
+	return "webhooks"
+}
+
+// Send creates one pending delivery of the event per subscribed webhook and wakes up the dispatcher
+func (s *webhookServiceImpl) Send(ctx context.Context, event *models.OutboxEvent) error {
+
+	// Only book events are delivered to webhooks
+	if event.AggregateType != models.AggregateBook {
+		return nil
+	}
+	eventType := models.BookEventType(event.EventType)
+
+	// List subscribed webhooks with repository
+	webhooks, err := s.repo.ListWebhooksForEvent(ctx, eventType)
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+
+	// Skip webhooks that already have the event (the outbox may send it again after a failure)
+	existing, err := s.repo.ListEventWebhookIDs(ctx, event.ID)
+	if err != nil {
+		return err
+	}
+
+	// Create deliveries with repository (due now, same payload and event ID as the outbox event)
+	now := time.Now()
+	deliveries := make([]models.WebhookDelivery, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		if slices.Contains(existing, webhook.ID) {
+			continue
+		}
+		deliveries = append(deliveries, models.WebhookDelivery{
+			ID:            uuid.New().String(),
+			WebhookID:     webhook.ID,
+			EventID:       event.ID,
+			EventType:     eventType,
+			Payload:       event.Payload,
+			Status:        models.WebhookDeliveryPending,
+			NextAttemptAt: &now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+	}
+	if err := s.repo.CreateDeliveries(ctx, deliveries); err != nil {
+		return err
+	}
+
+	// Wake up dispatcher
+	s.dispatcher.Notify()
+	return nil
+}
+
+/* Helper functions */
+
+// validateWebhookURL rejects URLs whose host is a non-public IP address or localhost (hosts resolved
+// by DNS are checked again on every delivery, when dialing)
+func validateWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return i18n.NewError(utils.ErrBadRequest, i18n.ErrorInvalidWebhookURL, nil)
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return i18n.NewError(utils.ErrBadRequest, i18n.ErrorPrivateWebhookURL, nil)
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !publicAddr(addr) {
+		return i18n.NewError(utils.ErrBadRequest, i18n.ErrorPrivateWebhookURL, nil)
+	}
+	return nil
+}
+
+// generateWebhookSecret returns a random 32-byte hex secret
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
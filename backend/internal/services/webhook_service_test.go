@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/santiago-buildit/code-challenge/backend/internal/models"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+// --- Mock definition ---
+
+type mockWebhookRepo struct {
+	mock.Mock
+}
+
+func (m *mockWebhookRepo) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	args := m.Called(ctx, webhook)
+	return args.Error(0)
+}
+
+func (m *mockWebhookRepo) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Webhook), args.Error(1)
+}
+
+func (m *mockWebhookRepo) GetWebhookByID(ctx context.Context, id string) (*models.Webhook, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.Webhook), args.Error(1)
+}
+
+func (m *mockWebhookRepo) UpdateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	args := m.Called(ctx, webhook)
+	return args.Error(0)
+}
+
+func (m *mockWebhookRepo) DeleteWebhook(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockWebhookRepo) ListWebhooksForEvent(ctx context.Context, eventType models.BookEventType) ([]models.Webhook, error) {
+	args := m.Called(ctx, eventType)
+	return args.Get(0).([]models.Webhook), args.Error(1)
+}
+
+func (m *mockWebhookRepo) CreateDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	args := m.Called(ctx, deliveries)
+	return args.Error(0)
+}
+
+func (m *mockWebhookRepo) ListEventWebhookIDs(ctx context.Context, eventID string) ([]string, error) {
+	args := m.Called(ctx, eventID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockWebhookRepo) ListDeliveries(ctx context.Context, webhookID string, req models.ListWebhookDeliveriesRequest) ([]models.WebhookDelivery, int, error) {
+	args := m.Called(ctx, webhookID, req)
+	return args.Get(0).([]models.WebhookDelivery), args.Int(1), args.Error(2)
+}
+
+func (m *mockWebhookRepo) GetDelivery(ctx context.Context, webhookID string, id string) (*models.WebhookDelivery, error) {
+	args := m.Called(ctx, webhookID, id)
+	return args.Get(0).(*models.WebhookDelivery), args.Error(1)
+}
+
+func (m *mockWebhookRepo) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	args := m.Called(ctx, now, lease, limit)
+	return args.Get(0).([]models.WebhookDelivery), args.Error(1)
+}
+
+func (m *mockWebhookRepo) UpdateDeliveryAttempt(ctx context.Context, delivery *models.WebhookDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+// --- Test ---
+
+func TestCreateWebhook_GeneratesSecret(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockWebhookRepo)
+	service := NewWebhookService(mockedRepo, NewWebhookDispatcher(mockedRepo, nil, zaptest.NewLogger(t)), zaptest.NewLogger(t))
+
+	var captured *models.Webhook
+	mockedRepo.On("CreateWebhook", ctx, mock.MatchedBy(func(w *models.Webhook) bool {
+		captured = w
+		return true
+	})).Return(nil)
+
+	res, err := service.CreateWebhook(ctx, models.CreateWebhookRequest{
+		URL:    "https://example.com/hooks",
+		Events: []string{"book.created"},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, res.Active) // Default
+	assert.NotEmpty(t, res.Secret)
+	assert.Equal(t, captured.Secret, res.Secret) // Returned on create only
+	mockedRepo.AssertExpectations(t)
+}
+
+func TestCreateWebhook_RejectsLocalAddresses(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockWebhookRepo)
+	service := NewWebhookService(mockedRepo, NewWebhookDispatcher(mockedRepo, nil, zaptest.NewLogger(t)), zaptest.NewLogger(t))
+
+	for _, url := range []string{
+		"http://169.254.169.254/latest/meta-data/",
+		"http://localhost:8080/hooks",
+		"http://127.0.0.1/hooks",
+		"https://10.0.0.12/hooks",
+		"http://[::1]/hooks",
+	} {
+		_, err := service.CreateWebhook(ctx, models.CreateWebhookRequest{URL: url, Events: []string{"book.created"}})
+
+		assert.ErrorIs(t, err, utils.ErrBadRequest, url)
+	}
+	mockedRepo.AssertNotCalled(t, "CreateWebhook", mock.Anything, mock.Anything)
+}
+
+func TestSendEvent_CreatesDeliveryPerSubscriber(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockWebhookRepo)
+	dispatcher := NewWebhookDispatcher(mockedRepo, nil, zaptest.NewLogger(t))
+	service := NewWebhookService(mockedRepo, dispatcher, zaptest.NewLogger(t))
+
+	event := &models.OutboxEvent{
+		ID: "event-1", EventType: "book.created", AggregateType: models.AggregateBook, AggregateID: "book-1",
+		Payload: []byte(`{"id":"event-1","type":"book.created","book_id":"book-1"}`),
+	}
+	webhooks := []models.Webhook{{ID: "webhook-1"}, {ID: "webhook-2"}, {ID: "webhook-3"}}
+
+	var captured []models.WebhookDelivery
+	mockedRepo.On("ListWebhooksForEvent", ctx, models.BookEventCreated).Return(webhooks, nil)
+	mockedRepo.On("ListEventWebhookIDs", ctx, "event-1").Return([]string{"webhook-2"}, nil) // Sent before a failure
+	mockedRepo.On("CreateDeliveries", ctx, mock.MatchedBy(func(d []models.WebhookDelivery) bool {
+		captured = d
+		return true
+	})).Return(nil)
+
+	err := service.Send(ctx, event)
+
+	assert.NoError(t, err)
+	mockedRepo.AssertExpectations(t)
+	if assert.Len(t, captured, 2) {
+		assert.Equal(t, "webhook-1", captured[0].WebhookID)
+		assert.Equal(t, "webhook-3", captured[1].WebhookID)
+		assert.Equal(t, "event-1", captured[0].EventID) // Outbox event ID
+		assert.Equal(t, models.WebhookDeliveryPending, captured[0].Status)
+		assert.NotNil(t, captured[0].NextAttemptAt) // Due now
+		assert.Equal(t, event.Payload, captured[0].Payload)
+	}
+	assert.Len(t, dispatcher.wake, 1) // Dispatcher notified
+}
+
+func TestSendEvent_NoSubscribers(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockWebhookRepo)
+	service := NewWebhookService(mockedRepo, NewWebhookDispatcher(mockedRepo, nil, zaptest.NewLogger(t)), zaptest.NewLogger(t))
+
+	mockedRepo.On("ListWebhooksForEvent", ctx, models.BookEventDeleted).Return([]models.Webhook{}, nil)
+
+	err := service.Send(ctx, &models.OutboxEvent{ID: "event-1", EventType: "book.deleted", AggregateType: models.AggregateBook})
+
+	assert.NoError(t, err)
+	mockedRepo.AssertExpectations(t)
+	mockedRepo.AssertNotCalled(t, "CreateDeliveries", mock.Anything, mock.Anything)
+}
+
+func TestSendEvent_RepositoryError(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockWebhookRepo)
+	service := NewWebhookService(mockedRepo, NewWebhookDispatcher(mockedRepo, nil, zaptest.NewLogger(t)), zaptest.NewLogger(t))
+
+	mockedRepo.On("ListWebhooksForEvent", ctx, models.BookEventUpdated).Return([]models.Webhook(nil), assert.AnError)
+
+	err := service.Send(ctx, &models.OutboxEvent{ID: "event-1", EventType: "book.updated", AggregateType: models.AggregateBook})
+
+	assert.Equal(t, assert.AnError, err) // Retried by the outbox
+}
+
+func TestListDeliveries_WebhookNotFound(t *testing.T) {
+	ctx := context.Background()
+	mockedRepo := new(mockWebhookRepo)
+	service := NewWebhookService(mockedRepo, NewWebhookDispatcher(mockedRepo, nil, zaptest.NewLogger(t)), zaptest.NewLogger(t))
+
+	mockedRepo.On("GetWebhookByID", ctx, "missing").Return((*models.Webhook)(nil), utils.ErrNotFound)
+
+	res, err := service.ListDeliveries(ctx, "missing", models.ListWebhookDeliveriesRequest{Page: 1, PageSize: 10})
+
+	assert.Nil(t, res)
+	assert.ErrorIs(t, err, utils.ErrNotFound)
+	mockedRepo.AssertNotCalled(t, "ListDeliveries", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,26 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// BlobStore stores binary objects (e.g. cover images) by key. Keys are slash-separated paths (e.g. "covers/<id>/small.jpg").
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) (*Blob, error) // utils.ErrNotFound when the key does not exist
+	Delete(ctx context.Context, key string) error       // Missing keys are ignored
+}
+
+// URLSigner is implemented by stores that hand out temporary download URLs, so clients fetch objects
+// directly instead of through the API
+type URLSigner interface {
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// Blob is a stored object with its metadata
+type Blob struct {
+	Data         []byte
+	ContentType  string
+	LastModified time.Time
+}
@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalBlobStore_PutGetDelete(t *testing.T) {
+	ctx := context.Background()
+	store := NewLocalBlobStore(t.TempDir())
+
+	data := []byte("\x89PNG\r\n\x1a\n fake png body")
+	assert.NoError(t, store.Put(ctx, "covers/book-1/original", data, "image/png"))
+
+	blob, err := store.Get(ctx, "covers/book-1/original")
+	assert.NoError(t, err)
+	assert.Equal(t, data, blob.Data)
+	assert.Equal(t, "image/png", blob.ContentType)
+	assert.False(t, blob.LastModified.IsZero())
+
+	assert.NoError(t, store.Delete(ctx, "covers/book-1/original"))
+	assert.NoError(t, store.Delete(ctx, "covers/book-1/original")) // Idempotent
+
+	_, err = store.Get(ctx, "covers/book-1/original")
+	assert.ErrorIs(t, err, utils.ErrNotFound)
+}
+
+func TestLocalBlobStore_RejectsEscapingKeys(t *testing.T) {
+	ctx := context.Background()
+	store := NewLocalBlobStore(t.TempDir())
+
+	assert.Error(t, store.Put(ctx, "../outside", []byte("x"), "text/plain"))
+	assert.Error(t, store.Put(ctx, "", []byte("x"), "text/plain"))
+}
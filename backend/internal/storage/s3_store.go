@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+)
+
+// S3Config configures an S3-compatible object store (AWS S3, MinIO, ...)
+type S3Config struct {
+	Endpoint  string // Custom endpoint, e.g. http://localhost:9000 for MinIO (empty = AWS, from the region)
+	Bucket    string
+	PathStyle bool // Bucket in the path instead of the host name (required by MinIO)
+}
+
+// S3BlobStore stores objects in an S3-compatible bucket with the AWS SDK, and signs temporary download URLs
+type S3BlobStore struct {
+	bucket    string
+	client    *s3.Client
+	presigner *s3.PresignClient
+}
+
+// NewS3BlobStore creates the store with the region, credentials and HTTP client of the AWS config
+func NewS3BlobStore(awsConfig aws.Config, config S3Config) (*S3BlobStore, error) {
+	if config.Bucket == "" || awsConfig.Region == "" {
+		return nil, fmt.Errorf("missing S3 bucket or region")
+	}
+	client := s3.NewFromConfig(awsConfig, func(o *s3.Options) {
+		if config.Endpoint != "" {
+			o.BaseEndpoint = aws.String(config.Endpoint)
+		}
+		o.UsePathStyle = config.PathStyle
+
+		// Checksums only when required (S3-compatible stand-ins may not support the default ones)
+		o.RequestChecksumCalculation = aws.RequestChecksumCalculationWhenRequired
+		o.ResponseChecksumValidation = aws.ResponseChecksumValidationWhenRequired
+	})
+	return &S3BlobStore{
+		bucket:    config.Bucket,
+		client:    client,
+		presigner: s3.NewPresignClient(client),
+	}, nil
+}
+
+func (s *S3BlobStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+
+	// Upload object
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put blob %q: %w", key, err)
+	}
+	return nil
+}
+
+func (s *S3BlobStore) Get(ctx context.Context, key string) (*Blob, error) {
+
+	// Download object
+	res, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	var noSuchKey *types.NoSuchKey
+	if errors.As(err, &noSuchKey) || isNotFound(err) {
+		return nil, utils.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blob %q: %w", key, err)
+	}
+	defer res.Body.Close()
+
+	// Read object
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &Blob{
+		Data:         data,
+		ContentType:  aws.ToString(res.ContentType),
+		LastModified: aws.ToTime(res.LastModified), // Zero when missing
+	}, nil
+}
+
+func (s *S3BlobStore) Delete(ctx context.Context, key string) error {
+
+	// Delete object (S3 answers 204 even for missing keys, some stand-ins answer 404)
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("failed to delete blob %q: %w", key, err)
+	}
+	return nil
+}
+
+func (s *S3BlobStore) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+
+	// Presign download (no request is sent, missing keys fail when the URL is used)
+	req, err := s.presigner.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign blob %q URL: %w", key, err)
+	}
+	return req.URL, nil
+}
+
+// isNotFound reports whether a request failed with status 404
+func isNotFound(err error) bool {
+	var resErr *awshttp.ResponseError
+	return errors.As(err, &resErr) && resErr.HTTPStatusCode() == http.StatusNotFound
+}
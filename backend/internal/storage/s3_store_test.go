@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/santiago-buildit/code-challenge/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeS3 is a minimal in-memory stand-in for an S3-compatible server (path-style)
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=minio/") || r.Header.Get("X-Amz-Content-Sha256") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = body
+		f.types[r.URL.Path] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		body, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("<Error><Code>NoSuchKey</Code></Error>"))
+			return
+		}
+		w.Header().Set("Content-Type", f.types[r.URL.Path])
+		w.Header().Set("Last-Modified", "Wed, 21 Oct 2026 07:28:00 GMT")
+		_, _ = w.Write(body)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// testAWSConfig has the static credentials accepted by fakeS3
+var testAWSConfig = aws.Config{
+	Region:      "us-east-1",
+	Credentials: credentials.NewStaticCredentialsProvider("minio", "minio-secret", ""),
+}
+
+func TestS3BlobStore_PutGetDelete(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeS3{objects: map[string][]byte{}, types: map[string]string{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store, err := NewS3BlobStore(testAWSConfig, S3Config{Endpoint: server.URL, Bucket: "covers", PathStyle: true})
+	assert.NoError(t, err)
+
+	assert.NoError(t, store.Put(ctx, "covers/book 1/small.jpg", []byte("jpeg"), "image/jpeg"))
+	assert.Contains(t, fake.objects, "/covers/covers/book 1/small.jpg") // Bucket in path
+
+	blob, err := store.Get(ctx, "covers/book 1/small.jpg")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("jpeg"), blob.Data)
+	assert.Equal(t, "image/jpeg", blob.ContentType)
+	assert.Equal(t, 2026, blob.LastModified.Year())
+
+	assert.NoError(t, store.Delete(ctx, "covers/book 1/small.jpg"))
+
+	_, err = store.Get(ctx, "covers/book 1/small.jpg")
+	assert.ErrorIs(t, err, utils.ErrNotFound)
+}
+
+func TestS3BlobStore_ErrorResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("<Error><Code>AccessDenied</Code></Error>"))
+	}))
+	defer server.Close()
+
+	store, err := NewS3BlobStore(testAWSConfig, S3Config{Endpoint: server.URL, Bucket: "covers", PathStyle: true})
+	assert.NoError(t, err)
+
+	err = store.Put(context.Background(), "covers/x", []byte("x"), "image/png")
+	assert.ErrorContains(t, err, "AccessDenied")
+}
+
+func TestS3BlobStore_SignedURL(t *testing.T) {
+	store, err := NewS3BlobStore(testAWSConfig, S3Config{Endpoint: "http://localhost:9000", Bucket: "covers", PathStyle: true})
+	assert.NoError(t, err)
+
+	signed, err := store.SignedURL(context.Background(), "covers/book 1/small.jpg", 15*time.Minute)
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(signed, "http://localhost:9000/covers/covers/book%201/small.jpg?"), signed)
+	assert.Contains(t, signed, "X-Amz-Credential=minio%2F")
+	assert.Contains(t, signed, "X-Amz-Expires=900")
+	assert.Contains(t, signed, "X-Amz-Signature=")
+}
+
+func TestNewS3BlobStore_MissingBucket(t *testing.T) {
+	_, err := NewS3BlobStore(testAWSConfig, S3Config{})
+	assert.Error(t, err)
+}
@@ -1,6 +1,10 @@
 package utils
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 /* Custom errors for REST API */
 
@@ -9,3 +13,30 @@ var ErrBadRequest = errors.New("bad request")
 
 // ErrNotFound is used when a requested entity does not exist
 var ErrNotFound = errors.New("not found")
+
+// ErrConflict is used when the request conflicts with an existing entity (e.g. duplicate unique value)
+var ErrConflict = errors.New("conflict")
+
+// ErrInvalidTransition is used when an entity cannot move from its current status to the requested one
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// ErrUnsupportedMediaType is used when uploaded content has an unsupported type (e.g. not an image)
+var ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+// TransitionError is an ErrInvalidTransition carrying the statuses allowed from the current one
+type TransitionError struct {
+	From    string
+	To      string
+	Allowed []string
+}
+
+func (e *TransitionError) Error() string {
+	if len(e.Allowed) == 0 {
+		return fmt.Sprintf("%v: %s to %s (no transitions allowed from %s)", ErrInvalidTransition, e.From, e.To, e.From)
+	}
+	return fmt.Sprintf("%v: %s to %s (allowed: %s)", ErrInvalidTransition, e.From, e.To, strings.Join(e.Allowed, ", "))
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrInvalidTransition
+}
@@ -2,6 +2,9 @@ package utils
 
 import (
 	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
 )
 
 // CheckRowsAffected verifies that an Exec result affected at least one row.
@@ -16,3 +19,9 @@ func CheckRowsAffected(res sql.Result) error {
 	}
 	return nil
 }
+
+// IsUniqueViolation reports whether err is a PostgreSQL unique constraint violation
+func IsUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -5,7 +5,8 @@ import type {
   ListBooksRequest,
   ListBooksResponse,
   BookDetailResponse,
-  MessageResponse
+  MessageResponse,
+  CoverSize
 } from '@/types/book'
 
 /* This file contains the API calls related to books */
@@ -61,3 +62,19 @@ export async function getBookWithHistory(id: string): Promise<BookDetailResponse
   const res = await api.get(`/books/${id}/details`)
   return res.data
 }
+
+// Upload cover (JPEG, PNG or GIF, max 4 MB)
+export async function uploadCover(id: string, file: File): Promise<BookResponse> {
+  const form = new FormData()
+  form.append('file', file)
+  const res = await api.put(`/books/${id}/cover`, form)
+  return res.data
+}
+
+// Absolute cover URL for an <img> (undefined without cover)
+export function coverUrl(book: BookResponse, size: CoverSize = 'medium'): string | undefined {
+  if (!book.cover_url) {
+    return undefined
+  }
+  return `${api.defaults.baseURL}${book.cover_url}&size=${size}`
+}
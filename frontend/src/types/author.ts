@@ -0,0 +1,36 @@
+export type ContributorRole = 'author' | 'editor' | 'translator' | 'illustrator'
+
+// Author credited on a book (created when unknown, matched by name case-insensitively)
+export interface ContributorPayload {
+  name: string
+  role?: ContributorRole // Default author
+}
+
+export interface ContributorResponse {
+  author_id: string
+  name: string
+  role: ContributorRole
+}
+
+// Response for ListAuthors (within ListAuthorsResponse)
+export interface AuthorResponse {
+  id: string
+  name: string
+  book_count: number
+  created_at: string
+  updated_at: string
+}
+
+export interface ListAuthorsRequest {
+  page?: number
+  page_size?: number
+  name?: string
+}
+
+export interface ListAuthorsResponse {
+  authors: AuthorResponse[]
+  total_items: number
+  total_pages: number
+  current_page: number
+  page_size: number
+}
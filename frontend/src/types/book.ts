@@ -1,40 +1,123 @@
+import type { ContributorPayload, ContributorResponse, ContributorRole } from './author'
+
 
 // Generic success response
 export interface MessageResponse {
   message: string
 }
 
-// Generic error response
-export interface ErrorResponse {
-  error: string
+// Validation failure of a request field
+export interface FieldError {
+  field: string // JSON name, with the path of nested fields (e.g. contributors[0].name)
+  rule: string // required, max, oneof... or type
+  message: string
+}
+
+// Error response (RFC 7807 problem, application/problem+json)
+export interface ProblemResponse {
+  type: string
+  title: string
+  status: number
+  detail: string
+  instance: string
+  code: string // Stable error code
+  request_id: string
+  errors?: FieldError[]
 }
 
+export type BookFormat = 'hardcover' | 'paperback' | 'ebook' | 'audiobook'
+
+export type BookStatus = 'available' | 'checked_out' | 'lost' | 'damaged' | 'in_repair' | 'missing' | 'withdrawn'
+
 // Common request for CreateBook and UpdateBook
 export interface BookPayload {
   isbn: string
   title: string
-  author: string
+  author: string // Single author (derived from contributors when they are given)
   description?: string
+  publisher?: string
+  publication_year?: number
+  edition?: string
+  language?: string // ISO 639-1 code, e.g. 'en'
+  page_count?: number
+  format?: BookFormat | ''
+  contributors?: ContributorPayload[]
+  tags?: string[] // Omitted keeps the current tags on update, [] removes them all
 }
 
 // Common response for CreateBook, GetBook, UpdateBook, and ListBooks (within ListBooksResponse)
 export interface BookResponse extends BookPayload {
   id: string
-  status: 'available' | 'checked_out'
+  status: BookStatus
   created_at: string
   updated_at: string
+  tags: string[]
+  publisher: string
+  publication_year: number // 0 when unknown
+  edition: string
+  language: string
+  page_count: number // 0 when unknown
+  format: BookFormat | ''
+  cover_url?: string // Relative to the API base URL (omitted without cover)
+  contributors: ContributorResponse[]
+  highlights?: BookHighlights
+}
+
+// Full-text search snippets (matches wrapped in <mark> tags)
+export interface BookHighlights {
+  title?: string
+  description?: string
+}
+
+// One ordering criterion of a list request
+export interface SortSpec {
+  field: NonNullable<ListBooksRequest['sort_by']>
+  order?: 'asc' | 'desc'
 }
 
 export interface ListBooksRequest {
-  page: number
+  page?: number // Required in offset mode
   page_size: number
-  sort_by?: 'isbn' | 'title' | 'author' | 'status'
+  sort_by?:
+    | 'isbn'
+    | 'title'
+    | 'author'
+    | 'status'
+    | 'created_at'
+    | 'updated_at'
+    | 'publisher'
+    | 'publication_year'
+    | 'page_count'
+    | 'relevance'
   sort_order?: 'asc' | 'desc'
+  sort?: SortSpec[] // Multi-column sort (overrides sort_by/sort_order)
+  pagination?: 'offset' | 'cursor'
+  cursor?: string
+  skip_total?: boolean
   isbn?: string
   title?: string
   author?: string
   status?: string
   text?: string
+  q?: string // Query language, e.g. author:tolkien AND (title:"ring" OR status:available)
+  isbn_match?: 'contains' | 'exact'
+  title_match?: 'contains' | 'exact'
+  author_match?: 'contains' | 'exact'
+  statuses?: string[]
+  publisher?: string
+  language?: string
+  formats?: BookFormat[]
+  year_from?: number // Inclusive
+  year_to?: number // Inclusive
+  tags?: string[]
+  tags_match?: 'any' | 'all'
+  author_id?: string
+  contributor_role?: ContributorRole
+  created_from?: string // RFC 3339, inclusive
+  created_to?: string // RFC 3339, exclusive
+  updated_from?: string
+  updated_to?: string
+  facets?: ('status' | 'author' | 'language' | 'format')[]
 }
 
 export interface ListBooksResponse {
@@ -43,14 +126,32 @@ export interface ListBooksResponse {
   total_pages: number
   current_page: number
   page_size: number
+  next_cursor?: string
+  prev_cursor?: string
+  fuzzy_match?: boolean
+  suggestions?: string[]
+  facets?: Record<string, FacetBucket[]>
+}
+
+// Number of matching books for one value of a facet field
+export interface FacetBucket {
+  value: string
+  count: number
 }
 
+// Cover image sizes (thumbnails are JPEG)
+export type CoverSize = 'original' | 'small' | 'medium' | 'large'
+
 export interface StatusChangeResponse {
-  status: 'available' | 'checked_out'
+  status: BookStatus
   timestamp: string
+  actor?: string
+  source?: 'desk' | 'kiosk' | 'api'
+  note?: string
 }
 
 export interface BookDetailResponse {
   book: BookResponse
-  history: StatusChangeResponse[]
+  history: StatusChangeResponse[] // Latest 50 changes
+  history_truncated: boolean // Older changes at GET /books/:id/history
 }
@@ -0,0 +1,12 @@
+// Common request for CreateTag and UpdateTag
+export interface TagPayload {
+  name: string
+}
+
+// Common response for CreateTag, GetTag, UpdateTag, and ListTags
+export interface TagResponse extends TagPayload {
+  id: string
+  book_count: number
+  created_at: string
+  updated_at: string
+}
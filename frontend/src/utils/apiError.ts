@@ -0,0 +1,11 @@
+// src/utils/apiError.ts
+
+import type { ProblemResponse } from '@/types/book'
+
+// Message of a failed API call: the problem detail, followed by the invalid fields
+export function getErrorMessage(err: unknown): string {
+    const problem = (err as any)?.response?.data as ProblemResponse | undefined
+    if (!problem?.detail) return 'An unexpected error occurred.'
+    if (!problem.errors?.length) return problem.detail
+    return `${problem.detail}: ${problem.errors.map(e => e.message).join('; ')}`
+}
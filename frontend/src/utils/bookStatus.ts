@@ -3,11 +3,19 @@
 export const BOOK_STATUS_LABELS: Record<string, string> = {
     available: 'Available',
     checked_out: 'Checked Out',
+    lost: 'Lost',
+    damaged: 'Damaged',
+    in_repair: 'In Repair',
+    missing: 'Missing',
+    withdrawn: 'Withdrawn',
 }
 
 export const BOOK_STATUS_VARIANTS: Record<string, 'success' | 'error' | 'neutral'> = {
     available: 'success',
     checked_out: 'error',
+    lost: 'error',
+    damaged: 'error',
+    missing: 'error',
 }
 
 export function getBookStatusLabel(status: string | undefined): string {
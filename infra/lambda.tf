@@ -20,6 +20,8 @@ resource "aws_lambda_function" "api" {
       DB_NAME     = var.db_name
       DB_USER     = var.db_username
       DB_PASSWORD = var.db_password
+      BLOB_STORE  = "s3"
+      S3_BUCKET   = aws_s3_bucket.covers.bucket
     }
   }
 
@@ -44,4 +46,58 @@ resource "aws_security_group" "lambda" {
   }
 
   tags = local.default_tags
-}
\ No newline at end of file
+}
+
+# Worker function for the outbox and webhook dispatchers (the API function is frozen between invocations)
+resource "aws_lambda_function" "worker" {
+  filename      = "${path.module}/../backend/bin/worker.zip"
+  function_name = "${local.name_prefix}worker"
+  role          = aws_iam_role.lambda_exec_role.arn
+  handler       = "bootstrap"
+  runtime       = "provided.al2023"
+  timeout       = 50 # Below the schedule rate, so runs don't overlap (webhook batches are sized to it)
+  memory_size   = 256
+  source_code_hash = filebase64sha256("${path.module}/../backend/bin/worker.zip")
+
+  environment {
+    variables = {
+      STAGE       = var.stage
+      DB_HOST     = aws_db_instance.postgres.address
+      DB_PORT     = var.db_port
+      DB_NAME     = var.db_name
+      DB_USER     = var.db_username
+      DB_PASSWORD = var.db_password
+      BLOB_STORE  = "s3"
+      S3_BUCKET   = aws_s3_bucket.covers.bucket
+    }
+  }
+
+  vpc_config {
+    subnet_ids         = [aws_subnet.private_a.id, aws_subnet.private_b.id]
+    security_group_ids = [aws_security_group.lambda.id]
+  }
+
+  tags = local.default_tags
+}
+
+# Run the worker every minute
+resource "aws_cloudwatch_event_rule" "worker" {
+  name                = "${local.name_prefix}worker-schedule"
+  schedule_expression = "rate(1 minute)"
+
+  tags = local.default_tags
+}
+
+resource "aws_cloudwatch_event_target" "worker" {
+  rule = aws_cloudwatch_event_rule.worker.name
+  arn  = aws_lambda_function.worker.arn
+}
+
+# Allow EventBridge to invoke the worker
+resource "aws_lambda_permission" "worker_schedule" {
+  statement_id  = "AllowEventBridgeInvoke"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.worker.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.worker.arn
+}
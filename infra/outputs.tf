@@ -20,6 +20,13 @@ output "lambda_function_name" {
   value       = aws_lambda_function.api.function_name
 }
 
+# Print the worker Lambda function name
+# IMPORTANT: This value is used in Makefile to update the function code
+output "worker_function_name" {
+  description = "Deployed worker Lambda function name (outbox and webhook dispatchers)"
+  value       = aws_lambda_function.worker.function_name
+}
+
 # Print the S3 bucket name for frontend
 # IMPORTANT: This value is used in Makefile for site deployments
 output "frontend_bucket_name" {
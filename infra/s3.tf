@@ -58,4 +58,19 @@ resource "aws_s3_bucket_policy" "frontend" {
   })
 }
 
+# Bucket for book cover images (private, served through the API)
+resource "aws_s3_bucket" "covers" {
+  bucket        = "${local.name_prefix}covers"
+  force_destroy = true # delete even if not empty
+  tags          = local.default_tags
+}
+
+# Block all public access to cover images
+resource "aws_s3_bucket_public_access_block" "covers_block" {
+  bucket = aws_s3_bucket.covers.id
 
+  block_public_acls       = true
+  block_public_policy     = true
+  ignore_public_acls      = true
+  restrict_public_buckets = true
+}
@@ -36,3 +36,79 @@ resource "aws_db_subnet_group" "rds_subnets" {
   subnet_ids = [aws_subnet.private_a.id, aws_subnet.private_b.id]
   tags       = local.default_tags
 }
+
+# Public subnet (NAT gateway only)
+resource "aws_subnet" "public_a" {
+  vpc_id                  = aws_vpc.main.id
+  cidr_block              = "10.0.101.0/24"
+  availability_zone       = "us-east-1a"
+  map_public_ip_on_launch = false
+  tags = merge(local.default_tags, { Name = "${local.name_prefix}public-subnet-a" })
+}
+
+# Internet gateway of the public subnet
+resource "aws_internet_gateway" "main" {
+  vpc_id = aws_vpc.main.id
+  tags   = merge(local.default_tags, { Name = "${local.name_prefix}igw" })
+}
+
+resource "aws_route_table" "public" {
+  vpc_id = aws_vpc.main.id
+
+  route {
+    cidr_block = "0.0.0.0/0"
+    gateway_id = aws_internet_gateway.main.id
+  }
+
+  tags = merge(local.default_tags, { Name = "${local.name_prefix}public-rt" })
+}
+
+resource "aws_route_table_association" "public_a" {
+  subnet_id      = aws_subnet.public_a.id
+  route_table_id = aws_route_table.public.id
+}
+
+# NAT gateway, so the Lambda functions (private subnets) can reach webhook subscribers and HTTP/SQS sinks
+resource "aws_eip" "nat" {
+  domain = "vpc"
+  tags   = merge(local.default_tags, { Name = "${local.name_prefix}nat-eip" })
+}
+
+resource "aws_nat_gateway" "main" {
+  allocation_id = aws_eip.nat.id
+  subnet_id     = aws_subnet.public_a.id
+  tags          = merge(local.default_tags, { Name = "${local.name_prefix}nat" })
+
+  depends_on = [aws_internet_gateway.main]
+}
+
+# Route table of the private subnets (internet through the NAT gateway)
+resource "aws_route_table" "private" {
+  vpc_id = aws_vpc.main.id
+
+  route {
+    cidr_block     = "0.0.0.0/0"
+    nat_gateway_id = aws_nat_gateway.main.id
+  }
+
+  tags = merge(local.default_tags, { Name = "${local.name_prefix}private-rt" })
+}
+
+resource "aws_route_table_association" "private_a" {
+  subnet_id      = aws_subnet.private_a.id
+  route_table_id = aws_route_table.private.id
+}
+
+resource "aws_route_table_association" "private_b" {
+  subnet_id      = aws_subnet.private_b.id
+  route_table_id = aws_route_table.private.id
+}
+
+# Gateway endpoint so S3 traffic (covers) doesn't go through the NAT gateway
+resource "aws_vpc_endpoint" "s3" {
+  vpc_id            = aws_vpc.main.id
+  service_name      = "com.amazonaws.${var.region}.s3"
+  vpc_endpoint_type = "Gateway"
+  route_table_ids   = [aws_route_table.private.id]
+  tags              = merge(local.default_tags, { Name = "${local.name_prefix}s3-endpoint" })
+}